@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 	"unicode/utf16"
 
 	"github.com/AlecAivazis/survey/v2"
@@ -36,7 +37,7 @@ const (
 var errConfigInitCanceled = errors.New("config init canceled")
 
 var availableSystemManagers = []string{
-	"apt", "brew", "go", "npm", "pnpm", "bun", "nvm", "snap", "flatpak", "fwupdmgr", "pipx", "cargo", "uv", "rustup", "gem", "winget", "scoop",
+	"apt", "dnf", "pacman", "brew", "go", "npm", "pnpm", "bun", "nvm", "snap", "flatpak", "fwupdmgr", "pipx", "cargo", "uv", "rustup", "gem", "nix", "mise", "containers", "vscode", "gh-ext", "krew", "winget", "scoop",
 }
 
 // テストで対話入力や外部依存を差し替えるためのフック
@@ -184,6 +185,60 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// installedListTimeout はインストール済みパッケージ一覧の取得1件あたりのタイムアウトです。
+const installedListTimeout = 30 * time.Second
+
+// collectInstalledPackages は hold が設定されたマネージャのインストール済みパッケージ名を取得します。
+// 利用不可、または一覧取得に対応しないマネージャは結果に含めません（検証をスキップします）。
+func collectInstalledPackages(ctx context.Context, managers map[string]config.ManagerConfig, lookup func(string) (updater.Updater, bool)) map[string][]string {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	installed := make(map[string][]string)
+
+	for name, managerCfg := range managers {
+		hold, err := managerCfg.Hold()
+		if err != nil || len(hold) == 0 {
+			continue
+		}
+
+		u, ok := lookup(name)
+		if !ok {
+			continue
+		}
+
+		lister, ok := u.(updater.InstalledLister)
+		if !ok {
+			continue
+		}
+
+		// 使用する CLI を設定で切り替えるマネージャがあるため、利用可否の判定前に設定を適用
+		if err := u.Configure(managerCfg); err != nil || !u.IsAvailable() {
+			continue
+		}
+
+		listCtx, cancel := context.WithTimeout(ctx, installedListTimeout)
+		packages, err := lister.ListInstalled(listCtx)
+
+		cancel()
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %s のインストール済みパッケージ取得に失敗したため hold の検証をスキップします: %v\n", name, err)
+			continue
+		}
+
+		names := make([]string, 0, len(packages))
+		for _, pkg := range packages {
+			names = append(names, pkg.Name)
+		}
+
+		installed[name] = names
+	}
+
+	return installed
+}
+
 func runConfigValidate(cmd *cobra.Command, args []string) error {
 	fmt.Println("🔍 設定の検証を開始します...")
 	fmt.Println()
@@ -209,13 +264,16 @@ func runConfigValidate(cmd *cobra.Command, args []string) error {
 		fmt.Println("⚪ 設定ファイルは未作成です（デフォルト値で検証します）")
 	}
 
+	loadUpdaterPlugins()
+
 	knownManagers := make(map[string]struct{})
 	for _, u := range updater.All() {
 		knownManagers[u.Name()] = struct{}{}
 	}
 
 	result := config.Validate(cfg, config.ValidateOptions{
-		KnownSysManagers: knownManagers,
+		KnownSysManagers:  knownManagers,
+		InstalledPackages: collectInstalledPackages(cmd.Context(), cfg.Sys.Managers, updater.Get),
 	})
 
 	if len(result.Warnings) > 0 {
@@ -347,6 +405,10 @@ func buildConfigFromInitAnswers(answers configInitAnswers) *config.Config {
 				Target:          []string{config.RepoCleanupTargetMerged, config.RepoCleanupTargetSquashed},
 				ExcludeBranches: []string{"main", "master", "develop"},
 			},
+			Discovery: config.RepoDiscoveryConfig{
+				MaxDepth:  1,
+				StopAtGit: true,
+			},
 		},
 		Sys: config.SysConfig{
 			Enable:   answers.EnabledManagers,
@@ -359,7 +421,7 @@ func buildConfigFromInitAnswers(answers configInitAnswers) *config.Config {
 	}
 
 	for _, mgr := range answers.EnabledManagers {
-		if mgr == "apt" || mgr == "snap" {
+		if isSudoManagedUpdater(mgr) {
 			cfg.Sys.Managers[mgr] = config.ManagerConfig{"use_sudo": true}
 		}
 	}
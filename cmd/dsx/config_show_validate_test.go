@@ -2,13 +2,16 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/scottlz0310/dsx/internal/config"
 	"github.com/scottlz0310/dsx/internal/testutil"
+	"github.com/scottlz0310/dsx/internal/updater"
 	"github.com/spf13/cobra"
 )
 
@@ -142,3 +145,48 @@ repo:
 		t.Fatalf("output does not mention repo.root: %q", out)
 	}
 }
+
+type installedListerStub struct {
+	stubUpdater
+	packages []updater.PackageInfo
+	calls    *int
+}
+
+func (s installedListerStub) ListInstalled(context.Context) ([]updater.PackageInfo, error) {
+	*s.calls++
+
+	return s.packages, nil
+}
+
+func TestCollectInstalledPackages(t *testing.T) {
+	calls := 0
+	updaters := map[string]updater.Updater{
+		"apt": installedListerStub{
+			stubUpdater: stubUpdater{name: "apt"},
+			packages:    []updater.PackageInfo{{Name: "curl"}, {Name: "docker-ce"}},
+			calls:       &calls,
+		},
+		"npm":  installedListerStub{stubUpdater: stubUpdater{name: "npm"}, calls: &calls},
+		"brew": stubUpdater{name: "brew"},
+	}
+
+	lookup := func(name string) (updater.Updater, bool) {
+		u, ok := updaters[name]
+		return u, ok
+	}
+
+	got := collectInstalledPackages(context.Background(), map[string]config.ManagerConfig{
+		"apt":     {"hold": []interface{}{"docker-ce"}},
+		"npm":     {"ignore": []interface{}{"corepack"}},
+		"brew":    {"hold": []interface{}{"python@3.11"}},
+		"unknown": {"hold": []interface{}{"x"}},
+	}, lookup)
+
+	if len(got) != 1 || strings.Join(got["apt"], ",") != "curl,docker-ce" {
+		t.Fatalf("collectInstalledPackages() = %#v, want only apt packages", got)
+	}
+
+	if calls != 1 {
+		t.Fatalf("ListInstalled calls = %d, want 1 (hold 未設定の npm は呼ばない)", calls)
+	}
+}
@@ -2,23 +2,104 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/scottlz0310/dsx/internal/runner"
 	progressui "github.com/scottlz0310/dsx/internal/tui"
 )
 
-func runJobsWithOptionalTUI(ctx context.Context, title string, jobs int, execJobs []runner.Job, useTUI bool, logFile string) runner.Summary {
-	var logger *runner.EventLogger
+// jobLogOptions は --log-file / --log-format / --log-dir で指定されたジョブ実行ログの出力設定です。
+type jobLogOptions struct {
+	Path    string
+	Format  string
+	Command string
+	// OutputDir はジョブごとの出力ログを保存する実行ディレクトリ（<log-dir>/<run-id>）です。
+	OutputDir string
+}
 
-	if logFile != "" {
-		var err error
+// jobLogCommandOverride は run から sys / repo を呼び出す間だけ設定し、
+// JSONL ログの command を "run" に揃えるために使います。
+var jobLogCommandOverride string
+
+func newJobLogOptions(path, format, command string) jobLogOptions {
+	if jobLogCommandOverride != "" {
+		command = jobLogCommandOverride
+	}
+
+	return jobLogOptions{Path: path, Format: format, Command: command}
+}
+
+// resolveJobOutputDir は --log-dir 配下に実行ごとのディレクトリパスを返します。
+// ディレクトリ名には実行 ID（history.NewRunID）を使用します。logDir が空の場合は空文字を返します。
+func resolveJobOutputDir(logDir, runID string) string {
+	if logDir == "" {
+		return ""
+	}
+
+	return filepath.Join(logDir, runID)
+}
+
+// jobOutputPath はジョブの出力ログのパスを返します。dir が空の場合は空文字を返します。
+// ジョブ名のパス区切りなどはファイル名に使える文字に置き換えます。
+func jobOutputPath(dir, jobName string) string {
+	if dir == "" {
+		return ""
+	}
+
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, jobName)
+
+	name = strings.Trim(name, ".")
+	if name == "" {
+		name = "job"
+	}
+
+	return filepath.Join(dir, name+".log")
+}
+
+// withJobOutputPaths は OutputPath が未指定のジョブに dir 配下の出力ログのパスを設定したコピーを返します。
+func withJobOutputPaths(execJobs []runner.Job, dir string) []runner.Job {
+	if dir == "" {
+		return execJobs
+	}
+
+	jobsWithOutput := make([]runner.Job, len(execJobs))
+
+	for i, job := range execJobs {
+		if job.OutputPath == "" {
+			job.OutputPath = jobOutputPath(dir, job.Name)
+		}
+
+		jobsWithOutput[i] = job
+	}
 
-		logger, err = runner.NewEventLogger(logFile)
+	return jobsWithOutput
+}
+
+func runJobsWithOptionalTUI(ctx context.Context, title string, jobs int, execJobs []runner.Job, useTUI bool, logOpts jobLogOptions) runner.Summary {
+	var logger runner.JobLogger
+
+	execJobs = withJobOutputPaths(execJobs, logOpts.OutputDir)
+
+	if logOpts.Path != "" {
+		jobLogger, err := runner.NewJobLogger(logOpts.Path, logOpts.Format, logOpts.Command)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "⚠️  ログファイルを開けません: %v\n", err)
 		} else {
+			logger = jobLogger
+
 			defer func() {
 				if cerr := logger.Close(); cerr != nil {
 					fmt.Fprintf(os.Stderr, "⚠️  ログファイルのクローズに失敗: %v\n", cerr)
@@ -37,13 +118,14 @@ func runJobsWithOptionalTUI(ctx context.Context, title string, jobs int, execJob
 			fmt.Fprintf(os.Stderr, "⚠️  TUI表示中にエラーが発生しました（実行結果は継続）: %v\n", err)
 		}
 	} else {
-		if logger != nil {
-			summary = runner.ExecuteWithEvents(ctx, jobs, execJobs, func(event runner.Event) {
+		summary = runner.ExecuteWithEvents(ctx, jobs, execJobs, func(event runner.Event) {
+			printRetryEvent(&event)
+			printDependencySkipEvent(&event)
+
+			if logger != nil {
 				logger.LogEvent(&event)
-			})
-		} else {
-			summary = runner.Execute(ctx, jobs, execJobs)
-		}
+			}
+		})
 	}
 
 	if logger != nil {
@@ -53,6 +135,25 @@ func runJobsWithOptionalTUI(ctx context.Context, title string, jobs int, execJob
 	return summary
 }
 
+// printRetryEvent は EventRetrying を受け取ったときに再試行の予告を stderr に表示します。
+func printRetryEvent(event *runner.Event) {
+	if event.Type != runner.EventRetrying {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "🔁 %s: 失敗したため再試行します（%d/%d 回目、%s 後）: %v\n",
+		event.JobName, event.Attempt, event.MaxAttempts, event.Delay.Round(time.Millisecond), event.Err)
+}
+
+// printDependencySkipEvent は依存先の失敗でジョブがスキップされたときに理由を表示します。
+func printDependencySkipEvent(event *runner.Event) {
+	if event.Type != runner.EventFinished || !errors.Is(event.Err, runner.ErrDependencyFailed) {
+		return
+	}
+
+	fmt.Printf("⏭️  %s: %v\n\n", event.JobName, event.Err)
+}
+
 // printFailedJobDetails は runner.Summary から失敗ジョブのエラー詳細を表示します。
 func printFailedJobDetails(summary runner.Summary) {
 	var failures []runner.Result
@@ -77,7 +178,25 @@ func printFailedJobDetails(summary runner.Summary) {
 		}
 
 		fmt.Fprintf(os.Stderr, "%s %s: %v\n", prefix, f.Name, f.Err)
+
+		if f.LogPath != "" {
+			indent := "  │  "
+			if i == len(failures)-1 {
+				indent = "     "
+			}
+
+			fmt.Fprintf(os.Stderr, "%s 出力ログ: %s\n", indent, f.LogPath)
+		}
+	}
+}
+
+// jobErrorWithLogPath はマネージャ名と出力ログのパスを付けたエラーを返します（sys update 用）。
+func jobErrorWithLogPath(name string, err error, logPath string) error {
+	if logPath == "" {
+		return fmt.Errorf("%s: %w", name, err)
 	}
+
+	return fmt.Errorf("%s: %w（出力ログ: %s）", name, err, logPath)
 }
 
 // printFailedErrors はエラー一覧から失敗詳細を表示します（sys update 用）。
@@ -2,6 +2,7 @@ package main
 
 import (
 	"errors"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -43,6 +44,18 @@ func TestPrintFailedJobDetails(t *testing.T) {
 				"└── npm: network timeout",
 			},
 		},
+		{
+			name: "出力ログのパスを表示",
+			summary: runner.Summary{
+				Results: []runner.Result{
+					{Name: "brew", Status: runner.StatusFailed, Err: errors.New("exit status 1"), LogPath: "/tmp/logs/20260102T030405Z/brew.log"},
+				},
+			},
+			wantMsgs: []string{
+				"└── brew: exit status 1",
+				"出力ログ: /tmp/logs/20260102T030405Z/brew.log",
+			},
+		},
 		{
 			name: "失敗ジョブが0件の場合は何も出力しない",
 			summary: runner.Summary{
@@ -151,3 +164,57 @@ func TestPrintFailedErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestJobOutputPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		dir     string
+		jobName string
+		want    string
+	}{
+		{name: "ディレクトリ未指定は空", dir: "", jobName: "apt", want: ""},
+		{name: "マネージャ名はそのまま", dir: "/logs/run", jobName: "brew", want: filepath.Join("/logs/run", "brew.log")},
+		{name: "パス区切りを置き換える", dir: "/logs/run", jobName: "github.com/owner/repo", want: filepath.Join("/logs/run", "github.com_owner_repo.log")},
+		{name: "日本語はそのまま", dir: "/logs/run", jobName: "メモ", want: filepath.Join("/logs/run", "メモ.log")},
+		{name: "ドットのみは job", dir: "/logs/run", jobName: "..", want: filepath.Join("/logs/run", "job.log")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jobOutputPath(tt.dir, tt.jobName); got != tt.want {
+				t.Errorf("jobOutputPath(%q, %q) = %q, want %q", tt.dir, tt.jobName, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveJobOutputDir(t *testing.T) {
+	if got := resolveJobOutputDir("", "20260102T030405Z-3f9a1c"); got != "" {
+		t.Fatalf("resolveJobOutputDir(\"\") = %q, want empty", got)
+	}
+
+	if got, want := resolveJobOutputDir("/logs", "20260102T030405Z-3f9a1c"), filepath.Join("/logs", "20260102T030405Z-3f9a1c"); got != want {
+		t.Fatalf("resolveJobOutputDir() = %q, want %q", got, want)
+	}
+}
+
+func TestWithJobOutputPaths(t *testing.T) {
+	execJobs := []runner.Job{
+		{Name: "apt"},
+		{Name: "npm", OutputPath: "/custom/npm.log"},
+	}
+
+	got := withJobOutputPaths(execJobs, "/logs/run")
+
+	if got[0].OutputPath != filepath.Join("/logs/run", "apt.log") {
+		t.Errorf("apt OutputPath = %q, want run dir", got[0].OutputPath)
+	}
+
+	if got[1].OutputPath != "/custom/npm.log" {
+		t.Errorf("npm OutputPath = %q, want unchanged", got[1].OutputPath)
+	}
+
+	if execJobs[0].OutputPath != "" {
+		t.Errorf("元のジョブが変更されています: %q", execJobs[0].OutputPath)
+	}
+}
@@ -17,6 +17,8 @@ import (
 	"time"
 
 	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/scottlz0310/dsx/internal/ghretry"
+	"github.com/scottlz0310/dsx/internal/history"
 	repomgr "github.com/scottlz0310/dsx/internal/repo"
 	"github.com/scottlz0310/dsx/internal/runner"
 	"github.com/spf13/cobra"
@@ -31,6 +33,8 @@ var (
 	repoUpdateTUI         bool
 	repoUpdateNoTUI       bool
 	repoUpdateLogFile     string
+	repoUpdateLogFormat   string
+	repoUpdateLogDir      string
 )
 
 var (
@@ -98,15 +102,13 @@ func init() {
 	repoUpdateCmd.Flags().BoolVar(&repoUpdateTUI, "tui", false, "Bubble Tea の進捗UIを表示（既定値は config.yaml の ui.tui）")
 	repoUpdateCmd.Flags().BoolVar(&repoUpdateNoTUI, "no-tui", false, "TUI 進捗表示を無効化（設定より優先）")
 	repoUpdateCmd.Flags().StringVar(&repoUpdateLogFile, "log-file", "", "ジョブ実行ログをファイルに保存")
+	repoUpdateCmd.Flags().StringVar(&repoUpdateLogFormat, "log-format", runner.LogFormatText, "ジョブ実行ログの形式（text / jsonl）")
+	repoUpdateCmd.Flags().StringVar(&repoUpdateLogDir, "log-dir", "", "リポジトリごとの git 出力を <dir>/<実行ID>/<repo>.log に保存")
 }
 
 func runRepoList(cmd *cobra.Command, args []string) error {
 	cfg, configExists, configPath := loadRepoConfig()
-
-	root := cfg.Repo.Root
-	if cmd.Flags().Changed("root") {
-		root = repoRootOverride
-	}
+	targets := resolveRepoRootTargets(cmd, cfg)
 
 	timeout := 10 * time.Minute
 	if parsed, parseErr := time.ParseDuration(cfg.Control.Timeout); parseErr == nil {
@@ -121,33 +123,37 @@ func runRepoList(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithTimeout(baseCtx, timeout)
 	defer cancel()
 
-	repos, err := repomgr.List(ctx, root)
-	if err != nil {
-		return wrapRepoRootError(err, root, cmd.Flags().Changed("root"), configExists, configPath)
-	}
+	return forEachRepoRoot(targets, func(target repoRootTarget) error {
+		root := target.Root.Path
 
-	if len(repos) == 0 {
-		fmt.Printf("📝 リポジトリが見つかりませんでした: %s\n", root)
-		return nil
-	}
+		repos, err := repomgr.List(ctx, root, repoDiscoverOptions(target.Cfg))
+		if err != nil {
+			return wrapRepoRootError(err, root, cmd.Flags().Changed("root"), configExists, configPath)
+		}
 
-	fmt.Printf("📦 管理下リポジトリ一覧 (%d件)\n\n", len(repos))
+		if len(repos) == 0 {
+			fmt.Printf("📝 リポジトリが見つかりませんでした: %s\n", root)
+			return nil
+		}
 
-	if err := printRepoTable(repos); err != nil {
-		return fmt.Errorf("一覧表示に失敗: %w", err)
-	}
+		fmt.Printf("📦 管理下リポジトリ一覧 (%d件)\n\n", len(repos))
 
-	return nil
+		if err := printRepoTable(repos); err != nil {
+			return fmt.Errorf("一覧表示に失敗: %w", err)
+		}
+
+		return nil
+	})
 }
 
 func runRepoUpdate(cmd *cobra.Command, args []string) error {
-	cfg, configExists, configPath := loadRepoConfig()
-
-	root := cfg.Repo.Root
-	if cmd.Flags().Changed("root") {
-		root = repoRootOverride
+	if _, err := runner.ParseLogFormat(repoUpdateLogFormat); err != nil {
+		return err
 	}
 
+	cfg, configExists, configPath := loadRepoConfig()
+	targets := resolveRepoRootTargets(cmd, cfg)
+
 	timeout := 10 * time.Minute
 	if parsed, parseErr := time.ParseDuration(cfg.Control.Timeout); parseErr == nil {
 		timeout = parsed
@@ -161,16 +167,6 @@ func runRepoUpdate(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithTimeout(baseCtx, timeout)
 	defer cancel()
 
-	repoPaths, err := repomgr.Discover(root)
-	if err != nil {
-		return wrapRepoRootError(err, root, cmd.Flags().Changed("root"), configExists, configPath)
-	}
-
-	opts, err := buildRepoUpdateOptions(cmd, cfg)
-	if err != nil {
-		return err
-	}
-
 	tuiReq, err := resolveTUIRequest(cfg.UI.TUI, cmd.Flags().Changed("tui"), repoUpdateTUI, cmd.Flags().Changed("no-tui"), repoUpdateNoTUI)
 	if err != nil {
 		return err
@@ -179,32 +175,83 @@ func runRepoUpdate(cmd *cobra.Command, args []string) error {
 	useTUI, warning := resolveTUIEnabled(tuiReq)
 	printTUIWarning(warning)
 
-	bootstrap, bootstrapErr := bootstrapReposFromGitHub(ctx, root, cfg, opts.DryRun)
-	if bootstrapErr != nil {
-		return fmt.Errorf("GitHub リポジトリの取得に失敗しました: %w", bootstrapErr)
-	}
+	var (
+		execJobs       []runner.Job
+		pullSkippedFns []func() []string
+		dryRun         bool
+	)
+
+	// ルートごとに探索・GitHub 補完を行い、ジョブは全ルート分をまとめて実行する。
+	rootErr := forEachRepoRoot(targets, func(target repoRootTarget) error {
+		root := target.Root.Path
+
+		repoPaths, discoverErr := repomgr.DiscoverWithOptions(ctx, root, repoDiscoverOptions(target.Cfg))
+		if discoverErr != nil {
+			return wrapRepoRootError(discoverErr, root, cmd.Flags().Changed("root"), configExists, configPath)
+		}
+
+		opts, optsErr := buildRepoUpdateOptions(cmd, target.Cfg)
+		if optsErr != nil {
+			return optsErr
+		}
+
+		dryRun = dryRun || opts.DryRun
+
+		bootstrap, bootstrapErr := bootstrapReposFromGitHub(ctx, root, target.Cfg, opts.DryRun)
+		if bootstrapErr != nil {
+			return fmt.Errorf("GitHub リポジトリの取得に失敗しました: %w", bootstrapErr)
+		}
+
+		repoPaths = mergeRepoPaths(repoPaths, bootstrap.ReadyPaths)
+		if len(repoPaths) == 0 {
+			printNoTargetResult(root, bootstrap)
+			return nil
+		}
+
+		rootJobs, getPullSkipped := buildRepoUpdateJobs(root, repoJobLabel(targets, target), repoPaths, opts, resolveRepoSyncTimeout(target.Cfg), useTUI)
+		execJobs = append(execJobs, rootJobs...)
+		pullSkippedFns = append(pullSkippedFns, getPullSkipped)
 
-	repoPaths = mergeRepoPaths(repoPaths, bootstrap.ReadyPaths)
-	if len(repoPaths) == 0 {
-		printNoTargetResult(root, bootstrap, tuiReq)
 		return nil
+	})
+
+	if len(execJobs) == 0 {
+		if rootErr == nil {
+			printNoTargetTUIMessage(tuiReq, "repo update")
+		}
+
+		return rootErr
+	}
+
+	getPullSkipped := func() []string {
+		var names []string
+		for _, fn := range pullSkippedFns {
+			names = append(names, fn()...)
+		}
+
+		return names
 	}
 
 	jobs := resolveRepoJobs(cfg.Control.Concurrency, repoUpdateJobs)
 
 	// TUI 使用時は開始メッセージを抑制（TUI が画面を制御するため）
 	if !useTUI {
-		fmt.Printf("🔄 リポジトリ更新を開始します (%d件, 並列=%d)\n", len(repoPaths), jobs)
+		if len(targets) > 1 {
+			fmt.Println()
+		}
 
-		if opts.DryRun {
+		fmt.Printf("🔄 リポジトリ更新を開始します (%d件, 並列=%d)\n", len(execJobs), jobs)
+
+		if dryRun {
 			fmt.Println("📋 DryRun モード: 実際の更新は行いません")
 		}
 
 		fmt.Println()
 	}
 
-	execJobs, getPullSkipped := buildRepoUpdateJobs(root, repoPaths, opts, useTUI)
-	summary := runJobsWithOptionalTUI(ctx, "repo update 進捗", jobs, execJobs, useTUI, repoUpdateLogFile)
+	logOpts := newJobLogOptions(repoUpdateLogFile, repoUpdateLogFormat, "repo")
+	logOpts.OutputDir = resolveJobOutputDir(repoUpdateLogDir, history.NewRunID(time.Now()))
+	summary := runJobsWithOptionalTUI(ctx, "repo update 進捗", jobs, execJobs, useTUI, logOpts)
 
 	// TUI 使用時は TUI 側で完了サマリーを表示済みのため、テキストサマリーは非 TUI 時のみ出力
 	// pull スキップ一覧は TUI モードでも表示する
@@ -218,11 +265,15 @@ func runRepoUpdate(cmd *cobra.Command, args []string) error {
 	printFailedJobDetails(summary)
 
 	if summary.Failed > 0 {
-		return fmt.Errorf("%d 件のリポジトリ更新に失敗しました", summary.Failed)
+		return errors.Join(rootErr, fmt.Errorf("%d 件のリポジトリ更新に失敗しました", summary.Failed))
 	}
 
 	if summary.Skipped > 0 {
-		return fmt.Errorf("キャンセルまたはタイムアウトにより %d 件をスキップしました", summary.Skipped)
+		return errors.Join(rootErr, fmt.Errorf("キャンセルまたはタイムアウトにより %d 件をスキップしました", summary.Skipped))
+	}
+
+	if rootErr != nil {
+		return rootErr
 	}
 
 	if !useTUI {
@@ -295,7 +346,29 @@ func buildRepoUpdateOptions(cmd *cobra.Command, cfg *config.Config) (repomgr.Upd
 	return opts, nil
 }
 
-func buildRepoUpdateJobs(root string, repoPaths []string, opts repomgr.UpdateOptions, useTUI bool) (jobs []runner.Job, getPullSkipped func() []string) {
+// repoDiscoverOptions は repo.discovery の設定からリポジトリ探索のオプションを組み立てます。
+func repoDiscoverOptions(cfg *config.Config) repomgr.DiscoverOptions {
+	return repomgr.DiscoverOptions{
+		MaxDepth:         cfg.Repo.Discovery.MaxDepth,
+		Include:          cfg.Repo.Discovery.Include,
+		Exclude:          cfg.Repo.Discovery.Exclude,
+		DescendIntoRepos: !cfg.Repo.Discovery.StopAtGit,
+	}
+}
+
+// resolveRepoSyncTimeout は repo.sync.timeout（リポジトリ1件あたりの制限時間）を返します。
+// 未指定または不正な値の場合は 0（制限なし）を返し、不正な値は警告します。
+func resolveRepoSyncTimeout(cfg *config.Config) time.Duration {
+	timeout, err := config.ParseJobTimeout(cfg.Repo.Sync.Timeout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  repo.sync.timeout を無視します: %v\n", err)
+		return 0
+	}
+
+	return timeout
+}
+
+func buildRepoUpdateJobs(root, label string, repoPaths []string, opts repomgr.UpdateOptions, jobTimeout time.Duration, useTUI bool) (jobs []runner.Job, getPullSkipped func() []string) {
 	var outputMu sync.Mutex
 
 	var (
@@ -319,8 +392,11 @@ func buildRepoUpdateJobs(root string, repoPaths []string, opts repomgr.UpdateOpt
 			repoName = filepath.Clean(repoPath)
 		}
 
+		repoName = prefixRepoJobName(label, repoName)
+
 		execJobs = append(execJobs, runner.Job{
-			Name: repoName,
+			Name:    repoName,
+			Timeout: jobTimeout,
 			Run: func(jobCtx context.Context) error {
 				updateResult, updateErr := repomgr.Update(jobCtx, repoPath, opts)
 
@@ -530,9 +606,7 @@ func resolveRepoSubmoduleUpdate(configValue, enableOverride, disableOverride boo
 	return configValue, nil
 }
 
-func printNoTargetResult(root string, bootstrap bootstrapResult, tuiReq tuiRequest) {
-	printNoTargetTUIMessage(tuiReq, "repo update")
-
+func printNoTargetResult(root string, bootstrap bootstrapResult) {
 	if bootstrap.PlannedOnly > 0 {
 		fmt.Printf("📝 DryRun のため clone 計画のみ表示しました（%d件）\n", bootstrap.PlannedOnly)
 		return
@@ -551,7 +625,7 @@ func bootstrapReposFromGitHub(ctx context.Context, root string, cfg *config.Conf
 
 	repos, err := repoListGitHubReposStep(ctx, owner)
 	if err != nil {
-		if isGitHubRateLimitError(err) {
+		if ghretry.IsRateLimitError(err) {
 			fmt.Fprintf(os.Stderr, "⚠️  GitHub のレート制限によりリポジトリ一覧の取得をスキップします: %v\n", err)
 			fmt.Fprintln(os.Stderr, "📝 GitHub からの補完は行わず、ローカルに存在するリポジトリのみ更新を継続します。")
 			fmt.Println()
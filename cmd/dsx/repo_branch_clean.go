@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -57,10 +58,7 @@ func init() {
 func runRepoBranchClean(cmd *cobra.Command, _ []string) error {
 	cfg, configExists, configPath := loadRepoConfig()
 
-	root := cfg.Repo.Root
-	if cmd.Flags().Changed("root") {
-		root = repoRootOverride
-	}
+	targets := resolveRepoRootTargets(cmd, cfg)
 
 	timeout := 10 * time.Minute
 	if parsed, parseErr := time.ParseDuration(cfg.Control.Timeout); parseErr == nil {
@@ -75,17 +73,6 @@ func runRepoBranchClean(cmd *cobra.Command, _ []string) error {
 	ctx, cancel := context.WithTimeout(baseCtx, timeout)
 	defer cancel()
 
-	repoPaths, err := repomgr.Discover(root)
-	if err != nil {
-		return wrapRepoRootError(err, root, cmd.Flags().Changed("root"), configExists, configPath)
-	}
-
-	if len(repoPaths) == 0 {
-		fmt.Printf("📝 対象のリポジトリが見つかりませんでした: %s\n", root)
-
-		return nil
-	}
-
 	scanOpts := repomgr.BranchScanOptions{
 		Fetch:           !repoBranchCleanNoFetch,
 		ExcludeBranches: repoBranchCleanExclude,
@@ -98,37 +85,61 @@ func runRepoBranchClean(cmd *cobra.Command, _ []string) error {
 		modeLabel = "自動実行モード"
 	}
 
-	fmt.Printf("🔍 ブランチスキャン開始 (%s, リポジトリ数: %d)\n\n", modeLabel, len(repoPaths))
-
 	totalDeleted := 0
 	totalPruned := 0
 	totalSkipped := 0
 	totalWarnings := 0
 	totalErrors := 0
+	scanned := 0
+
+	rootErr := forEachRepoRoot(targets, func(target repoRootTarget) error {
+		root := target.Root.Path
+
+		repoPaths, err := repomgr.DiscoverWithOptions(ctx, root, repoDiscoverOptions(target.Cfg))
+		if err != nil {
+			return wrapRepoRootError(err, root, cmd.Flags().Changed("root"), configExists, configPath)
+		}
+
+		if len(repoPaths) == 0 {
+			fmt.Printf("📝 対象のリポジトリが見つかりませんでした: %s\n", root)
+
+			return nil
+		}
+
+		scanned += len(repoPaths)
 
-	for _, repoPath := range repoPaths {
-		displayName := buildRepoJobDisplayName(root, repoPath)
-		deleted, pruned, skipped, warnings, errors := processRepoBranchClean(ctx, repoPath, displayName, scanOpts)
+		fmt.Printf("🔍 ブランチスキャン開始 (%s, リポジトリ数: %d)\n\n", modeLabel, len(repoPaths))
+
+		for _, repoPath := range repoPaths {
+			displayName := prefixRepoJobName(repoJobLabel(targets, target), buildRepoJobDisplayName(root, repoPath))
+			deleted, pruned, skipped, warnings, errCount := processRepoBranchClean(ctx, repoPath, displayName, scanOpts)
+
+			totalDeleted += deleted
+			totalPruned += pruned
+			totalSkipped += skipped
+			totalWarnings += warnings
+			totalErrors += errCount
+		}
+
+		return nil
+	})
 
-		totalDeleted += deleted
-		totalPruned += pruned
-		totalSkipped += skipped
-		totalWarnings += warnings
-		totalErrors += errors
+	if scanned == 0 {
+		return rootErr
 	}
 
 	printSummary(totalDeleted, totalPruned, totalSkipped, totalWarnings, totalErrors, repoBranchCleanDryRun)
 
 	if !repoBranchCleanDryRun && totalErrors > 0 {
-		return fmt.Errorf("ブランチクリーンアップで %d 件のエラーが発生しました", totalErrors)
+		return errors.Join(rootErr, fmt.Errorf("ブランチクリーンアップで %d 件のエラーが発生しました", totalErrors))
 	}
 
-	return nil
+	return rootErr
 }
 
 // processRepoBranchClean は単一リポジトリのブランチクリーンアップを実行し、削除・プルーン・スキップ・警告・エラーの件数を返します。
 // skipped は -d 失敗等で安全のため保持したブランチ件数（情報レベル）、warnings は --yes モードの対象外スキップ件数（注意レベル）。
-func processRepoBranchClean(ctx context.Context, repoPath, displayName string, scanOpts repomgr.BranchScanOptions) (deleted, pruned, skipped, warnings, errors int) {
+func processRepoBranchClean(ctx context.Context, repoPath, displayName string, scanOpts repomgr.BranchScanOptions) (deleted, pruned, skipped, warnings, errCount int) {
 	result, scanErr := repomgr.ScanBranches(ctx, repoPath, scanOpts)
 	if scanErr != nil {
 		fmt.Fprintf(os.Stderr, "  ⚠️  %s: スキャン失敗 (%v)\n", displayName, scanErr)
@@ -386,7 +397,7 @@ func printCleanResult(displayName string, result *repomgr.BranchCleanResult) {
 }
 
 // printSummary は全リポジトリ処理後のサマリーを表示します。
-func printSummary(deleted, pruned, skipped, warnings, errors int, dryRun bool) {
+func printSummary(deleted, pruned, skipped, warnings, errCount int, dryRun bool) {
 	fmt.Println("─────────────────────────────────────────")
 
 	if dryRun {
@@ -405,8 +416,8 @@ func printSummary(deleted, pruned, skipped, warnings, errors int, dryRun bool) {
 		fmt.Printf(", 警告 %d件", warnings)
 	}
 
-	if errors > 0 {
-		fmt.Printf(", エラー %d件", errors)
+	if errCount > 0 {
+		fmt.Printf(", エラー %d件", errCount)
 	}
 
 	fmt.Println()
@@ -3,25 +3,30 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/scottlz0310/dsx/internal/history"
 	repomgr "github.com/scottlz0310/dsx/internal/repo"
 	"github.com/scottlz0310/dsx/internal/runner"
 	"github.com/spf13/cobra"
 )
 
 var (
-	repoCleanupJobs    int
-	repoCleanupDryRun  bool
-	repoCleanupTUI     bool
-	repoCleanupNoTUI   bool
-	repoCleanupLogFile string
+	repoCleanupJobs      int
+	repoCleanupDryRun    bool
+	repoCleanupTUI       bool
+	repoCleanupNoTUI     bool
+	repoCleanupLogFile   string
+	repoCleanupLogFormat string
+	repoCleanupLogDir    string
 )
 
 var repoCleanupStep = repomgr.Cleanup
@@ -47,21 +52,24 @@ func init() {
 	repoCleanupCmd.Flags().BoolVar(&repoCleanupTUI, "tui", false, "Bubble Tea の進捗UIを表示（既定値は config.yaml の ui.tui）")
 	repoCleanupCmd.Flags().BoolVar(&repoCleanupNoTUI, "no-tui", false, "TUI 進捗表示を無効化（設定より優先）")
 	repoCleanupCmd.Flags().StringVar(&repoCleanupLogFile, "log-file", "", "ジョブ実行ログをファイルに保存")
+	repoCleanupCmd.Flags().StringVar(&repoCleanupLogFormat, "log-format", runner.LogFormatText, "ジョブ実行ログの形式（text / jsonl）")
+	repoCleanupCmd.Flags().StringVar(&repoCleanupLogDir, "log-dir", "", "リポジトリごとの git 出力を <dir>/<実行ID>/<repo>.log に保存")
 }
 
 func runRepoCleanup(cmd *cobra.Command, args []string) error {
+	if _, err := runner.ParseLogFormat(repoCleanupLogFormat); err != nil {
+		return err
+	}
+
 	cfg, configExists, configPath := loadRepoConfig()
 
-	if !cfg.Repo.Cleanup.Enabled {
+	targets := resolveRepoRootTargets(cmd, cfg)
+
+	if !slices.ContainsFunc(targets, func(target repoRootTarget) bool { return target.Cfg.Repo.Cleanup.Enabled }) {
 		fmt.Println("📝 repo.cleanup.enabled=false のため repo cleanup は無効です")
 		return nil
 	}
 
-	root := cfg.Repo.Root
-	if cmd.Flags().Changed("root") {
-		root = repoRootOverride
-	}
-
 	timeout := 10 * time.Minute
 	if parsed, parseErr := time.ParseDuration(cfg.Control.Timeout); parseErr == nil {
 		timeout = parsed
@@ -75,18 +83,6 @@ func runRepoCleanup(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithTimeout(baseCtx, timeout)
 	defer cancel()
 
-	repoPaths, err := repomgr.Discover(root)
-	if err != nil {
-		return wrapRepoRootError(err, root, cmd.Flags().Changed("root"), configExists, configPath)
-	}
-
-	if len(repoPaths) == 0 {
-		fmt.Printf("📝 cleanup 対象のリポジトリが見つかりませんでした: %s\n", root)
-		return nil
-	}
-
-	opts := buildRepoCleanupOptions(cmd, cfg)
-
 	tuiReq, err := resolveTUIRequest(cfg.UI.TUI, cmd.Flags().Changed("tui"), repoCleanupTUI, cmd.Flags().Changed("no-tui"), repoCleanupNoTUI)
 	if err != nil {
 		return err
@@ -95,31 +91,77 @@ func runRepoCleanup(cmd *cobra.Command, args []string) error {
 	useTUI, warning := resolveTUIEnabled(tuiReq)
 	printTUIWarning(warning)
 
+	var (
+		execJobs []runner.Job
+		dryRun   bool
+	)
+
+	// ルートごとに探索し、ジョブは全ルート分をまとめて実行する。
+	rootErr := forEachRepoRoot(targets, func(target repoRootTarget) error {
+		root := target.Root.Path
+
+		if !target.Cfg.Repo.Cleanup.Enabled {
+			fmt.Printf("📝 cleanup.enabled=false のためスキップします: %s\n", root)
+			return nil
+		}
+
+		repoPaths, discoverErr := repomgr.DiscoverWithOptions(ctx, root, repoDiscoverOptions(target.Cfg))
+		if discoverErr != nil {
+			return wrapRepoRootError(discoverErr, root, cmd.Flags().Changed("root"), configExists, configPath)
+		}
+
+		if len(repoPaths) == 0 {
+			fmt.Printf("📝 cleanup 対象のリポジトリが見つかりませんでした: %s\n", root)
+			return nil
+		}
+
+		opts := buildRepoCleanupOptions(cmd, target.Cfg)
+		dryRun = dryRun || opts.DryRun
+
+		execJobs = append(execJobs, buildRepoCleanupJobs(root, repoJobLabel(targets, target), repoPaths, opts, useTUI)...)
+
+		return nil
+	})
+
+	if len(execJobs) == 0 {
+		return rootErr
+	}
+
 	jobs := resolveRepoJobs(cfg.Control.Concurrency, repoCleanupJobs)
 
+	if len(targets) > 1 {
+		fmt.Println()
+	}
+
 	if useTUI {
 		fmt.Println("🖥️  TUI 進捗表示を有効化しました")
 	}
 
-	fmt.Printf("🧹 repo cleanup を開始します (%d件, 並列=%d)\n", len(repoPaths), jobs)
+	fmt.Printf("🧹 repo cleanup を開始します (%d件, 並列=%d)\n", len(execJobs), jobs)
 
-	if opts.DryRun {
+	if dryRun {
 		fmt.Println("📋 DryRun モード: 実際の削除は行いません")
 	}
 
 	fmt.Println()
 
-	execJobs := buildRepoCleanupJobs(root, repoPaths, opts, useTUI)
-	summary := runJobsWithOptionalTUI(ctx, "repo cleanup 進捗", jobs, execJobs, useTUI, repoCleanupLogFile)
+	logOpts := newJobLogOptions(repoCleanupLogFile, repoCleanupLogFormat, "repo")
+	logOpts.OutputDir = resolveJobOutputDir(repoCleanupLogDir, history.NewRunID(time.Now()))
+	summary := runJobsWithOptionalTUI(ctx, "repo cleanup 進捗", jobs, execJobs, useTUI, logOpts)
 
 	printRepoCleanupSummary(summary)
+	printFailedJobDetails(summary)
 
 	if summary.Failed > 0 {
-		return fmt.Errorf("%d 件の repo cleanup に失敗しました", summary.Failed)
+		return errors.Join(rootErr, fmt.Errorf("%d 件の repo cleanup に失敗しました", summary.Failed))
 	}
 
 	if summary.Skipped > 0 {
-		return fmt.Errorf("キャンセルまたはタイムアウトにより %d 件をスキップしました", summary.Skipped)
+		return errors.Join(rootErr, fmt.Errorf("キャンセルまたはタイムアウトにより %d 件をスキップしました", summary.Skipped))
+	}
+
+	if rootErr != nil {
+		return rootErr
 	}
 
 	fmt.Println("✅ repo cleanup が完了しました")
@@ -142,7 +184,7 @@ func buildRepoCleanupOptions(cmd *cobra.Command, cfg *config.Config) repomgr.Cle
 	return opts
 }
 
-func buildRepoCleanupJobs(root string, repoPaths []string, opts repomgr.CleanupOptions, useTUI bool) []runner.Job {
+func buildRepoCleanupJobs(root, label string, repoPaths []string, opts repomgr.CleanupOptions, useTUI bool) []runner.Job {
 	var outputMu sync.Mutex
 
 	nameCounts := make(map[string]int, len(repoPaths))
@@ -160,6 +202,8 @@ func buildRepoCleanupJobs(root string, repoPaths []string, opts repomgr.CleanupO
 			repoName = filepath.Clean(repoPath)
 		}
 
+		repoName = prefixRepoJobName(label, repoName)
+
 		execJobs = append(execJobs, runner.Job{
 			Name: repoName,
 			Run: func(jobCtx context.Context) error {
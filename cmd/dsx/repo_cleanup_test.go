@@ -368,7 +368,7 @@ func TestBuildRepoCleanupJobs_NameCollisionAndPrint(t *testing.T) {
 	repoA := filepath.Join(t.TempDir(), "repo")
 	repoB := filepath.Join(t.TempDir(), "repo")
 
-	execJobs := buildRepoCleanupJobs(root, []string{repoA, repoB}, repomgr.CleanupOptions{
+	execJobs := buildRepoCleanupJobs(root, "", []string{repoA, repoB}, repomgr.CleanupOptions{
 		Targets: []string{"merged"},
 	}, false)
 
@@ -0,0 +1,512 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	repomgr "github.com/scottlz0310/dsx/internal/repo"
+	"github.com/scottlz0310/dsx/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+// repoExecExitCodeUnknown はコマンドを起動できなかった・中断された場合の終了コードです。
+const repoExecExitCodeUnknown = -1
+
+var (
+	repoExecJobs      int
+	repoExecStatuses  []string
+	repoExecNames     []string
+	repoExecLanguages []string
+	repoExecTUI       bool
+	repoExecNoTUI     bool
+	repoExecOutput    string
+	repoExecLogFile   string
+	repoExecLogFormat string
+)
+
+var repoExecCmd = &cobra.Command{
+	Use:   "exec [flags] -- <command> [args...]",
+	Short: "管理下リポジトリで任意のコマンドを実行します",
+	Long: `設定された root 配下の各リポジトリをカレントディレクトリとして、-- 以降のコマンドを並列に実行します。
+
+各リポジトリの出力（標準出力・標準エラー）はまとめて取得し、行ごとに [リポジトリ名] を付けて表示します。
+シェルの機能（パイプやリダイレクト）を使う場合は sh -c '...' のように指定してください。
+
+絞り込み:
+  --status  repo list の状態（clean / dirty / unpushed / no_upstream）
+  --name    root からの相対パスの glob（** は任意の階層に一致）
+  --lang    マーカーファイルから判定した言語（go / node / python / rust など）
+`,
+	Example: `  dsx repo exec -- git log --oneline --since=1.week
+  dsx repo exec --lang go -j 4 -- go mod tidy
+  dsx repo exec --status dirty -- git status --short
+  dsx repo exec --name 'github.com/acme/*' -o json -- npm audit --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runRepoExec,
+}
+
+func init() {
+	repoCmd.AddCommand(repoExecCmd)
+
+	repoExecCmd.Flags().StringVar(&repoRootOverride, "root", "", "対象のルートディレクトリ（指定時は設定を上書き）")
+	repoExecCmd.Flags().IntVarP(&repoExecJobs, "jobs", "j", 0, "並列実行数（0以下の場合は設定値または1を使用）")
+	repoExecCmd.Flags().StringSliceVar(&repoExecStatuses, "status", nil, "状態で絞り込み（clean / dirty / unpushed / no_upstream、複数指定可）")
+	repoExecCmd.Flags().StringSliceVar(&repoExecNames, "name", nil, "名前（root からの相対パス）の glob で絞り込み（複数指定可）")
+	repoExecCmd.Flags().StringSliceVar(&repoExecLanguages, "lang", nil, "言語で絞り込み（"+strings.Join(repomgr.KnownLanguages(), " / ")+"、複数指定可）")
+	repoExecCmd.Flags().BoolVar(&repoExecTUI, "tui", false, "Bubble Tea の進捗UIを表示（既定値は config.yaml の ui.tui）")
+	repoExecCmd.Flags().BoolVar(&repoExecNoTUI, "no-tui", false, "TUI 進捗表示を無効化（設定より優先）")
+	repoExecCmd.Flags().StringVarP(&repoExecOutput, "output", "o", outputFormatText, "出力形式（text / json）。json 時は人間向け出力を stderr に出力")
+	repoExecCmd.Flags().StringVar(&repoExecLogFile, "log-file", "", "ジョブ実行ログをファイルに保存")
+	repoExecCmd.Flags().StringVar(&repoExecLogFormat, "log-format", runner.LogFormatText, "ジョブ実行ログの形式（text / jsonl）")
+}
+
+// repoExecTarget はコマンドを実行するリポジトリです。
+type repoExecTarget struct {
+	// Name は表示名（複数ルートの場合は "<ルート名>/<相対パス>"）です。
+	Name string
+	// RelPath はルートからの相対パスで、--name の照合に使用します。
+	RelPath string
+	Path    string
+}
+
+// repoExecFilter は --status / --name / --lang の絞り込み条件です。空の条件は絞り込みません。
+type repoExecFilter struct {
+	Statuses  []repomgr.Status
+	Names     []string
+	Languages []string
+}
+
+// repoExecResult は1リポジトリ分の実行結果で、--output json の要素にもなります。
+type repoExecResult struct {
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+	Status     string `json:"status"`
+	ExitCode   int    `json:"exit_code"`
+	Output     string `json:"output"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// repoExecSummaryReport は終了コード別の件数を含む集計です。
+type repoExecSummaryReport struct {
+	Total     int            `json:"total"`
+	Succeeded int            `json:"succeeded"`
+	Failed    int            `json:"failed"`
+	Skipped   int            `json:"skipped"`
+	ExitCodes map[string]int `json:"exit_codes"`
+}
+
+// repoExecReport は repo exec --output json で出力するドキュメントです。
+type repoExecReport struct {
+	Command []string              `json:"command"`
+	Success bool                  `json:"success"`
+	Repos   []repoExecResult      `json:"repos"`
+	Summary repoExecSummaryReport `json:"summary"`
+}
+
+func runRepoExec(cmd *cobra.Command, args []string) error {
+	format, err := parseOutputFormat(repoExecOutput)
+	if err != nil {
+		return err
+	}
+
+	if _, err := runner.ParseLogFormat(repoExecLogFormat); err != nil {
+		return err
+	}
+
+	filter, err := parseRepoExecFilter(repoExecStatuses, repoExecNames, repoExecLanguages)
+	if err != nil {
+		return err
+	}
+
+	if format != outputFormatJSON {
+		_, err := executeRepoExec(cmd, args, filter, false)
+		return err
+	}
+
+	// JSON モードでは stdout を JSON ドキュメント専用にする
+	restoreStdout := redirectStdoutToStderr()
+	report, runErr := executeRepoExec(cmd, args, filter, true)
+
+	restoreStdout()
+
+	if err := writeJSONReport(os.Stdout, report); err != nil {
+		return err
+	}
+
+	return runErr
+}
+
+// executeRepoExec は repo exec 本体を実行し、JSON 出力用のレポートを返します。
+// jsonMode が true の場合は TUI とリポジトリごとの出力表示を行いません（出力はレポートに含めます）。
+func executeRepoExec(cmd *cobra.Command, command []string, filter repoExecFilter, jsonMode bool) (repoExecReport, error) {
+	report := repoExecReport{Command: command, Repos: []repoExecResult{}}
+
+	cfg, configExists, configPath := loadRepoConfig()
+	targets := resolveRepoRootTargets(cmd, cfg)
+
+	timeout := 10 * time.Minute
+	if parsed, parseErr := time.ParseDuration(cfg.Control.Timeout); parseErr == nil {
+		timeout = parsed
+	}
+
+	baseCtx := cmd.Context()
+	if baseCtx == nil {
+		baseCtx = context.Background()
+	}
+
+	ctx, cancel := context.WithTimeout(baseCtx, timeout)
+	defer cancel()
+
+	execTargets, rootErr := collectRepoExecTargets(ctx, cmd, targets, configExists, configPath)
+
+	if len(execTargets) == 0 && rootErr != nil {
+		return report, rootErr
+	}
+
+	execTargets = filter.apply(ctx, execTargets)
+	if len(execTargets) == 0 {
+		fmt.Println("📝 条件に一致するリポジトリが見つかりませんでした")
+		return report, rootErr
+	}
+
+	tuiReq, err := resolveTUIRequest(cfg.UI.TUI, cmd.Flags().Changed("tui"), repoExecTUI, cmd.Flags().Changed("no-tui"), repoExecNoTUI)
+	if err != nil {
+		return report, err
+	}
+
+	if jsonMode {
+		tuiReq = tuiRequest{Requested: false, Source: tuiSourceFlag}
+	}
+
+	useTUI, warning := resolveTUIEnabled(tuiReq)
+	printTUIWarning(warning)
+
+	jobs := resolveRepoJobs(cfg.Control.Concurrency, repoExecJobs)
+
+	if !useTUI {
+		fmt.Printf("▶️  repo exec を開始します (%d件, 並列=%d): %s\n\n", len(execTargets), jobs, strings.Join(command, " "))
+	}
+
+	// TUI・JSON モードでは実行中に出力を表示せず、終了後にまとめて扱う
+	var streamOutput io.Writer
+	if !useTUI && !jsonMode {
+		streamOutput = os.Stdout
+	}
+
+	execJobs, getResults := buildRepoExecJobs(execTargets, command, streamOutput)
+	logOpts := newJobLogOptions(repoExecLogFile, repoExecLogFormat, "repo")
+	summary := runJobsWithOptionalTUI(ctx, "repo exec 進捗", jobs, execJobs, useTUI, logOpts)
+
+	report.Repos = mergeRepoExecResults(execTargets, getResults(), summary)
+	report.Summary = summarizeRepoExecResults(report.Repos)
+
+	resultErr := repoExecResultError(report.Summary, rootErr)
+	report.Success = resultErr == nil
+
+	// TUI 使用時は実行中に出力を表示していないため、ここでまとめて表示する
+	if useTUI {
+		for _, result := range report.Repos {
+			writePrefixedOutput(os.Stdout, result.Name, result.Output)
+		}
+	}
+
+	printRepoExecSummary(os.Stdout, report.Summary)
+
+	if !jsonMode {
+		printFailedJobDetails(summary)
+	}
+
+	return report, resultErr
+}
+
+// collectRepoExecTargets は各ルート配下のリポジトリを収集します。
+// 一部のルートで探索に失敗した場合も、他のルートで見つかったリポジトリは返します。
+func collectRepoExecTargets(ctx context.Context, cmd *cobra.Command, targets []repoRootTarget, configExists bool, configPath string) ([]repoExecTarget, error) {
+	var execTargets []repoExecTarget
+
+	rootErr := forEachRepoRoot(targets, func(target repoRootTarget) error {
+		root := target.Root.Path
+
+		repoPaths, err := repomgr.DiscoverWithOptions(ctx, root, repoDiscoverOptions(target.Cfg))
+		if err != nil {
+			return wrapRepoRootError(err, root, cmd.Flags().Changed("root"), configExists, configPath)
+		}
+
+		for _, repoPath := range repoPaths {
+			relPath := buildRepoJobDisplayName(root, repoPath)
+			execTargets = append(execTargets, repoExecTarget{
+				Name:    prefixRepoJobName(repoJobLabel(targets, target), relPath),
+				RelPath: relPath,
+				Path:    repoPath,
+			})
+		}
+
+		return nil
+	})
+
+	return execTargets, rootErr
+}
+
+// repoExecResultError は失敗・スキップがある場合にコマンド全体のエラーを返します。
+func repoExecResultError(summary repoExecSummaryReport, rootErr error) error {
+	if summary.Failed > 0 {
+		return errors.Join(rootErr, fmt.Errorf("%d 件のリポジトリでコマンドが失敗しました", summary.Failed))
+	}
+
+	if summary.Skipped > 0 {
+		return errors.Join(rootErr, fmt.Errorf("キャンセルまたはタイムアウトにより %d 件をスキップしました", summary.Skipped))
+	}
+
+	return rootErr
+}
+
+// parseRepoExecFilter はフラグの値を検証して絞り込み条件に変換します。
+func parseRepoExecFilter(statuses, names, languages []string) (repoExecFilter, error) {
+	filter := repoExecFilter{Names: names}
+
+	knownStatuses := []repomgr.Status{repomgr.StatusClean, repomgr.StatusDirty, repomgr.StatusUnpushed, repomgr.StatusNoUpstream}
+
+	for _, value := range statuses {
+		status := repomgr.Status(strings.ToLower(strings.TrimSpace(value)))
+		if !slices.Contains(knownStatuses, status) {
+			return repoExecFilter{}, fmt.Errorf("未対応の状態です: %s（clean / dirty / unpushed / no_upstream を指定してください）", value)
+		}
+
+		filter.Statuses = append(filter.Statuses, status)
+	}
+
+	knownLanguages := repomgr.KnownLanguages()
+
+	for _, value := range languages {
+		language := strings.ToLower(strings.TrimSpace(value))
+		if !slices.Contains(knownLanguages, language) {
+			return repoExecFilter{}, fmt.Errorf("未対応の言語です: %s（%s を指定してください）", value, strings.Join(knownLanguages, " / "))
+		}
+
+		filter.Languages = append(filter.Languages, language)
+	}
+
+	return filter, nil
+}
+
+// apply は条件に一致するリポジトリを返します。状態の取得に失敗したリポジトリは警告して除外します。
+func (f repoExecFilter) apply(ctx context.Context, targets []repoExecTarget) []repoExecTarget {
+	matched := make([]repoExecTarget, 0, len(targets))
+
+	for _, target := range targets {
+		ok, err := f.match(ctx, target)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %s: 状態を取得できないため対象外にします: %v\n", target.Name, err)
+			continue
+		}
+
+		if ok {
+			matched = append(matched, target)
+		}
+	}
+
+	return matched
+}
+
+func (f repoExecFilter) match(ctx context.Context, target repoExecTarget) (bool, error) {
+	if len(f.Names) > 0 && !slices.ContainsFunc(f.Names, func(pattern string) bool {
+		return repomgr.MatchDiscoverPattern(pattern, target.RelPath) || repomgr.MatchDiscoverPattern(pattern, target.Name)
+	}) {
+		return false, nil
+	}
+
+	if len(f.Languages) > 0 {
+		detected := repomgr.DetectLanguages(target.Path)
+		if !slices.ContainsFunc(f.Languages, func(language string) bool { return slices.Contains(detected, language) }) {
+			return false, nil
+		}
+	}
+
+	// 状態の取得は git を実行するため、他の条件を満たしたリポジトリのみ行う
+	if len(f.Statuses) > 0 {
+		info, err := repomgr.Inspect(ctx, target.Path)
+		if err != nil {
+			return false, err
+		}
+
+		if !slices.Contains(f.Statuses, info.Status) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// buildRepoExecJobs はリポジトリごとにコマンドを実行するジョブを生成します。
+// streamOutput が nil でない場合は、各ジョブの終了時に出力を行ごとに名前を付けて書き込みます。
+func buildRepoExecJobs(targets []repoExecTarget, command []string, streamOutput io.Writer) (jobs []runner.Job, getResults func() map[string]repoExecResult) {
+	var (
+		mu      sync.Mutex
+		results = make(map[string]repoExecResult, len(targets))
+	)
+
+	execJobs := make([]runner.Job, 0, len(targets))
+
+	for _, target := range targets {
+		execJobs = append(execJobs, runner.Job{
+			Name: target.Name,
+			Run: func(jobCtx context.Context) error {
+				output, exitCode, runErr := runRepoExecCommand(jobCtx, target.Path, command)
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				results[target.Name] = repoExecResult{Output: output, ExitCode: exitCode}
+
+				if streamOutput != nil {
+					writePrefixedOutput(streamOutput, target.Name, output)
+				}
+
+				return runErr
+			},
+		})
+	}
+
+	getResults = func() map[string]repoExecResult {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return maps.Clone(results)
+	}
+
+	return execJobs, getResults
+}
+
+// runRepoExecCommand は repoPath をカレントディレクトリとしてコマンドを実行し、出力と終了コードを返します。
+// runner のジョブ出力ログが ctx に設定されている場合は、出力をログにも書き込みます。
+func runRepoExecCommand(ctx context.Context, repoPath string, command []string) (output string, exitCode int, err error) {
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Dir = repoPath
+
+	var buf bytes.Buffer
+
+	cmd.Stdout = &buf
+	cmd.Stderr = &buf
+
+	runErr := cmd.Run()
+
+	if jobOutput := runner.JobOutput(ctx); jobOutput != nil {
+		fmt.Fprintf(jobOutput, "$ %s\n%s", strings.Join(command, " "), buf.String())
+	}
+
+	if runErr == nil {
+		return buf.String(), 0, nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(runErr, &exitErr) && exitErr.ExitCode() >= 0 {
+		return buf.String(), exitErr.ExitCode(), fmt.Errorf("終了コード %d", exitErr.ExitCode())
+	}
+
+	return buf.String(), repoExecExitCodeUnknown, fmt.Errorf("コマンドの実行に失敗: %w", runErr)
+}
+
+// writePrefixedOutput は出力の各行に [name] を付けて書き込みます。
+func writePrefixedOutput(w io.Writer, name, output string) {
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		fmt.Fprintf(w, "[%s] %s\n", name, scanner.Text())
+	}
+}
+
+// mergeRepoExecResults はジョブごとの出力と runner.Summary の状態・所要時間を対象の順序でまとめます。
+func mergeRepoExecResults(targets []repoExecTarget, outputs map[string]repoExecResult, summary runner.Summary) []repoExecResult {
+	jobResults := make(map[string]runner.Result, len(summary.Results))
+	for _, r := range summary.Results {
+		jobResults[r.Name] = r
+	}
+
+	results := make([]repoExecResult, 0, len(targets))
+
+	for _, target := range targets {
+		result, ok := outputs[target.Name]
+		if !ok {
+			result.ExitCode = repoExecExitCodeUnknown
+		}
+
+		result.Name = target.Name
+		result.Path = target.Path
+		result.Status = string(runner.StatusSkipped)
+
+		if jobResult, found := jobResults[target.Name]; found {
+			result.Status = string(jobResult.Status)
+			result.DurationMs = jobResult.Duration.Milliseconds()
+
+			if jobResult.Err != nil {
+				result.Error = jobResult.Err.Error()
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+
+	return results
+}
+
+func summarizeRepoExecResults(results []repoExecResult) repoExecSummaryReport {
+	summary := repoExecSummaryReport{Total: len(results), ExitCodes: map[string]int{}}
+
+	for _, result := range results {
+		switch runner.ResultStatus(result.Status) {
+		case runner.StatusSuccess:
+			summary.Succeeded++
+		case runner.StatusFailed:
+			summary.Failed++
+		default:
+			summary.Skipped++
+			continue
+		}
+
+		summary.ExitCodes[strconv.Itoa(result.ExitCode)]++
+	}
+
+	return summary
+}
+
+func printRepoExecSummary(w io.Writer, summary repoExecSummaryReport) {
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "📊 repo exec 結果: 成功 %d件, 失敗 %d件, スキップ %d件\n", summary.Succeeded, summary.Failed, summary.Skipped)
+
+	if len(summary.ExitCodes) == 0 {
+		return
+	}
+
+	codes := make([]int, 0, len(summary.ExitCodes))
+	for code := range summary.ExitCodes {
+		if n, err := strconv.Atoi(code); err == nil {
+			codes = append(codes, n)
+		}
+	}
+
+	sort.Ints(codes)
+
+	parts := make([]string, 0, len(codes))
+	for _, code := range codes {
+		parts = append(parts, fmt.Sprintf("%d: %d件", code, summary.ExitCodes[strconv.Itoa(code)]))
+	}
+
+	fmt.Fprintf(w, "   終了コード別: %s\n", strings.Join(parts, ", "))
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	repomgr "github.com/scottlz0310/dsx/internal/repo"
+	"github.com/scottlz0310/dsx/internal/runner"
+	"github.com/scottlz0310/dsx/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+// setupRepoExecTest は空の設定で root 配下に api（go）・web（node・未コミットの変更あり）・docs の3リポジトリを作成します。
+func setupRepoExecTest(t *testing.T) (root string) {
+	t.Helper()
+
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+
+	home := t.TempDir()
+	testutil.SetTestHome(t, home)
+
+	t.Cleanup(func() {
+		repoExecJobs = 0
+		repoExecStatuses = nil
+		repoExecNames = nil
+		repoExecLanguages = nil
+		repoExecTUI = false
+		repoExecNoTUI = false
+		repoExecOutput = outputFormatText
+		repoExecLogFile = ""
+		repoExecLogFormat = runner.LogFormatText
+		repoRootOverride = ""
+	})
+
+	root = filepath.Join(home, "src")
+
+	for name, marker := range map[string]string{"api": "go.mod", "web": "package.json", "docs": "README.md"} {
+		repoPath := filepath.Join(root, name)
+
+		for _, args := range [][]string{
+			{"init", "-q", repoPath},
+			{"-C", repoPath, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "init"},
+		} {
+			if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+				t.Fatalf("git %v failed: %v: %s", args, err, out)
+			}
+		}
+
+		// マーカーファイルは未追跡のまま置くため、すべてのリポジトリが dirty になる
+		if err := os.WriteFile(filepath.Join(repoPath, marker), []byte("x"), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", marker, err)
+		}
+	}
+
+	return root
+}
+
+// newRepoExecTestCommand は repo exec のフラグを解析し、コマンドと -- 以降の引数を返します。
+func newRepoExecTestCommand(t *testing.T, args ...string) (cmd *cobra.Command, command []string) {
+	t.Helper()
+
+	cmd = &cobra.Command{Use: "exec"}
+	cmd.Flags().StringVar(&repoRootOverride, "root", "", "")
+	cmd.Flags().IntVarP(&repoExecJobs, "jobs", "j", 0, "")
+	cmd.Flags().StringSliceVar(&repoExecStatuses, "status", nil, "")
+	cmd.Flags().StringSliceVar(&repoExecNames, "name", nil, "")
+	cmd.Flags().StringSliceVar(&repoExecLanguages, "lang", nil, "")
+	cmd.Flags().BoolVar(&repoExecTUI, "tui", false, "")
+	cmd.Flags().BoolVar(&repoExecNoTUI, "no-tui", false, "")
+	cmd.Flags().StringVarP(&repoExecOutput, "output", "o", outputFormatText, "")
+	cmd.Flags().StringVar(&repoExecLogFile, "log-file", "", "")
+	cmd.Flags().StringVar(&repoExecLogFormat, "log-format", runner.LogFormatText, "")
+
+	if err := cmd.Flags().Parse(args); err != nil {
+		t.Fatalf("flag parse failed: %v", err)
+	}
+
+	return cmd, cmd.Flags().Args()
+}
+
+func TestRunRepoExec_PrefixesOutputAndSummarizesExitCodes(t *testing.T) {
+	root := setupRepoExecTest(t)
+
+	cmd, command := newRepoExecTestCommand(t, "--root", root, "-j", "2", "--", "sh", "-c", "echo hello; test -f go.mod || exit 3")
+
+	var err error
+
+	stdout := captureStdout(t, func() {
+		err = runRepoExec(cmd, command)
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "2 件のリポジトリでコマンドが失敗しました") {
+		t.Fatalf("runRepoExec() error = %v, want two failures", err)
+	}
+
+	for _, want := range []string{"[api] hello", "[docs] hello", "[web] hello", "成功 1件, 失敗 2件", "終了コード別: 0: 1件, 3: 2件"} {
+		if !strings.Contains(stdout, want) {
+			t.Fatalf("stdout does not contain %q:\n%s", want, stdout)
+		}
+	}
+}
+
+func TestRunRepoExec_JSONOutputWithFilters(t *testing.T) {
+	root := setupRepoExecTest(t)
+
+	cmd, command := newRepoExecTestCommand(t, "--root", root, "--lang", "go,node", "--name", "w*", "-o", "json", "--", "sh", "-c", "pwd")
+
+	var err error
+
+	stdout := captureStdout(t, func() {
+		captureStderr(t, func() {
+			err = runRepoExec(cmd, command)
+		})
+	})
+	if err != nil {
+		t.Fatalf("runRepoExec() error = %v", err)
+	}
+
+	var report repoExecReport
+	if jsonErr := json.Unmarshal([]byte(stdout), &report); jsonErr != nil {
+		t.Fatalf("stdout is not a JSON document: %v\n%s", jsonErr, stdout)
+	}
+
+	if !report.Success || len(report.Repos) != 1 || report.Repos[0].Name != "web" {
+		t.Fatalf("report = %+v, want only web to succeed", report)
+	}
+
+	if got := report.Repos[0]; got.ExitCode != 0 || got.Status != string(runner.StatusSuccess) || !strings.Contains(got.Output, filepath.Join("src", "web")) {
+		t.Fatalf("report.Repos[0] = %+v, want exit 0 with output from the repository directory", got)
+	}
+
+	if report.Summary.Total != 1 || report.Summary.ExitCodes["0"] != 1 {
+		t.Fatalf("report.Summary = %+v, want one success", report.Summary)
+	}
+}
+
+func TestRunRepoExec_NoMatchingRepositories(t *testing.T) {
+	root := setupRepoExecTest(t)
+
+	cmd, command := newRepoExecTestCommand(t, "--root", root, "--status", "clean", "--", "false")
+
+	var err error
+
+	stdout := captureStdout(t, func() {
+		err = runRepoExec(cmd, command)
+	})
+	if err != nil {
+		t.Fatalf("runRepoExec() error = %v", err)
+	}
+
+	if !strings.Contains(stdout, "条件に一致するリポジトリが見つかりませんでした") {
+		t.Fatalf("stdout does not report no targets:\n%s", stdout)
+	}
+}
+
+func TestParseRepoExecFilter(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		statuses  []string
+		languages []string
+		wantErr   string
+	}{
+		{name: "状態と言語は大文字小文字を区別しない", statuses: []string{"Dirty", "no_upstream"}, languages: []string{"Go"}},
+		{name: "未対応の状態はエラー", statuses: []string{"behind"}, wantErr: "未対応の状態です: behind"},
+		{name: "未対応の言語はエラー", languages: []string{"cobol"}, wantErr: "未対応の言語です: cobol"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseRepoExecFilter(tc.statuses, nil, tc.languages)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("parseRepoExecFilter() error = %v, want %q", err, tc.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseRepoExecFilter() error = %v", err)
+			}
+
+			if len(got.Statuses) != len(tc.statuses) || got.Statuses[0] != repomgr.StatusDirty || got.Languages[0] != "go" {
+				t.Fatalf("parseRepoExecFilter() = %+v, want normalized values", got)
+			}
+		})
+	}
+}
+
+func TestSummarizeRepoExecResults(t *testing.T) {
+	t.Parallel()
+
+	got := summarizeRepoExecResults([]repoExecResult{
+		{Status: string(runner.StatusSuccess), ExitCode: 0},
+		{Status: string(runner.StatusFailed), ExitCode: 2},
+		{Status: string(runner.StatusFailed), ExitCode: 2},
+		{Status: string(runner.StatusSkipped), ExitCode: repoExecExitCodeUnknown},
+	})
+
+	if got.Total != 4 || got.Succeeded != 1 || got.Failed != 2 || got.Skipped != 1 {
+		t.Fatalf("summarizeRepoExecResults() = %+v", got)
+	}
+
+	// スキップしたリポジトリは終了コード別の集計に含めない
+	if len(got.ExitCodes) != 2 || got.ExitCodes["0"] != 1 || got.ExitCodes["2"] != 2 {
+		t.Fatalf("ExitCodes = %v, want map[0:1 2:2]", got.ExitCodes)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// repoRootTarget はコマンドの対象ルート1件と、そのルートの上書き設定を反映した設定です。
+type repoRootTarget struct {
+	Root config.RepoRootConfig
+	Cfg  *config.Config
+}
+
+// resolveRepoRootTargets は repo.roots（未指定時は repo.root）から対象ルートを解決します。
+// --root 指定時はそのパスのみを対象とし、同じパスの repo.roots エントリがあればその設定を使用します。
+func resolveRepoRootTargets(cmd *cobra.Command, cfg *config.Config) []repoRootTarget {
+	roots := cfg.EffectiveRepoRoots()
+
+	if cmd.Flags().Changed("root") {
+		root, ok := cfg.FindRepoRoot(repoRootOverride)
+		if !ok {
+			root = config.RepoRootConfig{Path: repoRootOverride}
+		}
+
+		roots = []config.RepoRootConfig{root}
+	}
+
+	targets := make([]repoRootTarget, 0, len(roots))
+	for _, root := range roots {
+		targets = append(targets, repoRootTarget{Root: root, Cfg: cfg.ForRepoRoot(root)})
+	}
+
+	return targets
+}
+
+// repoJobLabel は複数ルートを扱う場合にジョブ名へ付けるルートの表示名を返します（単一ルートでは空）。
+func repoJobLabel(targets []repoRootTarget, target repoRootTarget) string {
+	if len(targets) <= 1 {
+		return ""
+	}
+
+	return target.Root.Label()
+}
+
+// printRepoRootHeader は複数ルートを扱う場合にルートごとの見出しを表示します。
+func printRepoRootHeader(targets []repoRootTarget, index int) {
+	if len(targets) <= 1 {
+		return
+	}
+
+	if index > 0 {
+		fmt.Println()
+	}
+
+	root := targets[index].Root
+	if root.Label() != root.Path {
+		fmt.Printf("📂 %s（%s）\n", root.Label(), root.Path)
+	} else {
+		fmt.Printf("📂 %s\n", root.Path)
+	}
+
+	fmt.Println()
+}
+
+// forEachRepoRoot はルートごとに fn を実行します。単一ルートの場合はエラーをそのまま返します。
+// 複数ルートの場合は見出しを表示し、失敗したルートがあっても残りのルートを処理してからエラーをまとめて返します。
+func forEachRepoRoot(targets []repoRootTarget, fn func(target repoRootTarget) error) error {
+	if len(targets) == 1 {
+		return fn(targets[0])
+	}
+
+	var errs []error
+
+	for i, target := range targets {
+		printRepoRootHeader(targets, i)
+
+		if err := fn(target); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", target.Root.Label(), err)
+			errs = append(errs, fmt.Errorf("%s: %w", target.Root.Label(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// prefixRepoJobName は複数ルートを扱う場合にジョブ名の先頭へルートの表示名を付けます。
+func prefixRepoJobName(label, name string) string {
+	if label == "" {
+		return name
+	}
+
+	return label + "/" + name
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/scottlz0310/dsx/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+// newRepoRootTestCommand は --root フラグのみを持つテスト用コマンドを返します。
+func newRepoRootTestCommand(t *testing.T) *cobra.Command {
+	t.Helper()
+
+	original := repoRootOverride
+
+	t.Cleanup(func() { repoRootOverride = original })
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().StringVar(&repoRootOverride, "root", "", "")
+
+	return cmd
+}
+
+func TestResolveRepoRootTargets(t *testing.T) {
+	enabled := true
+
+	cfg := config.Default()
+	cfg.Repo.Root = "/src"
+	cfg.Repo.GitHub.Owner = "alice"
+	cfg.Repo.Roots = []config.RepoRootConfig{
+		{Path: "/work", Name: "work", GitHub: config.GitHubConfig{Owner: "acme"}, Sync: config.RepoRootSyncConfig{SubmoduleUpdate: &enabled}},
+		{Path: "/oss"},
+	}
+
+	t.Run("roots をすべて対象とし、ルートごとの上書きを反映する", func(t *testing.T) {
+		targets := resolveRepoRootTargets(newRepoRootTestCommand(t), cfg)
+
+		if len(targets) != 2 {
+			t.Fatalf("len(targets) = %d, want 2", len(targets))
+		}
+
+		if got := targets[0].Cfg; got.Repo.Root != "/work" || got.Repo.GitHub.Owner != "acme" || !got.Repo.Sync.SubmoduleUpdate {
+			t.Fatalf("targets[0].Cfg.Repo = %+v, want root=/work owner=acme submodule_update=true", got.Repo)
+		}
+
+		if got := targets[1].Cfg; got.Repo.Root != "/oss" || got.Repo.GitHub.Owner != "alice" {
+			t.Fatalf("targets[1].Cfg.Repo = %+v, want root=/oss owner=alice", got.Repo)
+		}
+
+		if repoJobLabel(targets, targets[0]) != "work" || repoJobLabel(targets, targets[1]) != "/oss" {
+			t.Fatalf("repoJobLabel() = %q, %q, want work, /oss", repoJobLabel(targets, targets[0]), repoJobLabel(targets, targets[1]))
+		}
+	})
+
+	t.Run("--root が roots のエントリと一致すればその設定を使う", func(t *testing.T) {
+		cmd := newRepoRootTestCommand(t)
+		if err := cmd.Flags().Set("root", "/work/"); err != nil {
+			t.Fatalf("Set(root) error = %v", err)
+		}
+
+		targets := resolveRepoRootTargets(cmd, cfg)
+		if len(targets) != 1 || targets[0].Root.Name != "work" || targets[0].Cfg.Repo.GitHub.Owner != "acme" {
+			t.Fatalf("targets = %+v, want only the work root", targets)
+		}
+
+		if label := repoJobLabel(targets, targets[0]); label != "" {
+			t.Fatalf("repoJobLabel() = %q, want empty for a single root", label)
+		}
+	})
+
+	t.Run("--root が roots にないパスなら全体設定を使う", func(t *testing.T) {
+		cmd := newRepoRootTestCommand(t)
+		if err := cmd.Flags().Set("root", "/tmp/other"); err != nil {
+			t.Fatalf("Set(root) error = %v", err)
+		}
+
+		targets := resolveRepoRootTargets(cmd, cfg)
+		if len(targets) != 1 || targets[0].Cfg.Repo.Root != "/tmp/other" || targets[0].Cfg.Repo.GitHub.Owner != "alice" {
+			t.Fatalf("targets = %+v, want /tmp/other with the global settings", targets)
+		}
+	})
+
+	t.Run("roots 未指定時は root を使う", func(t *testing.T) {
+		legacy := config.Default()
+		legacy.Repo.Root = "/src"
+
+		targets := resolveRepoRootTargets(newRepoRootTestCommand(t), legacy)
+		if len(targets) != 1 || targets[0].Cfg.Repo.Root != "/src" {
+			t.Fatalf("targets = %+v, want only /src", targets)
+		}
+	})
+}
+
+func TestForEachRepoRoot(t *testing.T) {
+	single := []repoRootTarget{{Root: config.RepoRootConfig{Path: "/src"}}}
+	wantErr := errors.New("boom")
+
+	if err := forEachRepoRoot(single, func(repoRootTarget) error { return wantErr }); !errors.Is(err, wantErr) {
+		t.Fatalf("forEachRepoRoot(single) error = %v, want %v", err, wantErr)
+	}
+
+	multi := []repoRootTarget{
+		{Root: config.RepoRootConfig{Path: "/work", Name: "work"}},
+		{Root: config.RepoRootConfig{Path: "/oss"}},
+	}
+
+	var visited []string
+
+	var err error
+
+	stdout := captureStdout(t, func() {
+		captureStderr(t, func() {
+			err = forEachRepoRoot(multi, func(target repoRootTarget) error {
+				visited = append(visited, target.Root.Path)
+				if target.Root.Path == "/work" {
+					return wantErr
+				}
+
+				return nil
+			})
+		})
+	})
+
+	if len(visited) != 2 {
+		t.Fatalf("visited = %v, want both roots even after a failure", visited)
+	}
+
+	if !errors.Is(err, wantErr) || !strings.Contains(err.Error(), "work: boom") {
+		t.Fatalf("forEachRepoRoot(multi) error = %v, want an error prefixed with the root label", err)
+	}
+
+	for _, want := range []string{"📂 work（/work）", "📂 /oss"} {
+		if !strings.Contains(stdout, want) {
+			t.Fatalf("stdout does not contain %q:\n%s", want, stdout)
+		}
+	}
+}
+
+func TestRunRepoList_MultipleRoots(t *testing.T) {
+	home := t.TempDir()
+	workRoot := filepath.Join(home, "work")
+	ossRoot := filepath.Join(home, "oss")
+
+	for _, repoPath := range []string{filepath.Join(workRoot, "api"), filepath.Join(ossRoot, "lib")} {
+		for _, args := range [][]string{
+			{"init", "-q", repoPath},
+			{"-C", repoPath, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "init"},
+		} {
+			if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+				t.Fatalf("git %v failed: %v: %s", args, err, out)
+			}
+		}
+	}
+
+	configDir := filepath.Join(home, ".config", "dsx")
+	if err := os.MkdirAll(configDir, 0o755); err != nil {
+		t.Fatalf("config dir creation failed: %v", err)
+	}
+
+	configBody := "version: 1\nrepo:\n  roots:\n    - path: " + workRoot + "\n      name: work\n    - path: " + ossRoot + "\n"
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configBody), 0o644); err != nil {
+		t.Fatalf("config file write failed: %v", err)
+	}
+
+	testutil.SetTestHome(t, home)
+
+	var err error
+
+	stdout := captureStdout(t, func() {
+		err = runRepoList(newRepoRootTestCommand(t), nil)
+	})
+	if err != nil {
+		t.Fatalf("runRepoList() error = %v", err)
+	}
+
+	workIdx := strings.Index(stdout, "📂 work")
+	ossIdx := strings.Index(stdout, "📂 "+ossRoot)
+
+	if workIdx < 0 || ossIdx < workIdx {
+		t.Fatalf("stdout should group repositories by root in order:\n%s", stdout)
+	}
+
+	if api := strings.Index(stdout, "api"); api < workIdx || api > ossIdx {
+		t.Fatalf("api should be listed under the work root:\n%s", stdout)
+	}
+
+	if lib := strings.Index(stdout, "lib"); lib < ossIdx {
+		t.Fatalf("lib should be listed under the oss root:\n%s", stdout)
+	}
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	repomgr "github.com/scottlz0310/dsx/internal/repo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	repoStatusOnly   []string
+	repoStatusOutput string
+)
+
+// repoStatusConditions は --only で指定できる条件と判定です。
+var repoStatusConditions = map[string]func(repomgr.Info) bool{
+	"clean":       func(info repomgr.Info) bool { return info.Status == repomgr.StatusClean },
+	"dirty":       func(info repomgr.Info) bool { return info.Dirty },
+	"unpushed":    func(info repomgr.Info) bool { return info.Ahead > 0 },
+	"behind":      func(info repomgr.Info) bool { return info.Behind > 0 },
+	"no_upstream": func(info repomgr.Info) bool { return !info.HasUpstream },
+	"stash":       func(info repomgr.Info) bool { return info.StashCount > 0 },
+	"detached":    func(info repomgr.Info) bool { return info.Detached },
+	"in_progress": func(info repomgr.Info) bool { return info.Operation != repomgr.OperationNone },
+}
+
+var repoStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "管理下リポジトリの詳細な状態を表示します",
+	Long: `設定された root 配下の Git リポジトリについて、現在のブランチ、Ahead/Behind、
+tracked / untracked の変更件数、stash の件数、進行中の rebase / merge、最終コミットを表示します。
+
+--only で条件に一致するリポジトリのみに絞り込めます（複数指定は OR 条件）:
+  clean / dirty / unpushed / behind / no_upstream / stash / detached / in_progress`,
+	Example: `  dsx repo status
+  dsx repo status --only dirty,unpushed
+  dsx repo status --only stash,in_progress -o json`,
+	RunE: runRepoStatus,
+}
+
+func init() {
+	repoCmd.AddCommand(repoStatusCmd)
+
+	repoStatusCmd.Flags().StringVar(&repoRootOverride, "root", "", "スキャン対象のルートディレクトリ（指定時は設定を上書き）")
+	repoStatusCmd.Flags().StringSliceVar(&repoStatusOnly, "only", nil, "条件に一致するリポジトリのみ表示（"+strings.Join(repoStatusConditionNames(), " / ")+"、複数指定可）")
+	repoStatusCmd.Flags().StringVarP(&repoStatusOutput, "output", "o", outputFormatText, "出力形式（text / json）。json 時は人間向け出力を stderr に出力")
+}
+
+// repoStatusEntry は repo status --output json の1リポジトリ分の要素です。
+type repoStatusEntry struct {
+	Root             string `json:"root"`
+	Name             string `json:"name"`
+	Path             string `json:"path"`
+	Status           string `json:"status"`
+	Branch           string `json:"branch"`
+	Detached         bool   `json:"detached"`
+	Upstream         string `json:"upstream,omitempty"`
+	Ahead            int    `json:"ahead"`
+	Behind           int    `json:"behind"`
+	TrackedChanges   int    `json:"tracked_changes"`
+	UntrackedFiles   int    `json:"untracked_files"`
+	StashCount       int    `json:"stash_count"`
+	Operation        string `json:"operation,omitempty"`
+	LastCommitHash   string `json:"last_commit_hash"`
+	LastCommitAuthor string `json:"last_commit_author"`
+	LastCommitAt     string `json:"last_commit_at"`
+}
+
+// repoStatusReport は repo status --output json で出力するドキュメントです。
+type repoStatusReport struct {
+	Only  []string          `json:"only"`
+	Repos []repoStatusEntry `json:"repos"`
+}
+
+func runRepoStatus(cmd *cobra.Command, args []string) error {
+	format, err := parseOutputFormat(repoStatusOutput)
+	if err != nil {
+		return err
+	}
+
+	only, err := parseRepoStatusOnly(repoStatusOnly)
+	if err != nil {
+		return err
+	}
+
+	if format != outputFormatJSON {
+		_, err := collectRepoStatus(cmd, only)
+		return err
+	}
+
+	// JSON モードでは stdout を JSON ドキュメント専用にし、表は stderr に出力する
+	restoreStdout := redirectStdoutToStderr()
+	report, runErr := collectRepoStatus(cmd, only)
+
+	restoreStdout()
+
+	if err := writeJSONReport(os.Stdout, report); err != nil {
+		return err
+	}
+
+	return runErr
+}
+
+// collectRepoStatus は各ルートのリポジトリの詳細を取得して表示し、JSON 出力用のレポートを返します。
+func collectRepoStatus(cmd *cobra.Command, only []string) (repoStatusReport, error) {
+	report := repoStatusReport{Only: only, Repos: []repoStatusEntry{}}
+	if report.Only == nil {
+		report.Only = []string{}
+	}
+
+	cfg, configExists, configPath := loadRepoConfig()
+	targets := resolveRepoRootTargets(cmd, cfg)
+
+	timeout := 10 * time.Minute
+	if parsed, parseErr := time.ParseDuration(cfg.Control.Timeout); parseErr == nil {
+		timeout = parsed
+	}
+
+	baseCtx := cmd.Context()
+	if baseCtx == nil {
+		baseCtx = context.Background()
+	}
+
+	ctx, cancel := context.WithTimeout(baseCtx, timeout)
+	defer cancel()
+
+	err := forEachRepoRoot(targets, func(target repoRootTarget) error {
+		root := target.Root.Path
+
+		repos, err := repomgr.ListDetail(ctx, root, repoDiscoverOptions(target.Cfg))
+		if err != nil {
+			return wrapRepoRootError(err, root, cmd.Flags().Changed("root"), configExists, configPath)
+		}
+
+		if len(repos) == 0 {
+			fmt.Printf("📝 リポジトリが見つかりませんでした: %s\n", root)
+			return nil
+		}
+
+		matched := filterRepoStatus(repos, only)
+		if len(matched) == 0 {
+			fmt.Printf("📝 条件に一致するリポジトリはありません (%d件中)\n", len(repos))
+			return nil
+		}
+
+		for _, info := range matched {
+			report.Repos = append(report.Repos, newRepoStatusEntry(root, info))
+		}
+
+		fmt.Printf("📦 リポジトリの状態 (%d件)\n\n", len(matched))
+
+		if err := writeRepoStatusTable(os.Stdout, matched); err != nil {
+			return fmt.Errorf("状態表示に失敗: %w", err)
+		}
+
+		return nil
+	})
+
+	return report, err
+}
+
+func repoStatusConditionNames() []string {
+	names := make([]string, 0, len(repoStatusConditions))
+	for name := range repoStatusConditions {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// parseRepoStatusOnly は --only の値を検証し、小文字に正規化して返します。
+func parseRepoStatusOnly(values []string) ([]string, error) {
+	only := make([]string, 0, len(values))
+
+	for _, value := range values {
+		name := strings.ToLower(strings.TrimSpace(value))
+		if _, ok := repoStatusConditions[name]; !ok {
+			return nil, fmt.Errorf("未対応の条件です: %s（%s を指定してください）", value, strings.Join(repoStatusConditionNames(), " / "))
+		}
+
+		only = append(only, name)
+	}
+
+	return only, nil
+}
+
+// filterRepoStatus は only のいずれかに一致するリポジトリを返します。only が空の場合はすべて返します。
+func filterRepoStatus(repos []repomgr.Info, only []string) []repomgr.Info {
+	if len(only) == 0 {
+		return repos
+	}
+
+	matched := make([]repomgr.Info, 0, len(repos))
+
+	for _, info := range repos {
+		if slices.ContainsFunc(only, func(name string) bool { return repoStatusConditions[name](info) }) {
+			matched = append(matched, info)
+		}
+	}
+
+	return matched
+}
+
+func newRepoStatusEntry(root string, info repomgr.Info) repoStatusEntry {
+	entry := repoStatusEntry{
+		Root:             root,
+		Name:             info.Name,
+		Path:             info.Path,
+		Status:           string(info.Status),
+		Branch:           info.Branch,
+		Detached:         info.Detached,
+		Upstream:         info.Upstream,
+		Ahead:            info.Ahead,
+		Behind:           info.Behind,
+		TrackedChanges:   info.TrackedChanges,
+		UntrackedFiles:   info.UntrackedFiles,
+		StashCount:       info.StashCount,
+		Operation:        string(info.Operation),
+		LastCommitHash:   info.LastCommitHash,
+		LastCommitAuthor: info.LastCommitAuthor,
+	}
+
+	if !info.LastCommitAt.IsZero() {
+		entry.LastCommitAt = info.LastCommitAt.Format(time.RFC3339)
+	}
+
+	return entry
+}
+
+func writeRepoStatusTable(output io.Writer, repos []repomgr.Info) error {
+	writer := tabwriter.NewWriter(output, 0, 8, 2, ' ', 0)
+
+	if _, err := fmt.Fprintln(writer, "名前\tブランチ\t状態\tAhead/Behind\t変更\t未追跡\tstash\t進行中\t最終コミット"); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintln(writer, "----\t--------\t----\t------------\t----\t------\t-----\t------\t------------"); err != nil {
+		return err
+	}
+
+	for _, repo := range repos {
+		branch := repo.Branch
+		if repo.Detached {
+			branch = fmt.Sprintf("(detached %s)", repo.LastCommitHash)
+		}
+
+		sync := "-"
+		if repo.HasUpstream {
+			sync = fmt.Sprintf("↑%d ↓%d", repo.Ahead, repo.Behind)
+		}
+
+		operation := repomgr.OperationLabel(repo.Operation)
+		if operation == "" {
+			operation = "-"
+		}
+
+		lastCommit := "-"
+		if repo.LastCommitAge != "" {
+			lastCommit = fmt.Sprintf("%s (%s)", repo.LastCommitAge, repo.LastCommitAuthor)
+		}
+
+		if _, err := fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			repo.Name,
+			branch,
+			repomgr.StatusLabel(repo.Status),
+			sync,
+			formatRepoStatusCount(repo.TrackedChanges),
+			formatRepoStatusCount(repo.UntrackedFiles),
+			formatRepoStatusCount(repo.StashCount),
+			operation,
+			lastCommit,
+		); err != nil {
+			return err
+		}
+	}
+
+	return writer.Flush()
+}
+
+// formatRepoStatusCount は件数を表示用に整形します（0 件は "-" として目立たせない）。
+func formatRepoStatusCount(count int) string {
+	if count == 0 {
+		return "-"
+	}
+
+	return strconv.Itoa(count)
+}
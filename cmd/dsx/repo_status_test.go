@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	repomgr "github.com/scottlz0310/dsx/internal/repo"
+	"github.com/scottlz0310/dsx/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+func newRepoStatusTestCommand(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+
+	t.Cleanup(func() {
+		repoStatusOnly = nil
+		repoStatusOutput = outputFormatText
+		repoRootOverride = ""
+	})
+
+	cmd := &cobra.Command{Use: "status"}
+	cmd.Flags().StringVar(&repoRootOverride, "root", "", "")
+	cmd.Flags().StringSliceVar(&repoStatusOnly, "only", nil, "")
+	cmd.Flags().StringVarP(&repoStatusOutput, "output", "o", outputFormatText, "")
+
+	if err := cmd.Flags().Parse(args); err != nil {
+		t.Fatalf("flag parse failed: %v", err)
+	}
+
+	return cmd
+}
+
+func TestRunRepoStatus_JSONWithOnly(t *testing.T) {
+	home := t.TempDir()
+	testutil.SetTestHome(t, home)
+
+	root := filepath.Join(home, "src")
+
+	for _, name := range []string{"api", "web"} {
+		repoPath := filepath.Join(root, name)
+
+		for _, args := range [][]string{
+			{"init", "-q", repoPath},
+			{"-C", repoPath, "-c", "user.name=alice", "-c", "user.email=alice@example.com", "commit", "-q", "--allow-empty", "-m", "init"},
+		} {
+			if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+				t.Fatalf("git %v failed: %v: %s", args, err, out)
+			}
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "web", "notes.txt"), []byte("x\n"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	var err error
+
+	stdout := captureStdout(t, func() {
+		captureStderr(t, func() {
+			err = runRepoStatus(newRepoStatusTestCommand(t, "--root", root, "--only", "dirty", "-o", "json"), nil)
+		})
+	})
+	if err != nil {
+		t.Fatalf("runRepoStatus() error = %v", err)
+	}
+
+	var report repoStatusReport
+	if jsonErr := json.Unmarshal([]byte(stdout), &report); jsonErr != nil {
+		t.Fatalf("stdout is not a JSON document: %v\n%s", jsonErr, stdout)
+	}
+
+	if len(report.Repos) != 1 {
+		t.Fatalf("report.Repos = %+v, want only web", report.Repos)
+	}
+
+	got := report.Repos[0]
+	if got.Name != "web" || got.Status != string(repomgr.StatusDirty) || got.UntrackedFiles != 1 || got.TrackedChanges != 0 {
+		t.Fatalf("report.Repos[0] = %+v, want dirty web with one untracked file", got)
+	}
+
+	if got.Branch == "" || got.LastCommitAuthor != "alice" || got.LastCommitAt == "" {
+		t.Fatalf("report.Repos[0] = %+v, want branch and last commit", got)
+	}
+}
+
+func TestParseRepoStatusOnly(t *testing.T) {
+	t.Parallel()
+
+	got, err := parseRepoStatusOnly([]string{"Dirty", " unpushed "})
+	if err != nil || strings.Join(got, ",") != "dirty,unpushed" {
+		t.Fatalf("parseRepoStatusOnly() = %v, %v, want [dirty unpushed]", got, err)
+	}
+
+	if _, err := parseRepoStatusOnly([]string{"ahead"}); err == nil || !strings.Contains(err.Error(), "未対応の条件です: ahead") {
+		t.Fatalf("parseRepoStatusOnly(ahead) error = %v, want an unsupported condition error", err)
+	}
+}
+
+func TestFilterRepoStatus(t *testing.T) {
+	t.Parallel()
+
+	repos := []repomgr.Info{
+		{Name: "clean", Status: repomgr.StatusClean, HasUpstream: true},
+		{Name: "dirty-ahead", Status: repomgr.StatusDirty, Dirty: true, HasUpstream: true, Ahead: 2},
+		{Name: "stash", Status: repomgr.StatusClean, HasUpstream: true, StashCount: 1},
+		{Name: "rebasing", Status: repomgr.StatusNoUpstream, Detached: true, Operation: repomgr.OperationRebase},
+	}
+
+	testCases := []struct {
+		name string
+		only []string
+		want string
+	}{
+		{name: "条件なしはすべて", want: "clean,dirty-ahead,stash,rebasing"},
+		{name: "unpushed は dirty でも ahead があれば一致", only: []string{"unpushed"}, want: "dirty-ahead"},
+		{name: "複数条件は OR", only: []string{"stash", "in_progress"}, want: "stash,rebasing"},
+		{name: "clean は状態がクリーンのもの", only: []string{"clean"}, want: "clean,stash"},
+		{name: "detached", only: []string{"detached"}, want: "rebasing"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			names := []string{}
+			for _, info := range filterRepoStatus(repos, tc.only) {
+				names = append(names, info.Name)
+			}
+
+			if got := strings.Join(names, ","); got != tc.want {
+				t.Fatalf("filterRepoStatus(%v) = %s, want %s", tc.only, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWriteRepoStatusTable(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	err := writeRepoStatusTable(&buf, []repomgr.Info{
+		{
+			Name: "api", Status: repomgr.StatusDirty, Branch: "main", HasUpstream: true, Ahead: 1,
+			TrackedChanges: 3, UntrackedFiles: 2, StashCount: 1, LastCommitAge: "3日前", LastCommitAuthor: "alice",
+		},
+		{
+			Name: "web", Status: repomgr.StatusNoUpstream, Detached: true, LastCommitHash: "abc1234",
+			Operation: repomgr.OperationRebase, LastCommitAge: "2時間前", LastCommitAuthor: "bob",
+		},
+	})
+	if err != nil {
+		t.Fatalf("writeRepoStatusTable() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("table lines = %d, want 4:\n%s", len(lines), buf.String())
+	}
+
+	for _, want := range []string{"main", "ダーティ", "↑1 ↓0", "3", "2", "3日前 (alice)"} {
+		if !strings.Contains(lines[2], want) {
+			t.Fatalf("api row does not contain %q: %s", want, lines[2])
+		}
+	}
+
+	for _, want := range []string{"(detached abc1234)", "追跡なし", "リベース中", "2時間前 (bob)"} {
+		if !strings.Contains(lines[3], want) {
+			t.Fatalf("web row does not contain %q: %s", want, lines[3])
+		}
+	}
+}
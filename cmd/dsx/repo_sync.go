@@ -0,0 +1,496 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	survey "github.com/AlecAivazis/survey/v2"
+	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/scottlz0310/dsx/internal/history"
+	repomgr "github.com/scottlz0310/dsx/internal/repo"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// manifestStateFileName は状態ディレクトリ配下のマニフェスト管理状態ファイル名です。
+	manifestStateFileName = "repo-manifest.json"
+	// defaultManifestArchiveDir はルート配下の既定のアーカイブ先ディレクトリ名です。
+	defaultManifestArchiveDir = ".dsx-archive"
+
+	pruneDroppedArchive = "archive"
+	pruneDroppedRemove  = "remove"
+)
+
+var (
+	repoSyncManifest     string
+	repoSyncDryRun       bool
+	repoSyncPruneDropped string
+	repoSyncArchiveDir   string
+	repoSyncYes          bool
+)
+
+var (
+	repoSyncCloneStep   = cloneManifestRepo
+	repoSyncConfirmStep = confirmDroppedRemoval
+)
+
+var repoSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "マニフェスト（repos.yaml）に従ってワークスペースを同期します",
+	Long: `マニフェスト（repos.yaml）に宣言したリポジトリのうち、ローカルにないものを clone します。
+
+マニフェストに含まれないローカルのリポジトリは「マニフェスト外」として表示し、
+以前の同期で管理していたがマニフェストから削除されたリポジトリは「削除済みエントリ」として表示します。
+削除済みエントリは --prune-dropped で整理できます:
+  archive  アーカイブ先ディレクトリ（既定: <root>/.dsx-archive）へ移動します
+  remove   削除します（未コミットの変更・stash・進行中の操作・リモートにないコミットやブランチがあるリポジトリは削除しません）
+`,
+	RunE: runRepoSync,
+}
+
+func init() {
+	repoCmd.AddCommand(repoSyncCmd)
+
+	repoSyncCmd.Flags().StringVar(&repoSyncManifest, "manifest", "", "マニフェストのパス（既定値は config.yaml の repo.manifest）")
+	repoSyncCmd.Flags().StringVar(&repoRootOverride, "root", "", "同期先のルートディレクトリ（指定時はマニフェストと設定を上書き）")
+	repoSyncCmd.Flags().BoolVarP(&repoSyncDryRun, "dry-run", "n", false, "実際の clone・移動・削除は行わず、計画のみ表示")
+	repoSyncCmd.Flags().StringVar(&repoSyncPruneDropped, "prune-dropped", "", "マニフェストから削除されたリポジトリの扱い（archive / remove）")
+	repoSyncCmd.Flags().StringVar(&repoSyncArchiveDir, "archive-dir", "", "--prune-dropped archive の移動先（既定: <root>/.dsx-archive）")
+	repoSyncCmd.Flags().BoolVarP(&repoSyncYes, "yes", "y", false, "--prune-dropped remove の確認を省略")
+}
+
+// repoSyncCounts は repo sync の件数集計です。
+type repoSyncCounts struct {
+	Cloned  int
+	Present int
+	Failed  int
+	Pruned  int
+}
+
+func runRepoSync(cmd *cobra.Command, _ []string) error {
+	switch repoSyncPruneDropped {
+	case "", pruneDroppedArchive, pruneDroppedRemove:
+	default:
+		return fmt.Errorf("--prune-dropped には archive または remove を指定してください: %q", repoSyncPruneDropped)
+	}
+
+	cfg, _, _ := loadRepoConfig()
+
+	manifestPath := strings.TrimSpace(repoSyncManifest)
+	if manifestPath == "" {
+		manifestPath = strings.TrimSpace(cfg.Repo.Manifest)
+	}
+
+	if manifestPath == "" {
+		return errors.New("マニフェストが指定されていません（--manifest または config.yaml の repo.manifest を指定してください）")
+	}
+
+	manifest, err := repomgr.LoadManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	root, err := resolveManifestRoot(cmd, cfg, manifestPath, manifest)
+	if err != nil {
+		return err
+	}
+
+	dryRun := cfg.Control.DryRun
+	if cmd.Flags().Changed("dry-run") {
+		dryRun = repoSyncDryRun
+	}
+
+	timeout := 10 * time.Minute
+	if parsed, parseErr := time.ParseDuration(cfg.Control.Timeout); parseErr == nil {
+		timeout = parsed
+	}
+
+	baseCtx := cmd.Context()
+	if baseCtx == nil {
+		baseCtx = context.Background()
+	}
+
+	ctx, cancel := context.WithTimeout(baseCtx, timeout)
+	defer cancel()
+
+	statePath, err := manifestStatePath()
+	if err != nil {
+		return err
+	}
+
+	state, err := repomgr.LoadManifestState(statePath)
+	if err != nil {
+		return err
+	}
+
+	discoverOpts := manifestDiscoverOptions(cfg, root, resolveManifestArchiveDir(root, repoSyncArchiveDir))
+
+	plan, err := repomgr.PlanManifestSync(ctx, root, manifest, discoverOpts, state)
+	if err != nil {
+		return fmt.Errorf("リポジトリの探索に失敗しました: %w", err)
+	}
+
+	archiveDir := resolveManifestArchiveDir(plan.Root, repoSyncArchiveDir)
+
+	fmt.Printf("📄 マニフェスト: %s（%d件）\n", manifestPath, len(manifest.Repos))
+	fmt.Printf("📂 同期先: %s\n", plan.Root)
+
+	if dryRun {
+		fmt.Println("📋 DryRun モード: 実際の clone・移動・削除は行いません")
+	}
+
+	fmt.Println()
+
+	counts := cloneManifestRepos(ctx, plan, dryRun)
+	counts.Present = len(plan.Present)
+
+	for _, entry := range plan.Conflicts {
+		fmt.Fprintf(os.Stderr, "⚠️  既存パスが Git リポジトリではないためスキップ: %s\n", entry.LocalPath())
+		counts.Failed++
+	}
+
+	printUnmanagedRepos(plan.Unmanaged)
+
+	kept, pruned, pruneErr := pruneDroppedRepos(ctx, plan, repoSyncPruneDropped, archiveDir, dryRun)
+	counts.Pruned = pruned
+
+	if !dryRun {
+		state.SetManaged(plan.Root, manifest.ManagedPaths(kept))
+
+		if saveErr := state.Save(statePath); saveErr != nil {
+			return saveErr
+		}
+	}
+
+	fmt.Println()
+	printRepoSyncSummary(counts, len(plan.Unmanaged), len(plan.Dropped), dryRun)
+
+	if pruneErr != nil {
+		return pruneErr
+	}
+
+	if counts.Failed > 0 {
+		return fmt.Errorf("%d 件のリポジトリを同期できませんでした", counts.Failed)
+	}
+
+	return nil
+}
+
+// resolveManifestRoot は同期先のルートを決定します。
+// 優先順位は --root、マニフェストの root（相対パスはマニフェストのディレクトリ基準）、repo.root の順です。
+func resolveManifestRoot(cmd *cobra.Command, cfg *config.Config, manifestPath string, manifest *repomgr.Manifest) (string, error) {
+	if cmd.Flags().Changed("root") {
+		return repoRootOverride, nil
+	}
+
+	if root := strings.TrimSpace(manifest.Root); root != "" {
+		if filepath.IsAbs(root) || root == "~" || strings.HasPrefix(root, "~/") {
+			return root, nil
+		}
+
+		return filepath.Join(filepath.Dir(manifestPath), root), nil
+	}
+
+	roots := cfg.EffectiveRepoRoots()
+	if len(roots) > 1 {
+		return "", errors.New("repo.roots が複数あるため同期先を決定できません（マニフェストの root または --root を指定してください）")
+	}
+
+	return roots[0].Path, nil
+}
+
+// resolveManifestArchiveDir は --prune-dropped archive の移動先を返します。
+func resolveManifestArchiveDir(root, override string) string {
+	if dir := strings.TrimSpace(override); dir != "" {
+		return dir
+	}
+
+	return filepath.Join(root, defaultManifestArchiveDir)
+}
+
+// manifestDiscoverOptions は repo.discovery の設定に、ルート配下のアーカイブ先を除外する設定を加えます。
+func manifestDiscoverOptions(cfg *config.Config, root, archiveDir string) repomgr.DiscoverOptions {
+	opts := repoDiscoverOptions(cfg)
+
+	rel, err := filepath.Rel(root, archiveDir)
+	if err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		opts.Exclude = append(append([]string(nil), opts.Exclude...), filepath.ToSlash(rel))
+	}
+
+	return opts
+}
+
+func manifestStatePath() (string, error) {
+	dir, err := history.StateDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, manifestStateFileName), nil
+}
+
+// cloneManifestRepos はローカルにないエントリを順に clone します。失敗しても残りのエントリを続行します。
+func cloneManifestRepos(ctx context.Context, plan *repomgr.ManifestPlan, dryRun bool) repoSyncCounts {
+	counts := repoSyncCounts{}
+
+	for _, entry := range plan.Missing {
+		target := filepath.Join(plan.Root, filepath.FromSlash(entry.LocalPath()))
+
+		fmt.Printf("📥 取得: %s\n", entry.LocalPath())
+		fmt.Printf("  $ git %s\n", strings.Join(entry.CloneArgs(target), " "))
+
+		if dryRun {
+			counts.Cloned++
+			continue
+		}
+
+		if err := repoSyncCloneStep(ctx, entry, target); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", entry.LocalPath(), err)
+			counts.Failed++
+
+			continue
+		}
+
+		counts.Cloned++
+	}
+
+	return counts
+}
+
+// cloneManifestRepo は entry を target へ clone し、LFS が必要な場合は LFS オブジェクトも取得します。
+func cloneManifestRepo(ctx context.Context, entry repomgr.ManifestRepo, target string) error {
+	if entry.LFS {
+		if _, err := repoLookPathStep("git-lfs"); err != nil {
+			return fmt.Errorf("lfs: true ですが git-lfs が見つかりません: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("親ディレクトリの作成に失敗: %w", err)
+	}
+
+	steps := [][]string{entry.CloneArgs(target)}
+	if entry.LFS {
+		steps = append(steps, []string{"-C", target, "lfs", "install", "--local"}, []string{"-C", target, "lfs", "pull"})
+	}
+
+	for _, args := range steps {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		cmd.Stdin = os.Stdin
+
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("git %s に失敗: %w", strings.Join(args, " "), err)
+		}
+	}
+
+	return nil
+}
+
+func printUnmanagedRepos(unmanaged []string) {
+	if len(unmanaged) == 0 {
+		return
+	}
+
+	fmt.Printf("\n📝 マニフェスト外のリポジトリ（%d件）:\n", len(unmanaged))
+
+	for _, rel := range unmanaged {
+		fmt.Printf("  - %s\n", rel)
+	}
+}
+
+// pruneDroppedRepos はマニフェストから削除されたリポジトリを action に従って整理します。
+// 整理しなかったリポジトリ（kept）は次回以降も削除済みエントリとして扱います。
+func pruneDroppedRepos(ctx context.Context, plan *repomgr.ManifestPlan, action, archiveDir string, dryRun bool) (kept []string, pruned int, err error) {
+	if len(plan.Dropped) == 0 {
+		return nil, 0, nil
+	}
+
+	fmt.Printf("\n🗂️  マニフェストから削除されたリポジトリ（%d件）:\n", len(plan.Dropped))
+
+	for _, rel := range plan.Dropped {
+		fmt.Printf("  - %s\n", rel)
+	}
+
+	switch action {
+	case pruneDroppedArchive:
+		kept, pruned, err = archiveDroppedRepos(plan, archiveDir, dryRun)
+	case pruneDroppedRemove:
+		kept, pruned, err = removeDroppedRepos(ctx, plan, dryRun)
+	default:
+		fmt.Println("  （--prune-dropped archive または remove で整理できます）")
+
+		kept = plan.Dropped
+	}
+
+	return kept, pruned, err
+}
+
+func archiveDroppedRepos(plan *repomgr.ManifestPlan, archiveDir string, dryRun bool) (kept []string, archived int, err error) {
+	var errs []error
+
+	for _, rel := range plan.Dropped {
+		source := filepath.Join(plan.Root, filepath.FromSlash(rel))
+		dest := filepath.Join(archiveDir, filepath.FromSlash(rel))
+
+		if _, statErr := os.Stat(dest); statErr == nil {
+			dest = fmt.Sprintf("%s-%s", dest, time.Now().Format("20060102T150405"))
+		}
+
+		fmt.Printf("📦 アーカイブ: %s → %s\n", rel, dest)
+
+		if dryRun {
+			archived++
+			continue
+		}
+
+		if mkdirErr := os.MkdirAll(filepath.Dir(dest), 0o755); mkdirErr != nil {
+			errs = append(errs, fmt.Errorf("%s のアーカイブに失敗: %w", rel, mkdirErr))
+			kept = append(kept, rel)
+
+			continue
+		}
+
+		if renameErr := os.Rename(source, dest); renameErr != nil {
+			errs = append(errs, fmt.Errorf("%s のアーカイブに失敗: %w", rel, renameErr))
+			kept = append(kept, rel)
+
+			continue
+		}
+
+		archived++
+	}
+
+	return kept, archived, errors.Join(errs...)
+}
+
+// removeDroppedRepos は削除しても作業が失われないリポジトリのみ削除します。
+func removeDroppedRepos(ctx context.Context, plan *repomgr.ManifestPlan, dryRun bool) (kept []string, removed int, err error) {
+	var removable []string
+
+	for _, rel := range plan.Dropped {
+		if reason := droppedRepoUnsafeReason(ctx, filepath.Join(plan.Root, filepath.FromSlash(rel))); reason != "" {
+			fmt.Fprintf(os.Stderr, "⚠️  %s: 安全に削除できないため残します（%s）。--prune-dropped archive を使用してください\n", rel, reason)
+			kept = append(kept, rel)
+
+			continue
+		}
+
+		removable = append(removable, rel)
+	}
+
+	if len(removable) == 0 {
+		return kept, 0, nil
+	}
+
+	if !dryRun && !repoSyncYes {
+		confirmed, confirmErr := repoSyncConfirmStep(removable)
+		if confirmErr != nil {
+			return plan.Dropped, 0, confirmErr
+		}
+
+		if !confirmed {
+			fmt.Println("  削除をキャンセルしました")
+			return plan.Dropped, 0, nil
+		}
+	}
+
+	var errs []error
+
+	for _, rel := range removable {
+		fmt.Printf("🗑️  削除: %s\n", rel)
+
+		if dryRun {
+			removed++
+			continue
+		}
+
+		if removeErr := os.RemoveAll(filepath.Join(plan.Root, filepath.FromSlash(rel))); removeErr != nil {
+			errs = append(errs, fmt.Errorf("%s の削除に失敗: %w", rel, removeErr))
+			kept = append(kept, rel)
+
+			continue
+		}
+
+		removed++
+	}
+
+	return kept, removed, errors.Join(errs...)
+}
+
+// droppedRepoUnsafeReason は削除すると失われる作業があればその理由を返します。空文字の場合は安全に削除できます。
+// 未コミットの変更に加え、stash・進行中の操作・現在以外のブランチにある未プッシュのコミットや
+// リモートにないブランチも確認します。
+func droppedRepoUnsafeReason(ctx context.Context, repoPath string) string {
+	info, err := repomgr.InspectDetail(ctx, repoPath)
+	if err != nil {
+		return err.Error()
+	}
+
+	if info.Status != repomgr.StatusClean {
+		return repomgr.StatusLabel(info.Status)
+	}
+
+	if info.StashCount > 0 {
+		return fmt.Sprintf("stash が %d 件あります", info.StashCount)
+	}
+
+	if info.Operation != repomgr.OperationNone {
+		return repomgr.OperationLabel(info.Operation)
+	}
+
+	work, err := repomgr.FindLocalOnlyWork(ctx, repoPath)
+	if err != nil {
+		return err.Error()
+	}
+
+	if work.UnpushedCommits > 0 {
+		return fmt.Sprintf("未プッシュのコミットが %d 件あります", work.UnpushedCommits)
+	}
+
+	if len(work.Branches) > 0 {
+		return "リモートにないブランチがあります: " + strings.Join(work.Branches, ", ")
+	}
+
+	return ""
+}
+
+// confirmDroppedRemoval は削除実行前の最終確認 [y/N] を行います。
+func confirmDroppedRemoval(rels []string) (bool, error) {
+	fmt.Printf("  ❓ 以下 %d 件のリポジトリを削除します: %s\n", len(rels), strings.Join(rels, ", "))
+
+	confirm := false
+	prompt := &survey.Confirm{
+		Message: "実行しますか?",
+		Default: false,
+	}
+
+	if err := survey.AskOne(prompt, &confirm); err != nil {
+		return false, err
+	}
+
+	return confirm, nil
+}
+
+func printRepoSyncSummary(counts repoSyncCounts, unmanaged, dropped int, dryRun bool) {
+	cloneLabel := "clone"
+	if dryRun {
+		cloneLabel = "clone 予定"
+	}
+
+	fmt.Printf("📊 %s: %d件, 既存: %d件, 失敗: %d件, マニフェスト外: %d件, 削除済みエントリ: %d件",
+		cloneLabel, counts.Cloned, counts.Present, counts.Failed, unmanaged, dropped)
+
+	if counts.Pruned > 0 {
+		fmt.Printf(", 整理: %d件", counts.Pruned)
+	}
+
+	fmt.Println()
+}
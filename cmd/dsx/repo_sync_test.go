@@ -0,0 +1,334 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scottlz0310/dsx/internal/config"
+	repomgr "github.com/scottlz0310/dsx/internal/repo"
+	"github.com/scottlz0310/dsx/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+// setupRepoSyncTest は空の設定・状態ディレクトリを用意し、clone と削除確認をスタブ化します。
+func setupRepoSyncTest(t *testing.T) (workspace string, cloned *[]string) {
+	t.Helper()
+
+	home := t.TempDir()
+	testutil.SetTestHome(t, home)
+	t.Setenv("XDG_STATE_HOME", filepath.Join(home, "state"))
+
+	workspace = filepath.Join(home, "ws")
+	cloned = &[]string{}
+
+	originalClone := repoSyncCloneStep
+	originalConfirm := repoSyncConfirmStep
+
+	t.Cleanup(func() {
+		repoSyncCloneStep = originalClone
+		repoSyncConfirmStep = originalConfirm
+		repoSyncManifest = ""
+		repoSyncDryRun = false
+		repoSyncPruneDropped = ""
+		repoSyncArchiveDir = ""
+		repoSyncYes = false
+		repoRootOverride = ""
+	})
+
+	repoSyncCloneStep = func(_ context.Context, entry repomgr.ManifestRepo, target string) error {
+		if strings.Contains(entry.URL, "broken") {
+			return errors.New("repository not found")
+		}
+
+		*cloned = append(*cloned, entry.LocalPath())
+
+		return os.MkdirAll(filepath.Join(target, ".git"), 0o755)
+	}
+
+	repoSyncConfirmStep = func([]string) (bool, error) {
+		t.Fatal("confirmation should not be requested")
+		return false, nil
+	}
+
+	return workspace, cloned
+}
+
+func newRepoSyncTestCommand(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+
+	cmd := &cobra.Command{Use: "sync"}
+	cmd.Flags().StringVar(&repoSyncManifest, "manifest", "", "")
+	cmd.Flags().StringVar(&repoRootOverride, "root", "", "")
+	cmd.Flags().BoolVarP(&repoSyncDryRun, "dry-run", "n", false, "")
+	cmd.Flags().StringVar(&repoSyncPruneDropped, "prune-dropped", "", "")
+	cmd.Flags().StringVar(&repoSyncArchiveDir, "archive-dir", "", "")
+	cmd.Flags().BoolVarP(&repoSyncYes, "yes", "y", false, "")
+
+	if err := cmd.Flags().Parse(args); err != nil {
+		t.Fatalf("flag parse failed: %v", err)
+	}
+
+	return cmd
+}
+
+func writeTestManifest(t *testing.T, dir, body string) string {
+	t.Helper()
+
+	manifestPath := filepath.Join(dir, "repos.yaml")
+	if err := os.WriteFile(manifestPath, []byte(body), 0o644); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+
+	return manifestPath
+}
+
+func TestRunRepoSync_ClonesMissingAndArchivesDropped(t *testing.T) {
+	workspace, cloned := setupRepoSyncTest(t)
+
+	// マニフェスト外のローカルリポジトリ
+	if err := os.MkdirAll(filepath.Join(workspace, "scratch", ".git"), 0o755); err != nil {
+		t.Fatalf("failed to create repo: %v", err)
+	}
+
+	manifestDir := t.TempDir()
+	manifestPath := writeTestManifest(t, manifestDir, `repos:
+  - url: https://github.com/acme/api.git
+  - url: https://github.com/acme/tool.git
+    path: tools/tool
+`)
+
+	var err error
+
+	stdout := captureStdout(t, func() {
+		err = runRepoSync(newRepoSyncTestCommand(t, "--manifest", manifestPath, "--root", workspace), nil)
+	})
+	if err != nil {
+		t.Fatalf("runRepoSync() error = %v", err)
+	}
+
+	if strings.Join(*cloned, ",") != "api,tools/tool" {
+		t.Fatalf("cloned = %v, want [api tools/tool]", *cloned)
+	}
+
+	for _, want := range []string{"📥 取得: api", "マニフェスト外のリポジトリ（1件）", "  - scratch"} {
+		if !strings.Contains(stdout, want) {
+			t.Fatalf("stdout does not contain %q:\n%s", want, stdout)
+		}
+	}
+
+	// tools/tool をマニフェストから削除して archive で整理する
+	writeTestManifest(t, manifestDir, "repos:\n  - url: https://github.com/acme/api.git\n")
+	*cloned = nil
+
+	stdout = captureStdout(t, func() {
+		err = runRepoSync(newRepoSyncTestCommand(t, "--manifest", manifestPath, "--root", workspace, "--prune-dropped", "archive"), nil)
+	})
+	if err != nil {
+		t.Fatalf("runRepoSync() error = %v", err)
+	}
+
+	if len(*cloned) != 0 {
+		t.Fatalf("cloned = %v, want nothing on the second run", *cloned)
+	}
+
+	if !strings.Contains(stdout, "マニフェストから削除されたリポジトリ（1件）") {
+		t.Fatalf("stdout does not report the dropped repository:\n%s", stdout)
+	}
+
+	if _, statErr := os.Stat(filepath.Join(workspace, defaultManifestArchiveDir, "tools", "tool", ".git")); statErr != nil {
+		t.Fatalf("dropped repository was not archived: %v", statErr)
+	}
+
+	if _, statErr := os.Stat(filepath.Join(workspace, "tools", "tool")); !os.IsNotExist(statErr) {
+		t.Fatalf("dropped repository still exists at the original path: %v", statErr)
+	}
+
+	// アーカイブ先はマニフェスト外として扱わない
+	if strings.Contains(stdout, "  - "+defaultManifestArchiveDir) {
+		t.Fatalf("archive directory should not be listed as unmanaged:\n%s", stdout)
+	}
+}
+
+func TestRunRepoSync_DryRunAndFailures(t *testing.T) {
+	workspace, cloned := setupRepoSyncTest(t)
+
+	manifestPath := writeTestManifest(t, t.TempDir(), `repos:
+  - url: https://github.com/acme/api.git
+  - url: https://github.com/acme/broken.git
+`)
+
+	var err error
+
+	stdout := captureStdout(t, func() {
+		err = runRepoSync(newRepoSyncTestCommand(t, "--manifest", manifestPath, "--root", workspace, "--dry-run"), nil)
+	})
+	if err != nil {
+		t.Fatalf("runRepoSync(--dry-run) error = %v", err)
+	}
+
+	if len(*cloned) != 0 || !strings.Contains(stdout, "clone 予定: 2件") {
+		t.Fatalf("dry-run should only plan clones: cloned=%v\n%s", *cloned, stdout)
+	}
+
+	captureStderr(t, func() {
+		captureStdout(t, func() {
+			err = runRepoSync(newRepoSyncTestCommand(t, "--manifest", manifestPath, "--root", workspace), nil)
+		})
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "1 件のリポジトリを同期できませんでした") {
+		t.Fatalf("runRepoSync() error = %v, want one failure", err)
+	}
+
+	if strings.Join(*cloned, ",") != "api" {
+		t.Fatalf("cloned = %v, want the remaining entries to continue after a failure", *cloned)
+	}
+}
+
+func TestRunRepoSync_InvalidPruneDropped(t *testing.T) {
+	setupRepoSyncTest(t)
+
+	err := runRepoSync(newRepoSyncTestCommand(t, "--prune-dropped", "delete"), nil)
+	if err == nil || !strings.Contains(err.Error(), "--prune-dropped") {
+		t.Fatalf("runRepoSync() error = %v, want a --prune-dropped error", err)
+	}
+}
+
+// cloneTestRepo は bare リモートを作成して root/rel に clone し、1コミットをプッシュした状態にします。
+func cloneTestRepo(t *testing.T, root, rel string) string {
+	t.Helper()
+
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	repoPath := filepath.Join(root, filepath.FromSlash(rel))
+
+	runTestGit(t, "", "init", "-q", "--bare", remote)
+	runTestGit(t, "", "clone", "-q", remote, repoPath)
+	runTestGit(t, repoPath, "commit", "-q", "--allow-empty", "-m", "init")
+	runTestGit(t, repoPath, "push", "-q", "-u", "origin", "HEAD")
+
+	return repoPath
+}
+
+func runTestGit(t *testing.T, repoPath string, args ...string) {
+	t.Helper()
+
+	if repoPath != "" {
+		args = append([]string{"-C", repoPath}, args...)
+	}
+
+	args = append([]string{"-c", "user.name=dsx-test", "-c", "user.email=dsx-test@example.com"}, args...)
+	if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v: %s", args, err, out)
+	}
+}
+
+func TestRemoveDroppedRepos_KeepsLocalOnlyWork(t *testing.T) {
+	setupRepoSyncTest(t)
+
+	root := t.TempDir()
+
+	cloneTestRepo(t, root, "clean")
+
+	stashed := cloneTestRepo(t, root, "stashed")
+	if err := os.WriteFile(filepath.Join(stashed, "notes.txt"), []byte("x\n"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	runTestGit(t, stashed, "stash", "push", "-q", "-u")
+
+	feature := cloneTestRepo(t, root, "feature")
+	runTestGit(t, feature, "checkout", "-q", "-b", "feature")
+	runTestGit(t, feature, "commit", "-q", "--allow-empty", "-m", "local only")
+	runTestGit(t, feature, "checkout", "-q", "-")
+
+	branchOnly := cloneTestRepo(t, root, "branch-only")
+	runTestGit(t, branchOnly, "branch", "wip")
+
+	plan := &repomgr.ManifestPlan{Root: root, Dropped: []string{"branch-only", "clean", "feature", "stashed"}}
+	repoSyncYes = true
+
+	var (
+		kept    []string
+		removed int
+		err     error
+	)
+
+	stderr := captureStderr(t, func() {
+		captureStdout(t, func() {
+			kept, removed, err = removeDroppedRepos(context.Background(), plan, false)
+		})
+	})
+	if err != nil {
+		t.Fatalf("removeDroppedRepos() error = %v", err)
+	}
+
+	if removed != 1 || strings.Join(kept, ",") != "branch-only,feature,stashed" {
+		t.Fatalf("removeDroppedRepos() kept=%v removed=%d, want only clean removed", kept, removed)
+	}
+
+	if _, statErr := os.Stat(filepath.Join(root, "clean")); !os.IsNotExist(statErr) {
+		t.Fatalf("clean repository still exists: %v", statErr)
+	}
+
+	for _, want := range []string{
+		"branch-only: 安全に削除できないため残します（リモートにないブランチがあります: wip）",
+		"feature: 安全に削除できないため残します（未プッシュのコミットが 1 件あります）",
+		"stashed: 安全に削除できないため残します（stash が 1 件あります）",
+		"--prune-dropped archive を使用してください",
+	} {
+		if !strings.Contains(stderr, want) {
+			t.Fatalf("stderr does not contain %q:\n%s", want, stderr)
+		}
+	}
+}
+
+func TestResolveManifestRoot(t *testing.T) {
+	cfg := config.Default()
+	cfg.Repo.Root = "/src"
+
+	testCases := []struct {
+		name     string
+		args     []string
+		manifest repomgr.Manifest
+		roots    []config.RepoRootConfig
+		want     string
+		wantErr  bool
+	}{
+		{name: "--root が最優先", args: []string{"--root", "/override"}, manifest: repomgr.Manifest{Root: "/ws"}, want: "/override"},
+		{name: "マニフェストの絶対パス", manifest: repomgr.Manifest{Root: "/ws"}, want: "/ws"},
+		{name: "マニフェストの相対パスはマニフェストのディレクトリ基準", manifest: repomgr.Manifest{Root: "repos"}, want: filepath.Join("/team", "repos")},
+		{name: "未指定時は repo.root", want: "/src"},
+		{name: "repo.roots が複数ならエラー", roots: []config.RepoRootConfig{{Path: "/a"}, {Path: "/b"}}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			setupRepoSyncTest(t)
+
+			c := *cfg
+			c.Repo.Roots = tc.roots
+
+			got, err := resolveManifestRoot(newRepoSyncTestCommand(t, tc.args...), &c, filepath.Join("/team", "repos.yaml"), &tc.manifest)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("resolveManifestRoot() error = nil, want error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("resolveManifestRoot() error = %v", err)
+			}
+
+			if got != tc.want {
+				t.Fatalf("resolveManifestRoot() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/scottlz0310/dsx/internal/config"
 	repomgr "github.com/scottlz0310/dsx/internal/repo"
@@ -65,6 +66,66 @@ func TestResolveRepoJobs(t *testing.T) {
 	}
 }
 
+func TestResolveRepoSyncTimeout(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		timeout string
+		want    time.Duration
+	}{
+		{name: "未指定は制限なし", timeout: "", want: 0},
+		{name: "期間を指定", timeout: "2m", want: 2 * time.Minute},
+		{name: "不正な値は制限なし", timeout: "later", want: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := config.Default()
+			cfg.Repo.Sync.Timeout = tc.timeout
+
+			if got := resolveRepoSyncTimeout(cfg); got != tc.want {
+				t.Fatalf("resolveRepoSyncTimeout(%q) = %s, want %s", tc.timeout, got, tc.want)
+			}
+
+			jobs, _ := buildRepoUpdateJobs("/src", "", []string{"/src/app"}, repomgr.UpdateOptions{}, tc.want, true)
+			if len(jobs) != 1 || jobs[0].Timeout != tc.want {
+				t.Fatalf("job timeout = %v, want %s", jobs, tc.want)
+			}
+		})
+	}
+}
+
+func TestRepoDiscoverOptions(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Default()
+
+	if got := repoDiscoverOptions(cfg); got.MaxDepth != 1 || got.DescendIntoRepos {
+		t.Fatalf("repoDiscoverOptions(default) = %+v, want MaxDepth=1 DescendIntoRepos=false", got)
+	}
+
+	cfg.Repo.Discovery = config.RepoDiscoveryConfig{
+		MaxDepth:  3,
+		Include:   []string{"github.com/*/*"},
+		Exclude:   []string{"node_modules"},
+		StopAtGit: false,
+	}
+
+	want := repomgr.DiscoverOptions{
+		MaxDepth:         3,
+		Include:          []string{"github.com/*/*"},
+		Exclude:          []string{"node_modules"},
+		DescendIntoRepos: true,
+	}
+
+	if got := repoDiscoverOptions(cfg); !reflect.DeepEqual(got, want) {
+		t.Fatalf("repoDiscoverOptions() = %+v, want %+v", got, want)
+	}
+}
+
 func TestResolveRepoSubmoduleUpdate(t *testing.T) {
 	t.Parallel()
 
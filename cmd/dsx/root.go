@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/scottlz0310/dsx/internal/updater"
 	"github.com/spf13/cobra"
 )
 
@@ -28,7 +29,10 @@ var rootCmd = &cobra.Command{
 
 システム更新:
   dsx sys update    パッケージマネージャで一括更新
+  dsx sys check     更新可能なパッケージを確認（更新は行わない）
   dsx sys list      利用可能なマネージャを一覧表示
+  dsx sys history   sys update の更新履歴を表示
+  dsx sys rollback  更新履歴をもとにパッケージを更新前のバージョンへ戻す
   dsx sys discover  インストール済み Go ツールを検出し go.targets 候補を表示
 
 リポジトリ管理:
@@ -100,6 +104,10 @@ var readBuildInfoStep = debug.ReadBuildInfo
 
 func initConfig() {
 	// 設定ファイルが存在しない場合（初回実行時など）はエラーを無視して続行
-	//nolint:errcheck // 初回実行時に設定ファイルがない場合のエラーは意図的に無視する
-	config.Load()
+	if cfg, err := config.Load(); err == nil {
+		// sys.managers の type: custom を組み込みマネージャと同じく sys list / sys update / config validate の対象にする
+		if err := updater.RegisterCustomManagers(cfg.Sys.Managers); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+		}
+	}
 }
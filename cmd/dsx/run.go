@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/scottlz0310/dsx/internal/runner"
 	"github.com/scottlz0310/dsx/internal/secret"
 	"github.com/spf13/cobra"
 )
@@ -17,11 +18,13 @@ var (
 )
 
 var (
-	runDryRun  bool
-	runJobs    int
-	runTUI     bool
-	runNoTUI   bool
-	runLogFile string
+	runDryRun    bool
+	runJobs      int
+	runTUI       bool
+	runNoTUI     bool
+	runLogFile   string
+	runLogFormat string
+	runLogDir    string
 )
 
 // runCmd は日次処理を実行するコマンドの定義です
@@ -38,7 +41,7 @@ var runCmd = &cobra.Command{
   4. システム更新
   5. リポジトリ同期
 
-フラグ（--dry-run, --tui/--no-tui, --jobs）は sys update / repo update に伝播されます。`,
+フラグ（--dry-run, --tui/--no-tui, --jobs, --log-file/--log-format/--log-dir）は sys update / repo update に伝播されます。`,
 	RunE: runDaily,
 }
 
@@ -50,6 +53,8 @@ func init() {
 	runCmd.Flags().BoolVar(&runTUI, "tui", false, "Bubble Tea の進捗UIを表示（sys/repo に伝播）")
 	runCmd.Flags().BoolVar(&runNoTUI, "no-tui", false, "TUI 進捗表示を無効化（sys/repo に伝播）")
 	runCmd.Flags().StringVar(&runLogFile, "log-file", "", "ジョブ実行ログをファイルに保存（sys/repo に伝播）")
+	runCmd.Flags().StringVar(&runLogFormat, "log-format", runner.LogFormatText, "ジョブ実行ログの形式（text / jsonl、sys/repo に伝播）")
+	runCmd.Flags().StringVar(&runLogDir, "log-dir", "", "ジョブごとのコマンド出力を保存するディレクトリ（sys/repo に伝播）")
 }
 
 // propagateRunFlags は run コマンドのフラグを sys/repo のグローバルフラグ変数に伝播します。
@@ -78,6 +83,16 @@ func propagateRunFlags(cmd *cobra.Command) {
 		sysLogFile = runLogFile
 		repoUpdateLogFile = runLogFile
 	}
+
+	if cmd.Flags().Changed("log-format") {
+		sysLogFormat = runLogFormat
+		repoUpdateLogFormat = runLogFormat
+	}
+
+	if cmd.Flags().Changed("log-dir") {
+		sysLogDir = runLogDir
+		repoUpdateLogDir = runLogDir
+	}
 }
 
 func runDaily(cmd *cobra.Command, args []string) error {
@@ -96,6 +111,14 @@ func runDaily(cmd *cobra.Command, args []string) error {
 	// run のフラグを子コマンドに伝播
 	propagateRunFlags(cmd)
 
+	if _, err := runner.ParseLogFormat(runLogFormat); err != nil {
+		return err
+	}
+
+	// JSONL ログの command は sys / repo ではなく run として記録する
+	jobLogCommandOverride = "run"
+	defer func() { jobLogCommandOverride = "" }()
+
 	// --tui と --no-tui の矛盾チェック（フェーズ実行前に検出）
 	if cmd.Flags().Changed("tui") && runTUI && cmd.Flags().Changed("no-tui") && runNoTUI {
 		return fmt.Errorf("--tui と --no-tui は同時指定できません")
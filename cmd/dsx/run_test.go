@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/scottlz0310/dsx/internal/runner"
 	"github.com/scottlz0310/dsx/internal/secret"
 	"github.com/scottlz0310/dsx/internal/testutil"
 	"github.com/spf13/cobra"
@@ -363,6 +364,69 @@ func TestPropagateRunFlags(t *testing.T) {
 	}
 }
 
+func TestPropagateRunFlags_LogFormat(t *testing.T) {
+	origSysLogFormat := sysLogFormat
+	origRepoLogFormat := repoUpdateLogFormat
+
+	t.Cleanup(func() {
+		sysLogFormat = origSysLogFormat
+		repoUpdateLogFormat = origRepoLogFormat
+		jobLogCommandOverride = ""
+	})
+
+	sysLogFormat = runner.LogFormatText
+	repoUpdateLogFormat = runner.LogFormatText
+
+	cmd := &cobra.Command{Use: "run"}
+	cmd.Flags().StringVar(&runLogFormat, "log-format", runner.LogFormatText, "")
+
+	if err := cmd.Flags().Set("log-format", runner.LogFormatJSONL); err != nil {
+		t.Fatalf("Set(log-format) error = %v", err)
+	}
+
+	propagateRunFlags(cmd)
+
+	if sysLogFormat != runner.LogFormatJSONL || repoUpdateLogFormat != runner.LogFormatJSONL {
+		t.Fatalf("sysLogFormat = %q, repoUpdateLogFormat = %q, want jsonl", sysLogFormat, repoUpdateLogFormat)
+	}
+
+	if got := newJobLogOptions("/tmp/dsx.jsonl", sysLogFormat, "sys"); got.Command != "sys" {
+		t.Fatalf("Command = %q, want sys", got.Command)
+	}
+
+	jobLogCommandOverride = "run"
+
+	if got := newJobLogOptions("/tmp/dsx.jsonl", sysLogFormat, "sys"); got.Command != "run" {
+		t.Fatalf("Command = %q, want run（run 実行中は上書き）", got.Command)
+	}
+}
+
+func TestPropagateRunFlags_LogDir(t *testing.T) {
+	origSysLogDir := sysLogDir
+	origRepoLogDir := repoUpdateLogDir
+
+	t.Cleanup(func() {
+		sysLogDir = origSysLogDir
+		repoUpdateLogDir = origRepoLogDir
+	})
+
+	sysLogDir = ""
+	repoUpdateLogDir = ""
+
+	cmd := &cobra.Command{Use: "run"}
+	cmd.Flags().StringVar(&runLogDir, "log-dir", "", "")
+
+	if err := cmd.Flags().Set("log-dir", "/tmp/dsx-logs"); err != nil {
+		t.Fatalf("Set(log-dir) error = %v", err)
+	}
+
+	propagateRunFlags(cmd)
+
+	if sysLogDir != "/tmp/dsx-logs" || repoUpdateLogDir != "/tmp/dsx-logs" {
+		t.Fatalf("sysLogDir = %q, repoUpdateLogDir = %q, want /tmp/dsx-logs", sysLogDir, repoUpdateLogDir)
+	}
+}
+
 //nolint:cyclop // テスト用アサーションヘルパーのため複雑度は許容する
 func assertPropagatedFlags(t *testing.T,
 	wantSysDryRun, wantRepoDryRun bool,
@@ -7,25 +7,33 @@ import (
 	"os"
 	"os/exec"
 	"os/signal"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/scottlz0310/dsx/internal/history"
 	"github.com/scottlz0310/dsx/internal/runner"
 	"github.com/scottlz0310/dsx/internal/updater"
 	"github.com/spf13/cobra"
 )
 
 var (
-	sysDryRun  bool
-	sysVerbose bool
-	sysJobs    int
-	sysTimeout string
-	sysTUI     bool
-	sysNoTUI   bool
-	sysLogFile string
+	sysDryRun    bool
+	sysVerbose   bool
+	sysJobs      int
+	sysTimeout   string
+	sysTUI       bool
+	sysNoTUI     bool
+	sysLogFile   string
+	sysLogFormat string
+	sysLogDir    string
+	sysOutput    string
 )
 
+// sysJobOutputDir は sys update の実行中だけ設定する、ジョブ出力ログの実行ディレクトリです。
+var sysJobOutputDir string
+
 // sysCmd はシステム関連コマンドのルートです
 var sysCmd = &cobra.Command{
 	Use:   "sys",
@@ -42,6 +50,8 @@ var sysUpdateCmd = &cobra.Command{
 
 対応マネージャ:
   - apt       (Debian/Ubuntu)
+  - dnf       (Fedora/RHEL、dnf がない場合は yum)
+  - pacman    (Arch Linux、aur_helper で paru / yay)
   - brew      (macOS/Linux Homebrew)
   - go        (Go ツール)
   - npm       (Node.js グローバルパッケージ)
@@ -56,12 +66,20 @@ var sysUpdateCmd = &cobra.Command{
   - uv        (Python CLI ツール)
   - rustup    (Rust ツールチェーン)
   - gem       (Ruby Gems)
+  - nix       (nix profile、home_manager_flake で home-manager)
+  - mise      (ランタイムバージョン管理、asdf の .tool-versions にも対応)
+  - containers (docker / podman のコンテナイメージ)
+  - vscode (VS Code / Cursor の拡張機能)
+  - gh-ext (GitHub CLI 拡張機能)
+  - krew (kubectl krew プラグイン)
 
 例:
   dsx sys update           # 設定に基づいて更新
   dsx sys update --dry-run # 更新計画のみ表示
   dsx sys update -v        # 詳細ログを表示
-  dsx sys update --jobs 4  # 4並列で更新`,
+  dsx sys update --jobs 4  # 4並列で更新
+  dsx sys update --log-dir ~/.local/state/dsx/logs  # マネージャごとの出力を保存
+  dsx sys update -o json   # 結果を JSON で stdout に出力`,
 	RunE: runSysUpdate,
 }
 
@@ -85,10 +103,52 @@ func init() {
 	sysUpdateCmd.Flags().BoolVar(&sysTUI, "tui", false, "Bubble Tea の進捗UIを表示（既定値は config.yaml の ui.tui）")
 	sysUpdateCmd.Flags().BoolVar(&sysNoTUI, "no-tui", false, "TUI 進捗表示を無効化（設定より優先）")
 	sysUpdateCmd.Flags().StringVar(&sysLogFile, "log-file", "", "ジョブ実行ログをファイルに保存")
+	sysUpdateCmd.Flags().StringVar(&sysLogFormat, "log-format", runner.LogFormatText, "ジョブ実行ログの形式（text / jsonl）")
+	sysUpdateCmd.Flags().StringVar(&sysLogDir, "log-dir", "", "マネージャごとのコマンド出力を <dir>/<実行ID>/<name>.log に保存")
+	sysUpdateCmd.Flags().StringVarP(&sysOutput, "output", "o", outputFormatText, "出力形式（text / json）。json 時は人間向け出力を stderr に出力")
 }
 
 func runSysUpdate(cmd *cobra.Command, args []string) error {
-	defer printSelfUpdateNoticeAtEnd()
+	format, err := parseOutputFormat(sysOutput)
+	if err != nil {
+		return err
+	}
+
+	if _, err := runner.ParseLogFormat(sysLogFormat); err != nil {
+		return err
+	}
+
+	if format != outputFormatJSON {
+		defer printSelfUpdateNoticeAtEnd()
+
+		_, err := executeSysUpdate(cmd, false)
+
+		return err
+	}
+
+	// JSON モードでは stdout を JSON ドキュメント専用にする
+	restoreStdout := redirectStdoutToStderr()
+	report, runErr := executeSysUpdate(cmd, true)
+
+	printSelfUpdateNoticeAtEnd()
+	restoreStdout()
+
+	if err := writeJSONReport(os.Stdout, report); err != nil {
+		return err
+	}
+
+	return runErr
+}
+
+// executeSysUpdate は sys update 本体を実行し、JSON 出力用のレポートを返します。
+// forceNoTUI が true の場合は設定に関わらず TUI を使用しません。
+func executeSysUpdate(cmd *cobra.Command, forceNoTUI bool) (sysUpdateReport, error) {
+	startedAt := time.Now()
+	// --log-dir のディレクトリ名と履歴の実行 ID を一致させる
+	runID := history.NewRunID(startedAt)
+
+	sysJobOutputDir = resolveJobOutputDir(sysLogDir, runID)
+	defer func() { sysJobOutputDir = "" }()
 
 	// 設定の読み込み
 	cfg, opts := loadSysUpdateConfig(cmd)
@@ -98,6 +158,8 @@ func runSysUpdate(cmd *cobra.Command, args []string) error {
 	defer cancel()
 
 	// 有効なマネージャを取得
+	loadUpdaterPlugins()
+
 	enabledUpdaters, err := updater.GetEnabled(&cfg.Sys)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
@@ -107,7 +169,11 @@ func runSysUpdate(cmd *cobra.Command, args []string) error {
 
 	tuiReq, err := resolveTUIRequest(cfg.UI.TUI, cmd.Flags().Changed("tui"), sysTUI, cmd.Flags().Changed("no-tui"), sysNoTUI)
 	if err != nil {
-		return err
+		return buildSysUpdateReport(updateStats{}, nil, opts.DryRun), err
+	}
+
+	if forceNoTUI {
+		tuiReq = tuiRequest{Requested: false, Source: tuiSourceFlag}
 	}
 
 	useTUI, warning := resolveTUIEnabled(tuiReq)
@@ -118,7 +184,7 @@ func runSysUpdate(cmd *cobra.Command, args []string) error {
 		printNoTargetTUIMessage(tuiReq, "sys update")
 		printNoManagerHelp()
 
-		return nil
+		return buildSysUpdateReport(updateStats{}, nil, opts.DryRun), nil
 	}
 
 	// TUI 使用時は開始メッセージを抑制（TUI が画面を制御するため）
@@ -134,8 +200,11 @@ func runSysUpdate(cmd *cobra.Command, args []string) error {
 	jobs := resolveSysJobs(cfg.Control.Concurrency, sysJobs)
 
 	stats, err := runSysUpdatePhases(ctx, cfg, opts, enabledUpdaters, jobs, useTUI)
+	report := buildSysUpdateReport(stats, enabledUpdaters, opts.DryRun)
+	recordSysUpdateHistory(&report, runID, startedAt, time.Now())
+
 	if err != nil {
-		return err
+		return report, err
 	}
 
 	// TUI 使用時は TUI 側で完了サマリーを表示済みのため、テキストサマリーは非 TUI 時のみ出力
@@ -147,7 +216,7 @@ func runSysUpdate(cmd *cobra.Command, args []string) error {
 	printFailedErrors(stats.Errors)
 
 	if len(stats.Errors) > 0 {
-		return fmt.Errorf("%d 件のエラーが発生しました", len(stats.Errors))
+		return report, fmt.Errorf("%d 件のエラーが発生しました", len(stats.Errors))
 	}
 
 	if !useTUI {
@@ -155,33 +224,55 @@ func runSysUpdate(cmd *cobra.Command, args []string) error {
 		fmt.Println("✅ システムパッケージの更新が完了しました")
 	}
 
-	return nil
+	return report, nil
 }
 
-// runSysUpdatePhases はマネージャ本体更新・単独実行・並列実行の各フェーズを実行します。
+// runSysUpdatePhases はマネージャ本体更新フェーズの後、依存関係と単独実行の制約を反映した
+// 1つのジョブグラフで各マネージャを更新します。
 func runSysUpdatePhases(ctx context.Context, cfg *config.Config, opts updater.UpdateOptions, enabledUpdaters []updater.Updater, jobs int, useTUI bool) (updateStats, error) {
 	var stats updateStats
 
 	remainingUpdaters, selfUpdateStats := runManagerSelfUpdatePhase(ctx, opts, enabledUpdaters, useTUI)
 	mergeUpdateStats(&stats, selfUpdateStats)
 
-	exclusiveUpdaters, parallelUpdaters := splitUpdatersForExecution(remainingUpdaters)
+	if len(remainingUpdaters) == 0 {
+		return stats, nil
+	}
 
-	if len(exclusiveUpdaters) > 0 {
-		if err := runExclusivePhase(ctx, cfg, opts, exclusiveUpdaters, useTUI, &stats); err != nil {
+	if phaseRequiresSudo(remainingUpdaters, cfg.Sys.Managers) {
+		if err := ensureSudoAuthentication(ctx, "sys update", useTUI); err != nil {
 			return stats, err
 		}
-	}
 
-	if len(parallelUpdaters) > 0 {
-		if err := runParallelPhase(ctx, cfg, opts, parallelUpdaters, jobs, useTUI, &stats); err != nil {
-			return stats, err
+		if !useTUI {
+			fmt.Println()
 		}
 	}
 
+	if !useTUI {
+		printSysUpdateExecutionNotice(remainingUpdaters, jobs)
+	}
+
+	mergeUpdateStats(&stats, executeUpdateGraph(ctx, remainingUpdaters, opts, cfg.Sys.Managers, jobs, useTUI))
+
 	return stats, nil
 }
 
+// printSysUpdateExecutionNotice は単独実行するマネージャと並列数を表示します。
+func printSysUpdateExecutionNotice(updaters []updater.Updater, jobs int) {
+	if exclusive, _ := splitUpdatersForExecution(updaters); len(exclusive) > 0 {
+		fmt.Printf("🔒 依存関係の都合で単独実行するマネージャがあります（%s）。\n", strings.Join(updaterNames(exclusive), ", "))
+		fmt.Println()
+	}
+
+	if jobs > 1 {
+		fmt.Printf("⚡ %d 並列で更新します。\n", jobs)
+		fmt.Println()
+	}
+}
+
+const selfUpdateJobSuffix = "-self-update"
+
 type managerSelfUpdateTarget struct {
 	updater updater.Updater
 	self    updater.ManagerSelfUpdater
@@ -216,9 +307,12 @@ func runManagerSelfUpdatePhase(ctx context.Context, opts updater.UpdateOptions,
 
 		printManagerSelfUpdateHeader(target.updater)
 
+		startedAt := time.Now()
+
 		result, err := executeManagerSelfUpdate(ctx, target.self, opts)
 		if err != nil {
 			recordManagerSelfUpdateError(target.updater, err, &stats)
+			setLastReportDuration(stats.SelfUpdates, startedAt)
 			fmt.Fprintf(os.Stderr, "❌ エラー: %v\n", err)
 			fmt.Println()
 
@@ -229,6 +323,8 @@ func runManagerSelfUpdatePhase(ctx context.Context, opts updater.UpdateOptions,
 		fmt.Println()
 
 		mergeSelfUpdateResult(&stats, result)
+		recordSelfUpdateReport(&stats, target.updater, result)
+		setLastReportDuration(stats.SelfUpdates, startedAt)
 
 		if !result.ShouldContinueNormalUpdate() {
 			skipNormalUpdate[target.updater.Name()] = true
@@ -250,7 +346,7 @@ func runManagerSelfUpdatePhaseWithTUI(ctx context.Context, opts updater.UpdateOp
 		target := item
 
 		jobs = append(jobs, runner.Job{
-			Name: target.updater.Name() + "-self-update",
+			Name: target.updater.Name() + selfUpdateJobSuffix,
 			Run: func(jobCtx context.Context) error {
 				result, err := executeManagerSelfUpdate(jobCtx, target.self, opts)
 				if err != nil {
@@ -267,6 +363,7 @@ func runManagerSelfUpdatePhaseWithTUI(ctx context.Context, opts updater.UpdateOp
 
 				statsMu.Lock()
 				mergeSelfUpdateResult(&stats, result)
+				recordSelfUpdateReport(&stats, target.updater, result)
 
 				if !result.ShouldContinueNormalUpdate() {
 					skipNormalUpdate[target.updater.Name()] = true
@@ -278,9 +375,12 @@ func runManagerSelfUpdatePhaseWithTUI(ctx context.Context, opts updater.UpdateOp
 		})
 	}
 
-	summary := runManagerSelfUpdateJobs(ctx, "マネージャ本体更新 進捗", 1, jobs, true, sysLogFile)
+	summary := runManagerSelfUpdateJobs(ctx, "マネージャ本体更新 進捗", 1, jobs, true, sysJobLogOptions())
+	applySummaryJobResults(stats.SelfUpdates, summary, selfUpdateJobSuffix)
+
 	if summary.Skipped > 0 {
 		stats.Errors = append(stats.Errors, fmt.Errorf("キャンセルまたはタイムアウトによりマネージャ本体更新 %d 件をスキップしました", summary.Skipped))
+		stats.SelfUpdates = append(stats.SelfUpdates, collectSkippedManagerReports(summary, updaters, selfUpdateJobSuffix)...)
 	}
 
 	return filterNormalUpdateUpdaters(updaters, skipNormalUpdate), stats
@@ -349,6 +449,22 @@ func buildDryRunSelfUpdateResult(checkResult *updater.CheckResult) *updater.Self
 	return result
 }
 
+// recordSelfUpdateReport はマネージャ本体更新の結果を JSON 出力用に記録します。
+func recordSelfUpdateReport(stats *updateStats, u updater.Updater, result *updater.SelfUpdateResult) {
+	if result == nil {
+		return
+	}
+
+	report := newManagerReport(u, &result.UpdateResult, nil)
+	report.Continuation = string(result.Continuation)
+
+	if report.Continuation == "" {
+		report.Continuation = string(updater.ContinueNormalUpdate)
+	}
+
+	stats.SelfUpdates = append(stats.SelfUpdates, report)
+}
+
 func mergeSelfUpdateResult(stats *updateStats, result *updater.SelfUpdateResult) {
 	if result == nil {
 		return
@@ -362,6 +478,7 @@ func mergeSelfUpdateResult(stats *updateStats, result *updater.SelfUpdateResult)
 func recordManagerSelfUpdateError(u updater.Updater, err error, stats *updateStats) {
 	stats.Errors = append(stats.Errors, fmt.Errorf("%s 本体更新: %w", u.Name(), err))
 	stats.Failed++
+	stats.SelfUpdates = append(stats.SelfUpdates, newManagerReport(u, nil, err))
 }
 
 func filterNormalUpdateUpdaters(updaters []updater.Updater, skip map[string]bool) []updater.Updater {
@@ -381,47 +498,6 @@ func filterNormalUpdateUpdaters(updaters []updater.Updater, skip map[string]bool
 	return filtered
 }
 
-func runExclusivePhase(ctx context.Context, cfg *config.Config, opts updater.UpdateOptions, updaters []updater.Updater, useTUI bool, stats *updateStats) error {
-	if phaseRequiresSudo(updaters, cfg.Sys.Managers) {
-		if err := ensureSudoAuthentication(ctx, "単独実行フェーズ", useTUI); err != nil {
-			return err
-		}
-
-		if !useTUI {
-			fmt.Println()
-		}
-	}
-
-	if !useTUI {
-		fmt.Println("🔒 依存関係の都合で単独実行するマネージャがあります（apt）。")
-		fmt.Println()
-	}
-
-	if useTUI {
-		mergeUpdateStats(stats, executeUpdatesParallel(ctx, updaters, opts, 1, true))
-	} else {
-		mergeUpdateStats(stats, executeUpdates(ctx, updaters, opts))
-	}
-
-	return nil
-}
-
-func runParallelPhase(ctx context.Context, cfg *config.Config, opts updater.UpdateOptions, updaters []updater.Updater, jobs int, useTUI bool, stats *updateStats) error {
-	if phaseRequiresSudo(updaters, cfg.Sys.Managers) {
-		if err := ensureSudoAuthentication(ctx, "並列実行フェーズ", useTUI); err != nil {
-			return err
-		}
-
-		if !useTUI {
-			fmt.Println()
-		}
-	}
-
-	mergeUpdateStats(stats, executeParallelUpdaters(ctx, updaters, opts, jobs, useTUI))
-
-	return nil
-}
-
 func printSysUpdateDryRunNotice(dryRun bool) {
 	if !dryRun {
 		return
@@ -447,6 +523,9 @@ type updateStats struct {
 	Updated int
 	Failed  int
 	Errors  []error
+	// SelfUpdates / Managers は --output json 用のマネージャ別結果です。
+	SelfUpdates []managerReport
+	Managers    []managerReport
 }
 
 // loadSysUpdateConfig は設定とオプションを読み込みます。
@@ -509,104 +588,109 @@ func printNoManagerHelp() {
 	fmt.Println("   例: enable: [\"apt\", \"go\"]")
 }
 
-// executeUpdates は各マネージャで更新を実行し、統計を返します。
-func executeUpdates(ctx context.Context, updaters []updater.Updater, opts updater.UpdateOptions) updateStats {
-	var stats updateStats
-
-	for _, u := range updaters {
-		select {
-		case <-ctx.Done():
-			stats.Errors = append(stats.Errors, fmt.Errorf("タイムアウトまたはキャンセルされました"))
-
-			return stats
-		default:
-		}
-
-		printUpdaterHeader(u)
-
-		result, err := u.Update(ctx, opts)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "❌ エラー: %v\n", err)
-			stats.Errors = append(stats.Errors, fmt.Errorf("%s: %w", u.Name(), err))
-			stats.Failed++
-
-			continue
-		}
+// executeUpdateGraph はマネージャ更新を依存関係つきのジョブグラフとして runner で実行し、統計を返します。
+// 依存先が失敗したマネージャは runner が ErrDependencyFailed を理由にスキップします。
+// 再試行で同じマネージャが複数回実行されるため、結果は runner の最終結果をもとに実行後にまとめて記録します。
+func executeUpdateGraph(ctx context.Context, updaters []updater.Updater, opts updater.UpdateOptions, managers map[string]config.ManagerConfig, jobs int, useTUI bool) updateStats {
+	var (
+		stats    updateStats
+		outputMu sync.Mutex
+	)
 
-		printUpdaterResult(result)
-		stats.Updated += result.UpdatedCount
-		stats.Failed += result.FailedCount
-		stats.Errors = append(stats.Errors, result.Errors...)
+	graph := planSysUpdateGraph(updaters, managers)
+	results := make([]*updater.UpdateResult, len(graph.updaters))
+	execJobs := buildUpdaterJobs(graph, opts, managers, useTUI, results, &outputMu)
+	summary := runJobsWithOptionalTUI(ctx, "sys update 進捗", jobs, execJobs, useTUI, sysJobLogOptions())
+	mergeUpdaterJobResults(&stats, graph.updaters, results, summary)
+	applySummaryJobResults(stats.Managers, summary, "")
 
-		fmt.Println()
+	if interrupted := countInterruptedJobs(summary); interrupted > 0 {
+		stats.Errors = append(stats.Errors, fmt.Errorf("キャンセルまたはタイムアウトにより %d 件をスキップしました", interrupted))
 	}
 
+	stats.Managers = append(stats.Managers, collectSkippedManagerReports(summary, graph.updaters, "")...)
+
 	return stats
 }
 
-func executeParallelUpdaters(ctx context.Context, updaters []updater.Updater, opts updater.UpdateOptions, jobs int, useTUI bool) updateStats {
-	switch {
-	case useTUI:
-		parallelJobs := jobs
-		if parallelJobs <= 0 {
-			parallelJobs = 1
-		}
+// countInterruptedJobs はキャンセルまたはタイムアウトでスキップされたジョブ数を返します。
+// 依存先の失敗によるスキップは依存先のエラーとして計上済みのため含めません。
+func countInterruptedJobs(summary runner.Summary) int {
+	count := 0
 
-		return executeUpdatesParallel(ctx, updaters, opts, parallelJobs, true)
-	case jobs > 1:
-		fmt.Printf("⚡ %d 並列で更新します。\n", jobs)
-		fmt.Println()
-		return executeUpdatesParallel(ctx, updaters, opts, jobs, false)
-	default:
-		return executeUpdates(ctx, updaters, opts)
+	for _, r := range summary.Results {
+		if r.Status == runner.StatusSkipped && !errors.Is(r.Err, runner.ErrDependencyFailed) {
+			count++
+		}
 	}
+
+	return count
 }
 
-// executeUpdatesParallel はマネージャ更新を並列実行し、統計を返します。
-func executeUpdatesParallel(ctx context.Context, updaters []updater.Updater, opts updater.UpdateOptions, jobs int, useTUI bool) updateStats {
-	var (
-		stats    updateStats
-		statsMu  sync.Mutex
-		outputMu sync.Mutex
-	)
+// buildUpdaterJobs はマネージャごとの runner.Job を graph の順に作り、依存関係を DependsOn / After に設定します。
+// 各試行の UpdateResult は results の同じ添字に上書きで保存します。
+func buildUpdaterJobs(graph sysUpdateGraph, opts updater.UpdateOptions, managers map[string]config.ManagerConfig, useTUI bool, results []*updater.UpdateResult, outputMu *sync.Mutex) []runner.Job {
+	execJobs := make([]runner.Job, 0, len(graph.updaters))
+
+	for index, updaterItem := range graph.updaters {
+		i := index
+		u := updaterItem
 
-	execJobs := buildUpdaterJobs(updaters, opts, useTUI, &stats, &statsMu, &outputMu)
-	summary := runJobsWithOptionalTUI(ctx, "sys update 進捗", jobs, execJobs, useTUI, sysLogFile)
+		job := newUpdaterJob(u, managers, func(jobCtx context.Context) error {
+			return runUpdaterJob(jobCtx, u, opts, useTUI, &results[i], outputMu)
+		})
+		job.DependsOn = graph.dependsOn[u.Name()]
+		job.After = graph.after[u.Name()]
 
-	if summary.Skipped > 0 {
-		stats.Errors = append(stats.Errors, fmt.Errorf("キャンセルまたはタイムアウトにより %d 件をスキップしました", summary.Skipped))
+		execJobs = append(execJobs, job)
 	}
 
-	return stats
+	return execJobs
 }
 
-func buildUpdaterJobs(updaters []updater.Updater, opts updater.UpdateOptions, useTUI bool, stats *updateStats, statsMu, outputMu *sync.Mutex) []runner.Job {
-	execJobs := make([]runner.Job, 0, len(updaters))
+func sysJobLogOptions() jobLogOptions {
+	logOpts := newJobLogOptions(sysLogFile, sysLogFormat, "sys")
+	logOpts.OutputDir = sysJobOutputDir
 
-	for _, updaterItem := range updaters {
-		u := updaterItem
+	return logOpts
+}
 
-		execJobs = append(execJobs, runner.Job{
-			Name: u.Name(),
-			Run: func(jobCtx context.Context) error {
-				return runUpdaterJob(jobCtx, u, opts, useTUI, stats, statsMu, outputMu)
-			},
-		})
-	}
+// sysRetryBackoff は sys.managers.<name>.retries で再試行するまでの待機時間です（テストで差し替え）。
+var sysRetryBackoff = runner.ExponentialBackoff(5*time.Second, time.Minute)
 
-	return execJobs
+// newUpdaterJob は sys.managers.<name>.timeout / retries を反映した runner.Job を作ります。
+// 設定の誤りは config validate で報告するため、読めない値は未指定として扱います。
+func newUpdaterJob(u updater.Updater, managers map[string]config.ManagerConfig, run func(context.Context) error) runner.Job {
+	managerCfg := managers[u.Name()]
+	timeout, _ := managerCfg.Timeout()
+	retries, _ := managerCfg.Retries()
+
+	return runner.Job{
+		Name:       u.Name(),
+		Run:        run,
+		Timeout:    timeout,
+		Retry:      runner.RetryPolicy{Attempts: retries + 1, Backoff: sysRetryBackoff},
+		OutputPath: jobOutputPath(sysJobOutputDir, u.Name()),
+	}
 }
 
-func runUpdaterJob(jobCtx context.Context, u updater.Updater, opts updater.UpdateOptions, useTUI bool, stats *updateStats, statsMu, outputMu *sync.Mutex) error {
+func runUpdaterJob(jobCtx context.Context, u updater.Updater, opts updater.UpdateOptions, useTUI bool, result **updater.UpdateResult, outputMu *sync.Mutex) error {
 	printUpdaterHeaderIfNeeded(u, useTUI, outputMu)
 
-	result, err := u.Update(jobCtx, opts)
+	updateResult, err := u.Update(jobCtx, opts)
+	*result = updateResult
+
 	if err != nil {
-		return handleUpdaterError(u, err, useTUI, stats, statsMu, outputMu)
+		if !useTUI && !isContextCancellation(err) {
+			outputMu.Lock()
+			fmt.Fprintf(os.Stderr, "❌ エラー: %v\n", err)
+			outputMu.Unlock()
+		}
+
+		return err
 	}
 
-	printUpdaterResultIfNeeded(result, useTUI, outputMu)
-	mergeUpdaterResult(stats, statsMu, result)
+	printUpdaterResultIfNeeded(updateResult, useTUI, outputMu)
 
 	return nil
 }
@@ -621,27 +705,6 @@ func printUpdaterHeaderIfNeeded(u updater.Updater, useTUI bool, outputMu *sync.M
 	outputMu.Unlock()
 }
 
-func handleUpdaterError(u updater.Updater, err error, useTUI bool, stats *updateStats, statsMu, outputMu *sync.Mutex) error {
-	if isContextCancellation(err) {
-		return err
-	}
-
-	if !useTUI {
-		outputMu.Lock()
-		fmt.Fprintf(os.Stderr, "❌ エラー: %v\n", err)
-		outputMu.Unlock()
-	}
-
-	statsMu.Lock()
-
-	stats.Errors = append(stats.Errors, fmt.Errorf("%s: %w", u.Name(), err))
-	stats.Failed++
-
-	statsMu.Unlock()
-
-	return err
-}
-
 func printUpdaterResultIfNeeded(result *updater.UpdateResult, useTUI bool, outputMu *sync.Mutex) {
 	if useTUI {
 		return
@@ -653,14 +716,46 @@ func printUpdaterResultIfNeeded(result *updater.UpdateResult, useTUI bool, outpu
 	outputMu.Unlock()
 }
 
-func mergeUpdaterResult(stats *updateStats, statsMu *sync.Mutex, result *updater.UpdateResult) {
-	statsMu.Lock()
+// mergeUpdaterJobResults は runner の最終結果をもとにマネージャ別の結果を統計へ反映します。
+// スキップされたジョブは collectSkippedManagerReports で別途レポート化します。
+func mergeUpdaterJobResults(stats *updateStats, updaters []updater.Updater, results []*updater.UpdateResult, summary runner.Summary) {
+	for i, u := range updaters {
+		if i >= len(summary.Results) {
+			break
+		}
+
+		switch job := summary.Results[i]; job.Status {
+		case runner.StatusSuccess:
+			mergeUpdaterResult(u, stats, results[i])
+		case runner.StatusFailed:
+			stats.Errors = append(stats.Errors, jobErrorWithLogPath(u.Name(), job.Err, job.LogPath))
+			stats.Failed++
+			stats.Managers = append(stats.Managers, newManagerReport(u, results[i], job.Err))
+		case runner.StatusSkipped:
+			// collectSkippedManagerReports で扱う
+		}
+	}
+}
+
+func mergeUpdaterResult(u updater.Updater, stats *updateStats, result *updater.UpdateResult) {
+	if result == nil {
+		result = &updater.UpdateResult{}
+	}
 
 	stats.Updated += result.UpdatedCount
 	stats.Failed += result.FailedCount
 	stats.Errors = append(stats.Errors, result.Errors...)
+	stats.Managers = append(stats.Managers, newManagerReport(u, result, nil))
+}
+
+// setLastReportDuration は直前に追加したレポートへ startedAt からの経過時間を設定します。
+// runner を使わない逐次実行の経路で使用します。
+func setLastReportDuration(reports []managerReport, startedAt time.Time) {
+	if len(reports) == 0 {
+		return
+	}
 
-	statsMu.Unlock()
+	reports[len(reports)-1].DurationMs = time.Since(startedAt).Milliseconds()
 }
 
 func resolveSysJobs(configJobs, flagJobs int) int {
@@ -711,18 +806,33 @@ func isContextCancellation(err error) bool {
 }
 
 func mustRunExclusively(u updater.Updater) bool {
+	if constraints, ok := u.(updater.ExecutionConstraints); ok {
+		return constraints.RunsExclusively()
+	}
+
 	// Bun 本体が Homebrew / Scoop 管理の場合、所有元の更新と bun update -g の競合を避けます。
-	return u.Name() == "apt" || u.Name() == "bun"
+	return u.Name() == "apt" || u.Name() == "dnf" || u.Name() == "pacman" || u.Name() == "bun"
 }
 
 func mergeUpdateStats(dst *updateStats, src updateStats) {
 	dst.Updated += src.Updated
 	dst.Failed += src.Failed
 	dst.Errors = append(dst.Errors, src.Errors...)
+	dst.SelfUpdates = append(dst.SelfUpdates, src.SelfUpdates...)
+	dst.Managers = append(dst.Managers, src.Managers...)
 }
 
 func phaseRequiresSudo(updaters []updater.Updater, managers map[string]config.ManagerConfig) bool {
 	for _, u := range updaters {
+		// カスタムマネージャなど、sudo の要否を自身で宣言するマネージャはその宣言に従う
+		if constraints, ok := u.(updater.ExecutionConstraints); ok {
+			if constraints.RequiresSudo() {
+				return true
+			}
+
+			continue
+		}
+
 		if updaterRequiresSudo(u.Name(), managers) {
 			return true
 		}
@@ -745,7 +855,7 @@ func updaterRequiresSudo(name string, managers map[string]config.ManagerConfig)
 }
 
 func isSudoManagedUpdater(name string) bool {
-	return name == "apt" || name == "snap"
+	return name == "apt" || name == "dnf" || name == "pacman" || name == "snap"
 }
 
 func resolveManagerUseSudo(name string, managers map[string]config.ManagerConfig) (useSudo, configured bool) {
@@ -815,6 +925,10 @@ func printUpdaterResult(result *updater.UpdateResult) {
 		}
 	}
 
+	if len(result.Held) > 0 {
+		fmt.Printf("  ⏸️  保留 (hold): %s\n", joinPackageNames(result.Held))
+	}
+
 	if len(result.Errors) > 0 {
 		for _, e := range result.Errors {
 			fmt.Fprintf(os.Stderr, "  ⚠️  %v\n", e)
@@ -822,6 +936,15 @@ func printUpdaterResult(result *updater.UpdateResult) {
 	}
 }
 
+func joinPackageNames(packages []updater.PackageInfo) string {
+	names := make([]string, 0, len(packages))
+	for _, pkg := range packages {
+		names = append(names, pkg.Name)
+	}
+
+	return strings.Join(names, ", ")
+}
+
 // printUpdateSummary は更新サマリーを表示します。
 func printUpdateSummary(stats updateStats) {
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
@@ -854,6 +977,8 @@ func runSysList(cmd *cobra.Command, args []string) error {
 	}
 
 	// 登録されている全マネージャを表示
+	loadUpdaterPlugins()
+
 	allUpdaters := updater.All()
 	if len(allUpdaters) == 0 {
 		fmt.Println("  (登録されているマネージャがありません)")
@@ -863,10 +988,19 @@ func runSysList(cmd *cobra.Command, args []string) error {
 	fmt.Println("名前       | 表示名                    | 利用可能 | 有効")
 	fmt.Println("-----------|---------------------------|----------|------")
 
+	var reasons []string
+
 	for _, u := range allUpdaters {
+		// 使用する CLI を設定で切り替えるマネージャがあるため、利用可否の判定前に設定を適用（一覧表示のみのため設定エラーは無視）
+		if managerCfg, ok := cfg.Sys.Managers[u.Name()]; ok {
+			_ = u.Configure(managerCfg)
+		}
+
 		available := "❌"
 		if u.IsAvailable() {
 			available = "✅"
+		} else if label := updater.UnavailableLabel(u); label != u.Name() {
+			reasons = append(reasons, label)
 		}
 
 		enabled := enabledMark(enabledSet[u.Name()])
@@ -875,6 +1009,15 @@ func runSysList(cmd *cobra.Command, args []string) error {
 			u.Name(), u.DisplayName(), available, enabled)
 	}
 
+	if len(reasons) > 0 {
+		fmt.Println()
+		fmt.Println("利用できない理由:")
+
+		for _, reason := range reasons {
+			fmt.Printf("  - %s\n", reason)
+		}
+	}
+
 	fmt.Println()
 	fmt.Println("💡 マネージャを有効化するには config.yaml の sys.enable を編集してください。")
 
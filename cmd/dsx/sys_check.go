@@ -0,0 +1,445 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/scottlz0310/dsx/internal/runner"
+	"github.com/scottlz0310/dsx/internal/updater"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sysCheckJobs   int
+	sysCheckOutput string
+)
+
+// errSysCheckPending は更新待ちパッケージがあることを示すエラーです。
+// cron やログインフックで終了コードを判定できるよう、非ゼロ終了させるために返します。
+var errSysCheckPending = errors.New("更新待ちのパッケージがあります")
+
+// sysCheckCmd は更新可能なパッケージを読み取り専用で確認するコマンドです
+var sysCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "更新可能なパッケージを確認します（更新は行いません）",
+	Long: `設定ファイルで有効化されたパッケージマネージャの Check を並列実行し、
+更新可能なパッケージをマネージャ横断の一覧で表示します。
+マネージャ本体更新に対応するマネージャは本体の更新可否も確認します。
+
+更新待ちのパッケージがある場合、または確認に失敗した場合は非ゼロで終了します。
+Check で sudo を使うマネージャ（apt）は、パスワードを求めない sudo -n で認証を確認し、
+認証済みでない場合はそのマネージャのみ確認失敗として扱います（cron 等の TTY のない環境向け）。
+
+例:
+  dsx sys check           # 更新可能なパッケージを一覧表示
+  dsx sys check -j 4      # 4並列で確認
+  dsx sys check -o json   # 結果を JSON で出力`,
+	RunE: runSysCheck,
+}
+
+func init() {
+	sysCmd.AddCommand(sysCheckCmd)
+
+	sysCheckCmd.Flags().IntVarP(&sysCheckJobs, "jobs", "j", 0, "並列実行数（0以下の場合は設定値または1を使用）")
+	sysCheckCmd.Flags().StringVarP(&sysTimeout, "timeout", "t", "10m", "全体のタイムアウト時間")
+	sysCheckCmd.Flags().StringVarP(&sysCheckOutput, "output", "o", outputFormatText, "出力形式（text / json）")
+}
+
+// sysCheckEntry は1回の Check / CheckSelfUpdate の結果です。
+type sysCheckEntry struct {
+	Updater    updater.Updater
+	SelfUpdate bool
+	Result     *updater.CheckResult
+	Err        error
+}
+
+// sysCheckEntryReport は sysCheckEntry の JSON 表現です。
+type sysCheckEntryReport struct {
+	Name             string          `json:"name"`
+	DisplayName      string          `json:"display_name"`
+	SelfUpdate       bool            `json:"self_update"`
+	AvailableUpdates int             `json:"available_updates"`
+	Packages         []packageReport `json:"packages"`
+	Held             []packageReport `json:"held"`
+	Message          string          `json:"message,omitempty"`
+	Error            string          `json:"error,omitempty"`
+}
+
+// sysCheckReport は sys check --output json で出力するドキュメントです。
+type sysCheckReport struct {
+	Version  string                `json:"version"`
+	Pending  int                   `json:"pending"`
+	Failed   int                   `json:"failed"`
+	Managers []sysCheckEntryReport `json:"managers"`
+}
+
+// sysCheckExecuteStep はテストで差し替えるための実行関数です。
+var sysCheckExecuteStep = runner.ExecuteWithEvents
+
+// sysCheckSudoStep はテストで差し替えるための sudo 認証の確認関数です。
+// sys check は cron などの TTY のない環境でも使うため、パスワードを求めない sudo -n で確認します。
+var sysCheckSudoStep = func(ctx context.Context) error {
+	return exec.CommandContext(ctx, "sudo", "-n", "-v").Run()
+}
+
+func runSysCheck(cmd *cobra.Command, args []string) error {
+	format, err := parseOutputFormat(sysCheckOutput)
+	if err != nil {
+		return err
+	}
+
+	// JSON モードでは Check が出力するコマンドログも含めて stdout から退避する
+	stdout := os.Stdout
+
+	if format == outputFormatJSON {
+		defer redirectStdoutToStderr()()
+	}
+
+	cfg, _ := loadSysUpdateConfig(cmd)
+
+	ctx, cancel := setupContext()
+	defer cancel()
+
+	loadUpdaterPlugins()
+
+	enabledUpdaters, err := updater.GetEnabled(&cfg.Sys)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+	}
+
+	configureUpdaterRuntimeVersion(enabledUpdaters, version)
+
+	if len(enabledUpdaters) == 0 {
+		if format == outputFormatJSON {
+			return writeJSONReport(stdout, buildSysCheckReport(nil))
+		}
+
+		printNoManagerHelp()
+
+		return nil
+	}
+
+	sudoErrs := preauthenticateSysCheck(ctx, enabledUpdaters, cfg.Sys.Managers)
+
+	entries := collectSysChecks(ctx, enabledUpdaters, resolveSysJobs(cfg.Control.Concurrency, sysCheckJobs), sudoErrs, os.Stdout)
+	pending, failed := countSysCheckEntries(entries)
+
+	if format == outputFormatJSON {
+		if err := writeJSONReport(stdout, buildSysCheckReport(entries)); err != nil {
+			return err
+		}
+	} else if err := writeSysCheckReport(os.Stdout, entries); err != nil {
+		return fmt.Errorf("一覧表示に失敗: %w", err)
+	}
+
+	return resolveSysCheckError(pending, failed)
+}
+
+// checkUsesSudo は Check 自体が sudo を使うマネージャかを返します（apt は Check で apt update を実行します）。
+// snap / dnf / pacman などは Update でのみ sudo を使うため、sys check では認証しません。
+func checkUsesSudo(name string, managers map[string]config.ManagerConfig) bool {
+	return name == "apt" && updaterRequiresSudo(name, managers)
+}
+
+// preauthenticateSysCheck は Check で sudo を使うマネージャがある場合に、対話なしで sudo の認証を確認します。
+// 認証できない場合は、そのマネージャ名ごとの確認失敗の理由を返します（他のマネージャの確認は続けます）。
+func preauthenticateSysCheck(ctx context.Context, updaters []updater.Updater, managers map[string]config.ManagerConfig) map[string]error {
+	var names []string
+
+	for _, u := range updaters {
+		if checkUsesSudo(u.Name(), managers) {
+			names = append(names, u.Name())
+		}
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	err := sysCheckSudoStep(ctx)
+	if err == nil {
+		return nil
+	}
+
+	errs := make(map[string]error, len(names))
+	for _, name := range names {
+		errs[name] = fmt.Errorf("sudo の認証が必要なため確認できません（事前に sudo -v を実行するか use_sudo: false を設定してください）: %w", err)
+	}
+
+	return errs
+}
+
+// collectSysChecks は各マネージャの Check / CheckSelfUpdate を並列実行します。
+// sudoErrs に含まれるマネージャは Check を実行せず、その理由で確認失敗とします。
+// 結果はジョブ投入順（sys.enable 順、本体確認が先）に並びます。
+func collectSysChecks(ctx context.Context, updaters []updater.Updater, jobs int, sudoErrs map[string]error, progress io.Writer) []sysCheckEntry {
+	entries := make([]sysCheckEntry, 0, len(updaters)*2)
+
+	for _, u := range updaters {
+		if _, ok := u.(updater.ManagerSelfUpdater); ok {
+			entries = append(entries, sysCheckEntry{Updater: u, SelfUpdate: true})
+		}
+
+		entries = append(entries, sysCheckEntry{Updater: u})
+	}
+
+	execJobs := make([]runner.Job, 0, len(entries))
+
+	for i := range entries {
+		entry := &entries[i]
+
+		name := entry.Updater.Name()
+		if entry.SelfUpdate {
+			name += selfUpdateJobSuffix
+		}
+
+		execJobs = append(execJobs, runner.Job{
+			Name: name,
+			Run: func(jobCtx context.Context) error {
+				if err := sudoErrs[entry.Updater.Name()]; err != nil && !entry.SelfUpdate {
+					entry.Err = err
+
+					return err
+				}
+
+				entry.Result, entry.Err = runSysCheckEntry(jobCtx, entry)
+
+				return entry.Err
+			},
+		})
+	}
+
+	summary := sysCheckExecuteStep(ctx, jobs, execJobs, func(event runner.Event) {
+		if event.Type == runner.EventStarted {
+			fmt.Fprintf(progress, "🔍 %s を確認中...\n", event.JobName)
+		}
+	})
+
+	// キャンセル等で Run が呼ばれなかったジョブはエラーとして扱う
+	for i, result := range summary.Results {
+		if i < len(entries) && result.Status == runner.StatusSkipped && entries[i].Err == nil {
+			entries[i].Err = result.Err
+		}
+	}
+
+	fmt.Fprintln(progress)
+
+	return entries
+}
+
+func runSysCheckEntry(ctx context.Context, entry *sysCheckEntry) (*updater.CheckResult, error) {
+	if !entry.SelfUpdate {
+		return entry.Updater.Check(ctx)
+	}
+
+	self, ok := entry.Updater.(updater.ManagerSelfUpdater)
+	if !ok {
+		return nil, nil
+	}
+
+	return self.CheckSelfUpdate(ctx)
+}
+
+// countSysCheckEntries は更新待ちのパッケージ数と、確認に失敗したマネージャ数を返します。
+// 一部の対象だけ確認できなかったマネージャも失敗として数えます（確認できた分の更新待ちは pending に含めます）。
+func countSysCheckEntries(entries []sysCheckEntry) (pending, failed int) {
+	for i := range entries {
+		if sysCheckEntryError(&entries[i]) != nil {
+			failed++
+		}
+
+		if entries[i].Err == nil && entries[i].Result != nil {
+			pending += entries[i].Result.AvailableUpdates
+		}
+	}
+
+	return pending, failed
+}
+
+// sysCheckEntryError は Check の失敗、または一部の対象を確認できなかったエラーを返します。
+func sysCheckEntryError(entry *sysCheckEntry) error {
+	if entry.Err != nil {
+		return entry.Err
+	}
+
+	if entry.Result != nil && len(entry.Result.Errors) > 0 {
+		return errors.Join(entry.Result.Errors...)
+	}
+
+	return nil
+}
+
+func resolveSysCheckError(pending, failed int) error {
+	switch {
+	case failed > 0 && pending > 0:
+		return fmt.Errorf("%w（%d 件）。%d 件のマネージャで確認に失敗しました", errSysCheckPending, pending, failed)
+	case failed > 0:
+		return fmt.Errorf("%d 件のマネージャで確認に失敗しました", failed)
+	case pending > 0:
+		return fmt.Errorf("%w（%d 件）", errSysCheckPending, pending)
+	default:
+		return nil
+	}
+}
+
+func sysCheckManagerLabel(entry *sysCheckEntry) string {
+	if entry.SelfUpdate {
+		return entry.Updater.Name() + " (本体)"
+	}
+
+	return entry.Updater.Name()
+}
+
+// writeSysCheckReport は更新可能パッケージの一覧表と確認失敗の一覧を出力します。
+func writeSysCheckReport(output io.Writer, entries []sysCheckEntry) error {
+	pending, failed := countSysCheckEntries(entries)
+
+	if pending == 0 && failed == 0 {
+		if _, err := fmt.Fprintln(output, "✅ すべてのパッケージは最新です"); err != nil {
+			return err
+		}
+
+		return writeSysCheckHeld(output, entries)
+	}
+
+	if pending > 0 {
+		if _, err := fmt.Fprintf(output, "📋 更新可能なパッケージ (%d件)\n\n", pending); err != nil {
+			return err
+		}
+
+		if err := writeSysCheckTable(output, entries); err != nil {
+			return err
+		}
+	}
+
+	if err := writeSysCheckHeld(output, entries); err != nil {
+		return err
+	}
+
+	if failed == 0 {
+		return nil
+	}
+
+	if _, err := fmt.Fprintln(output, "\n❌ 確認に失敗したマネージャ:"); err != nil {
+		return err
+	}
+
+	for i := range entries {
+		entryErr := sysCheckEntryError(&entries[i])
+		if entryErr == nil {
+			continue
+		}
+
+		// errors.Join の複数行は1行にまとめて表示する
+		message := strings.ReplaceAll(entryErr.Error(), "\n", "; ")
+		if _, err := fmt.Fprintf(output, "  - %s: %s\n", sysCheckManagerLabel(&entries[i]), message); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeSysCheckTable(output io.Writer, entries []sysCheckEntry) error {
+	writer := tabwriter.NewWriter(output, 0, 8, 2, ' ', 0)
+
+	if _, err := fmt.Fprintln(writer, "マネージャ\tパッケージ\t現在\t新規"); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintln(writer, "----------\t----------\t----\t----"); err != nil {
+		return err
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Err != nil || entry.Result == nil {
+			continue
+		}
+
+		for _, pkg := range entry.Result.Packages {
+			if _, err := fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n",
+				sysCheckManagerLabel(entry), pkg.Name, versionOrDash(pkg.CurrentVersion), versionOrDash(pkg.NewVersion)); err != nil {
+				return err
+			}
+		}
+
+		// 件数のみ分かり詳細が取得できないマネージャも一覧に残す
+		if len(entry.Result.Packages) == 0 && entry.Result.AvailableUpdates > 0 {
+			if _, err := fmt.Fprintf(writer, "%s\t(%d 件)\t-\t-\n", sysCheckManagerLabel(entry), entry.Result.AvailableUpdates); err != nil {
+				return err
+			}
+		}
+	}
+
+	return writer.Flush()
+}
+
+// writeSysCheckHeld は hold 設定により更新対象から外したパッケージをマネージャごとに出力します。
+func writeSysCheckHeld(output io.Writer, entries []sysCheckEntry) error {
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Result == nil || len(entry.Result.Held) == 0 {
+			continue
+		}
+
+		if _, err := fmt.Fprintf(output, "⏸️  %s: 保留 (hold): %s\n", sysCheckManagerLabel(entry), joinPackageNames(entry.Result.Held)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func versionOrDash(v string) string {
+	if v == "" {
+		return "-"
+	}
+
+	return v
+}
+
+func buildSysCheckReport(entries []sysCheckEntry) sysCheckReport {
+	pending, failed := countSysCheckEntries(entries)
+
+	report := sysCheckReport{
+		Version:  version,
+		Pending:  pending,
+		Failed:   failed,
+		Managers: make([]sysCheckEntryReport, 0, len(entries)),
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+
+		item := sysCheckEntryReport{
+			Name:        entry.Updater.Name(),
+			DisplayName: entry.Updater.DisplayName(),
+			SelfUpdate:  entry.SelfUpdate,
+			Packages:    []packageReport{},
+			Held:        []packageReport{},
+		}
+
+		if entry.Result != nil {
+			item.AvailableUpdates = entry.Result.AvailableUpdates
+			item.Packages = newPackageReports(entry.Result.Packages)
+			item.Held = newPackageReports(entry.Result.Held)
+			item.Message = entry.Result.Message
+		}
+
+		if entryErr := sysCheckEntryError(entry); entryErr != nil {
+			item.Error = strings.ReplaceAll(entryErr.Error(), "\n", "; ")
+		}
+
+		report.Managers = append(report.Managers, item)
+	}
+
+	return report
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/scottlz0310/dsx/internal/updater"
+)
+
+type checkStubUpdater struct {
+	stubUpdater
+	checkResult *updater.CheckResult
+	checkErr    error
+}
+
+func (s checkStubUpdater) Check(context.Context) (*updater.CheckResult, error) {
+	if s.checkErr != nil {
+		return nil, s.checkErr
+	}
+
+	return s.checkResult, nil
+}
+
+type selfCheckStubUpdater struct {
+	checkStubUpdater
+	selfCheckResult *updater.CheckResult
+}
+
+func (s selfCheckStubUpdater) CheckSelfUpdate(context.Context) (*updater.CheckResult, error) {
+	return s.selfCheckResult, nil
+}
+
+func (s selfCheckStubUpdater) SelfUpdate(context.Context, updater.UpdateOptions) (*updater.SelfUpdateResult, error) {
+	return nil, errors.New("sys check では呼ばれない想定")
+}
+
+func TestCollectSysChecks(t *testing.T) {
+	updaters := []updater.Updater{
+		checkStubUpdater{
+			stubUpdater: stubUpdater{name: "apt"},
+			checkResult: &updater.CheckResult{
+				AvailableUpdates: 1,
+				Packages:         []updater.PackageInfo{{Name: "curl", CurrentVersion: "8.5.0", NewVersion: "8.6.0"}},
+			},
+		},
+		selfCheckStubUpdater{
+			checkStubUpdater: checkStubUpdater{
+				stubUpdater: stubUpdater{name: "uv"},
+				checkResult: &updater.CheckResult{},
+			},
+			selfCheckResult: &updater.CheckResult{
+				AvailableUpdates: 1,
+				Packages:         []updater.PackageInfo{{Name: "uv", CurrentVersion: "0.11.16", NewVersion: "0.11.17"}},
+			},
+		},
+		checkStubUpdater{
+			stubUpdater: stubUpdater{name: "npm"},
+			checkErr:    errors.New("npm not responding"),
+		},
+	}
+
+	entries := collectSysChecks(context.Background(), updaters, 2, nil, io.Discard)
+
+	if len(entries) != 4 {
+		t.Fatalf("entries length = %d, want 4 (apt, uv self, uv, npm)", len(entries))
+	}
+
+	if !entries[1].SelfUpdate || entries[1].Updater.Name() != "uv" {
+		t.Fatalf("entries[1] = %+v, want uv self update check", entries[1])
+	}
+
+	pending, failed := countSysCheckEntries(entries)
+	if pending != 2 || failed != 1 {
+		t.Fatalf("pending/failed = %d/%d, want 2/1", pending, failed)
+	}
+
+	err := resolveSysCheckError(pending, failed)
+	if !errors.Is(err, errSysCheckPending) {
+		t.Fatalf("err = %v, want errSysCheckPending", err)
+	}
+}
+
+func TestCollectSysChecks_CanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	entries := collectSysChecks(ctx, []updater.Updater{checkStubUpdater{stubUpdater: stubUpdater{name: "brew"}, checkResult: &updater.CheckResult{}}}, 1, nil, io.Discard)
+
+	if len(entries) != 1 || entries[0].Err == nil {
+		t.Fatalf("entries = %+v, want canceled error", entries)
+	}
+}
+
+func TestPreauthenticateSysCheck(t *testing.T) {
+	original := sysCheckSudoStep
+
+	t.Cleanup(func() { sysCheckSudoStep = original })
+
+	calls := 0
+	sysCheckSudoStep = func(context.Context) error {
+		calls++
+
+		return errors.New("sudo: a password is required")
+	}
+
+	snap := checkStubUpdater{stubUpdater: stubUpdater{name: "snap"}, checkResult: &updater.CheckResult{}}
+	apt := checkStubUpdater{stubUpdater: stubUpdater{name: "apt"}, checkResult: &updater.CheckResult{}}
+
+	if errs := preauthenticateSysCheck(context.Background(), []updater.Updater{snap}, nil); errs != nil || calls != 0 {
+		t.Fatalf("snap only: errs = %v, sudo calls = %d, want no authentication", errs, calls)
+	}
+
+	if errs := preauthenticateSysCheck(context.Background(), []updater.Updater{apt}, map[string]config.ManagerConfig{"apt": {"use_sudo": false}}); errs != nil || calls != 0 {
+		t.Fatalf("apt without sudo: errs = %v, sudo calls = %d, want no authentication", errs, calls)
+	}
+
+	updaters := []updater.Updater{apt, snap}
+
+	errs := preauthenticateSysCheck(context.Background(), updaters, nil)
+	if calls != 1 || len(errs) != 1 || errs["apt"] == nil {
+		t.Fatalf("errs = %v, sudo calls = %d, want apt failure only", errs, calls)
+	}
+
+	entries := collectSysChecks(context.Background(), updaters, 2, errs, io.Discard)
+	if entries[0].Err == nil || !strings.Contains(entries[0].Err.Error(), "sudo の認証が必要") || entries[1].Err != nil {
+		t.Fatalf("entries = %+v, want only apt failed", entries)
+	}
+}
+
+func TestCountSysCheckEntries_PartialErrors(t *testing.T) {
+	t.Parallel()
+
+	entries := []sysCheckEntry{
+		{
+			Updater: stubUpdater{name: "containers"},
+			Result: &updater.CheckResult{
+				AvailableUpdates: 1,
+				Packages:         []updater.PackageInfo{{Name: "postgres:16"}},
+				Errors:           []error{errors.New("redis:7: 401 Unauthorized"), errors.New("nginx:1: timeout")},
+			},
+		},
+		{Updater: stubUpdater{name: "brew"}, Result: &updater.CheckResult{}},
+	}
+
+	pending, failed := countSysCheckEntries(entries)
+	if pending != 1 || failed != 1 {
+		t.Fatalf("pending/failed = %d/%d, want 1/1", pending, failed)
+	}
+
+	var buf bytes.Buffer
+	if err := writeSysCheckReport(&buf, entries); err != nil {
+		t.Fatalf("writeSysCheckReport error: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "  - containers: redis:7: 401 Unauthorized; nginx:1: timeout") {
+		t.Fatalf("output does not list the partial failure:\n%s", buf.String())
+	}
+
+	report := buildSysCheckReport(entries)
+	if report.Failed != 1 || report.Managers[0].Error == "" || len(report.Managers[0].Packages) != 1 {
+		t.Fatalf("report = %+v, want containers failure with its checked package", report)
+	}
+}
+
+func TestResolveSysCheckError(t *testing.T) {
+	t.Parallel()
+
+	if err := resolveSysCheckError(0, 0); err != nil {
+		t.Fatalf("resolveSysCheckError(0, 0) = %v, want nil", err)
+	}
+
+	if err := resolveSysCheckError(3, 0); !errors.Is(err, errSysCheckPending) {
+		t.Fatalf("resolveSysCheckError(3, 0) = %v, want pending error", err)
+	}
+
+	err := resolveSysCheckError(0, 1)
+	if err == nil || errors.Is(err, errSysCheckPending) {
+		t.Fatalf("resolveSysCheckError(0, 1) = %v, want failure-only error", err)
+	}
+}
+
+func TestWriteSysCheckReport(t *testing.T) {
+	t.Parallel()
+
+	entries := []sysCheckEntry{
+		{
+			Updater: stubUpdater{name: "apt"},
+			Result: &updater.CheckResult{
+				AvailableUpdates: 1,
+				Packages:         []updater.PackageInfo{{Name: "curl", CurrentVersion: "8.5.0", NewVersion: "8.6.0"}},
+			},
+		},
+		{
+			Updater:    stubUpdater{name: "uv"},
+			SelfUpdate: true,
+			Result:     &updater.CheckResult{AvailableUpdates: 1, Packages: []updater.PackageInfo{{Name: "uv", NewVersion: "0.11.17"}}},
+		},
+		{
+			Updater: stubUpdater{name: "snap"},
+			Result:  &updater.CheckResult{AvailableUpdates: 2},
+		},
+		{
+			Updater: stubUpdater{name: "npm"},
+			Err:     errors.New("boom"),
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeSysCheckReport(&buf, entries); err != nil {
+		t.Fatalf("writeSysCheckReport error: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"(4件)", "apt", "curl", "8.5.0", "8.6.0", "uv (本体)", "(2 件)", "npm: boom"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("output does not contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteSysCheckReport_UpToDate(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := writeSysCheckReport(&buf, []sysCheckEntry{{Updater: stubUpdater{name: "apt"}, Result: &updater.CheckResult{}}}); err != nil {
+		t.Fatalf("writeSysCheckReport error: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "最新") {
+		t.Fatalf("output = %q, want up-to-date message", buf.String())
+	}
+}
+
+func TestWriteSysCheckReport_Held(t *testing.T) {
+	t.Parallel()
+
+	entries := []sysCheckEntry{{
+		Updater: stubUpdater{name: "apt"},
+		Result: &updater.CheckResult{
+			Held: []updater.PackageInfo{{Name: "docker-ce"}, {Name: "linux-image-generic"}},
+		},
+	}}
+
+	var buf bytes.Buffer
+	if err := writeSysCheckReport(&buf, entries); err != nil {
+		t.Fatalf("writeSysCheckReport error: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"最新", "apt: 保留 (hold): docker-ce, linux-image-generic"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("output does not contain %q:\n%s", want, out)
+		}
+	}
+
+	report := buildSysCheckReport(entries)
+	if len(report.Managers[0].Held) != 2 {
+		t.Fatalf("held = %+v, want 2 packages", report.Managers[0].Held)
+	}
+}
+
+func TestBuildSysCheckReport(t *testing.T) {
+	t.Parallel()
+
+	report := buildSysCheckReport([]sysCheckEntry{
+		{Updater: stubUpdater{name: "apt"}, Result: &updater.CheckResult{AvailableUpdates: 1, Packages: []updater.PackageInfo{{Name: "curl"}}}},
+		{Updater: stubUpdater{name: "npm"}, Err: errors.New("boom")},
+	})
+
+	if report.Pending != 1 || report.Failed != 1 || len(report.Managers) != 2 {
+		t.Fatalf("report = %+v, want pending:1 failed:1 managers:2", report)
+	}
+
+	if report.Managers[1].Error != "boom" || report.Managers[1].Packages == nil {
+		t.Fatalf("npm report = %+v, want error and empty packages", report.Managers[1])
+	}
+}
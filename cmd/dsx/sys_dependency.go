@@ -0,0 +1,138 @@
+package main
+
+import (
+	"slices"
+
+	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/scottlz0310/dsx/internal/updater"
+)
+
+// resolveManagerDependencies はマネージャごとに先に完了させるマネージャ名を返します。
+// Updater が宣言する DependsOn と sys.managers.<name>.after を合わせ、
+// 対象の updaters に含まれない依存先と自分自身は無視します。
+func resolveManagerDependencies(updaters []updater.Updater, managers map[string]config.ManagerConfig) map[string][]string {
+	present := make(map[string]bool, len(updaters))
+	for _, u := range updaters {
+		present[u.Name()] = true
+	}
+
+	deps := make(map[string][]string, len(updaters))
+
+	for _, u := range updaters {
+		name := u.Name()
+
+		var candidates []string
+
+		if declarer, ok := u.(updater.DependencyDeclarer); ok {
+			candidates = append(candidates, declarer.DependsOn()...)
+		}
+
+		// 型の誤りは config validate で報告するため、ここでは読める範囲だけ使う
+		after, _ := managers[name].After()
+		candidates = append(candidates, after...)
+
+		seen := make(map[string]bool, len(candidates))
+
+		for _, dep := range candidates {
+			if dep == name || !present[dep] || seen[dep] {
+				continue
+			}
+
+			seen[dep] = true
+			deps[name] = append(deps[name], dep)
+		}
+	}
+
+	return deps
+}
+
+// sysUpdateGraph は更新フェーズで実行するマネージャのジョブグラフです。
+type sysUpdateGraph struct {
+	// updaters は依存先が先になるよう並べたマネージャです。循環により順序を決められないものは末尾に置きます。
+	updaters []updater.Updater
+	// dependsOn は成功している必要がある依存先です（組み込み依存と sys.managers.<name>.after）。
+	dependsOn map[string][]string
+	// after は単独実行のために完了を待つマネージャです（成否は問いません）。
+	after map[string][]string
+}
+
+// planSysUpdateGraph は依存関係と単独実行の制約から更新フェーズのジョブグラフを組み立てます。
+// 単独実行のマネージャは、並び順で前にあるマネージャの完了を待ち、後ろのマネージャに自身の完了を待たせることで
+// 他のマネージャと同時に実行されないようにします。循環依存は runner がジョブの失敗として報告します。
+func planSysUpdateGraph(updaters []updater.Updater, managers map[string]config.ManagerConfig) sysUpdateGraph {
+	deps := resolveManagerDependencies(updaters, managers)
+	ordered, blocked := orderByDependencies(updaters, deps)
+
+	graph := sysUpdateGraph{
+		updaters:  slices.Concat(ordered, blocked),
+		dependsOn: deps,
+		after:     make(map[string][]string),
+	}
+
+	lastExclusive := -1
+
+	for i, u := range ordered {
+		name := u.Name()
+
+		if mustRunExclusively(u) {
+			// 直前の単独実行マネージャと、それ以降に並ぶマネージャの完了を待つ
+			for _, before := range ordered[max(lastExclusive, 0):i] {
+				graph.after[name] = append(graph.after[name], before.Name())
+			}
+
+			lastExclusive = i
+
+			continue
+		}
+
+		if lastExclusive >= 0 {
+			graph.after[name] = append(graph.after[name], ordered[lastExclusive].Name())
+		}
+	}
+
+	return graph
+}
+
+// orderByDependencies は依存先が先になるようマネージャを並べます。
+// 依存関係を満たす範囲で単独実行のマネージャを先にし、それぞれ sys.enable の順序を保ちます。
+// 依存関係が循環していて並べられないマネージャは blocked として返します。
+func orderByDependencies(updaters []updater.Updater, deps map[string][]string) (ordered, blocked []updater.Updater) {
+	exclusive, parallel := splitUpdatersForExecution(updaters)
+	pending := slices.Concat(exclusive, parallel)
+	done := make(map[string]bool, len(updaters))
+	ordered = make([]updater.Updater, 0, len(updaters))
+
+	for len(pending) > 0 {
+		next := slices.IndexFunc(pending, func(u updater.Updater) bool {
+			return dependenciesSatisfied(deps[u.Name()], done)
+		})
+		if next < 0 {
+			break
+		}
+
+		done[pending[next].Name()] = true
+		ordered = append(ordered, pending[next])
+		pending = slices.Delete(pending, next, next+1)
+	}
+
+	return ordered, pending
+}
+
+func dependenciesSatisfied(deps []string, done map[string]bool) bool {
+	for _, dep := range deps {
+		if !done[dep] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func updaterNames(updaters []updater.Updater) []string {
+	names := make([]string, 0, len(updaters))
+	for _, u := range updaters {
+		names = append(names, u.Name())
+	}
+
+	return names
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/scottlz0310/dsx/internal/updater"
+)
+
+type dependencyStubUpdater struct {
+	stubUpdater
+	dependsOn []string
+}
+
+func (s dependencyStubUpdater) DependsOn() []string {
+	return s.dependsOn
+}
+
+func TestResolveManagerDependencies(t *testing.T) {
+	t.Parallel()
+
+	updaters := []updater.Updater{
+		stubUpdater{name: "nvm"},
+		dependencyStubUpdater{stubUpdater: stubUpdater{name: "npm"}, dependsOn: []string{"nvm"}},
+		dependencyStubUpdater{stubUpdater: stubUpdater{name: "cargo"}, dependsOn: []string{"rustup"}},
+		stubUpdater{name: "pipx"},
+	}
+	managers := map[string]config.ManagerConfig{
+		"npm":  {"after": []interface{}{"nvm", "npm"}},
+		"pipx": {"after": []interface{}{"npm", "brew"}},
+	}
+
+	deps := resolveManagerDependencies(updaters, managers)
+
+	if got := strings.Join(deps["npm"], ","); got != "nvm" {
+		t.Fatalf("npm deps = %q, want nvm (deduplicated, self ignored)", got)
+	}
+
+	if _, ok := deps["cargo"]; ok {
+		t.Fatalf("cargo deps = %v, want none (rustup is not enabled)", deps["cargo"])
+	}
+
+	if got := strings.Join(deps["pipx"], ","); got != "npm" {
+		t.Fatalf("pipx deps = %q, want npm (brew is not enabled)", got)
+	}
+}
+
+func TestPlanSysUpdateGraph(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		updaters  []updater.Updater
+		managers  map[string]config.ManagerConfig
+		wantOrder string
+		wantAfter map[string]string
+	}{
+		{
+			name:      "依存なしは sys.enable の順序を維持",
+			updaters:  []updater.Updater{stubUpdater{name: "brew"}, stubUpdater{name: "go"}},
+			wantOrder: "brew,go",
+			wantAfter: map[string]string{},
+		},
+		{
+			name: "依存先が先に並ぶ",
+			updaters: []updater.Updater{
+				dependencyStubUpdater{stubUpdater: stubUpdater{name: "npm"}, dependsOn: []string{"nvm"}},
+				stubUpdater{name: "nvm"},
+				stubUpdater{name: "go"},
+			},
+			wantOrder: "nvm,npm,go",
+			wantAfter: map[string]string{},
+		},
+		{
+			name:      "単独実行は先頭で直列化し、後続はその完了を待つ",
+			updaters:  []updater.Updater{stubUpdater{name: "brew"}, stubUpdater{name: "apt"}, stubUpdater{name: "bun"}, stubUpdater{name: "go"}},
+			wantOrder: "apt,bun,brew,go",
+			wantAfter: map[string]string{"bun": "apt", "brew": "bun", "go": "bun"},
+		},
+		{
+			name:      "単独実行でも依存先の後に並ぶ",
+			updaters:  []updater.Updater{stubUpdater{name: "apt"}, stubUpdater{name: "nvm"}, stubUpdater{name: "go"}},
+			managers:  map[string]config.ManagerConfig{"apt": {"after": []interface{}{"nvm"}}},
+			wantOrder: "nvm,apt,go",
+			wantAfter: map[string]string{"apt": "nvm", "go": "apt"},
+		},
+		{
+			name:      "循環は末尾に並べて順序制約を付けない",
+			updaters:  []updater.Updater{stubUpdater{name: "npm"}, stubUpdater{name: "apt"}, stubUpdater{name: "pnpm"}},
+			managers:  map[string]config.ManagerConfig{"npm": {"after": []interface{}{"pnpm"}}, "pnpm": {"after": []interface{}{"npm"}}},
+			wantOrder: "apt,npm,pnpm",
+			wantAfter: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			graph := planSysUpdateGraph(tt.updaters, tt.managers)
+
+			if got := strings.Join(updaterNames(graph.updaters), ","); got != tt.wantOrder {
+				t.Fatalf("order = %q, want %q", got, tt.wantOrder)
+			}
+
+			after := make(map[string]string, len(graph.after))
+			for name, names := range graph.after {
+				after[name] = strings.Join(names, ",")
+			}
+
+			if !maps.Equal(after, tt.wantAfter) {
+				t.Fatalf("after = %v, want %v", after, tt.wantAfter)
+			}
+		})
+	}
+}
+
+func TestRunSysUpdatePhases_SkipsDependentsOfFailedManager(t *testing.T) {
+	cfg := config.Default()
+	cfg.Sys.Managers = map[string]config.ManagerConfig{
+		"pipx": {"after": []interface{}{"npm"}},
+	}
+
+	updaters := []updater.Updater{
+		stubUpdater{name: "nvm", updateErr: errors.New("nvm install failed")},
+		dependencyStubUpdater{stubUpdater: stubUpdater{name: "npm"}, dependsOn: []string{"nvm"}},
+		stubUpdater{name: "pipx"},
+		stubUpdater{name: "gem"},
+	}
+
+	stats, err := runSysUpdatePhases(context.Background(), cfg, updater.UpdateOptions{}, updaters, 1, false)
+	if err != nil {
+		t.Fatalf("runSysUpdatePhases error: %v", err)
+	}
+
+	statuses := make(map[string]string, len(stats.Managers))
+	for _, report := range stats.Managers {
+		statuses[report.Name] = report.Status
+	}
+
+	want := map[string]string{
+		"nvm":  managerReportStatusFailed,
+		"npm":  managerReportStatusSkipped,
+		"pipx": managerReportStatusSkipped,
+		"gem":  managerReportStatusSuccess,
+	}
+
+	for name, status := range want {
+		if statuses[name] != status {
+			t.Fatalf("%s status = %q, want %q (all: %v)", name, statuses[name], status, statuses)
+		}
+	}
+
+	for _, report := range stats.Managers {
+		if report.Name == "npm" && (len(report.Errors) != 1 || !strings.Contains(report.Errors[0], "依存ジョブが失敗したためスキップしました: nvm")) {
+			t.Fatalf("npm errors = %v, want dependency failure reason", report.Errors)
+		}
+	}
+
+	if len(stats.Errors) != 1 {
+		t.Fatalf("errors = %v, want only the nvm failure (dependency skips are not counted as cancellations)", stats.Errors)
+	}
+}
+
+func TestRunSysUpdatePhases_DependencyCycle(t *testing.T) {
+	cfg := config.Default()
+	cfg.Sys.Managers = map[string]config.ManagerConfig{
+		"npm":  {"after": []interface{}{"pnpm"}},
+		"pnpm": {"after": []interface{}{"npm"}},
+	}
+
+	updaters := []updater.Updater{stubUpdater{name: "npm"}, stubUpdater{name: "pnpm"}, stubUpdater{name: "gem"}}
+
+	var (
+		stats updateStats
+		err   error
+	)
+
+	captureStdout(t, func() {
+		captureStderr(t, func() {
+			stats, err = runSysUpdatePhases(context.Background(), cfg, updater.UpdateOptions{}, updaters, 1, false)
+		})
+	})
+	if err != nil {
+		t.Fatalf("runSysUpdatePhases error: %v", err)
+	}
+
+	statuses := make(map[string]string, len(stats.Managers))
+	for _, report := range stats.Managers {
+		statuses[report.Name] = report.Status
+	}
+
+	// 循環に含まれるマネージャのみ失敗し、他のマネージャは更新を続ける
+	want := map[string]string{"npm": managerReportStatusFailed, "pnpm": managerReportStatusFailed, "gem": managerReportStatusSuccess}
+	if !maps.Equal(statuses, want) {
+		t.Fatalf("statuses = %v, want %v", statuses, want)
+	}
+
+	if len(stats.Errors) != 2 || !strings.Contains(stats.Errors[0].Error(), "依存関係が循環しています") {
+		t.Fatalf("errors = %v, want cycle errors", stats.Errors)
+	}
+}
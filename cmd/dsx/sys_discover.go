@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/scottlz0310/dsx/internal/config"
@@ -19,28 +21,28 @@ var (
 	sysDiscoverDryRun  bool
 )
 
-// supportedDiscoverManagers は discover コマンドで対応しているマネージャの一覧です。
-var supportedDiscoverManagers = []string{"go"}
-
-// sysDiscoverCmd はインストール済みツールを検出するコマンドです。
+// sysDiscoverCmd はインストール済みのマネージャとツールを検出するコマンドです。
 var sysDiscoverCmd = &cobra.Command{
 	Use:   "discover",
-	Short: "インストール済みのツールを検出します",
-	Long: `$GOBIN/$GOPATH/bin にインストールされた Go ツールをスキャンし、
+	Short: "インストール済みのマネージャとツールを検出します",
+	Long: `登録済みのすべてのマネージャについて、この環境で利用できるかとインストール済みパッケージ数を調べ、
+sys.enable に追加する候補（マネージャごとの既定設定を含む）を表示します。
+あわせて $GOBIN/$GOPATH/bin にインストールされた Go ツールをスキャンし、
 go.targets に追加可能な候補を表示します。
 
 例:
-  dsx sys discover                    # 全対応マネージャをスキャン（表示のみ）
+  dsx sys discover                    # 全マネージャをスキャン（表示のみ）
   dsx sys discover --manager go       # Go バイナリのみスキャン
-  dsx sys discover --apply            # 検出結果を go.targets に書き込む
+  dsx sys discover --manager cargo    # cargo のみスキャン
+  dsx sys discover --apply            # 検出結果を sys.enable と go.targets に書き込む
   dsx sys discover --apply --dry-run  # 書き込み内容をプレビュー表示（書き込みなし）`,
 	RunE: runSysDiscover,
 }
 
 func init() {
 	sysCmd.AddCommand(sysDiscoverCmd)
-	sysDiscoverCmd.Flags().StringVar(&sysDiscoverManager, "manager", "", "スキャン対象のマネージャ（例: go）")
-	sysDiscoverCmd.Flags().BoolVar(&sysDiscoverApply, "apply", false, "検出結果を go.targets に書き込む")
+	sysDiscoverCmd.Flags().StringVar(&sysDiscoverManager, "manager", "", "スキャン対象のマネージャ（例: go, cargo）")
+	sysDiscoverCmd.Flags().BoolVar(&sysDiscoverApply, "apply", false, "検出結果を sys.enable と go.targets に書き込む")
 	sysDiscoverCmd.Flags().BoolVar(&sysDiscoverDryRun, "dry-run", false, "書き込みは行わず変更内容をプレビュー表示する（--apply と組み合わせて使用）")
 }
 
@@ -49,6 +51,8 @@ func runSysDiscover(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("--dry-run は --apply と組み合わせて使用してください")
 	}
 
+	loadUpdaterPlugins()
+
 	managers, err := resolveDiscoverManagers(sysDiscoverManager)
 	if err != nil {
 		return err
@@ -62,21 +66,32 @@ func runSysDiscover(cmd *cobra.Command, args []string) error {
 	ctx, stop := signal.NotifyContext(baseCtx, os.Interrupt)
 	defer stop()
 
-	for _, m := range managers {
-		if err := discoverManager(ctx, m, sysDiscoverApply, sysDiscoverDryRun); err != nil {
-			return err
-		}
+	if err := discoverManagerEnable(ctx, managers, sysDiscoverApply, sysDiscoverDryRun); err != nil {
+		return err
+	}
+
+	if slices.Contains(managers, "go") {
+		return discoverGoManager(ctx, sysDiscoverApply, sysDiscoverDryRun)
 	}
 
 	return nil
 }
 
 // resolveDiscoverManagers は --manager フラグを解決し、対象マネージャ一覧を返します。
-// 指定がなければ全対応マネージャを返します。未対応名を指定した場合はエラーを返します。
+// 指定がなければ登録済みの全マネージャを返します。未登録の名前を指定した場合はエラーを返します。
 func resolveDiscoverManagers(manager string) ([]string, error) {
 	manager = strings.TrimSpace(manager)
 	if manager == "" {
-		return supportedDiscoverManagers, nil
+		all := updater.All()
+		names := make([]string, 0, len(all))
+
+		for _, u := range all {
+			names = append(names, u.Name())
+		}
+
+		sort.Strings(names)
+
+		return names, nil
 	}
 
 	if !isSupportedDiscoverManager(manager) {
@@ -86,25 +101,47 @@ func resolveDiscoverManagers(manager string) ([]string, error) {
 	return []string{manager}, nil
 }
 
-// isSupportedDiscoverManager は指定されたマネージャ名が discover で対応しているかを返します。
+// isSupportedDiscoverManager は指定されたマネージャ名が discover で対応しているか（登録済みか）を返します。
 func isSupportedDiscoverManager(name string) bool {
-	for _, m := range supportedDiscoverManagers {
-		if m == name {
-			return true
+	_, ok := updater.Get(name)
+	return ok
+}
+
+// discoverManagerEnable はマネージャの利用可否とパッケージ数を調べ、sys.enable の追加候補を表示します。
+// apply が true の場合は sys.enable に書き込みます（dryRun ではプレビューのみ）。
+func discoverManagerEnable(ctx context.Context, managers []string, apply, dryRun bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("設定ファイルの読み込みに失敗: %w", err)
+	}
+
+	targets := make([]updater.Updater, 0, len(managers))
+
+	for _, name := range managers {
+		if u, ok := updater.Get(name); ok {
+			targets = append(targets, u)
 		}
 	}
 
-	return false
-}
+	discoveries := discoverManagers(ctx, targets, cfg, countInstalledPackages)
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return fmt.Errorf("処理が中断されました")
+	}
+
+	printManagerDiscoveries(os.Stdout, discoveries)
+
+	proposal := buildManagerEnableProposal(cfg, discoveries)
 
-// discoverManager は指定マネージャのスキャンを実行します。
-func discoverManager(ctx context.Context, manager string, apply, dryRun bool) error {
-	switch manager {
-	case "go":
-		return discoverGoManager(ctx, apply, dryRun)
-	default:
-		return fmt.Errorf("未対応のマネージャ: %s", manager)
+	if apply {
+		return applyManagerEnable(cfg, proposal, dryRun)
+	}
+
+	if proposal.HasChanges() {
+		fmt.Println("💡 追加候補を sys.enable に書き込むには --apply フラグを使用してください。")
+		fmt.Println()
 	}
+
+	return nil
 }
 
 // discoverGoManager は Go バイナリをスキャンして結果を表示します。
@@ -0,0 +1,385 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/scottlz0310/dsx/internal/updater"
+)
+
+// unknownPackageCount はインストール済みパッケージ数を取得できないことを表します。
+const unknownPackageCount = -1
+
+// discoverManagerDefaults は sys.enable に新しく追加するマネージャへ提案する既定の設定です。
+// sys.managers.<name> が既にある場合は提案しません。
+var discoverManagerDefaults = map[string]config.ManagerConfig{
+	"fwupdmgr": {"timeout": "3m"},
+	"snap":     {"timeout": "5m", "retries": 2},
+}
+
+// managerDiscovery はマネージャ1件の検出結果です。
+type managerDiscovery struct {
+	Name      string
+	Available bool
+	// Reason は利用できない理由、またはパッケージ数の取得に失敗した理由です。
+	Reason string
+	// PackageCount はインストール済みパッケージ数です。取得できない場合は unknownPackageCount です。
+	PackageCount int
+	Enabled      bool
+}
+
+// Proposed は sys.enable への追加を提案するかを返します。
+// 利用可能で、パッケージが 1 件以上ある（または件数を取得できない）マネージャが対象です。
+func (d managerDiscovery) Proposed() bool {
+	return d.Available && !d.Enabled && d.PackageCount != 0
+}
+
+// managerEnableProposal は sys.enable と sys.managers への変更案です。
+type managerEnableProposal struct {
+	Existing []string
+	ToAdd    []string
+	Defaults map[string]config.ManagerConfig
+	// Unavailable は sys.enable に含まれるが、この環境では利用できないマネージャです（削除は提案しません）。
+	Unavailable []string
+}
+
+// HasChanges は変更案に書き込む内容があるかを返します。
+func (p managerEnableProposal) HasChanges() bool {
+	return len(p.ToAdd) > 0 || len(p.Defaults) > 0
+}
+
+// packageCounter はマネージャのインストール済みパッケージ数を返します。
+type packageCounter func(ctx context.Context, u updater.Updater) (int, error)
+
+// countInstalledPackages は InstalledLister でインストール済みパッケージ数を数えます。
+// go は go.targets ではなく $GOBIN などにインストール済みのバイナリを数えます。
+func countInstalledPackages(ctx context.Context, u updater.Updater) (int, error) {
+	if u.Name() == "go" {
+		result, err := updater.DiscoverGoBinaries(ctx)
+		if err != nil {
+			return unknownPackageCount, err
+		}
+
+		return len(result.Detected), nil
+	}
+
+	lister, ok := u.(updater.InstalledLister)
+	if !ok {
+		return unknownPackageCount, nil
+	}
+
+	packages, err := lister.ListInstalled(ctx)
+	if err != nil {
+		return unknownPackageCount, err
+	}
+
+	return len(packages), nil
+}
+
+// discoverManagers は各マネージャの利用可否とインストール済みパッケージ数を並列に調べます。
+// 結果は availableSystemManagers の順（それ以外のマネージャは名前順で末尾）に並べます。
+func discoverManagers(ctx context.Context, updaters []updater.Updater, cfg *config.Config, count packageCounter) []managerDiscovery {
+	ordered := append([]updater.Updater(nil), updaters...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		oi, oj := managerOrder(ordered[i].Name()), managerOrder(ordered[j].Name())
+		if oi != oj {
+			return oi < oj
+		}
+
+		return ordered[i].Name() < ordered[j].Name()
+	})
+
+	enabled := make(map[string]struct{}, len(cfg.Sys.Enable))
+	for _, name := range cfg.Sys.Enable {
+		enabled[name] = struct{}{}
+	}
+
+	results := make([]managerDiscovery, len(ordered))
+
+	var wg sync.WaitGroup
+
+	for i, u := range ordered {
+		_, isEnabled := enabled[u.Name()]
+		results[i] = managerDiscovery{
+			Name:         u.Name(),
+			PackageCount: unknownPackageCount,
+			Enabled:      isEnabled,
+		}
+
+		wg.Add(1)
+
+		go func(d *managerDiscovery, u updater.Updater) {
+			defer wg.Done()
+
+			// 使用する CLI を設定で切り替えるマネージャがあるため、利用可否の判定前に設定を適用
+			if err := u.Configure(cfg.Sys.Managers[u.Name()]); err != nil {
+				d.Reason = fmt.Sprintf("設定エラー: %v", err)
+				return
+			}
+
+			if !u.IsAvailable() {
+				d.Reason = "利用不可"
+				if reporter, ok := u.(updater.AvailabilityReporter); ok && reporter.UnavailableReason() != "" {
+					d.Reason = reporter.UnavailableReason()
+				}
+
+				return
+			}
+
+			d.Available = true
+
+			listCtx, cancel := context.WithTimeout(ctx, installedListTimeout)
+			defer cancel()
+
+			n, err := count(listCtx, u)
+			if err != nil {
+				d.Reason = fmt.Sprintf("パッケージ数の取得に失敗: %v", err)
+				return
+			}
+
+			d.PackageCount = n
+		}(&results[i], u)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// managerOrder は availableSystemManagers 内の位置を返します。含まれない場合は末尾扱いです。
+func managerOrder(name string) int {
+	for i, m := range availableSystemManagers {
+		if m == name {
+			return i
+		}
+	}
+
+	return len(availableSystemManagers)
+}
+
+// buildManagerEnableProposal は検出結果と既存設定から sys.enable の変更案を作成します。
+// 既存の sys.enable は順序を含めてそのまま残し、新しいマネージャを末尾に追加します。
+func buildManagerEnableProposal(cfg *config.Config, discoveries []managerDiscovery) managerEnableProposal {
+	proposal := managerEnableProposal{
+		Existing: append([]string(nil), cfg.Sys.Enable...),
+		Defaults: make(map[string]config.ManagerConfig),
+	}
+
+	available := make(map[string]bool, len(discoveries))
+
+	for _, d := range discoveries {
+		available[d.Name] = d.Available
+
+		if !d.Proposed() {
+			continue
+		}
+
+		proposal.ToAdd = append(proposal.ToAdd, d.Name)
+
+		if defaults, ok := discoverManagerDefaults[d.Name]; ok {
+			if _, configured := cfg.Sys.Managers[d.Name]; !configured {
+				proposal.Defaults[d.Name] = defaults
+			}
+		}
+	}
+
+	for _, name := range cfg.Sys.Enable {
+		if isAvailable, discovered := available[name]; discovered && !isAvailable {
+			proposal.Unavailable = append(proposal.Unavailable, name)
+		}
+	}
+
+	return proposal
+}
+
+// printManagerDiscoveries は検出結果を表形式で表示します。
+func printManagerDiscoveries(w io.Writer, discoveries []managerDiscovery) {
+	fmt.Fprintln(w, "[managers] 検出結果:")
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  名前\t状態\tパッケージ\tsys.enable")
+
+	var warnings []string
+
+	for _, d := range discoveries {
+		status := "✅ 利用可能"
+		if !d.Available {
+			status = "❌ " + d.Reason
+		}
+
+		packages := "-"
+		if d.PackageCount != unknownPackageCount {
+			packages = strconv.Itoa(d.PackageCount)
+		}
+
+		enable := "-"
+
+		switch {
+		case d.Enabled:
+			enable = "有効"
+		case d.Proposed():
+			enable = "+ 追加候補"
+		case d.Available && d.PackageCount == 0:
+			enable = "-（パッケージなし）"
+		}
+
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", d.Name, status, packages, enable)
+
+		if d.Available && d.Reason != "" {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", d.Name, d.Reason))
+		}
+	}
+
+	_ = tw.Flush()
+
+	for _, warning := range warnings {
+		fmt.Fprintf(w, "  ⚠️  %s\n", warning)
+	}
+
+	fmt.Fprintln(w)
+}
+
+// printManagerEnableDiff は sys.enable と sys.managers への変更内容を差分形式で表示します。
+func printManagerEnableDiff(w io.Writer, proposal managerEnableProposal) {
+	fmt.Fprintln(w, "  sys.enable:")
+
+	for _, name := range proposal.Existing {
+		fmt.Fprintf(w, "    %s\n", name)
+	}
+
+	for _, name := range proposal.ToAdd {
+		fmt.Fprintf(w, "  + %s\n", name)
+	}
+
+	if len(proposal.Defaults) > 0 {
+		fmt.Fprintln(w, "  sys.managers:")
+
+		names := make([]string, 0, len(proposal.Defaults))
+		for name := range proposal.Defaults {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			fmt.Fprintf(w, "  + %s: %s\n", name, formatManagerDefaults(proposal.Defaults[name]))
+		}
+	}
+
+	for _, name := range proposal.Unavailable {
+		fmt.Fprintf(w, "  ⚠️  %s は sys.enable に含まれていますが、この環境では利用できません\n", name)
+	}
+}
+
+// formatManagerDefaults は ManagerConfig をキー順の "key: value" 形式で返します。
+func formatManagerDefaults(mc config.ManagerConfig) string {
+	keys := make([]string, 0, len(mc))
+	for key := range mc {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s: %v", key, mc[key]))
+	}
+
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// applyManagerEnable は変更案を config.yaml の sys.enable / sys.managers に反映します。
+// dryRun が true の場合、書き込みは行わず変更内容をプレビュー表示します。
+func applyManagerEnable(cfg *config.Config, proposal managerEnableProposal, dryRun bool) error {
+	configPath, err := config.ConfigPath()
+	if err != nil {
+		return fmt.Errorf("設定ファイルパスの取得に失敗: %w", err)
+	}
+
+	fileExists, _, err := config.ConfigFileExists()
+	if err != nil {
+		return fmt.Errorf("設定ファイルの確認に失敗: %w", err)
+	}
+
+	if dryRun {
+		printManagerEnableDryRun(os.Stdout, proposal, configPath, fileExists)
+		return nil
+	}
+
+	return writeManagerEnable(cfg, proposal, configPath, fileExists)
+}
+
+// printManagerEnableDryRun は dry-run モードでの sys.enable の変更プレビューを表示します。
+func printManagerEnableDryRun(w io.Writer, proposal managerEnableProposal, configPath string, fileExists bool) {
+	fmt.Fprintln(w, "[dry-run] sys.enable への変更プレビュー（書き込みは行いません）:")
+
+	if !fileExists && proposal.HasChanges() {
+		fmt.Fprintf(w, "  ⚠️  config.yaml が存在しないため、新規作成されます: %s\n", configPath)
+	}
+
+	if !proposal.HasChanges() {
+		fmt.Fprintln(w, "  追加対象なし（利用可能なマネージャはすべて有効化済み）")
+	}
+
+	printManagerEnableDiff(w, proposal)
+
+	if fileExists {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "  ⚠️  注意: 既存 config.yaml 内のコメントは書き込み時に保持されません。")
+	}
+
+	fmt.Fprintln(w)
+}
+
+// writeManagerEnable は sys.enable と既定のマネージャ設定を設定ファイルにアトミックに書き込みます。
+func writeManagerEnable(cfg *config.Config, proposal managerEnableProposal, configPath string, fileExists bool) error {
+	if !proposal.HasChanges() {
+		fmt.Println("✅ sys.enable に追加する新しいマネージャはありませんでした")
+		return nil
+	}
+
+	fmt.Println("sys.enable への変更:")
+	printManagerEnableDiff(os.Stdout, proposal)
+	fmt.Println()
+
+	if !fileExists {
+		fmt.Printf("config.yaml が存在しないため新規作成します: %s\n", configPath)
+	}
+
+	cfg.Sys.Enable = append(append([]string(nil), proposal.Existing...), proposal.ToAdd...)
+
+	if len(proposal.Defaults) > 0 && cfg.Sys.Managers == nil {
+		cfg.Sys.Managers = make(map[string]config.ManagerConfig)
+	}
+
+	for name, defaults := range proposal.Defaults {
+		mc := make(config.ManagerConfig, len(defaults))
+		for key, value := range defaults {
+			mc[key] = value
+		}
+
+		cfg.Sys.Managers[name] = mc
+	}
+
+	backupPath, err := config.SaveAtomic(cfg, "")
+	if err != nil {
+		return fmt.Errorf("設定ファイルの書き込みに失敗: %w", err)
+	}
+
+	fmt.Printf("✅ sys.enable を更新しました（%d 件追加）\n", len(proposal.ToAdd))
+	fmt.Printf("   設定ファイル: %s\n", configPath)
+
+	if backupPath != "" {
+		fmt.Printf("   バックアップ:  %s\n", backupPath)
+	}
+
+	return nil
+}
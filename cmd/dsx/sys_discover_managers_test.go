@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/scottlz0310/dsx/internal/updater"
+)
+
+// discoverStubUpdater は利用可否と利用不可の理由を指定できるテスト用 Updater です。
+type discoverStubUpdater struct {
+	stubUpdater
+	available bool
+	reason    string
+}
+
+func (s discoverStubUpdater) IsAvailable() bool {
+	return s.available
+}
+
+func (s discoverStubUpdater) UnavailableReason() string {
+	return s.reason
+}
+
+func TestDiscoverManagers(t *testing.T) {
+	updaters := []updater.Updater{
+		discoverStubUpdater{stubUpdater: stubUpdater{name: "zzz-plugin"}, available: true},
+		discoverStubUpdater{stubUpdater: stubUpdater{name: "cargo"}, available: true},
+		discoverStubUpdater{stubUpdater: stubUpdater{name: "apt"}, available: false, reason: "apt が見つかりません"},
+		discoverStubUpdater{stubUpdater: stubUpdater{name: "pipx"}, available: true},
+		discoverStubUpdater{stubUpdater: stubUpdater{name: "npm"}, available: true},
+	}
+
+	counts := map[string]int{"cargo": 3, "pipx": 0}
+	count := func(_ context.Context, u updater.Updater) (int, error) {
+		if u.Name() == "npm" {
+			return unknownPackageCount, errors.New("npm ls が失敗しました")
+		}
+
+		if n, ok := counts[u.Name()]; ok {
+			return n, nil
+		}
+
+		return unknownPackageCount, nil
+	}
+
+	cfg := &config.Config{}
+	cfg.Sys.Enable = []string{"cargo"}
+
+	got := discoverManagers(context.Background(), updaters, cfg, count)
+
+	want := []managerDiscovery{
+		{Name: "apt", Reason: "apt が見つかりません", PackageCount: unknownPackageCount},
+		{Name: "npm", Available: true, Reason: "パッケージ数の取得に失敗: npm ls が失敗しました", PackageCount: unknownPackageCount},
+		{Name: "pipx", Available: true, PackageCount: 0},
+		{Name: "cargo", Available: true, PackageCount: 3, Enabled: true},
+		{Name: "zzz-plugin", Available: true, PackageCount: unknownPackageCount},
+	}
+
+	if !slices.Equal(got, want) {
+		t.Fatalf("discoverManagers() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestManagerDiscovery_Proposed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		discovery managerDiscovery
+		want      bool
+	}{
+		{
+			name:      "パッケージがあるマネージャは提案する",
+			discovery: managerDiscovery{Name: "cargo", Available: true, PackageCount: 2},
+			want:      true,
+		},
+		{
+			name:      "件数不明のマネージャは提案する",
+			discovery: managerDiscovery{Name: "nvm", Available: true, PackageCount: unknownPackageCount},
+			want:      true,
+		},
+		{
+			name:      "パッケージがないマネージャは提案しない",
+			discovery: managerDiscovery{Name: "pipx", Available: true, PackageCount: 0},
+			want:      false,
+		},
+		{
+			name:      "利用できないマネージャは提案しない",
+			discovery: managerDiscovery{Name: "apt", PackageCount: unknownPackageCount},
+			want:      false,
+		},
+		{
+			name:      "有効化済みのマネージャは提案しない",
+			discovery: managerDiscovery{Name: "go", Available: true, PackageCount: 5, Enabled: true},
+			want:      false,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tc.discovery.Proposed(); got != tc.want {
+				t.Fatalf("Proposed() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBuildManagerEnableProposal(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{}
+	cfg.Sys.Enable = []string{"go", "apt"}
+	cfg.Sys.Managers = map[string]config.ManagerConfig{
+		"fwupdmgr": {"timeout": "10m"},
+	}
+
+	discoveries := []managerDiscovery{
+		{Name: "apt", PackageCount: unknownPackageCount, Enabled: true},
+		{Name: "go", Available: true, PackageCount: 4, Enabled: true},
+		{Name: "snap", Available: true, PackageCount: 6},
+		{Name: "fwupdmgr", Available: true, PackageCount: unknownPackageCount},
+		{Name: "pipx", Available: true, PackageCount: 0},
+		{Name: "cargo", Available: true, PackageCount: 1},
+	}
+
+	got := buildManagerEnableProposal(cfg, discoveries)
+
+	if !slices.Equal(got.Existing, []string{"go", "apt"}) {
+		t.Fatalf("Existing = %v, want [go apt]", got.Existing)
+	}
+
+	if !slices.Equal(got.ToAdd, []string{"snap", "fwupdmgr", "cargo"}) {
+		t.Fatalf("ToAdd = %v, want [snap fwupdmgr cargo]", got.ToAdd)
+	}
+
+	if len(got.Defaults) != 1 {
+		t.Fatalf("Defaults = %v, want only snap（設定済みの fwupdmgr は提案しない）", got.Defaults)
+	}
+
+	if snap := got.Defaults["snap"]; snap["timeout"] != "5m" || snap["retries"] != 2 {
+		t.Fatalf("Defaults[snap] = %v, want timeout=5m retries=2", snap)
+	}
+
+	if !slices.Equal(got.Unavailable, []string{"apt"}) {
+		t.Fatalf("Unavailable = %v, want [apt]", got.Unavailable)
+	}
+
+	if !got.HasChanges() {
+		t.Fatal("HasChanges() = false, want true")
+	}
+}
+
+func TestBuildManagerEnableProposal_NoChanges(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{}
+	cfg.Sys.Enable = []string{"go"}
+
+	got := buildManagerEnableProposal(cfg, []managerDiscovery{
+		{Name: "go", Available: true, PackageCount: 4, Enabled: true},
+		{Name: "pipx", Available: true, PackageCount: 0},
+	})
+
+	if got.HasChanges() {
+		t.Fatalf("HasChanges() = true, want false: %+v", got)
+	}
+}
+
+func TestPrintManagerDiscoveries(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	printManagerDiscoveries(&buf, []managerDiscovery{
+		{Name: "apt", Reason: "apt が見つかりません", PackageCount: unknownPackageCount},
+		{Name: "npm", Available: true, Reason: "パッケージ数の取得に失敗: timeout", PackageCount: unknownPackageCount},
+		{Name: "pipx", Available: true, PackageCount: 0},
+		{Name: "cargo", Available: true, PackageCount: 3},
+		{Name: "go", Available: true, PackageCount: 5, Enabled: true},
+	})
+
+	lines := strings.Split(buf.String(), "\n")
+
+	for _, tc := range []struct {
+		prefix string
+		want   []string
+	}{
+		{prefix: "  apt ", want: []string{"❌ apt が見つかりません", "-"}},
+		{prefix: "  npm ", want: []string{"✅ 利用可能", "+ 追加候補"}},
+		{prefix: "  pipx ", want: []string{"0", "-（パッケージなし）"}},
+		{prefix: "  cargo ", want: []string{"3", "+ 追加候補"}},
+		{prefix: "  go ", want: []string{"5", "有効"}},
+	} {
+		idx := slices.IndexFunc(lines, func(line string) bool { return strings.HasPrefix(line, tc.prefix) })
+		if idx < 0 {
+			t.Fatalf("output does not contain a row for %q:\n%s", tc.prefix, buf.String())
+		}
+
+		for _, want := range tc.want {
+			if !strings.Contains(lines[idx], want) {
+				t.Fatalf("row %q does not contain %q", lines[idx], want)
+			}
+		}
+	}
+
+	if !strings.Contains(buf.String(), "⚠️  npm: パッケージ数の取得に失敗: timeout") {
+		t.Fatalf("output does not contain the count error:\n%s", buf.String())
+	}
+}
+
+func TestPrintManagerEnableDryRun(t *testing.T) {
+	t.Parallel()
+
+	proposal := managerEnableProposal{
+		Existing:    []string{"go"},
+		ToAdd:       []string{"snap", "cargo"},
+		Defaults:    map[string]config.ManagerConfig{"snap": {"timeout": "5m", "retries": 2}},
+		Unavailable: []string{"apt"},
+	}
+
+	var buf bytes.Buffer
+
+	printManagerEnableDryRun(&buf, proposal, "/home/user/.config/dsx/config.yaml", true)
+
+	got := buf.String()
+	for _, want := range []string{
+		"[dry-run] sys.enable への変更プレビュー（書き込みは行いません）:",
+		"  sys.enable:\n    go\n  + snap\n  + cargo\n",
+		"  sys.managers:\n  + snap: {retries: 2, timeout: 5m}\n",
+		"⚠️  apt は sys.enable に含まれていますが、この環境では利用できません",
+		"コメントは書き込み時に保持されません",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("output does not contain %q:\n%s", want, got)
+		}
+	}
+
+	if strings.Contains(got, "新規作成されます") {
+		t.Fatalf("既存ファイルなのに新規作成の警告が表示されている:\n%s", got)
+	}
+}
+
+func TestPrintManagerEnableDryRun_NoChangesNewFile(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	printManagerEnableDryRun(&buf, managerEnableProposal{Existing: []string{"go"}}, "/home/user/.config/dsx/config.yaml", false)
+
+	got := buf.String()
+	if !strings.Contains(got, "追加対象なし") {
+		t.Fatalf("output does not contain 追加対象なし:\n%s", got)
+	}
+
+	if strings.Contains(got, "新規作成されます") || strings.Contains(got, "コメントは書き込み時に保持されません") {
+		t.Fatalf("変更なし・新規ファイルなのに警告が表示されている:\n%s", got)
+	}
+}
+
+func TestWriteManagerEnable(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	cfg := config.Default()
+	cfg.Sys.Enable = []string{"go"}
+	cfg.Sys.Managers = nil
+
+	proposal := managerEnableProposal{
+		Existing: []string{"go"},
+		ToAdd:    []string{"snap"},
+		Defaults: map[string]config.ManagerConfig{"snap": {"timeout": "5m", "retries": 2}},
+	}
+
+	out := captureStdout(t, func() {
+		if err := writeManagerEnable(cfg, proposal, "config.yaml", false); err != nil {
+			t.Fatalf("writeManagerEnable() error = %v", err)
+		}
+	})
+
+	if !strings.Contains(out, "sys.enable を更新しました（1 件追加）") {
+		t.Fatalf("output does not contain the summary:\n%s", out)
+	}
+
+	loaded, err := config.Load()
+	if err != nil {
+		t.Fatalf("config.Load() error = %v", err)
+	}
+
+	if !slices.Equal(loaded.Sys.Enable, []string{"go", "snap"}) {
+		t.Fatalf("sys.enable = %v, want [go snap]", loaded.Sys.Enable)
+	}
+
+	if timeout := loaded.Sys.Managers["snap"]["timeout"]; timeout != "5m" {
+		t.Fatalf("sys.managers.snap.timeout = %v, want 5m", timeout)
+	}
+}
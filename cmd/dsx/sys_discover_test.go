@@ -1,7 +1,7 @@
 package main
 
 import (
-	"context"
+	"slices"
 	"strings"
 	"testing"
 
@@ -23,9 +23,9 @@ func TestIsSupportedDiscoverManager(t *testing.T) {
 			want:  true,
 		},
 		{
-			name:  "apt は未対応",
+			name:  "apt は対応している",
 			input: "apt",
-			want:  false,
+			want:  true,
 		},
 		{
 			name:  "空文字列は未対応",
@@ -38,8 +38,8 @@ func TestIsSupportedDiscoverManager(t *testing.T) {
 			want:  false,
 		},
 		{
-			name:  "brew は未対応",
-			input: "brew",
+			name:  "未登録のマネージャは未対応",
+			input: "no_such_manager",
 			want:  false,
 		},
 	}
@@ -66,12 +66,6 @@ func TestResolveDiscoverManagers(t *testing.T) {
 		want    []string
 		wantErr bool
 	}{
-		{
-			name:    "空文字列は全対応マネージャを返す",
-			manager: "",
-			want:    supportedDiscoverManagers,
-			wantErr: false,
-		},
 		{
 			name:    "go を指定すると go のみ返す",
 			manager: "go",
@@ -79,10 +73,10 @@ func TestResolveDiscoverManagers(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name:    "未対応マネージャはエラー",
+			name:    "登録済みマネージャはそのマネージャのみ返す",
 			manager: "apt",
-			want:    nil,
-			wantErr: true,
+			want:    []string{"apt"},
+			wantErr: false,
 		},
 		{
 			name:    "未知の文字列はエラー",
@@ -227,26 +221,32 @@ func TestResolveDiscoverManagers_ErrorMessage(t *testing.T) {
 	t.Parallel()
 
 	// エラーメッセージに未対応マネージャ名が含まれることを確認
-	_, err := resolveDiscoverManagers("brew")
+	_, err := resolveDiscoverManagers("no_such_manager")
 	if err == nil {
 		t.Fatal("未対応マネージャでエラーが返らなかった")
 	}
 
-	if !strings.Contains(err.Error(), "brew") {
-		t.Fatalf("エラーメッセージにマネージャ名「brew」が含まれていない: %q", err.Error())
+	if !strings.Contains(err.Error(), "no_such_manager") {
+		t.Fatalf("エラーメッセージにマネージャ名「no_such_manager」が含まれていない: %q", err.Error())
 	}
 }
 
-func TestDiscoverManager_UnsupportedManagerReturnsError(t *testing.T) {
+func TestResolveDiscoverManagers_AllRegistered(t *testing.T) {
 	t.Parallel()
 
-	err := discoverManager(context.Background(), "apt", false, false)
-	if err == nil {
-		t.Fatal("未対応マネージャでエラーが返らなかった")
+	got, err := resolveDiscoverManagers("")
+	if err != nil {
+		t.Fatalf("予期しないエラー: %v", err)
+	}
+
+	for _, want := range []string{"apt", "cargo", "go", "npm", "pipx"} {
+		if !slices.Contains(got, want) {
+			t.Fatalf("resolveDiscoverManagers(\"\") = %v, want containing %q", got, want)
+		}
 	}
 
-	if !strings.Contains(err.Error(), "apt") {
-		t.Fatalf("エラーメッセージに「apt」が含まれていない: %q", err.Error())
+	if !slices.IsSorted(got) {
+		t.Fatalf("resolveDiscoverManagers(\"\") = %v, want sorted", got)
 	}
 }
 
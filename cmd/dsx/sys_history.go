@@ -0,0 +1,285 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/scottlz0310/dsx/internal/history"
+	"github.com/spf13/cobra"
+)
+
+const historyDateLayout = "2006-01-02"
+
+var (
+	sysHistoryManager string
+	sysHistoryPackage string
+	sysHistorySince   string
+	sysHistoryUntil   string
+	sysHistoryLimit   int
+	sysHistoryOutput  string
+)
+
+// sysHistoryCmd は sys update の実行履歴を表示するコマンドです
+var sysHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "sys update の更新履歴を表示します",
+	Long: `sys update の実行ごとに記録したパッケージの更新履歴（旧 → 新）を表示します。
+履歴は $XDG_STATE_HOME/dsx/history.jsonl（未設定時は ~/.local/state/dsx/history.jsonl）に
+追記されます。ドライランの実行は記録しません。
+
+--since / --until には日付（2006-01-02）または RFC3339 形式の日時を指定できます。
+いずれも指定した日時ちょうどに開始した実行を含みます。
+--until に日付のみを指定した場合は、その日の終わりまでを含みます。
+
+例:
+  dsx sys history                         # すべての履歴を表示
+  dsx sys history -m nvm -p node          # nvm の node の更新履歴
+  dsx sys history -p 'linux-*'            # パターンでパッケージを絞り込み
+  dsx sys history --since 2026-01-01      # 指定日以降の履歴
+  dsx sys history -n 20 -o json           # 直近20件を JSON で出力`,
+	Args: cobra.NoArgs,
+	RunE: runSysHistory,
+}
+
+func init() {
+	sysCmd.AddCommand(sysHistoryCmd)
+
+	sysHistoryCmd.Flags().StringVarP(&sysHistoryManager, "manager", "m", "", "マネージャ名で絞り込み")
+	sysHistoryCmd.Flags().StringVarP(&sysHistoryPackage, "package", "p", "", "パッケージ名（グロブ可）で絞り込み")
+	sysHistoryCmd.Flags().StringVar(&sysHistorySince, "since", "", "この日時以降の履歴のみ表示")
+	sysHistoryCmd.Flags().StringVar(&sysHistoryUntil, "until", "", "この日時以前の履歴のみ表示")
+	sysHistoryCmd.Flags().IntVarP(&sysHistoryLimit, "limit", "n", 0, "直近 N 件のみ表示（0以下は無制限）")
+	sysHistoryCmd.Flags().StringVarP(&sysHistoryOutput, "output", "o", outputFormatText, "出力形式（text / json）")
+}
+
+// sysHistoryReport は sys history --output json で出力するドキュメントです。
+type sysHistoryReport struct {
+	Path    string          `json:"path"`
+	Entries []history.Entry `json:"entries"`
+}
+
+func runSysHistory(cmd *cobra.Command, args []string) error {
+	format, err := parseOutputFormat(sysHistoryOutput)
+	if err != nil {
+		return err
+	}
+
+	filter, err := buildHistoryFilter(sysHistoryManager, sysHistoryPackage, sysHistorySince, sysHistoryUntil)
+	if err != nil {
+		return err
+	}
+
+	store, err := history.NewStore("")
+	if err != nil {
+		return err
+	}
+
+	runs, err := store.Load()
+	if err != nil {
+		return err
+	}
+
+	entries := limitHistoryEntries(history.Entries(runs, filter), sysHistoryLimit)
+
+	if format == outputFormatJSON {
+		if entries == nil {
+			entries = []history.Entry{}
+		}
+
+		return writeJSONReport(os.Stdout, sysHistoryReport{Path: store.Path(), Entries: entries})
+	}
+
+	if len(runs) == 0 {
+		fmt.Printf("📭 更新履歴はまだありません（%s）\n", store.Path())
+		return nil
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("📭 条件に一致する更新履歴はありません")
+		return nil
+	}
+
+	if err := writeHistoryTable(os.Stdout, entries); err != nil {
+		return fmt.Errorf("一覧表示に失敗: %w", err)
+	}
+
+	return nil
+}
+
+// buildHistoryFilter はフラグの値から履歴の絞り込み条件を組み立てます。
+func buildHistoryFilter(manager, pkg, since, until string) (history.Filter, error) {
+	filter := history.Filter{
+		Manager: strings.TrimSpace(manager),
+		Package: strings.TrimSpace(pkg),
+	}
+
+	var err error
+
+	if filter.Since, err = parseHistoryTime(since, false); err != nil {
+		return history.Filter{}, fmt.Errorf("--since の値が不正です: %w", err)
+	}
+
+	if filter.Until, err = parseHistoryTime(until, true); err != nil {
+		return history.Filter{}, fmt.Errorf("--until の値が不正です: %w", err)
+	}
+
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && filter.Since.After(filter.Until) {
+		return history.Filter{}, fmt.Errorf("--since は --until 以前の日時を指定してください")
+	}
+
+	return filter, nil
+}
+
+// parseHistoryTime は日付（ローカル時刻）または RFC3339 の日時を解釈します。
+// endOfDay が true で日付のみが指定された場合は、その日の終わり（翌日の 0 時の直前）を返します。
+func parseHistoryTime(value string, endOfDay bool) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.ParseInLocation(historyDateLayout, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q（2006-01-02 または RFC3339 形式で指定してください）", value)
+	}
+
+	if endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+
+	return t, nil
+}
+
+// limitHistoryEntries は直近 limit 件の行のみを返します。
+func limitHistoryEntries(entries []history.Entry, limit int) []history.Entry {
+	if limit <= 0 || len(entries) <= limit {
+		return entries
+	}
+
+	return entries[len(entries)-limit:]
+}
+
+func writeHistoryTable(output io.Writer, entries []history.Entry) error {
+	writer := tabwriter.NewWriter(output, 0, 8, 2, ' ', 0)
+
+	if _, err := fmt.Fprintln(writer, "日時\t実行ID\tマネージャ\tパッケージ\t旧\t新\t状態"); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintln(writer, "----\t------\t----------\t----------\t--\t--\t----"); err != nil {
+		return err
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+
+		if _, err := fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			entry.Time.Local().Format("2006-01-02 15:04"),
+			entry.RunID,
+			historyManagerLabel(entry.Manager, entry.Phase),
+			versionOrDash(entry.Package),
+			versionOrDash(entry.From),
+			versionOrDash(entry.To),
+			historyStatusLabel(entry)); err != nil {
+			return err
+		}
+	}
+
+	return writer.Flush()
+}
+
+func historyManagerLabel(name, phase string) string {
+	if phase == history.PhaseSelfUpdate {
+		return name + " (本体)"
+	}
+
+	return name
+}
+
+func historyStatusLabel(entry *history.Entry) string {
+	if entry.Error != "" {
+		return entry.Status + ": " + entry.Error
+	}
+
+	return entry.Status
+}
+
+// recordSysUpdateHistory は sys update の結果を履歴ファイルに追記します。
+// 履歴の記録に失敗しても更新自体は完了しているため、警告の表示のみ行います。
+func recordSysUpdateHistory(report *sysUpdateReport, runID string, startedAt, finishedAt time.Time) {
+	if report.DryRun || len(report.SelfUpdate)+len(report.Managers) == 0 {
+		return
+	}
+
+	store, err := history.NewStore("")
+	if err == nil {
+		err = store.Append(buildHistoryRun(report, runID, startedAt, finishedAt))
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  更新履歴の記録に失敗しました: %v\n", err)
+	}
+}
+
+// buildHistoryRun は sys update のレポートから履歴1件分の記録を組み立てます。
+func buildHistoryRun(report *sysUpdateReport, runID string, startedAt, finishedAt time.Time) *history.Run {
+	run := &history.Run{
+		ID:         runID,
+		StartedAt:  startedAt,
+		FinishedAt: finishedAt,
+		Version:    report.Version,
+		Success:    report.Success,
+		Updated:    report.Summary.Updated,
+		Failed:     report.Summary.Failed,
+		Managers:   make([]history.ManagerRecord, 0, len(report.SelfUpdate)+len(report.Managers)),
+	}
+
+	for i := range report.SelfUpdate {
+		run.Managers = append(run.Managers, newHistoryRecord(&report.SelfUpdate[i], history.PhaseSelfUpdate))
+	}
+
+	for i := range report.Managers {
+		run.Managers = append(run.Managers, newHistoryRecord(&report.Managers[i], history.PhaseUpdate))
+	}
+
+	return run
+}
+
+func newHistoryRecord(report *managerReport, phase string) history.ManagerRecord {
+	record := history.ManagerRecord{
+		Name:         report.Name,
+		Phase:        phase,
+		Status:       report.Status,
+		DurationMs:   report.DurationMs,
+		UpdatedCount: report.UpdatedCount,
+		Packages:     newHistoryPackageChanges(report.Packages),
+		Errors:       report.Errors,
+	}
+
+	if len(report.Held) > 0 {
+		record.Held = newHistoryPackageChanges(report.Held)
+	}
+
+	return record
+}
+
+func newHistoryPackageChanges(packages []packageReport) []history.PackageChange {
+	changes := make([]history.PackageChange, 0, len(packages))
+	for _, pkg := range packages {
+		changes = append(changes, history.PackageChange{
+			Name: pkg.Name,
+			From: pkg.CurrentVersion,
+			To:   pkg.NewVersion,
+		})
+	}
+
+	return changes
+}
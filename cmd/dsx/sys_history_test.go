@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottlz0310/dsx/internal/history"
+	"github.com/scottlz0310/dsx/internal/runner"
+)
+
+func TestParseHistoryTime(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		value    string
+		endOfDay bool
+		want     time.Time
+		wantErr  bool
+	}{
+		{name: "空文字", value: "", want: time.Time{}},
+		{name: "日付", value: "2026-03-01", want: time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)},
+		{name: "日付（終端）", value: "2026-03-01", endOfDay: true, want: time.Date(2026, 3, 1, 23, 59, 59, 999999999, time.Local)},
+		{name: "RFC3339", value: "2026-03-01T09:30:00Z", endOfDay: true, want: time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)},
+		{name: "不正な形式", value: "yesterday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseHistoryTime(tt.value, tt.endOfDay)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseHistoryTime(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Fatalf("parseHistoryTime(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildHistoryFilter_InvalidRange(t *testing.T) {
+	t.Parallel()
+
+	if _, err := buildHistoryFilter("", "", "2026-03-02", "2026-03-01"); err == nil {
+		t.Fatal("buildHistoryFilter error = nil, want range error")
+	}
+
+	if _, err := buildHistoryFilter("", "", "2026-03-01T09:00:00Z", "2026-03-01T09:00:00Z"); err != nil {
+		t.Fatalf("buildHistoryFilter with equal bounds error: %v", err)
+	}
+
+	filter, err := buildHistoryFilter(" nvm ", "node", "2026-03-01", "2026-03-01")
+	if err != nil {
+		t.Fatalf("buildHistoryFilter error: %v", err)
+	}
+
+	if filter.Manager != "nvm" || filter.Package != "node" || !filter.Since.Before(filter.Until) {
+		t.Fatalf("filter = %+v, want trimmed single-day filter", filter)
+	}
+}
+
+func TestBuildHistoryRun(t *testing.T) {
+	t.Parallel()
+
+	startedAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	report := &sysUpdateReport{
+		Version: "v1.2.3",
+		Success: true,
+		SelfUpdate: []managerReport{
+			{Name: "uv", Status: managerReportStatusSuccess, Packages: []packageReport{{Name: "uv", CurrentVersion: "0.11.16", NewVersion: "0.11.17"}}},
+		},
+		Managers: []managerReport{
+			{
+				Name:         "nvm",
+				Status:       managerReportStatusSuccess,
+				DurationMs:   1500,
+				UpdatedCount: 1,
+				Packages:     []packageReport{{Name: "node", CurrentVersion: "20.11.0", NewVersion: "22.3.0"}},
+				Held:         []packageReport{},
+			},
+		},
+		Summary: summaryReport{Updated: 2},
+	}
+
+	run := buildHistoryRun(report, "20260301T090000Z-3f9a1c", startedAt, startedAt.Add(time.Minute))
+
+	if run.ID != "20260301T090000Z-3f9a1c" || run.Version != "v1.2.3" || run.Updated != 2 {
+		t.Fatalf("run = %+v, want ID/version/updated from report", run)
+	}
+
+	if len(run.Managers) != 2 || run.Managers[0].Phase != history.PhaseSelfUpdate || run.Managers[1].Phase != history.PhaseUpdate {
+		t.Fatalf("managers = %+v, want self update then update", run.Managers)
+	}
+
+	nvm := run.Managers[1]
+	if nvm.DurationMs != 1500 || nvm.UpdatedCount != 1 || nvm.Packages[0].From != "20.11.0" || nvm.Packages[0].To != "22.3.0" || nvm.Held != nil {
+		t.Fatalf("nvm record = %+v, want transition and duration", nvm)
+	}
+}
+
+func TestRecordSysUpdateHistory(t *testing.T) {
+	stateHome := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", stateHome)
+
+	startedAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	report := &sysUpdateReport{Managers: []managerReport{{Name: "apt", Status: managerReportStatusSuccess}}}
+
+	recordSysUpdateHistory(&sysUpdateReport{DryRun: true, Managers: report.Managers}, "run-1", startedAt, startedAt)
+	recordSysUpdateHistory(&sysUpdateReport{}, "run-2", startedAt, startedAt)
+	recordSysUpdateHistory(report, "run-3", startedAt, startedAt.Add(time.Second))
+
+	store, err := history.NewStore(filepath.Join(stateHome, "dsx", "history.jsonl"))
+	if err != nil {
+		t.Fatalf("NewStore error: %v", err)
+	}
+
+	runs, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+
+	if len(runs) != 1 || runs[0].Managers[0].Name != "apt" {
+		t.Fatalf("runs = %+v, want only the non-dry-run record", runs)
+	}
+}
+
+func TestWriteHistoryTable(t *testing.T) {
+	t.Parallel()
+
+	entries := []history.Entry{
+		{RunID: "20260301T090000Z", Time: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC), Manager: "nvm", Phase: history.PhaseUpdate, Package: "node", From: "20.11.0", To: "22.3.0", Status: "success"},
+		{RunID: "20260301T090000Z", Time: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC), Manager: "uv", Phase: history.PhaseSelfUpdate, Package: "uv", To: "0.11.17", Status: "success"},
+		{RunID: "20260301T090000Z", Time: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC), Manager: "npm", Phase: history.PhaseUpdate, Status: "failed", Error: "boom"},
+	}
+
+	var buf bytes.Buffer
+	if err := writeHistoryTable(&buf, entries); err != nil {
+		t.Fatalf("writeHistoryTable error: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"実行ID", "20260301T090000Z", "node", "20.11.0", "22.3.0", "uv (本体)", "failed: boom"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("output does not contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestLimitHistoryEntries(t *testing.T) {
+	t.Parallel()
+
+	entries := []history.Entry{{RunID: "a"}, {RunID: "b"}, {RunID: "c"}}
+
+	if got := limitHistoryEntries(entries, 0); len(got) != 3 {
+		t.Fatalf("limit 0 = %d entries, want 3", len(got))
+	}
+
+	got := limitHistoryEntries(entries, 2)
+	if len(got) != 2 || got[0].RunID != "b" || got[1].RunID != "c" {
+		t.Fatalf("limit 2 = %+v, want latest two", got)
+	}
+}
+
+func TestApplySummaryDurations(t *testing.T) {
+	t.Parallel()
+
+	reports := []managerReport{{Name: "uv"}, {Name: "npm", DurationMs: 7}}
+	summary := runner.Summary{Results: []runner.Result{
+		{Name: "uv" + selfUpdateJobSuffix, Duration: 1500 * time.Millisecond},
+		{Name: "npm" + selfUpdateJobSuffix, Duration: time.Second},
+	}}
+
+	applySummaryJobResults(reports, summary, selfUpdateJobSuffix)
+
+	if reports[0].DurationMs != 1500 || reports[1].DurationMs != 7 {
+		t.Fatalf("reports = %+v, want uv=1500ms and npm unchanged", reports)
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/scottlz0310/dsx/internal/runner"
+	"github.com/scottlz0310/dsx/internal/updater"
+)
+
+const (
+	outputFormatText = "text"
+	outputFormatJSON = "json"
+)
+
+const (
+	managerReportStatusSuccess = "success"
+	managerReportStatusFailed  = "failed"
+	managerReportStatusSkipped = "skipped"
+)
+
+// parseOutputFormat は --output フラグの値を検証して正規化します。
+func parseOutputFormat(value string) (string, error) {
+	format := strings.ToLower(strings.TrimSpace(value))
+
+	switch format {
+	case "", outputFormatText:
+		return outputFormatText, nil
+	case outputFormatJSON:
+		return outputFormatJSON, nil
+	default:
+		return "", fmt.Errorf("未対応の出力形式です: %s（text または json を指定してください）", value)
+	}
+}
+
+// packageReport は PackageInfo の JSON 表現です。
+type packageReport struct {
+	Name           string `json:"name"`
+	CurrentVersion string `json:"current_version,omitempty"`
+	NewVersion     string `json:"new_version,omitempty"`
+}
+
+// managerReport は1マネージャ分の UpdateResult の JSON 表現です。
+type managerReport struct {
+	Name         string          `json:"name"`
+	DisplayName  string          `json:"display_name,omitempty"`
+	Status       string          `json:"status"`
+	UpdatedCount int             `json:"updated_count"`
+	FailedCount  int             `json:"failed_count"`
+	Packages     []packageReport `json:"packages"`
+	Held         []packageReport `json:"held"`
+	Errors       []string        `json:"errors"`
+	Message      string          `json:"message,omitempty"`
+	// DurationMs は Update / SelfUpdate の所要時間（ミリ秒）です。
+	DurationMs int64 `json:"duration_ms"`
+	// Continuation はマネージャ本体更新フェーズでのみ設定されます。
+	Continuation string `json:"continuation,omitempty"`
+	// LogPath は --log-dir 指定時のコマンド出力ログのパスです。
+	LogPath string `json:"log_path,omitempty"`
+}
+
+// summaryReport は updateStats の JSON 表現です。
+type summaryReport struct {
+	Updated int      `json:"updated"`
+	Failed  int      `json:"failed"`
+	Errors  []string `json:"errors"`
+}
+
+// sysUpdateReport は sys update --output json で出力するドキュメントです。
+type sysUpdateReport struct {
+	Version    string          `json:"version"`
+	DryRun     bool            `json:"dry_run"`
+	Success    bool            `json:"success"`
+	SelfUpdate []managerReport `json:"self_update"`
+	Managers   []managerReport `json:"managers"`
+	Summary    summaryReport   `json:"summary"`
+}
+
+func newPackageReports(packages []updater.PackageInfo) []packageReport {
+	reports := make([]packageReport, 0, len(packages))
+	for _, pkg := range packages {
+		reports = append(reports, packageReport{
+			Name:           pkg.Name,
+			CurrentVersion: pkg.CurrentVersion,
+			NewVersion:     pkg.NewVersion,
+		})
+	}
+
+	return reports
+}
+
+func errorStrings(errs []error) []string {
+	result := make([]string, 0, len(errs))
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		result = append(result, err.Error())
+	}
+
+	return result
+}
+
+// newManagerReport は Update / SelfUpdate の結果からマネージャ別レポートを生成します。
+// runErr は Update 自体が返したエラーです（result が nil の場合もあります）。
+func newManagerReport(u updater.Updater, result *updater.UpdateResult, runErr error) managerReport {
+	report := managerReport{
+		Name:        u.Name(),
+		DisplayName: u.DisplayName(),
+		Status:      managerReportStatusSuccess,
+		Packages:    []packageReport{},
+		Held:        []packageReport{},
+		Errors:      []string{},
+	}
+
+	if result != nil {
+		report.UpdatedCount = result.UpdatedCount
+		report.FailedCount = result.FailedCount
+		report.Packages = newPackageReports(result.Packages)
+		report.Held = newPackageReports(result.Held)
+		report.Errors = errorStrings(result.Errors)
+		report.Message = result.Message
+	}
+
+	if runErr != nil {
+		report.Errors = append(report.Errors, runErr.Error())
+	}
+
+	if runErr != nil || report.FailedCount > 0 || len(report.Errors) > 0 {
+		report.Status = managerReportStatusFailed
+	}
+
+	return report
+}
+
+func newSkippedManagerReport(name, displayName string, reason error) managerReport {
+	report := managerReport{
+		Name:        name,
+		DisplayName: displayName,
+		Status:      managerReportStatusSkipped,
+		Packages:    []packageReport{},
+		Held:        []packageReport{},
+		Errors:      []string{},
+	}
+
+	if reason != nil {
+		report.Errors = append(report.Errors, reason.Error())
+	}
+
+	return report
+}
+
+// collectSkippedManagerReports は runner.Summary からスキップされたジョブをレポート化します。
+func collectSkippedManagerReports(summary runner.Summary, updaters []updater.Updater, nameSuffix string) []managerReport {
+	displayNames := make(map[string]string, len(updaters))
+	for _, u := range updaters {
+		displayNames[u.Name()+nameSuffix] = u.DisplayName()
+	}
+
+	var reports []managerReport
+
+	for _, r := range summary.Results {
+		if r.Status != runner.StatusSkipped {
+			continue
+		}
+
+		reports = append(reports, newSkippedManagerReport(strings.TrimSuffix(r.Name, nameSuffix), displayNames[r.Name], r.Err))
+	}
+
+	return reports
+}
+
+// applySummaryJobResults は runner.Summary のジョブ所要時間と出力ログのパスをマネージャ別レポートに反映します。
+// 所要時間が既に設定されているレポートは上書きしません。
+func applySummaryJobResults(reports []managerReport, summary runner.Summary, nameSuffix string) {
+	results := make(map[string]runner.Result, len(summary.Results))
+	for _, r := range summary.Results {
+		results[strings.TrimSuffix(r.Name, nameSuffix)] = r
+	}
+
+	for i := range reports {
+		r, ok := results[reports[i].Name]
+		if !ok {
+			continue
+		}
+
+		if reports[i].DurationMs == 0 {
+			reports[i].DurationMs = r.Duration.Milliseconds()
+		}
+
+		if reports[i].LogPath == "" {
+			reports[i].LogPath = r.LogPath
+		}
+	}
+}
+
+// buildSysUpdateReport は実行結果から JSON 出力用のドキュメントを組み立てます。
+// 並列実行で順序が不定になるため、マネージャは sys.enable の順序に並べ直します。
+func buildSysUpdateReport(stats updateStats, enabled []updater.Updater, dryRun bool) sysUpdateReport {
+	order := make(map[string]int, len(enabled))
+	for i, u := range enabled {
+		order[u.Name()] = i
+	}
+
+	selfUpdates := append([]managerReport{}, stats.SelfUpdates...)
+	managers := append([]managerReport{}, stats.Managers...)
+
+	sortManagerReports(selfUpdates, order)
+	sortManagerReports(managers, order)
+
+	return sysUpdateReport{
+		Version:    version,
+		DryRun:     dryRun,
+		Success:    len(stats.Errors) == 0,
+		SelfUpdate: selfUpdates,
+		Managers:   managers,
+		Summary: summaryReport{
+			Updated: stats.Updated,
+			Failed:  stats.Failed,
+			Errors:  errorStrings(stats.Errors),
+		},
+	}
+}
+
+func sortManagerReports(reports []managerReport, order map[string]int) {
+	sort.SliceStable(reports, func(i, j int) bool {
+		return order[reports[i].Name] < order[reports[j].Name]
+	})
+}
+
+// writeJSONReport は任意のレポートを整形済み JSON として書き出します。
+func writeJSONReport(w io.Writer, report any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("JSON 出力に失敗: %w", err)
+	}
+
+	return nil
+}
+
+// redirectStdoutToStderr は人間向け出力を stderr へ退避し、stdout を JSON 専用にします。
+// 更新コマンドの子プロセスも os.Stdout を参照するため、まとめて stderr に流れます。
+// 戻り値の関数で元の stdout を復元します。
+func redirectStdoutToStderr() func() {
+	original := os.Stdout
+	os.Stdout = os.Stderr
+
+	return func() {
+		os.Stdout = original
+	}
+}
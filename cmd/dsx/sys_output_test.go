@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/scottlz0310/dsx/internal/runner"
+	"github.com/scottlz0310/dsx/internal/updater"
+)
+
+func TestParseOutputFormat(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "空文字は text", input: "", want: outputFormatText},
+		{name: "text", input: "text", want: outputFormatText},
+		{name: "json（大文字・空白を許容）", input: " JSON ", want: outputFormatJSON},
+		{name: "未対応形式", input: "yaml", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseOutputFormat(tc.input)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("parseOutputFormat(%q) error = nil, want error", tc.input)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseOutputFormat(%q) error = %v", tc.input, err)
+			}
+
+			if got != tc.want {
+				t.Fatalf("parseOutputFormat(%q) = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewManagerReport(t *testing.T) {
+	t.Parallel()
+
+	u := stubUpdater{name: "apt"}
+
+	t.Run("成功", func(t *testing.T) {
+		t.Parallel()
+
+		got := newManagerReport(u, &updater.UpdateResult{
+			UpdatedCount: 1,
+			Packages:     []updater.PackageInfo{{Name: "curl", CurrentVersion: "8.5.0", NewVersion: "8.6.0"}},
+			Message:      "1 件のパッケージを更新しました",
+		}, nil)
+
+		if got.Status != managerReportStatusSuccess {
+			t.Fatalf("Status = %q, want success", got.Status)
+		}
+
+		if len(got.Packages) != 1 || got.Packages[0].CurrentVersion != "8.5.0" || got.Packages[0].NewVersion != "8.6.0" {
+			t.Fatalf("Packages = %+v, want curl 8.5.0 -> 8.6.0", got.Packages)
+		}
+	})
+
+	t.Run("保留パッケージを held に出力", func(t *testing.T) {
+		t.Parallel()
+
+		got := newManagerReport(u, &updater.UpdateResult{
+			Held: []updater.PackageInfo{{Name: "docker-ce", CurrentVersion: "25.0.0", NewVersion: "26.0.0"}},
+		}, nil)
+
+		if got.Status != managerReportStatusSuccess {
+			t.Fatalf("Status = %q, want success", got.Status)
+		}
+
+		if len(got.Held) != 1 || got.Held[0].Name != "docker-ce" {
+			t.Fatalf("Held = %+v, want [docker-ce]", got.Held)
+		}
+	})
+
+	t.Run("Update エラーは failed", func(t *testing.T) {
+		t.Parallel()
+
+		got := newManagerReport(u, nil, errors.New("boom"))
+
+		if got.Status != managerReportStatusFailed {
+			t.Fatalf("Status = %q, want failed", got.Status)
+		}
+
+		if len(got.Errors) != 1 || got.Errors[0] != "boom" {
+			t.Fatalf("Errors = %v, want [boom]", got.Errors)
+		}
+
+		if got.Packages == nil {
+			t.Fatal("Packages = nil, want empty slice for JSON")
+		}
+	})
+
+	t.Run("結果内のエラーも failed", func(t *testing.T) {
+		t.Parallel()
+
+		got := newManagerReport(u, &updater.UpdateResult{Errors: []error{errors.New("partial")}}, nil)
+		if got.Status != managerReportStatusFailed {
+			t.Fatalf("Status = %q, want failed", got.Status)
+		}
+	})
+}
+
+func TestExecuteUpdateGraph_RecordsManagerReports(t *testing.T) {
+	updaters := []updater.Updater{
+		stubUpdater{name: "brew"},
+		stubUpdater{name: "npm", updateErr: errors.New("network")},
+	}
+
+	var stats updateStats
+
+	captureStdout(t, func() {
+		stats = executeUpdateGraph(context.Background(), updaters, updater.UpdateOptions{}, nil, 1, false)
+	})
+
+	if len(stats.Managers) != 2 {
+		t.Fatalf("Managers length = %d, want 2", len(stats.Managers))
+	}
+
+	if stats.Managers[0].Name != "brew" || stats.Managers[0].Status != managerReportStatusSuccess {
+		t.Fatalf("Managers[0] = %+v, want brew success", stats.Managers[0])
+	}
+
+	if stats.Managers[1].Name != "npm" || stats.Managers[1].Status != managerReportStatusFailed {
+		t.Fatalf("Managers[1] = %+v, want npm failed", stats.Managers[1])
+	}
+}
+
+func TestExecuteUpdateGraph_CanceledContextRecordsSkipped(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	stats := executeUpdateGraph(ctx, []updater.Updater{stubUpdater{name: "brew"}, stubUpdater{name: "go"}}, updater.UpdateOptions{}, nil, 1, false)
+
+	if len(stats.Managers) != 2 {
+		t.Fatalf("Managers length = %d, want 2", len(stats.Managers))
+	}
+
+	for _, report := range stats.Managers {
+		if report.Status != managerReportStatusSkipped {
+			t.Fatalf("report = %+v, want skipped", report)
+		}
+	}
+}
+
+func TestCollectSkippedManagerReports(t *testing.T) {
+	t.Parallel()
+
+	summary := runner.Summary{
+		Results: []runner.Result{
+			{Name: "uv-self-update", Status: runner.StatusSkipped, Err: context.Canceled},
+			{Name: "bun-self-update", Status: runner.StatusSuccess},
+		},
+	}
+
+	got := collectSkippedManagerReports(summary, []updater.Updater{stubUpdater{name: "uv"}, stubUpdater{name: "bun"}}, selfUpdateJobSuffix)
+
+	if len(got) != 1 || got[0].Name != "uv" || got[0].DisplayName != "uv" {
+		t.Fatalf("reports = %+v, want single uv skipped", got)
+	}
+}
+
+func TestBuildSysUpdateReport_JSON(t *testing.T) {
+	t.Parallel()
+
+	enabled := []updater.Updater{stubUpdater{name: "apt"}, stubUpdater{name: "uv"}, stubUpdater{name: "npm"}}
+	stats := updateStats{
+		Updated: 2,
+		Failed:  1,
+		Errors:  []error{errors.New("npm: failed")},
+		SelfUpdates: []managerReport{
+			{Name: "uv", Status: managerReportStatusSuccess, Continuation: string(updater.ContinueNormalUpdate)},
+		},
+		Managers: []managerReport{
+			{Name: "npm", Status: managerReportStatusFailed},
+			{Name: "apt", Status: managerReportStatusSuccess, UpdatedCount: 2},
+		},
+	}
+
+	report := buildSysUpdateReport(stats, enabled, true)
+
+	var buf bytes.Buffer
+	if err := writeJSONReport(&buf, report); err != nil {
+		t.Fatalf("writeJSONReport error: %v", err)
+	}
+
+	var decoded sysUpdateReport
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+
+	if !decoded.DryRun || decoded.Success {
+		t.Fatalf("dry_run/success = %v/%v, want true/false", decoded.DryRun, decoded.Success)
+	}
+
+	if len(decoded.Managers) != 2 || decoded.Managers[0].Name != "apt" || decoded.Managers[1].Name != "npm" {
+		t.Fatalf("managers = %+v, want ordered [apt npm]", decoded.Managers)
+	}
+
+	if len(decoded.SelfUpdate) != 1 || decoded.SelfUpdate[0].Continuation != "continue" {
+		t.Fatalf("self_update = %+v, want uv continue", decoded.SelfUpdate)
+	}
+
+	if decoded.Summary.Updated != 2 || decoded.Summary.Failed != 1 || len(decoded.Summary.Errors) != 1 {
+		t.Fatalf("summary = %+v, want updated:2 failed:1 errors:1", decoded.Summary)
+	}
+}
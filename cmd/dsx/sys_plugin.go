@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/scottlz0310/dsx/internal/updater"
+	"github.com/spf13/cobra"
+)
+
+// sysPluginCmd は外部 updater プラグイン（dsx-updater-*）を扱うコマンドです。
+var sysPluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "外部 updater プラグインを管理します",
+	Long: `PATH または ~/.config/dsx/plugins にある dsx-updater-<name> の実行ファイルは、
+マネージャを扱うコマンド（sys update / check / list / discover / rollback、config validate）の実行時に
+外部 updater プラグインとして読み込まれ、マネージャ <name> として扱われます。`,
+}
+
+var (
+	// registerUpdaterPluginsStep はプラグインの登録処理です（テストで差し替え）。
+	registerUpdaterPluginsStep = updater.RegisterPlugins
+	loadUpdaterPluginsOnce     sync.Once
+)
+
+var sysPluginListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "検出したプラグインを一覧表示します",
+	Args:  cobra.NoArgs,
+	RunE:  runSysPluginList,
+}
+
+var sysPluginVerifyCmd = &cobra.Command{
+	Use:   "verify <名前|パス>",
+	Short: "プラグインがプロトコルの契約を満たすか検証します",
+	Long: `プラグインを name / display_name / configure / is_available / check / update（dry_run）などで呼び出し、
+応答がプロトコルの契約を満たすかを検証します。update / self_update は dry_run でのみ呼び出します。
+sys.managers.<name> の設定がある場合は config として渡します。
+
+例:
+  dsx sys plugin verify example                      # 検出済みのプラグインを名前で指定
+  dsx sys plugin verify ./bin/dsx-updater-example    # 実行ファイルのパスを指定`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSysPluginVerify,
+}
+
+func init() {
+	sysCmd.AddCommand(sysPluginCmd)
+	sysPluginCmd.AddCommand(sysPluginListCmd)
+	sysPluginCmd.AddCommand(sysPluginVerifyCmd)
+}
+
+// loadUpdaterPlugins は PATH と ~/.config/dsx/plugins の dsx-updater-* を外部 updater プラグインとして登録します。
+// 登録には各プラグインの起動が必要なため、起動時ではなくマネージャを解決するコマンドの実行時に1回だけ行います。
+func loadUpdaterPlugins() {
+	loadUpdaterPluginsOnce.Do(func() {
+		if err := registerUpdaterPluginsStep(context.Background(), updater.PluginDirs()); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+		}
+	})
+}
+
+func runSysPluginList(cmd *cobra.Command, _ []string) error {
+	plugins := updater.DiscoverPlugins(updater.PluginDirs())
+	if len(plugins) == 0 {
+		fmt.Println("プラグインは見つかりませんでした。")
+		fmt.Printf("💡 %s<name> の実行ファイルを PATH または %s に配置してください。\n", updater.PluginExecutablePrefix, displayPluginDir())
+
+		return nil
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	fmt.Printf("🔌 プラグイン（%d 件）:\n", len(plugins))
+
+	for _, plugin := range plugins {
+		fmt.Printf("  - %s  %s\n", plugin.Name, plugin.Path)
+		fmt.Printf("      %s\n", describePluginStatus(ctx, plugin))
+	}
+
+	return nil
+}
+
+// describePluginStatus はプラグインの登録状況を1行で返します。
+func describePluginStatus(ctx context.Context, plugin updater.PluginExecutable) string {
+	if existing, ok := updater.Get(plugin.Name); ok {
+		if registered, isPlugin := existing.(updater.PluginSource); !isPlugin || registered.PluginPath() != plugin.Path {
+			return "⚠️  既存のマネージャと同じ名前のため読み込まれません"
+		}
+	}
+
+	u, err := updater.LoadPlugin(ctx, plugin)
+	if err != nil {
+		return fmt.Sprintf("❌ %v", err)
+	}
+
+	return "✅ " + u.DisplayName()
+}
+
+func runSysPluginVerify(cmd *cobra.Command, args []string) error {
+	plugin, err := resolvePluginArg(args[0], updater.PluginDirs())
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var managerCfg config.ManagerConfig
+	if cfg := config.Get(); cfg != nil {
+		managerCfg = cfg.Sys.Managers[plugin.Name]
+	}
+
+	report := updater.VerifyPlugin(ctx, plugin, managerCfg)
+	printPluginConformanceReport(os.Stdout, report)
+
+	if !report.Passed() {
+		return fmt.Errorf("%s はプロトコルの契約を満たしていません", plugin.Path)
+	}
+
+	return nil
+}
+
+// resolvePluginArg はプラグイン名または実行ファイルのパスを解決します。
+// パス区切りを含む場合はパスとして扱い、マネージャ名は実行ファイル名から求めます。
+func resolvePluginArg(arg string, dirs []string) (updater.PluginExecutable, error) {
+	if !strings.ContainsAny(arg, `/\`) {
+		for _, plugin := range updater.DiscoverPlugins(dirs) {
+			if plugin.Name == arg || updater.PluginExecutablePrefix+plugin.Name == arg {
+				return plugin, nil
+			}
+		}
+
+		return updater.PluginExecutable{}, fmt.Errorf("プラグインが見つかりません: %s（%s%s を PATH または %s に配置してください）",
+			arg, updater.PluginExecutablePrefix, strings.TrimPrefix(arg, updater.PluginExecutablePrefix), displayPluginDir())
+	}
+
+	path, err := filepath.Abs(arg)
+	if err != nil {
+		return updater.PluginExecutable{}, fmt.Errorf("パスの解決に失敗: %w", err)
+	}
+
+	base := filepath.Base(path)
+	if runtime.GOOS == "windows" {
+		base = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+
+	name := strings.TrimPrefix(base, updater.PluginExecutablePrefix)
+	if !strings.HasPrefix(base, updater.PluginExecutablePrefix) || name == "" {
+		return updater.PluginExecutable{}, fmt.Errorf("実行ファイル名は %s<name> である必要があります: %s", updater.PluginExecutablePrefix, filepath.Base(path))
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return updater.PluginExecutable{}, fmt.Errorf("プラグインの実行ファイルを確認できません: %w", err)
+	}
+
+	return updater.PluginExecutable{Name: name, Path: path}, nil
+}
+
+func printPluginConformanceReport(w io.Writer, report *updater.PluginConformanceReport) {
+	fmt.Fprintf(w, "🔌 %s（%s）\n", report.Plugin.Name, report.Plugin.Path)
+
+	passed, failed, skipped := 0, 0, 0
+
+	for _, check := range report.Checks {
+		icon := "✅"
+
+		switch check.Status {
+		case updater.PluginConformanceFail:
+			icon = "❌"
+			failed++
+		case updater.PluginConformanceSkip:
+			icon = "⏭️ "
+			skipped++
+		default:
+			passed++
+		}
+
+		if check.Detail == "" {
+			fmt.Fprintf(w, "  %s %s\n", icon, check.Contract)
+			continue
+		}
+
+		fmt.Fprintf(w, "  %s %s: %s\n", icon, check.Contract, check.Detail)
+	}
+
+	fmt.Fprintf(w, "\n成功 %d 件 / 失敗 %d 件 / 未検証 %d 件\n", passed, failed, skipped)
+}
+
+func displayPluginDir() string {
+	dir, err := config.PluginDir()
+	if err != nil {
+		return "~/.config/dsx/plugins"
+	}
+
+	return dir
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/scottlz0310/dsx/internal/testutil"
+	"github.com/scottlz0310/dsx/internal/updater"
+)
+
+func TestLoadUpdaterPlugins_OnlyOnDemand(t *testing.T) {
+	original := registerUpdaterPluginsStep
+	calls := 0
+
+	registerUpdaterPluginsStep = func(context.Context, []string) error {
+		calls++
+		return nil
+	}
+	loadUpdaterPluginsOnce = sync.Once{}
+
+	t.Cleanup(func() {
+		registerUpdaterPluginsStep = original
+		loadUpdaterPluginsOnce = sync.Once{}
+	})
+
+	testutil.SetTestHome(t, t.TempDir())
+
+	// repo / run / config など、すべてのコマンドの起動時に実行される初期化ではプラグインを起動しない
+	initConfig()
+
+	if calls != 0 {
+		t.Fatalf("initConfig でプラグインを %d 回登録しました。マネージャを扱うコマンドまで遅延させる必要があります", calls)
+	}
+
+	loadUpdaterPlugins()
+	loadUpdaterPlugins()
+
+	if calls != 1 {
+		t.Fatalf("registerUpdaterPluginsStep calls = %d, want 1", calls)
+	}
+}
+
+func TestResolvePluginArg(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("実行権限で判定するため Windows ではスキップ")
+	}
+
+	dir := t.TempDir()
+	pluginPath := filepath.Join(dir, "dsx-updater-foo")
+
+	if err := os.WriteFile(pluginPath, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatalf("plugin write failed: %v", err)
+	}
+
+	otherPath := filepath.Join(dir, "foo-updater")
+	if err := os.WriteFile(otherPath, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatalf("file write failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		arg     string
+		want    updater.PluginExecutable
+		wantErr string
+	}{
+		{
+			name: "名前で指定",
+			arg:  "foo",
+			want: updater.PluginExecutable{Name: "foo", Path: pluginPath},
+		},
+		{
+			name: "実行ファイル名で指定",
+			arg:  "dsx-updater-foo",
+			want: updater.PluginExecutable{Name: "foo", Path: pluginPath},
+		},
+		{
+			name: "パスで指定",
+			arg:  pluginPath,
+			want: updater.PluginExecutable{Name: "foo", Path: pluginPath},
+		},
+		{
+			name:    "見つからない名前",
+			arg:     "bar",
+			wantErr: "プラグインが見つかりません: bar",
+		},
+		{
+			name:    "接頭辞のないパス",
+			arg:     otherPath,
+			wantErr: "dsx-updater-<name> である必要があります",
+		},
+		{
+			name:    "存在しないパス",
+			arg:     filepath.Join(dir, "dsx-updater-missing"),
+			wantErr: "プラグインの実行ファイルを確認できません",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := resolvePluginArg(tc.arg, []string{dir})
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("resolvePluginArg(%q) error = %v, want containing %q", tc.arg, err, tc.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("resolvePluginArg(%q) error = %v", tc.arg, err)
+			}
+
+			if got != tc.want {
+				t.Fatalf("resolvePluginArg(%q) = %+v, want %+v", tc.arg, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPrintPluginConformanceReport(t *testing.T) {
+	report := &updater.PluginConformanceReport{
+		Plugin: updater.PluginExecutable{Name: "foo", Path: "/usr/local/bin/dsx-updater-foo"},
+		Checks: []updater.PluginConformanceCheck{
+			{Contract: "name", Status: updater.PluginConformancePass, Detail: "foo（機能: なし）"},
+			{Contract: "unsupported_method", Status: updater.PluginConformanceFail, Detail: "未知のメソッドに成功を返しました"},
+			{Contract: "check", Status: updater.PluginConformanceSkip},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	printPluginConformanceReport(&buf, report)
+
+	got := buf.String()
+	for _, want := range []string{
+		"🔌 foo（/usr/local/bin/dsx-updater-foo）",
+		"✅ name: foo（機能: なし）",
+		"❌ unsupported_method: 未知のメソッドに成功を返しました",
+		"check\n",
+		"成功 1 件 / 失敗 1 件 / 未検証 1 件",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("output does not contain %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,436 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/scottlz0310/dsx/internal/history"
+	"github.com/scottlz0310/dsx/internal/updater"
+	"github.com/spf13/cobra"
+)
+
+const (
+	rollbackStatusPlanned     = "planned"
+	rollbackStatusSuccess     = "success"
+	rollbackStatusFailed      = "failed"
+	rollbackStatusUnsupported = "unsupported"
+)
+
+var (
+	sysRollbackRun     string
+	sysRollbackManager string
+	sysRollbackApply   bool
+	sysRollbackOutput  string
+)
+
+// sysRollbackCmd は更新履歴からダウングレード計画を生成・実行するコマンドです
+var sysRollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "更新履歴をもとにパッケージを更新前のバージョンへ戻します",
+	Long: `sys history に記録された実行（--run で実行 ID を指定）で更新されたパッケージについて、
+各マネージャのダウングレードコマンドを生成します。既定では計画の表示のみ行い、
+--apply を指定した場合にコマンドを順番に実行します。
+
+対応マネージャと生成するコマンド:
+  - go     go install <package>@<旧バージョン>
+  - cargo  cargo install <crate> --version <旧バージョン> --force
+  - pipx   pipx install --force <package>==<旧バージョン>
+  - uv     uv tool install --force <tool>==<旧バージョン>
+  - gem    gem install <gem> -v <旧バージョン>
+  - npm / pnpm / bun  グローバルパッケージを <package>@<旧バージョン> で入れ直し
+  - apt    apt install --allow-downgrades <package>=<旧バージョン>
+  - snap   snap revert <snap>
+
+ダウングレードに対応していないマネージャや、更新前のバージョンが記録されていない
+パッケージは「非対応」として理由とともに一覧表示します。
+
+例:
+  dsx sys history                                        # 実行 ID を確認
+  dsx sys rollback --run 20260301T090000Z-3f9a1c         # ロールバック計画を表示
+  dsx sys rollback --run 20260301T090000Z-3f9a1c -m go   # go のみ
+  dsx sys rollback --run 20260301T090000Z-3f9a1c --apply # 計画を実行`,
+	Args: cobra.NoArgs,
+	RunE: runSysRollback,
+}
+
+func init() {
+	sysCmd.AddCommand(sysRollbackCmd)
+
+	sysRollbackCmd.Flags().StringVar(&sysRollbackRun, "run", "", "ロールバックする実行 ID（sys history で確認）")
+	sysRollbackCmd.Flags().StringVarP(&sysRollbackManager, "manager", "m", "", "対象のマネージャを限定")
+	sysRollbackCmd.Flags().BoolVar(&sysRollbackApply, "apply", false, "ダウングレードコマンドを実行（未指定時は計画のみ表示）")
+	sysRollbackCmd.Flags().StringVarP(&sysTimeout, "timeout", "t", "10m", "全体のタイムアウト時間")
+	sysRollbackCmd.Flags().StringVarP(&sysRollbackOutput, "output", "o", outputFormatText, "出力形式（text / json）")
+
+	_ = sysRollbackCmd.MarkFlagRequired("run")
+}
+
+// rollbackStep はロールバック計画の1パッケージ分です。
+type rollbackStep struct {
+	Manager string
+	Phase   string
+	Package history.PackageChange
+	Command *updater.RollbackCommand
+	Reason  string
+	Status  string
+	Err     error
+}
+
+// rollbackStepReport は rollbackStep の JSON 表現です。
+// Current は更新後（現在）のバージョン、Target は戻し先（更新前）のバージョンです。
+type rollbackStepReport struct {
+	Manager string `json:"manager"`
+	Package string `json:"package"`
+	Current string `json:"current,omitempty"`
+	Target  string `json:"target,omitempty"`
+	Command string `json:"command,omitempty"`
+	Note    string `json:"note,omitempty"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// sysRollbackReport は sys rollback --output json で出力するドキュメントです。
+type sysRollbackReport struct {
+	RunID       string               `json:"run_id"`
+	Apply       bool                 `json:"apply"`
+	Supported   int                  `json:"supported"`
+	Unsupported int                  `json:"unsupported"`
+	Failed      int                  `json:"failed"`
+	Steps       []rollbackStepReport `json:"steps"`
+}
+
+// sysRollbackRunStep はテストで差し替えるためのコマンド実行関数です。
+var sysRollbackRunStep = func(ctx context.Context, cmd *updater.RollbackCommand) error {
+	return cmd.Run(ctx)
+}
+
+func runSysRollback(cmd *cobra.Command, args []string) error {
+	format, err := parseOutputFormat(sysRollbackOutput)
+	if err != nil {
+		return err
+	}
+
+	stdout := os.Stdout
+
+	if format == outputFormatJSON {
+		defer redirectStdoutToStderr()()
+	}
+
+	store, err := history.NewStore("")
+	if err != nil {
+		return err
+	}
+
+	run, err := store.Find(sysRollbackRun)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  設定ファイルの読み込みに失敗（デフォルト設定を使用）: %v\n", err)
+
+		cfg = config.Default()
+	}
+
+	loadUpdaterPlugins()
+
+	steps := buildRollbackPlan(run, sysRollbackManager, updater.Get, cfg.Sys.Managers)
+
+	if format != outputFormatJSON {
+		if err := writeRollbackPlan(os.Stdout, run, steps); err != nil {
+			return fmt.Errorf("計画の表示に失敗: %w", err)
+		}
+	}
+
+	var applyErr error
+
+	if sysRollbackApply {
+		ctx, cancel := setupContext()
+		defer cancel()
+
+		applyErr = applyRollbackPlan(ctx, steps, format == outputFormatJSON)
+	} else if format != outputFormatJSON && countRollbackSteps(steps, rollbackStatusPlanned) > 0 {
+		fmt.Println()
+		fmt.Println("💡 --apply を指定すると上記のコマンドを実行します。")
+	}
+
+	if format == outputFormatJSON {
+		if err := writeJSONReport(stdout, buildSysRollbackReport(run.ID, sysRollbackApply, steps)); err != nil {
+			return err
+		}
+	}
+
+	return applyErr
+}
+
+// buildRollbackPlan は実行記録からパッケージごとのダウングレードコマンドを組み立てます。
+// 対応していないマネージャ・パッケージも理由つきで計画に含めます。
+func buildRollbackPlan(run *history.Run, managerFilter string, lookup func(string) (updater.Updater, bool), managers map[string]config.ManagerConfig) []rollbackStep {
+	var steps []rollbackStep
+
+	for _, record := range run.Managers {
+		if managerFilter != "" && record.Name != managerFilter {
+			continue
+		}
+
+		// 更新件数だけが記録されている場合は戻し先がわからないため、マネージャ単位で非対応として示す
+		if len(record.Packages) == 0 && record.UpdatedCount > 0 {
+			steps = append(steps, rollbackStep{
+				Manager: record.Name,
+				Phase:   record.Phase,
+				Status:  rollbackStatusUnsupported,
+				Reason:  fmt.Sprintf("更新パッケージが記録されていません（%d 件更新）", record.UpdatedCount),
+			})
+
+			continue
+		}
+
+		rollbacker, reason := resolveRollbacker(&record, lookup, managers)
+
+		for _, pkg := range record.Packages {
+			step := rollbackStep{
+				Manager: record.Name,
+				Phase:   record.Phase,
+				Package: pkg,
+				Status:  rollbackStatusUnsupported,
+				Reason:  reason,
+			}
+
+			if rollbacker != nil {
+				command, err := rollbacker.RollbackCommand(updater.PackageInfo{Name: pkg.Name, CurrentVersion: pkg.From, NewVersion: pkg.To})
+				if err != nil {
+					step.Reason = err.Error()
+				} else {
+					step.Command = command
+					step.Status = rollbackStatusPlanned
+				}
+			}
+
+			steps = append(steps, step)
+		}
+	}
+
+	return steps
+}
+
+func resolveRollbacker(record *history.ManagerRecord, lookup func(string) (updater.Updater, bool), managers map[string]config.ManagerConfig) (updater.Rollbacker, string) {
+	if record.Phase == history.PhaseSelfUpdate {
+		return nil, "マネージャ本体の更新はロールバックに対応していません"
+	}
+
+	u, ok := lookup(record.Name)
+	if !ok {
+		return nil, "未登録のマネージャです"
+	}
+
+	rollbacker, ok := u.(updater.Rollbacker)
+	if !ok {
+		return nil, "このマネージャはダウングレードに対応していません"
+	}
+
+	if err := u.Configure(managers[record.Name]); err != nil {
+		return nil, fmt.Sprintf("設定の適用に失敗: %v", err)
+	}
+
+	return rollbacker, ""
+}
+
+// applyRollbackPlan は計画済みのコマンドを順番に実行し、各ステップの結果を記録します。
+func applyRollbackPlan(ctx context.Context, steps []rollbackStep, suppressOutput bool) error {
+	if countRollbackSteps(steps, rollbackStatusPlanned) == 0 {
+		return nil
+	}
+
+	if rollbackRequiresSudo(steps) {
+		if err := ensureSudoAuthentication(ctx, "sys rollback", suppressOutput); err != nil {
+			return err
+		}
+	}
+
+	failed := 0
+
+	for i := range steps {
+		step := &steps[i]
+		if step.Command == nil {
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			step.Status = rollbackStatusFailed
+			step.Err = err
+			failed++
+
+			continue
+		}
+
+		fmt.Printf("\n↩️  %s: %s\n", step.Manager, step.Command.String())
+
+		if err := sysRollbackRunStep(ctx, step.Command); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ エラー: %v\n", err)
+
+			step.Status = rollbackStatusFailed
+			step.Err = err
+			failed++
+
+			continue
+		}
+
+		step.Status = rollbackStatusSuccess
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d 件のロールバックに失敗しました", failed)
+	}
+
+	fmt.Println()
+	fmt.Println("✅ ロールバックが完了しました")
+
+	return nil
+}
+
+func rollbackRequiresSudo(steps []rollbackStep) bool {
+	for i := range steps {
+		if steps[i].Command != nil && steps[i].Command.Name == "sudo" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func countRollbackSteps(steps []rollbackStep, status string) int {
+	count := 0
+
+	for i := range steps {
+		if steps[i].Status == status {
+			count++
+		}
+	}
+
+	return count
+}
+
+// writeRollbackPlan はダウングレードコマンドの一覧と非対応パッケージの一覧を出力します。
+func writeRollbackPlan(output io.Writer, run *history.Run, steps []rollbackStep) error {
+	if _, err := fmt.Fprintf(output, "↩️  ロールバック計画（実行 ID: %s / %s）\n\n",
+		run.ID, run.StartedAt.Local().Format("2006-01-02 15:04")); err != nil {
+		return err
+	}
+
+	if len(steps) == 0 {
+		_, err := fmt.Fprintln(output, "📭 この実行で更新されたパッケージはありません")
+		return err
+	}
+
+	if countRollbackSteps(steps, rollbackStatusPlanned) > 0 {
+		if err := writeRollbackTable(output, steps); err != nil {
+			return err
+		}
+	}
+
+	if countRollbackSteps(steps, rollbackStatusUnsupported) == 0 {
+		return nil
+	}
+
+	if _, err := fmt.Fprintln(output, "\n⚠️  ロールバック非対応（手動で対応してください）:"); err != nil {
+		return err
+	}
+
+	for i := range steps {
+		step := &steps[i]
+		if step.Status != rollbackStatusUnsupported {
+			continue
+		}
+
+		if step.Package.Name == "" {
+			if _, err := fmt.Fprintf(output, "  - %s: %s\n", historyManagerLabel(step.Manager, step.Phase), step.Reason); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if _, err := fmt.Fprintf(output, "  - %s %s (%s → %s): %s\n",
+			historyManagerLabel(step.Manager, step.Phase), step.Package.Name,
+			versionOrDash(step.Package.From), versionOrDash(step.Package.To), step.Reason); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeRollbackTable(output io.Writer, steps []rollbackStep) error {
+	writer := tabwriter.NewWriter(output, 0, 8, 2, ' ', 0)
+
+	if _, err := fmt.Fprintln(writer, "マネージャ\tパッケージ\t現在\t戻し先\tコマンド"); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintln(writer, "----------\t----------\t----\t------\t--------"); err != nil {
+		return err
+	}
+
+	for i := range steps {
+		step := &steps[i]
+		if step.Command == nil {
+			continue
+		}
+
+		command := step.Command.String()
+		if step.Command.Note != "" {
+			command += "  ※" + step.Command.Note
+		}
+
+		if _, err := fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n",
+			step.Manager, step.Package.Name, versionOrDash(step.Package.To), versionOrDash(step.Package.From), command); err != nil {
+			return err
+		}
+	}
+
+	return writer.Flush()
+}
+
+func buildSysRollbackReport(runID string, apply bool, steps []rollbackStep) sysRollbackReport {
+	report := sysRollbackReport{
+		RunID: runID,
+		Apply: apply,
+		Steps: make([]rollbackStepReport, 0, len(steps)),
+	}
+
+	for i := range steps {
+		step := &steps[i]
+
+		item := rollbackStepReport{
+			Manager: step.Manager,
+			Package: step.Package.Name,
+			Current: step.Package.To,
+			Target:  step.Package.From,
+			Status:  step.Status,
+			Reason:  step.Reason,
+		}
+
+		if step.Command != nil {
+			item.Command = step.Command.String()
+			item.Note = step.Command.Note
+			report.Supported++
+		} else {
+			report.Unsupported++
+		}
+
+		if step.Err != nil {
+			item.Error = step.Err.Error()
+			report.Failed++
+		}
+
+		report.Steps = append(report.Steps, item)
+	}
+
+	return report
+}
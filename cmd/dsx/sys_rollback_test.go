@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/scottlz0310/dsx/internal/history"
+	"github.com/scottlz0310/dsx/internal/updater"
+)
+
+type rollbackStubUpdater struct {
+	stubUpdater
+}
+
+func (s rollbackStubUpdater) RollbackCommand(pkg updater.PackageInfo) (*updater.RollbackCommand, error) {
+	if pkg.CurrentVersion == "" {
+		return nil, updater.ErrRollbackVersionUnknown
+	}
+
+	return &updater.RollbackCommand{Name: s.name, Args: []string{"install", pkg.Name + "@" + pkg.CurrentVersion}}, nil
+}
+
+func newRollbackTestRun() *history.Run {
+	return &history.Run{
+		ID:        "20260301T090000Z",
+		StartedAt: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+		Managers: []history.ManagerRecord{
+			{Name: "uv", Phase: history.PhaseSelfUpdate, Packages: []history.PackageChange{{Name: "uv", From: "0.11.16", To: "0.11.17"}}},
+			{Name: "go", Phase: history.PhaseUpdate, Packages: []history.PackageChange{
+				{Name: "gopls", From: "v0.16.0", To: "v0.17.0"},
+				{Name: "staticcheck", To: "v0.5.0"},
+			}},
+			{Name: "brew", Phase: history.PhaseUpdate, Packages: []history.PackageChange{{Name: "git", From: "2.44.0", To: "2.45.0"}}},
+			{Name: "apt", Phase: history.PhaseUpdate},
+		},
+	}
+}
+
+func rollbackTestLookup(name string) (updater.Updater, bool) {
+	switch name {
+	case "go":
+		return rollbackStubUpdater{stubUpdater{name: "go"}}, true
+	case "brew":
+		return stubUpdater{name: "brew"}, true
+	default:
+		return nil, false
+	}
+}
+
+func TestBuildRollbackPlan(t *testing.T) {
+	t.Parallel()
+
+	steps := buildRollbackPlan(newRollbackTestRun(), "", rollbackTestLookup, map[string]config.ManagerConfig{})
+
+	if len(steps) != 4 {
+		t.Fatalf("steps length = %d, want 4: %+v", len(steps), steps)
+	}
+
+	want := []struct {
+		manager string
+		pkg     string
+		status  string
+		command string
+	}{
+		{manager: "uv", pkg: "uv", status: rollbackStatusUnsupported},
+		{manager: "go", pkg: "gopls", status: rollbackStatusPlanned, command: "go install gopls@v0.16.0"},
+		{manager: "go", pkg: "staticcheck", status: rollbackStatusUnsupported},
+		{manager: "brew", pkg: "git", status: rollbackStatusUnsupported},
+	}
+
+	for i, w := range want {
+		step := steps[i]
+		if step.Manager != w.manager || step.Package.Name != w.pkg || step.Status != w.status {
+			t.Fatalf("steps[%d] = %+v, want %s/%s %s", i, step, w.manager, w.pkg, w.status)
+		}
+
+		if w.command != "" && step.Command.String() != w.command {
+			t.Fatalf("steps[%d] command = %q, want %q", i, step.Command.String(), w.command)
+		}
+
+		if w.status == rollbackStatusUnsupported && step.Reason == "" {
+			t.Fatalf("steps[%d] reason is empty, want explanation", i)
+		}
+	}
+
+	filtered := buildRollbackPlan(newRollbackTestRun(), "brew", rollbackTestLookup, nil)
+	if len(filtered) != 1 || filtered[0].Manager != "brew" {
+		t.Fatalf("filtered = %+v, want brew only", filtered)
+	}
+}
+
+func TestBuildRollbackPlan_UpdatedWithoutPackages(t *testing.T) {
+	t.Parallel()
+
+	run := &history.Run{
+		ID: "20260301T090000Z",
+		Managers: []history.ManagerRecord{
+			{Name: "mytool", Phase: history.PhaseUpdate, UpdatedCount: 1},
+			{Name: "apt", Phase: history.PhaseUpdate},
+		},
+	}
+
+	steps := buildRollbackPlan(run, "", rollbackTestLookup, nil)
+	if len(steps) != 1 {
+		t.Fatalf("steps = %+v, want only mytool", steps)
+	}
+
+	if steps[0].Manager != "mytool" || steps[0].Status != rollbackStatusUnsupported || !strings.Contains(steps[0].Reason, "更新パッケージが記録されていません") {
+		t.Fatalf("steps[0] = %+v, want unsupported mytool with reason", steps[0])
+	}
+
+	var buf bytes.Buffer
+	if err := writeRollbackPlan(&buf, run, steps); err != nil {
+		t.Fatalf("writeRollbackPlan error: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "  - mytool: 更新パッケージが記録されていません") {
+		t.Fatalf("output does not list mytool as unsupported:\n%s", buf.String())
+	}
+}
+
+func TestWriteRollbackPlan(t *testing.T) {
+	t.Parallel()
+
+	run := newRollbackTestRun()
+	steps := buildRollbackPlan(run, "", rollbackTestLookup, nil)
+
+	var buf bytes.Buffer
+	if err := writeRollbackPlan(&buf, run, steps); err != nil {
+		t.Fatalf("writeRollbackPlan error: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"20260301T090000Z", "go install gopls@v0.16.0", "非対応", "uv (本体) uv", "brew git (2.44.0 → 2.45.0)", "staticcheck (- → v0.5.0)"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("output does not contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestApplyRollbackPlan(t *testing.T) {
+	original := sysRollbackRunStep
+
+	t.Cleanup(func() { sysRollbackRunStep = original })
+
+	var executed []string
+
+	sysRollbackRunStep = func(_ context.Context, cmd *updater.RollbackCommand) error {
+		executed = append(executed, cmd.String())
+		if strings.Contains(cmd.String(), "broken") {
+			return errors.New("exit status 1")
+		}
+
+		return nil
+	}
+
+	steps := []rollbackStep{
+		{Manager: "go", Command: &updater.RollbackCommand{Name: "go", Args: []string{"install", "gopls@v0.16.0"}}, Status: rollbackStatusPlanned},
+		{Manager: "brew", Status: rollbackStatusUnsupported, Reason: "このマネージャはダウングレードに対応していません"},
+		{Manager: "go", Command: &updater.RollbackCommand{Name: "go", Args: []string{"install", "broken@v1.0.0"}}, Status: rollbackStatusPlanned},
+	}
+
+	err := applyRollbackPlan(context.Background(), steps, true)
+	if err == nil || !strings.Contains(err.Error(), "1 件") {
+		t.Fatalf("err = %v, want one failure", err)
+	}
+
+	if len(executed) != 2 {
+		t.Fatalf("executed = %v, want 2 commands", executed)
+	}
+
+	if steps[0].Status != rollbackStatusSuccess || steps[1].Status != rollbackStatusUnsupported || steps[2].Status != rollbackStatusFailed {
+		t.Fatalf("statuses = %s/%s/%s, want success/unsupported/failed", steps[0].Status, steps[1].Status, steps[2].Status)
+	}
+
+	report := buildSysRollbackReport("run", true, steps)
+	if report.Supported != 2 || report.Unsupported != 1 || report.Failed != 1 {
+		t.Fatalf("report = %+v, want supported:2 unsupported:1 failed:1", report)
+	}
+
+	if report.Steps[0].Current != "" || report.Steps[0].Command != "go install gopls@v0.16.0" {
+		t.Fatalf("report.Steps[0] = %+v, want command", report.Steps[0])
+	}
+}
+
+func TestWriteRollbackTable_ShowsNote(t *testing.T) {
+	t.Parallel()
+
+	steps := []rollbackStep{{
+		Manager: "snap",
+		Package: history.PackageChange{Name: "firefox", From: "124.0", To: "125.0"},
+		Command: &updater.RollbackCommand{Name: "snap", Args: []string{"revert", "firefox"}, Note: "直前のリビジョンへ戻す（記録バージョンとは限らない）"},
+		Status:  rollbackStatusPlanned,
+	}}
+
+	var buf bytes.Buffer
+	if err := writeRollbackTable(&buf, steps); err != nil {
+		t.Fatalf("writeRollbackTable error: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "snap revert firefox  ※直前のリビジョンへ戻す（記録バージョンとは限らない）") {
+		t.Fatalf("output does not contain the snap note:\n%s", buf.String())
+	}
+
+	report := buildSysRollbackReport("run", false, steps)
+	if report.Steps[0].Note != steps[0].Command.Note {
+		t.Fatalf("report.Steps[0].Note = %q, want %q", report.Steps[0].Note, steps[0].Command.Note)
+	}
+}
+
+func TestRollbackRequiresSudo(t *testing.T) {
+	t.Parallel()
+
+	steps := []rollbackStep{{Command: &updater.RollbackCommand{Name: "go"}}}
+	if rollbackRequiresSudo(steps) {
+		t.Fatal("rollbackRequiresSudo = true, want false")
+	}
+
+	steps = append(steps, rollbackStep{Command: &updater.RollbackCommand{Name: "sudo", Args: []string{"apt"}}})
+	if !rollbackRequiresSudo(steps) {
+		t.Fatal("rollbackRequiresSudo = false, want true")
+	}
+}
+
+func TestBuildRollbackPlan_FromCargoUpdateHistory(t *testing.T) {
+	dir := t.TempDir()
+
+	// cargo install-update が更新前に表示する一覧表だけを出力する偽コマンド
+	fileName, content := "cargo-install-update", "#!/bin/sh\necho 'Package  Installed  Latest   Needs update'\necho 'ripgrep  v13.0.0    v14.0.0  Yes'\n"
+	if runtime.GOOS == "windows" {
+		fileName, content = "cargo-install-update.cmd", "@echo off\necho Package  Installed  Latest   Needs update\necho ripgrep  v13.0.0    v14.0.0  Yes\n"
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, fileName), []byte(content), 0o755); err != nil {
+		t.Fatalf("fake cargo-install-update write failed: %v", err)
+	}
+
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cargo := &updater.CargoUpdater{}
+
+	var result *updater.UpdateResult
+
+	captureStdout(t, func() {
+		var err error
+		if result, err = cargo.Update(context.Background(), updater.UpdateOptions{}); err != nil {
+			t.Fatalf("cargo Update error: %v", err)
+		}
+	})
+
+	startedAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	report := &sysUpdateReport{Managers: []managerReport{newManagerReport(cargo, result, nil)}}
+	run := buildHistoryRun(report, "20260301T090000Z-3f9a1c", startedAt, startedAt.Add(time.Minute))
+
+	steps := buildRollbackPlan(run, "", func(name string) (updater.Updater, bool) { return cargo, name == "cargo" }, nil)
+	if len(steps) != 1 || steps[0].Status != rollbackStatusPlanned {
+		t.Fatalf("steps = %+v, want one planned step", steps)
+	}
+
+	if got, want := steps[0].Command.String(), "cargo install ripgrep --version 13.0.0 --force"; got != want {
+		t.Fatalf("command = %q, want %q", got, want)
+	}
+}
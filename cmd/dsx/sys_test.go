@@ -3,8 +3,12 @@ package main
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/scottlz0310/dsx/internal/config"
 	"github.com/scottlz0310/dsx/internal/runner"
@@ -279,7 +283,7 @@ func TestRunManagerSelfUpdatePhaseWithTUI_CollectsJobResults(t *testing.T) {
 
 	var jobNames []string
 
-	runManagerSelfUpdateJobs = func(ctx context.Context, title string, maxJobs int, jobs []runner.Job, useTUI bool, logFile string) runner.Summary {
+	runManagerSelfUpdateJobs = func(ctx context.Context, title string, maxJobs int, jobs []runner.Job, useTUI bool, logOpts jobLogOptions) runner.Summary {
 		if title != "マネージャ本体更新 進捗" {
 			t.Fatalf("title = %q, want マネージャ本体更新 進捗", title)
 		}
@@ -371,7 +375,7 @@ func TestRunManagerSelfUpdatePhaseWithTUI_RecordsSkippedSummary(t *testing.T) {
 		runManagerSelfUpdateJobs = previous
 	})
 
-	runManagerSelfUpdateJobs = func(context.Context, string, int, []runner.Job, bool, string) runner.Summary {
+	runManagerSelfUpdateJobs = func(context.Context, string, int, []runner.Job, bool, jobLogOptions) runner.Summary {
 		return runner.Summary{Skipped: 2}
 	}
 
@@ -531,15 +535,6 @@ func TestFilterNormalUpdateUpdaters(t *testing.T) {
 	}
 }
 
-func updaterNames(updaters []updater.Updater) []string {
-	names := make([]string, 0, len(updaters))
-	for _, u := range updaters {
-		names = append(names, u.Name())
-	}
-
-	return names
-}
-
 func TestSplitUpdatersForExecution(t *testing.T) {
 	t.Parallel()
 
@@ -582,6 +577,11 @@ func TestMustRunExclusively(t *testing.T) {
 			in:   stubUpdater{name: "apt"},
 			want: true,
 		},
+		{
+			name: "pacmanは単独実行",
+			in:   stubUpdater{name: "pacman"},
+			want: true,
+		},
 		{
 			name: "brewは並列可",
 			in:   stubUpdater{name: "brew"},
@@ -592,6 +592,16 @@ func TestMustRunExclusively(t *testing.T) {
 			in:   stubUpdater{name: "bun"},
 			want: true,
 		},
+		{
+			name: "exclusive のカスタムマネージャは単独実行",
+			in:   mustNewCustomUpdater(t, "foo", config.ManagerConfig{"type": "custom", "update": "foo upgrade", "exclusive": true}),
+			want: true,
+		},
+		{
+			name: "カスタムマネージャは既定で並列可",
+			in:   mustNewCustomUpdater(t, "bar", config.ManagerConfig{"type": "custom", "update": "foo upgrade"}),
+			want: false,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -607,7 +617,70 @@ func TestMustRunExclusively(t *testing.T) {
 	}
 }
 
-func TestExecuteUpdatesParallel_ContextCanceledIsNotFailed(t *testing.T) {
+func TestPrintSysUpdateExecutionNotice(t *testing.T) {
+	updaters := []updater.Updater{stubUpdater{name: "brew"}, stubUpdater{name: "bun"}, mustNewCustomUpdater(t, "my-tool", config.ManagerConfig{"type": "custom", "update": "my-tool upgrade", "exclusive": true})}
+
+	stdout := captureStdout(t, func() {
+		printSysUpdateExecutionNotice(updaters, 4)
+	})
+
+	for _, want := range []string{"単独実行するマネージャがあります（bun, my-tool）", "4 並列で更新します"} {
+		if !strings.Contains(stdout, want) {
+			t.Fatalf("stdout does not contain %q:\n%s", want, stdout)
+		}
+	}
+}
+
+// concurrencyStubUpdater は実行中の更新数を数え、単独実行のマネージャが他と重なったかを記録します。
+type concurrencyStubUpdater struct {
+	stubUpdater
+	exclusive  bool
+	running    *int32
+	overlapped *int32
+}
+
+func (s concurrencyStubUpdater) RequiresSudo() bool { return false }
+
+func (s concurrencyStubUpdater) RunsExclusively() bool { return s.exclusive }
+
+func (s concurrencyStubUpdater) Update(context.Context, updater.UpdateOptions) (*updater.UpdateResult, error) {
+	active := atomic.AddInt32(s.running, 1)
+	defer atomic.AddInt32(s.running, -1)
+
+	time.Sleep(20 * time.Millisecond)
+
+	if s.exclusive && (active > 1 || atomic.LoadInt32(s.running) > 1) {
+		atomic.StoreInt32(s.overlapped, 1)
+	}
+
+	return &updater.UpdateResult{}, nil
+}
+
+func TestExecuteUpdateGraph_ExclusiveManagersRunAlone(t *testing.T) {
+	var running, overlapped int32
+
+	newStub := func(name string, exclusive bool) updater.Updater {
+		return concurrencyStubUpdater{stubUpdater: stubUpdater{name: name}, exclusive: exclusive, running: &running, overlapped: &overlapped}
+	}
+
+	updaters := []updater.Updater{newStub("brew", false), newStub("apt", true), newStub("go", false), newStub("my-tool", true), newStub("cargo", false)}
+
+	var stats updateStats
+
+	captureStdout(t, func() {
+		stats = executeUpdateGraph(context.Background(), updaters, updater.UpdateOptions{}, nil, 4, false)
+	})
+
+	if len(stats.Errors) != 0 || len(stats.Managers) != len(updaters) {
+		t.Fatalf("stats = %+v, want all managers to succeed", stats)
+	}
+
+	if overlapped != 0 {
+		t.Fatal("exclusive managers ran concurrently with other managers")
+	}
+}
+
+func TestExecuteUpdateGraph_ContextCanceledIsNotFailed(t *testing.T) {
 	t.Parallel()
 
 	updaters := []updater.Updater{
@@ -617,7 +690,7 @@ func TestExecuteUpdatesParallel_ContextCanceledIsNotFailed(t *testing.T) {
 		},
 	}
 
-	stats := executeUpdatesParallel(context.Background(), updaters, updater.UpdateOptions{}, 2, false)
+	stats := executeUpdateGraph(context.Background(), updaters, updater.UpdateOptions{}, nil, 2, false)
 
 	if stats.Failed != 0 {
 		t.Fatalf("Failed = %d, want 0", stats.Failed)
@@ -632,7 +705,7 @@ func TestExecuteUpdatesParallel_ContextCanceledIsNotFailed(t *testing.T) {
 	}
 }
 
-func TestExecuteUpdatesParallel_NonContextErrorIsFailed(t *testing.T) {
+func TestExecuteUpdateGraph_NonContextErrorIsFailed(t *testing.T) {
 	t.Parallel()
 
 	updaters := []updater.Updater{
@@ -642,7 +715,7 @@ func TestExecuteUpdatesParallel_NonContextErrorIsFailed(t *testing.T) {
 		},
 	}
 
-	stats := executeUpdatesParallel(context.Background(), updaters, updater.UpdateOptions{}, 2, false)
+	stats := executeUpdateGraph(context.Background(), updaters, updater.UpdateOptions{}, nil, 2, false)
 
 	if stats.Failed != 1 {
 		t.Fatalf("Failed = %d, want 1", stats.Failed)
@@ -657,6 +730,146 @@ func TestExecuteUpdatesParallel_NonContextErrorIsFailed(t *testing.T) {
 	}
 }
 
+type flakyStubUpdater struct {
+	stubUpdater
+	failures int
+	calls    *int32
+}
+
+func (s flakyStubUpdater) Update(context.Context, updater.UpdateOptions) (*updater.UpdateResult, error) {
+	if int(atomic.AddInt32(s.calls, 1)) <= s.failures {
+		return nil, errors.New("temporary failure")
+	}
+
+	return &updater.UpdateResult{UpdatedCount: 1}, nil
+}
+
+type hangingStubUpdater struct {
+	stubUpdater
+}
+
+func (s hangingStubUpdater) Update(ctx context.Context, _ updater.UpdateOptions) (*updater.UpdateResult, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestExecuteUpdateGraph_ManagerTimeoutAndRetries(t *testing.T) {
+	originalBackoff := sysRetryBackoff
+	sysRetryBackoff = func(int, error) time.Duration { return 0 }
+
+	t.Cleanup(func() {
+		sysRetryBackoff = originalBackoff
+	})
+
+	managers := map[string]config.ManagerConfig{
+		"snap":    {"retries": 2},
+		"fwupd":   {"timeout": "20ms"},
+		"pipx":    {"retries": 1},
+		"flatpak": {},
+	}
+
+	for _, jobs := range []int{1, 2} {
+		var snapCalls, pipxCalls, flatpakCalls int32
+
+		updaters := []updater.Updater{
+			flakyStubUpdater{stubUpdater: stubUpdater{name: "snap"}, failures: 2, calls: &snapCalls},
+			hangingStubUpdater{stubUpdater: stubUpdater{name: "fwupd"}},
+			flakyStubUpdater{stubUpdater: stubUpdater{name: "pipx"}, failures: 5, calls: &pipxCalls},
+			flakyStubUpdater{stubUpdater: stubUpdater{name: "flatpak"}, failures: 1, calls: &flatpakCalls},
+		}
+
+		var stats updateStats
+
+		captureStdout(t, func() {
+			stats = executeUpdateGraph(context.Background(), updaters, updater.UpdateOptions{}, managers, jobs, false)
+		})
+
+		statuses := make(map[string]string, len(stats.Managers))
+		for _, report := range stats.Managers {
+			statuses[report.Name] = report.Status
+		}
+
+		want := map[string]string{
+			"snap":    managerReportStatusSuccess,
+			"fwupd":   managerReportStatusFailed,
+			"pipx":    managerReportStatusFailed,
+			"flatpak": managerReportStatusFailed,
+		}
+
+		if len(stats.Managers) != len(want) {
+			t.Fatalf("jobs=%d: reports = %d, want %d (再試行分を重複記録しない)", jobs, len(stats.Managers), len(want))
+		}
+
+		for name, status := range want {
+			if statuses[name] != status {
+				t.Fatalf("jobs=%d: %s status = %q, want %q", jobs, name, statuses[name], status)
+			}
+		}
+
+		if snapCalls != 3 || pipxCalls != 2 || flatpakCalls != 1 {
+			t.Fatalf("jobs=%d: calls snap=%d pipx=%d flatpak=%d, want 3/2/1", jobs, snapCalls, pipxCalls, flatpakCalls)
+		}
+
+		if stats.Failed != 3 || stats.Updated != 1 {
+			t.Fatalf("jobs=%d: Failed=%d Updated=%d, want 3/1", jobs, stats.Failed, stats.Updated)
+		}
+
+		var timedOut bool
+
+		for _, err := range stats.Errors {
+			if errors.Is(err, runner.ErrJobTimeout) {
+				timedOut = true
+			}
+		}
+
+		if !timedOut {
+			t.Fatalf("jobs=%d: errors = %v, want ErrJobTimeout", jobs, stats.Errors)
+		}
+	}
+}
+
+func TestExecuteUpdateGraph_JobOutputDir(t *testing.T) {
+	for _, jobs := range []int{1, 2} {
+		sysJobOutputDir = t.TempDir()
+
+		t.Cleanup(func() {
+			sysJobOutputDir = ""
+		})
+
+		updaters := []updater.Updater{
+			stubUpdater{name: "brew", updateErr: errors.New("exit status 1")},
+			stubUpdater{name: "apt"},
+		}
+
+		var stats updateStats
+
+		captureStdout(t, func() {
+			stats = executeUpdateGraph(context.Background(), updaters, updater.UpdateOptions{}, nil, jobs, false)
+		})
+
+		brewLog := filepath.Join(sysJobOutputDir, "brew.log")
+
+		content, err := os.ReadFile(brewLog)
+		if err != nil {
+			t.Fatalf("jobs=%d: 出力ログが作成されていません: %v", jobs, err)
+		}
+
+		if !strings.Contains(string(content), "exit status 1") {
+			t.Fatalf("jobs=%d: 出力ログ = %q, want failure", jobs, content)
+		}
+
+		if len(stats.Errors) != 1 || !strings.Contains(stats.Errors[0].Error(), "出力ログ: "+brewLog) {
+			t.Fatalf("jobs=%d: errors = %v, want log path", jobs, stats.Errors)
+		}
+
+		for _, report := range stats.Managers {
+			if want := filepath.Join(sysJobOutputDir, report.Name+".log"); report.LogPath != want {
+				t.Fatalf("jobs=%d: %s LogPath = %q, want %q", jobs, report.Name, report.LogPath, want)
+			}
+		}
+	}
+}
+
 func TestEnabledMark(t *testing.T) {
 	t.Parallel()
 
@@ -715,6 +928,34 @@ func TestUpdaterRequiresSudo(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name:     "dnfはデフォルトでsudo必要",
+			updater:  "dnf",
+			managers: map[string]config.ManagerConfig{},
+			want:     true,
+		},
+		{
+			name:    "dnfはuse_sudo=falseでsudo不要",
+			updater: "dnf",
+			managers: map[string]config.ManagerConfig{
+				"dnf": {"use_sudo": false},
+			},
+			want: false,
+		},
+		{
+			name:     "pacmanはデフォルトでsudo必要",
+			updater:  "pacman",
+			managers: map[string]config.ManagerConfig{},
+			want:     true,
+		},
+		{
+			name:    "pacmanはuse_sudo=falseでsudo不要",
+			updater: "pacman",
+			managers: map[string]config.ManagerConfig{
+				"pacman": {"use_sudo": false},
+			},
+			want: false,
+		},
 		{
 			name:    "snapはsudo=falseでsudo不要（旧キー互換）",
 			updater: "snap",
@@ -743,6 +984,17 @@ func TestUpdaterRequiresSudo(t *testing.T) {
 	}
 }
 
+func mustNewCustomUpdater(t *testing.T, name string, cfg config.ManagerConfig) *updater.CustomUpdater {
+	t.Helper()
+
+	u, err := updater.NewCustomUpdater(name, cfg)
+	if err != nil {
+		t.Fatalf("NewCustomUpdater() error = %v", err)
+	}
+
+	return u
+}
+
 func TestPhaseRequiresSudo(t *testing.T) {
 	t.Parallel()
 
@@ -778,6 +1030,21 @@ func TestPhaseRequiresSudo(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "sudo: true のカスタムマネージャはsudo必要",
+			updaters: []updater.Updater{
+				stubUpdater{name: "go"},
+				mustNewCustomUpdater(t, "foo", config.ManagerConfig{"type": "custom", "update": "foo upgrade", "sudo": true}),
+			},
+			want: true,
+		},
+		{
+			name: "sudo のないカスタムマネージャは不要",
+			updaters: []updater.Updater{
+				mustNewCustomUpdater(t, "foo", config.ManagerConfig{"type": "custom", "update": "foo upgrade"}),
+			},
+			want: false,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,324 @@
+// dsx-updater-example は dsx の外部 updater プラグインプロトコル（バージョン 1）の参照実装です。
+//
+// プラグインは呼び出しごとに起動され、標準入力の JSON リクエスト1件に対して標準出力へ JSON の応答を1件返します。
+// 進捗などの人間向けの出力は標準エラーに書き出します（update / self_update では端末とログにそのまま表示されます）。
+//
+// この参照実装は、状態ファイル（JSON）に記録した「インストール済み」と「最新」のバージョンを比較して更新を模擬します。
+// 実際のツールに対応させる場合は、loadState / saveState を対象ツールの問い合わせ・更新処理に置き換えてください。
+//
+//	{
+//	  "self": {"installed": "0.1.0", "latest": "0.2.0"},
+//	  "packages": {"foo": {"installed": "1.0.0", "latest": "1.1.0"}}
+//	}
+//
+// 適合性は `dsx sys plugin verify dsx-updater-example` で検証できます。
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	protocolVersion = 1
+	pluginName      = "example"
+)
+
+type request struct {
+	ProtocolVersion int                    `json:"protocol_version"`
+	Method          string                 `json:"method"`
+	Config          map[string]interface{} `json:"config"`
+	Options         struct {
+		DryRun bool `json:"dry_run"`
+	} `json:"options"`
+	Packages []string `json:"packages"`
+}
+
+type response struct {
+	ProtocolVersion int         `json:"protocol_version"`
+	Result          interface{} `json:"result,omitempty"`
+	Error           *errorBody  `json:"error,omitempty"`
+}
+
+type errorBody struct {
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+type pkg struct {
+	Name           string `json:"name"`
+	CurrentVersion string `json:"current_version,omitempty"`
+	NewVersion     string `json:"new_version,omitempty"`
+}
+
+type checkResult struct {
+	AvailableUpdates int    `json:"available_updates"`
+	Packages         []pkg  `json:"packages"`
+	Message          string `json:"message,omitempty"`
+}
+
+type updateResult struct {
+	UpdatedCount int      `json:"updated_count"`
+	FailedCount  int      `json:"failed_count"`
+	Packages     []pkg    `json:"packages"`
+	Errors       []string `json:"errors,omitempty"`
+	Message      string   `json:"message,omitempty"`
+	Continuation string   `json:"continuation,omitempty"`
+}
+
+type version struct {
+	Installed string `json:"installed"`
+	Latest    string `json:"latest"`
+}
+
+type state struct {
+	Self     version            `json:"self"`
+	Packages map[string]version `json:"packages"`
+}
+
+// errUnsupported は未対応のメソッドを表します。error.code に "unsupported_method" を返すことはプロトコルの必須要件です。
+var errUnsupported = errors.New("unsupported method")
+
+func main() {
+	var req request
+
+	resp := response{ProtocolVersion: protocolVersion}
+
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		resp.Error = &errorBody{Message: fmt.Sprintf("リクエストを解析できません: %v", err)}
+		writeResponse(resp)
+		os.Exit(1)
+	}
+
+	result, err := handle(req)
+
+	switch {
+	case errors.Is(err, errUnsupported):
+		resp.Error = &errorBody{Code: "unsupported_method", Message: fmt.Sprintf("未対応のメソッドです: %s", req.Method)}
+	case err != nil:
+		resp.Error = &errorBody{Message: err.Error()}
+	default:
+		resp.Result = result
+	}
+
+	writeResponse(resp)
+}
+
+func writeResponse(resp response) {
+	if err := json.NewEncoder(os.Stdout).Encode(resp); err != nil {
+		fmt.Fprintf(os.Stderr, "応答の書き出しに失敗: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func handle(req request) (interface{}, error) {
+	if req.ProtocolVersion != protocolVersion {
+		return nil, fmt.Errorf("未対応のプロトコルバージョンです: %d", req.ProtocolVersion)
+	}
+
+	switch req.Method {
+	case "name":
+		return map[string]interface{}{"name": pluginName, "capabilities": []string{"self_update"}}, nil
+	case "display_name":
+		return map[string]string{"display_name": "Example (参照プラグイン)"}, nil
+	case "configure":
+		_, err := statePath(req.Config)
+		return struct{}{}, err
+	case "is_available":
+		return isAvailable(req.Config)
+	case "check":
+		return check(req.Config)
+	case "update":
+		return update(req, false)
+	case "check_self_update":
+		return checkSelf(req.Config)
+	case "self_update":
+		return update(req, true)
+	default:
+		return nil, errUnsupported
+	}
+}
+
+// statePath は sys.managers.example.state_file（未指定時は $XDG_STATE_HOME/dsx-updater-example/state.json）を返します。
+func statePath(cfg map[string]interface{}) (string, error) {
+	if raw, ok := cfg["state_file"]; ok && raw != nil {
+		path, isString := raw.(string)
+		if !isString || path == "" {
+			return "", fmt.Errorf("state_file は空でない文字列で指定してください: %v", raw)
+		}
+
+		return path, nil
+	}
+
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+
+		dir = filepath.Join(home, ".local", "state")
+	}
+
+	return filepath.Join(dir, "dsx-updater-example", "state.json"), nil
+}
+
+func isAvailable(cfg map[string]interface{}) (interface{}, error) {
+	path, err := statePath(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return map[string]interface{}{"available": false, "reason": fmt.Sprintf("状態ファイル %s がありません", path)}, nil
+	}
+
+	return map[string]interface{}{"available": true}, nil
+}
+
+func loadState(cfg map[string]interface{}) (*state, string, error) {
+	path, err := statePath(cfg)
+	if err != nil {
+		return nil, "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("状態ファイルの読み込みに失敗: %w", err)
+	}
+
+	var s state
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, "", fmt.Errorf("状態ファイルの解析に失敗: %w", err)
+	}
+
+	return &s, path, nil
+}
+
+func saveState(path string, s *state) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+func check(cfg map[string]interface{}) (interface{}, error) {
+	s, _, err := loadState(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	packages := outdated(s)
+
+	return checkResult{AvailableUpdates: len(packages), Packages: packages}, nil
+}
+
+func checkSelf(cfg map[string]interface{}) (interface{}, error) {
+	s, _, err := loadState(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	result := checkResult{Packages: []pkg{}}
+	if s.Self.Latest != "" && s.Self.Installed != s.Self.Latest {
+		result.Packages = append(result.Packages, pkg{Name: pluginName, CurrentVersion: s.Self.Installed, NewVersion: s.Self.Latest})
+		result.AvailableUpdates = 1
+	}
+
+	return result, nil
+}
+
+// outdated はインストール済みと最新のバージョンが異なるパッケージを名前順に返します。
+func outdated(s *state) []pkg {
+	names := make([]string, 0, len(s.Packages))
+	for name := range s.Packages {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	packages := make([]pkg, 0)
+
+	for _, name := range names {
+		v := s.Packages[name]
+		if v.Latest != "" && v.Installed != v.Latest {
+			packages = append(packages, pkg{Name: name, CurrentVersion: v.Installed, NewVersion: v.Latest})
+		}
+	}
+
+	return packages
+}
+
+// update は packages（空の場合はすべての更新候補）を最新にします。dry_run では状態ファイルを変更しません。
+func update(req request, self bool) (interface{}, error) {
+	s, path, err := loadState(req.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []pkg
+
+	if self {
+		if s.Self.Latest != "" && s.Self.Installed != s.Self.Latest {
+			targets = []pkg{{Name: pluginName, CurrentVersion: s.Self.Installed, NewVersion: s.Self.Latest}}
+		}
+	} else {
+		targets = selectTargets(outdated(s), req.Packages)
+	}
+
+	result := updateResult{Packages: targets, Continuation: "continue"}
+
+	if req.Options.DryRun {
+		result.Message = fmt.Sprintf("%d 件が更新可能です（DryRunモード）", len(targets))
+		return result, nil
+	}
+
+	for _, target := range targets {
+		fmt.Fprintf(os.Stderr, "%s: %s -> %s\n", target.Name, target.CurrentVersion, target.NewVersion)
+
+		if self {
+			s.Self.Installed = target.NewVersion
+			continue
+		}
+
+		v := s.Packages[target.Name]
+		v.Installed = target.NewVersion
+		s.Packages[target.Name] = v
+	}
+
+	if err := saveState(path, s); err != nil {
+		return nil, fmt.Errorf("状態ファイルの書き込みに失敗: %w", err)
+	}
+
+	result.UpdatedCount = len(targets)
+	result.Message = fmt.Sprintf("%d 件を更新しました", len(targets))
+
+	return result, nil
+}
+
+func selectTargets(candidates []pkg, names []string) []pkg {
+	if len(names) == 0 {
+		return candidates
+	}
+
+	wanted := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		wanted[name] = struct{}{}
+	}
+
+	targets := make([]pkg, 0, len(names))
+
+	for _, candidate := range candidates {
+		if _, ok := wanted[candidate.Name]; ok {
+			targets = append(targets, candidate)
+		}
+	}
+
+	return targets
+}
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// ManagerKeyType はマネージャの種類のキーです。"custom" を指定すると設定だけで定義するカスタムマネージャになります。
+	ManagerKeyType = "type"
+	// ManagerTypeCustom は check / update などのシェルコマンドで定義するカスタムマネージャの種類です。
+	ManagerTypeCustom = "custom"
+
+	customKeyDisplayName = "display_name"
+	customKeyAvailable   = "available"
+	customKeyCheck       = "check"
+	customKeyUpdate      = "update"
+	customKeyParseRegex  = "parse_regex"
+	customKeyParseJSON   = "parse_json"
+	customKeyJSONFields  = "json_fields"
+	customKeySudo        = "sudo"
+	customKeyExclusive   = "exclusive"
+)
+
+// CustomManagerSpec は sys.managers.<name> に type: custom で宣言されたマネージャの定義です。
+type CustomManagerSpec struct {
+	// DisplayName は表示名です。空の場合は呼び出し側で既定の表示名を使います。
+	DisplayName string
+	// Available は利用可否を判定するシェルコマンドです（終了コード 0 で利用可能）。
+	// 空の場合は Update の先頭のコマンドが PATH にあるかで判定します。
+	Available string
+	// Check は更新可能なパッケージを出力するシェルコマンドです。空の場合は更新可否を判定せず毎回 Update を実行します。
+	Check string
+	// Update は更新を実行するシェルコマンドです（必須）。
+	Update string
+	// ParseRegex は Check の出力の各行から name / current / new の名前付きグループを抽出する正規表現です。
+	ParseRegex *regexp.Regexp
+	// ParseJSON は Check の JSON 出力のうち、パッケージの配列（またはパッケージ名をキーとするオブジェクト）を指すドット区切りのパスです。
+	// ルート自体を指す場合は "." を指定します。
+	ParseJSON string
+	// JSONName / JSONCurrent / JSONNew は ParseJSON の各要素から値を取り出すドット区切りのパスです。
+	JSONName    string
+	JSONCurrent string
+	JSONNew     string
+	// Sudo は更新前に sudo の認証を行い、Update を sudo で実行するかどうかです。
+	Sudo bool
+	// Exclusive は他のマネージャと並列に実行しないかどうかです。
+	Exclusive bool
+}
+
+// IsCustom は type: custom で宣言されたカスタムマネージャかどうかを返します。
+func (c ManagerConfig) IsCustom() bool {
+	value, ok := c[ManagerKeyType].(string)
+	return ok && strings.TrimSpace(value) == ManagerTypeCustom
+}
+
+// CustomManager は type: custom のマネージャ定義を解釈します。
+// update がない、正規表現が不正、parse_regex と parse_json の両方を指定した場合などはエラーを返します。
+func (c ManagerConfig) CustomManager() (*CustomManagerSpec, error) {
+	if !c.IsCustom() {
+		return nil, fmt.Errorf("%s: %q ではありません", ManagerKeyType, ManagerTypeCustom)
+	}
+
+	spec := &CustomManagerSpec{JSONName: "name", JSONCurrent: "current", JSONNew: "latest"}
+
+	stringFields := []struct {
+		key string
+		dst *string
+	}{
+		{key: customKeyDisplayName, dst: &spec.DisplayName},
+		{key: customKeyAvailable, dst: &spec.Available},
+		{key: customKeyCheck, dst: &spec.Check},
+		{key: customKeyUpdate, dst: &spec.Update},
+		{key: customKeyParseJSON, dst: &spec.ParseJSON},
+	}
+
+	for _, field := range stringFields {
+		value, err := c.optionalString(field.key)
+		if err != nil {
+			return nil, err
+		}
+
+		*field.dst = value
+	}
+
+	if spec.Update == "" {
+		return nil, fmt.Errorf("%s は必須です", customKeyUpdate)
+	}
+
+	pattern, err := c.optionalString(customKeyParseRegex)
+	if err != nil {
+		return nil, err
+	}
+
+	if pattern != "" {
+		if spec.ParseJSON != "" {
+			return nil, fmt.Errorf("%s と %s は同時に指定できません", customKeyParseRegex, customKeyParseJSON)
+		}
+
+		spec.ParseRegex, err = regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%s が不正な正規表現です: %w", customKeyParseRegex, err)
+		}
+	}
+
+	if err := c.applyCustomJSONFields(spec); err != nil {
+		return nil, err
+	}
+
+	for _, field := range []struct {
+		key string
+		dst *bool
+	}{
+		{key: customKeySudo, dst: &spec.Sudo},
+		{key: customKeyExclusive, dst: &spec.Exclusive},
+	} {
+		raw, ok := c[field.key]
+		if !ok || raw == nil {
+			continue
+		}
+
+		value, isBool := raw.(bool)
+		if !isBool {
+			return nil, fmt.Errorf("%s は true / false で指定してください: %v", field.key, raw)
+		}
+
+		*field.dst = value
+	}
+
+	return spec, nil
+}
+
+// applyCustomJSONFields は json_fields（name / current / new）を読み取ります。
+func (c ManagerConfig) applyCustomJSONFields(spec *CustomManagerSpec) error {
+	raw, ok := c[customKeyJSONFields]
+	if !ok || raw == nil {
+		return nil
+	}
+
+	fields, isMap := raw.(map[string]interface{})
+	if !isMap {
+		return fmt.Errorf("%s は name / current / new をキーとするマップで指定してください: %v", customKeyJSONFields, raw)
+	}
+
+	for key, value := range fields {
+		path, isString := value.(string)
+		if !isString || strings.TrimSpace(path) == "" {
+			return fmt.Errorf("%s.%s は空でない文字列で指定してください: %v", customKeyJSONFields, key, value)
+		}
+
+		switch key {
+		case "name":
+			spec.JSONName = strings.TrimSpace(path)
+		case "current":
+			spec.JSONCurrent = strings.TrimSpace(path)
+		case "new":
+			spec.JSONNew = strings.TrimSpace(path)
+		default:
+			return fmt.Errorf("%s に未知のキーがあります: %q（name / current / new のいずれか）", customKeyJSONFields, key)
+		}
+	}
+
+	return nil
+}
+
+func (c ManagerConfig) optionalString(key string) (string, error) {
+	raw, ok := c[key]
+	if !ok || raw == nil {
+		return "", nil
+	}
+
+	value, isString := raw.(string)
+	if !isString {
+		return "", fmt.Errorf("%s は文字列で指定してください: %v", key, raw)
+	}
+
+	return strings.TrimSpace(value), nil
+}
@@ -109,6 +109,10 @@ func Default() *Config {
 				Target:          []string{RepoCleanupTargetMerged, RepoCleanupTargetSquashed},
 				ExcludeBranches: []string{"main", "master", "develop"},
 			},
+			Discovery: RepoDiscoveryConfig{
+				MaxDepth:  1,
+				StopAtGit: true,
+			},
 		},
 		Sys: SysConfig{
 			Enable:   []string{},
@@ -152,6 +156,8 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("repo.cleanup.enabled", true)
 	v.SetDefault("repo.cleanup.target", []string{RepoCleanupTargetMerged, RepoCleanupTargetSquashed})
 	v.SetDefault("repo.cleanup.exclude_branches", []string{"main", "master", "develop"})
+	v.SetDefault("repo.discovery.max_depth", 1)
+	v.SetDefault("repo.discovery.stop_at_git", true)
 
 	// Sys defaults (managers are enabled per environment usually, but defaults can be empty)
 	v.SetDefault("sys.enable", []string{})
@@ -173,6 +179,16 @@ func ConfigPath() (string, error) {
 	return filepath.Join(home, ".config", "dsx", "config.yaml"), nil
 }
 
+// PluginDir は外部 updater プラグイン（dsx-updater-*）を配置するディレクトリを返します。
+func PluginDir() (string, error) {
+	path, err := ConfigPath()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(filepath.Dir(path), "plugins"), nil
+}
+
 // ConfigFileExists は設定ファイルの存在有無を返します。
 // path には判定対象の設定ファイルパスが入ります。
 func ConfigFileExists() (exists bool, path string, err error) {
@@ -42,6 +42,8 @@ func TestDefault(t *testing.T) {
 		assert.True(t, cfg.Repo.Cleanup.Enabled)
 		assert.Contains(t, cfg.Repo.Cleanup.Target, "merged")
 		assert.Contains(t, cfg.Repo.Cleanup.ExcludeBranches, "main")
+		assert.Equal(t, 1, cfg.Repo.Discovery.MaxDepth)
+		assert.True(t, cfg.Repo.Discovery.StopAtGit)
 
 		// Sys defaults
 		assert.Empty(t, cfg.Sys.Enable)
@@ -69,6 +71,8 @@ func TestLoad(t *testing.T) {
 		// デフォルト値が設定されていることを確認
 		assert.Equal(t, 1, cfg.Version)
 		assert.Equal(t, 8, cfg.Control.Concurrency)
+		assert.Equal(t, 1, cfg.Repo.Discovery.MaxDepth)
+		assert.True(t, cfg.Repo.Discovery.StopAtGit)
 	})
 
 	t.Run("有効なYAML設定ファイルの読み込み", func(t *testing.T) {
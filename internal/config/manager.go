@@ -0,0 +1,168 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	// ManagerKeyHold は更新を保留（pin）するパッケージ名パターンのキーです。
+	// 保留されたパッケージは更新対象から外し、結果に「保留」として報告します。
+	ManagerKeyHold = "hold"
+	// ManagerKeyIgnore は更新確認・更新の対象外とするパッケージ名パターンのキーです。
+	// 無視されたパッケージは結果にも表示しません。
+	ManagerKeyIgnore = "ignore"
+	// ManagerKeyAfter はこのマネージャより先に更新を完了させるマネージャ名のキーです。
+	// 先行マネージャが失敗した場合、このマネージャの更新はスキップされます。
+	ManagerKeyAfter = "after"
+	// ManagerKeyTimeout は1回の更新あたりの制限時間（例: "5m"）のキーです。
+	// 全体の control.timeout とは別に、ハングしたマネージャだけを打ち切るために使います。
+	ManagerKeyTimeout = "timeout"
+	// ManagerKeyRetries は更新に失敗したときに再試行する回数（初回を含まない）のキーです。
+	ManagerKeyRetries = "retries"
+)
+
+// StringList は指定キーの値を文字列リストとして返します。
+// YAML から読み込んだ []interface{} と、コードから渡された []string / string の両方を受け付けます。
+// 文字列以外の要素が含まれる場合はエラーを返します。
+func (c ManagerConfig) StringList(key string) ([]string, error) {
+	raw, ok := c[key]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	switch v := raw.(type) {
+	case string:
+		return normalizeStringList([]string{v}), nil
+	case []string:
+		return normalizeStringList(v), nil
+	case []interface{}:
+		items := make([]string, 0, len(v))
+
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s[%d] が文字列ではありません: %v", key, i, item)
+			}
+
+			items = append(items, s)
+		}
+
+		return normalizeStringList(items), nil
+	default:
+		return nil, fmt.Errorf("%s は文字列のリストで指定してください: %v", key, raw)
+	}
+}
+
+// Hold は hold に指定されたパッケージ名パターンを返します。
+func (c ManagerConfig) Hold() ([]string, error) {
+	return c.StringList(ManagerKeyHold)
+}
+
+// Ignore は ignore に指定されたパッケージ名パターンを返します。
+func (c ManagerConfig) Ignore() ([]string, error) {
+	return c.StringList(ManagerKeyIgnore)
+}
+
+// After は after に指定された先行マネージャ名を返します。
+func (c ManagerConfig) After() ([]string, error) {
+	return c.StringList(ManagerKeyAfter)
+}
+
+// Timeout は timeout に指定された制限時間を返します。未指定の場合は 0 を返します。
+func (c ManagerConfig) Timeout() (time.Duration, error) {
+	raw, ok := c[ManagerKeyTimeout]
+	if !ok || raw == nil {
+		return 0, nil
+	}
+
+	value, ok := raw.(string)
+	if !ok {
+		return 0, fmt.Errorf("%s は期間の文字列で指定してください（例: \"5m\"）: %v", ManagerKeyTimeout, raw)
+	}
+
+	return ParseJobTimeout(value)
+}
+
+// Retries は retries に指定された再試行回数を返します。未指定の場合は 0 を返します。
+func (c ManagerConfig) Retries() (int, error) {
+	raw, ok := c[ManagerKeyRetries]
+	if !ok || raw == nil {
+		return 0, nil
+	}
+
+	var retries int
+
+	switch v := raw.(type) {
+	case int:
+		retries = v
+	case int64:
+		retries = int(v)
+	case uint64:
+		retries = int(v)
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, fmt.Errorf("%s は整数で指定してください: %v", ManagerKeyRetries, raw)
+		}
+
+		retries = int(v)
+	default:
+		return 0, fmt.Errorf("%s は整数で指定してください: %v", ManagerKeyRetries, raw)
+	}
+
+	if retries < 0 {
+		return 0, fmt.Errorf("%s は0以上で指定してください: %d", ManagerKeyRetries, retries)
+	}
+
+	return retries, nil
+}
+
+// ParseJobTimeout はジョブ単位の制限時間（例: "5m"）を解釈します。
+// 空文字は「制限なし」として 0 を返し、0 以下の期間はエラーにします。
+func ParseJobTimeout(value string) (time.Duration, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return 0, nil
+	}
+
+	parsed, err := time.ParseDuration(trimmed)
+	if err != nil {
+		return 0, fmt.Errorf("不正な期間です: %q（例: \"5m\"）", value)
+	}
+
+	if parsed <= 0 {
+		return 0, fmt.Errorf("0より大きい値を指定してください: %q", value)
+	}
+
+	return parsed, nil
+}
+
+// MatchPackagePattern はパッケージ名が hold / ignore のパターンに一致するかを判定します。
+// パターンは path.Match 形式のグロブ（例: "linux-*"）で、不正なパターンは完全一致で比較します。
+func MatchPackagePattern(pattern, name string) bool {
+	if pattern == name {
+		return true
+	}
+
+	matched, err := path.Match(pattern, name)
+
+	return err == nil && matched
+}
+
+func normalizeStringList(items []string) []string {
+	result := make([]string, 0, len(items))
+
+	for _, item := range items {
+		trimmed := strings.TrimSpace(item)
+		if trimmed == "" {
+			continue
+		}
+
+		result = append(result, trimmed)
+	}
+
+	return result
+}
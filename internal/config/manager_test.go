@@ -0,0 +1,195 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestManagerConfigStringList(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		cfg     ManagerConfig
+		want    []string
+		wantErr bool
+	}{
+		{name: "未設定は nil", cfg: ManagerConfig{}, want: nil},
+		{name: "YAML のリスト", cfg: ManagerConfig{"hold": []interface{}{"docker-ce", " linux-* ", ""}}, want: []string{"docker-ce", "linux-*"}},
+		{name: "[]string", cfg: ManagerConfig{"hold": []string{"node"}}, want: []string{"node"}},
+		{name: "単一文字列", cfg: ManagerConfig{"hold": "node"}, want: []string{"node"}},
+		{name: "文字列以外の要素はエラー", cfg: ManagerConfig{"hold": []interface{}{"node", true}}, wantErr: true},
+		{name: "リスト以外はエラー", cfg: ManagerConfig{"hold": 1}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tc.cfg.Hold()
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("Hold() error = nil, want error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Hold() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("Hold() = %#v, want %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMatchPackagePattern(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: "docker-ce", name: "docker-ce", want: true},
+		{pattern: "docker-ce", name: "docker-ce-cli", want: false},
+		{pattern: "linux-*", name: "linux-image-generic", want: true},
+		{pattern: "[", name: "[", want: true},
+		{pattern: "[", name: "a", want: false},
+	}
+
+	for _, tc := range testCases {
+		if got := MatchPackagePattern(tc.pattern, tc.name); got != tc.want {
+			t.Errorf("MatchPackagePattern(%q, %q) = %v, want %v", tc.pattern, tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestManagerConfigTimeoutAndRetries(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		cfg         ManagerConfig
+		wantTimeout time.Duration
+		wantRetries int
+		wantErr     bool
+	}{
+		{name: "未設定は 0", cfg: ManagerConfig{}},
+		{name: "YAML の値", cfg: ManagerConfig{"timeout": "5m", "retries": 2}, wantTimeout: 5 * time.Minute, wantRetries: 2},
+		{name: "float64 の整数値", cfg: ManagerConfig{"retries": float64(3)}, wantRetries: 3},
+		{name: "期間でない timeout はエラー", cfg: ManagerConfig{"timeout": 300}, wantErr: true},
+		{name: "0 以下の timeout はエラー", cfg: ManagerConfig{"timeout": "0s"}, wantErr: true},
+		{name: "負の retries はエラー", cfg: ManagerConfig{"retries": -1}, wantErr: true},
+		{name: "小数の retries はエラー", cfg: ManagerConfig{"retries": 1.5}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			timeout, timeoutErr := tc.cfg.Timeout()
+			retries, retriesErr := tc.cfg.Retries()
+
+			if tc.wantErr {
+				if timeoutErr == nil && retriesErr == nil {
+					t.Fatalf("Timeout()/Retries() error = nil, want error")
+				}
+
+				return
+			}
+
+			if timeoutErr != nil || retriesErr != nil {
+				t.Fatalf("Timeout() error = %v, Retries() error = %v", timeoutErr, retriesErr)
+			}
+
+			if timeout != tc.wantTimeout || retries != tc.wantRetries {
+				t.Fatalf("Timeout() = %s, Retries() = %d, want %s, %d", timeout, retries, tc.wantTimeout, tc.wantRetries)
+			}
+		})
+	}
+}
+
+func TestManagerConfigCustomManager(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		cfg     ManagerConfig
+		check   func(t *testing.T, spec *CustomManagerSpec)
+		wantErr string
+	}{
+		{
+			name: "最小構成は既定の JSON フィールド",
+			cfg:  ManagerConfig{"type": "custom", "update": " foo self-update "},
+			check: func(t *testing.T, spec *CustomManagerSpec) {
+				if spec.Update != "foo self-update" || spec.Check != "" || spec.ParseRegex != nil {
+					t.Fatalf("spec = %#v", spec)
+				}
+
+				if spec.JSONName != "name" || spec.JSONCurrent != "current" || spec.JSONNew != "latest" {
+					t.Fatalf("json fields = %q / %q / %q", spec.JSONName, spec.JSONCurrent, spec.JSONNew)
+				}
+			},
+		},
+		{
+			name: "全項目",
+			cfg: ManagerConfig{
+				"type": "custom", "display_name": "Foo", "available": "command -v foo", "check": "foo outdated --json",
+				"update": "foo upgrade", "parse_json": "updates", "json_fields": map[string]interface{}{"name": "id", "new": "version.latest"},
+				"sudo": true, "exclusive": true,
+			},
+			check: func(t *testing.T, spec *CustomManagerSpec) {
+				if spec.DisplayName != "Foo" || spec.Available != "command -v foo" || spec.ParseJSON != "updates" || !spec.Sudo || !spec.Exclusive {
+					t.Fatalf("spec = %#v", spec)
+				}
+
+				if spec.JSONName != "id" || spec.JSONCurrent != "current" || spec.JSONNew != "version.latest" {
+					t.Fatalf("json fields = %q / %q / %q", spec.JSONName, spec.JSONCurrent, spec.JSONNew)
+				}
+			},
+		},
+		{name: "type: custom 以外はエラー", cfg: ManagerConfig{"update": "foo"}, wantErr: "type"},
+		{name: "update がなければエラー", cfg: ManagerConfig{"type": "custom", "check": "foo"}, wantErr: "update は必須です"},
+		{name: "不正な正規表現はエラー", cfg: ManagerConfig{"type": "custom", "update": "foo", "parse_regex": "("}, wantErr: "parse_regex"},
+		{
+			name:    "parse_regex と parse_json の同時指定はエラー",
+			cfg:     ManagerConfig{"type": "custom", "update": "foo", "parse_regex": ".*", "parse_json": "."},
+			wantErr: "同時に指定できません",
+		},
+		{
+			name:    "json_fields の未知のキーはエラー",
+			cfg:     ManagerConfig{"type": "custom", "update": "foo", "json_fields": map[string]interface{}{"version": "v"}},
+			wantErr: "未知のキー",
+		},
+		{name: "sudo が真偽値でなければエラー", cfg: ManagerConfig{"type": "custom", "update": "foo", "sudo": "yes"}, wantErr: "sudo"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			spec, err := tc.cfg.CustomManager()
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("CustomManager() error = %v, want %q", err, tc.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CustomManager() error = %v", err)
+			}
+
+			tc.check(t, spec)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Label は出力に使うルートの表示名を返します。
+func (r RepoRootConfig) Label() string {
+	if name := strings.TrimSpace(r.Name); name != "" {
+		return name
+	}
+
+	return r.Path
+}
+
+// EffectiveRepoRoots は処理対象のルート一覧を返します。
+// repo.roots が空の場合は repo.root を1件のルートとして返します（後方互換）。
+func (c *Config) EffectiveRepoRoots() []RepoRootConfig {
+	if len(c.Repo.Roots) == 0 {
+		return []RepoRootConfig{{Path: c.Repo.Root}}
+	}
+
+	return append([]RepoRootConfig(nil), c.Repo.Roots...)
+}
+
+// FindRepoRoot は path と一致する repo.roots のエントリを返します。
+func (c *Config) FindRepoRoot(path string) (RepoRootConfig, bool) {
+	target := filepath.Clean(strings.TrimSpace(path))
+
+	for _, root := range c.Repo.Roots {
+		if filepath.Clean(strings.TrimSpace(root.Path)) == target {
+			return root, true
+		}
+	}
+
+	return RepoRootConfig{}, false
+}
+
+// ForRepoRoot は root のパスとルートごとの上書き設定を反映した設定のコピーを返します。
+// 返した設定の Repo.Root は root.Path、Repo.Roots は空です。
+func (c *Config) ForRepoRoot(root RepoRootConfig) *Config {
+	merged := *c
+	merged.Repo.Root = root.Path
+	merged.Repo.Roots = nil
+
+	if owner := strings.TrimSpace(root.GitHub.Owner); owner != "" {
+		merged.Repo.GitHub.Owner = owner
+	}
+
+	if protocol := strings.TrimSpace(root.GitHub.Protocol); protocol != "" {
+		merged.Repo.GitHub.Protocol = protocol
+	}
+
+	overrideBool(&merged.Repo.Sync.AutoStash, root.Sync.AutoStash)
+	overrideBool(&merged.Repo.Sync.Prune, root.Sync.Prune)
+	overrideBool(&merged.Repo.Sync.SubmoduleUpdate, root.Sync.SubmoduleUpdate)
+
+	if timeout := strings.TrimSpace(root.Sync.Timeout); timeout != "" {
+		merged.Repo.Sync.Timeout = timeout
+	}
+
+	overrideBool(&merged.Repo.Cleanup.Enabled, root.Cleanup.Enabled)
+
+	if root.Cleanup.Target != nil {
+		merged.Repo.Cleanup.Target = root.Cleanup.Target
+	}
+
+	if root.Cleanup.ExcludeBranches != nil {
+		merged.Repo.Cleanup.ExcludeBranches = root.Cleanup.ExcludeBranches
+	}
+
+	return &merged
+}
+
+func overrideBool(dst *bool, value *bool) {
+	if value != nil {
+		*dst = *value
+	}
+}
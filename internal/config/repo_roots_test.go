@@ -0,0 +1,110 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEffectiveRepoRoots(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		repo RepoConfig
+		want []RepoRootConfig
+	}{
+		{
+			name: "roots 未指定時は root を1件のルートとして扱う",
+			repo: RepoConfig{Root: "~/src"},
+			want: []RepoRootConfig{{Path: "~/src"}},
+		},
+		{
+			name: "roots 指定時は root より優先",
+			repo: RepoConfig{Root: "~/src", Roots: []RepoRootConfig{{Path: "~/work", Name: "work"}, {Path: "~/oss"}}},
+			want: []RepoRootConfig{{Path: "~/work", Name: "work"}, {Path: "~/oss"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &Config{Repo: tc.repo}
+			if got := cfg.EffectiveRepoRoots(); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("EffectiveRepoRoots() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFindRepoRoot(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{Repo: RepoConfig{Roots: []RepoRootConfig{{Path: "/src/work", Name: "work"}}}}
+
+	got, ok := cfg.FindRepoRoot("/src/work/")
+	if !ok || got.Name != "work" {
+		t.Fatalf("FindRepoRoot() = %+v, %v, want work entry", got, ok)
+	}
+
+	if _, ok := cfg.FindRepoRoot("/src/oss"); ok {
+		t.Fatalf("FindRepoRoot() ok = true for an unknown path")
+	}
+}
+
+func TestForRepoRoot(t *testing.T) {
+	t.Parallel()
+
+	disabled := false
+	enabled := true
+
+	cfg := &Config{Repo: RepoConfig{
+		Root:   "~/src",
+		GitHub: GitHubConfig{Owner: "alice", Protocol: "https"},
+		Sync:   RepoSyncConfig{AutoStash: true, Prune: true, SubmoduleUpdate: false, Timeout: "10m"},
+		Cleanup: RepoCleanupConfig{
+			Enabled:         true,
+			Target:          []string{"merged"},
+			ExcludeBranches: []string{"main"},
+		},
+		Roots: []RepoRootConfig{{Path: "~/work"}},
+	}}
+
+	got := cfg.ForRepoRoot(RepoRootConfig{
+		Path:    "~/work",
+		GitHub:  GitHubConfig{Owner: "acme", Protocol: "ssh"},
+		Sync:    RepoRootSyncConfig{AutoStash: &disabled, SubmoduleUpdate: &enabled, Timeout: "20m"},
+		Cleanup: RepoRootCleanupConfig{Enabled: &disabled, ExcludeBranches: []string{"develop"}},
+	})
+
+	want := RepoConfig{
+		Root:   "~/work",
+		GitHub: GitHubConfig{Owner: "acme", Protocol: "ssh"},
+		Sync:   RepoSyncConfig{AutoStash: false, Prune: true, SubmoduleUpdate: true, Timeout: "20m"},
+		Cleanup: RepoCleanupConfig{
+			Enabled:         false,
+			Target:          []string{"merged"},
+			ExcludeBranches: []string{"develop"},
+		},
+	}
+
+	if !reflect.DeepEqual(got.Repo, want) {
+		t.Fatalf("ForRepoRoot().Repo = %+v, want %+v", got.Repo, want)
+	}
+
+	if cfg.Repo.GitHub.Owner != "alice" || !cfg.Repo.Sync.AutoStash || len(cfg.Repo.Roots) != 1 {
+		t.Fatalf("ForRepoRoot() modified the original config: %+v", cfg.Repo)
+	}
+}
+
+func TestRepoRootConfigLabel(t *testing.T) {
+	t.Parallel()
+
+	if got := (RepoRootConfig{Path: "~/work", Name: "work"}).Label(); got != "work" {
+		t.Fatalf("Label() = %q, want work", got)
+	}
+
+	if got := (RepoRootConfig{Path: "~/work", Name: " "}).Label(); got != "~/work" {
+		t.Fatalf("Label() = %q, want ~/work", got)
+	}
+}
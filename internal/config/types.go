@@ -33,10 +33,15 @@ type ControlConfig struct {
 
 // RepoConfig はリポジトリ管理機能に関する設定です。
 type RepoConfig struct {
-	Root    string            `mapstructure:"root" yaml:"root"`
-	GitHub  GitHubConfig      `mapstructure:"github" yaml:"github"`
-	Sync    RepoSyncConfig    `mapstructure:"sync" yaml:"sync"`
-	Cleanup RepoCleanupConfig `mapstructure:"cleanup" yaml:"cleanup"`
+	Root string `mapstructure:"root" yaml:"root"`
+	// Roots は複数のルートとルートごとの設定です。指定した場合は root を使用しません。
+	Roots     []RepoRootConfig    `mapstructure:"roots" yaml:"roots,omitempty"`
+	GitHub    GitHubConfig        `mapstructure:"github" yaml:"github"`
+	Sync      RepoSyncConfig      `mapstructure:"sync" yaml:"sync"`
+	Cleanup   RepoCleanupConfig   `mapstructure:"cleanup" yaml:"cleanup"`
+	Discovery RepoDiscoveryConfig `mapstructure:"discovery" yaml:"discovery"`
+	// Manifest は repo sync が使用するマニフェスト（repos.yaml）のパスです。
+	Manifest string `mapstructure:"manifest" yaml:"manifest,omitempty"`
 }
 
 type GitHubConfig struct {
@@ -48,6 +53,8 @@ type RepoSyncConfig struct {
 	AutoStash       bool `mapstructure:"auto_stash" yaml:"auto_stash"`
 	Prune           bool `mapstructure:"prune" yaml:"prune"`
 	SubmoduleUpdate bool `mapstructure:"submodule_update" yaml:"submodule_update"`
+	// Timeout はリポジトリ1件あたりの更新の制限時間です（例: "2m"）。空の場合は制限しません。
+	Timeout string `mapstructure:"timeout" yaml:"timeout,omitempty"`
 }
 
 type RepoCleanupConfig struct {
@@ -56,6 +63,43 @@ type RepoCleanupConfig struct {
 	ExcludeBranches []string `mapstructure:"exclude_branches" yaml:"exclude_branches"` // ["main", "master", "develop"]
 }
 
+// RepoRootConfig は repo.roots の1件です。
+// 未指定の項目は repo.github / repo.sync / repo.cleanup の値を使用します。
+type RepoRootConfig struct {
+	Path string `mapstructure:"path" yaml:"path"`
+	// Name は出力での表示名です。空の場合は Path を使用します。
+	Name    string                `mapstructure:"name" yaml:"name,omitempty"`
+	GitHub  GitHubConfig          `mapstructure:"github" yaml:"github,omitempty"`
+	Sync    RepoRootSyncConfig    `mapstructure:"sync" yaml:"sync,omitempty"`
+	Cleanup RepoRootCleanupConfig `mapstructure:"cleanup" yaml:"cleanup,omitempty"`
+}
+
+// RepoRootSyncConfig は repo.sync をルートごとに上書きする設定です。nil の項目は上書きしません。
+type RepoRootSyncConfig struct {
+	AutoStash       *bool  `mapstructure:"auto_stash" yaml:"auto_stash,omitempty"`
+	Prune           *bool  `mapstructure:"prune" yaml:"prune,omitempty"`
+	SubmoduleUpdate *bool  `mapstructure:"submodule_update" yaml:"submodule_update,omitempty"`
+	Timeout         string `mapstructure:"timeout" yaml:"timeout,omitempty"`
+}
+
+// RepoRootCleanupConfig は repo.cleanup をルートごとに上書きする設定です。nil の項目は上書きしません。
+type RepoRootCleanupConfig struct {
+	Enabled         *bool    `mapstructure:"enabled" yaml:"enabled,omitempty"`
+	Target          []string `mapstructure:"target" yaml:"target,omitempty"`
+	ExcludeBranches []string `mapstructure:"exclude_branches" yaml:"exclude_branches,omitempty"`
+}
+
+// RepoDiscoveryConfig は repo.root 配下のリポジトリ探索に関する設定です。
+type RepoDiscoveryConfig struct {
+	// MaxDepth は root からの最大探索深さです（root 直下 = 1。例: ~/src/<host>/<owner>/<repo> なら 3）。
+	MaxDepth int `mapstructure:"max_depth" yaml:"max_depth"`
+	// Include / Exclude は root からの相対パスに対する glob です（"/" を含まない場合はディレクトリ名と照合、"**" は任意の階層）。
+	Include []string `mapstructure:"include" yaml:"include,omitempty"`
+	Exclude []string `mapstructure:"exclude" yaml:"exclude,omitempty"`
+	// StopAtGit が true の場合、最初に見つかった .git で探索を打ち切ります（false ではネストしたリポジトリも検出します）。
+	StopAtGit bool `mapstructure:"stop_at_git" yaml:"stop_at_git"`
+}
+
 // SysConfig はシステム更新機能に関する設定です。
 type SysConfig struct {
 	Enable   []string                 `mapstructure:"enable" yaml:"enable"`     // 有効化するマネージャ名のリスト
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -14,6 +15,9 @@ type ValidateOptions struct {
 	// KnownSysManagers を指定すると、sys.enable の未知マネージャを警告します。
 	// nil/空の場合はチェックしません。
 	KnownSysManagers map[string]struct{}
+	// InstalledPackages はマネージャ名ごとのインストール済みパッケージ名です。
+	// 指定されたマネージャについて、何にも一致しない hold パターンを警告します。
+	InstalledPackages map[string][]string
 }
 
 // ValidationIssue は設定検証で見つかった問題（エラー/警告）です。
@@ -57,6 +61,7 @@ func Validate(cfg *Config, opts ValidateOptions) ValidationResult {
 	validateRepo(&result, cfg)
 	validateSecrets(&result, cfg)
 	validateSys(&result, cfg, opts)
+	validateSysManagers(&result, cfg, opts)
 
 	return result
 }
@@ -98,23 +103,81 @@ func validateControl(result *ValidationResult, cfg *Config) {
 }
 
 func validateRepo(result *ValidationResult, cfg *Config) {
-	root := strings.TrimSpace(cfg.Repo.Root)
+	if len(cfg.Repo.Roots) > 0 {
+		validateRepoRoots(result, cfg.Repo.Roots)
+	} else if !validateRepoRootPath(result, fieldRepoRoot, cfg.Repo.Root) {
+		return
+	}
+
+	protocol := strings.ToLower(strings.TrimSpace(cfg.Repo.GitHub.Protocol))
+	switch protocol {
+	case "https", "ssh":
+		// ok
+	case "":
+		result.Warnings = append(result.Warnings, ValidationIssue{
+			Field:   "repo.github.protocol",
+			Message: "空です（既定値: https）",
+		})
+	default:
+		result.Errors = append(result.Errors, ValidationIssue{
+			Field:   "repo.github.protocol",
+			Message: fmt.Sprintf("不正な値です: %q（https または ssh を指定してください）", cfg.Repo.GitHub.Protocol),
+		})
+	}
+
+	if _, err := ParseJobTimeout(cfg.Repo.Sync.Timeout); err != nil {
+		result.Errors = append(result.Errors, ValidationIssue{
+			Field:   "repo.sync.timeout",
+			Message: err.Error(),
+		})
+	}
+
+	validateRepoDiscovery(result, cfg.Repo.Discovery)
+	validateRepoCleanupTargets(result, "repo.cleanup.target", cfg.Repo.Cleanup.Target)
+}
+
+func validateRepoCleanupTargets(result *ValidationResult, field string, targets []string) {
+	allowedTargets := map[string]struct{}{
+		RepoCleanupTargetMerged:   {},
+		RepoCleanupTargetSquashed: {},
+	}
+
+	for _, target := range targets {
+		trimmed := strings.ToLower(strings.TrimSpace(target))
+		if trimmed == "" {
+			continue
+		}
+
+		if _, ok := allowedTargets[trimmed]; ok {
+			continue
+		}
+
+		result.Warnings = append(result.Warnings, ValidationIssue{
+			Field:   field,
+			Message: fmt.Sprintf("未知の対象が含まれています（cleanup実装時に影響する可能性があります）: %q", target),
+		})
+	}
+}
+
+// validateRepoRootPath はルートのパスを検証し、以降の検証を続けられる場合は true を返します。
+func validateRepoRootPath(result *ValidationResult, field, root string) bool {
+	root = strings.TrimSpace(root)
 	if root == "" {
 		result.Errors = append(result.Errors, ValidationIssue{
-			Field:   fieldRepoRoot,
+			Field:   field,
 			Message: "空です（例: \"~/src\" ではなくフルパスで指定してください）",
 		})
 
-		return
+		return false
 	}
 
 	if strings.HasPrefix(root, "~") {
 		result.Errors = append(result.Errors, ValidationIssue{
-			Field:   fieldRepoRoot,
+			Field:   field,
 			Message: fmt.Sprintf("チルダ（~）は自動展開されません: %q（フルパスで指定してください）", root),
 		})
 
-		return
+		return false
 	}
 
 	cleaned := filepath.Clean(root)
@@ -124,58 +187,89 @@ func validateRepo(result *ValidationResult, cfg *Config) {
 	case err == nil:
 		if !info.IsDir() {
 			result.Errors = append(result.Errors, ValidationIssue{
-				Field:   fieldRepoRoot,
+				Field:   field,
 				Message: fmt.Sprintf("ディレクトリではありません: %s", cleaned),
 			})
 		}
 	case os.IsNotExist(err):
 		result.Errors = append(result.Errors, ValidationIssue{
-			Field:   fieldRepoRoot,
+			Field:   field,
 			Message: fmt.Sprintf("ディレクトリが存在しません: %s（必要なら作成するか、`dsx config init` を再実行してください）", cleaned),
 		})
 	default:
 		result.Errors = append(result.Errors, ValidationIssue{
-			Field:   fieldRepoRoot,
+			Field:   field,
 			Message: fmt.Sprintf("ディレクトリの確認に失敗しました: %s: %v", cleaned, err),
 		})
 	}
 
-	protocol := strings.ToLower(strings.TrimSpace(cfg.Repo.GitHub.Protocol))
-	switch protocol {
-	case "https", "ssh":
-		// ok
-	case "":
-		result.Warnings = append(result.Warnings, ValidationIssue{
-			Field:   "repo.github.protocol",
-			Message: "空です（既定値: https）",
-		})
-	default:
-		result.Errors = append(result.Errors, ValidationIssue{
-			Field:   "repo.github.protocol",
-			Message: fmt.Sprintf("不正な値です: %q（https または ssh を指定してください）", cfg.Repo.GitHub.Protocol),
-		})
-	}
+	return true
+}
 
-	allowedTargets := map[string]struct{}{
-		RepoCleanupTargetMerged:   {},
-		RepoCleanupTargetSquashed: {},
-	}
+// validateRepoRoots は repo.roots の各エントリ（パスの重複やルートごとの上書き設定）を検証します。
+func validateRepoRoots(result *ValidationResult, roots []RepoRootConfig) {
+	seen := make(map[string]int, len(roots))
+
+	for i, root := range roots {
+		field := fmt.Sprintf("repo.roots[%d]", i)
+
+		if validateRepoRootPath(result, field+".path", root.Path) {
+			cleaned := filepath.Clean(strings.TrimSpace(root.Path))
+			if first, ok := seen[cleaned]; ok {
+				result.Errors = append(result.Errors, ValidationIssue{
+					Field:   field + ".path",
+					Message: fmt.Sprintf("repo.roots[%d] と同じパスです: %s", first, cleaned),
+				})
+			} else {
+				seen[cleaned] = i
+			}
+		}
 
-	for _, target := range cfg.Repo.Cleanup.Target {
-		trimmed := strings.ToLower(strings.TrimSpace(target))
-		if trimmed == "" {
-			continue
+		switch protocol := strings.ToLower(strings.TrimSpace(root.GitHub.Protocol)); protocol {
+		case "", "https", "ssh":
+			// ok（空の場合は repo.github.protocol を使用）
+		default:
+			result.Errors = append(result.Errors, ValidationIssue{
+				Field:   field + ".github.protocol",
+				Message: fmt.Sprintf("不正な値です: %q（https または ssh を指定してください）", root.GitHub.Protocol),
+			})
 		}
 
-		if _, ok := allowedTargets[trimmed]; ok {
-			continue
+		if _, err := ParseJobTimeout(root.Sync.Timeout); err != nil {
+			result.Errors = append(result.Errors, ValidationIssue{
+				Field:   field + ".sync.timeout",
+				Message: err.Error(),
+			})
 		}
 
-		result.Warnings = append(result.Warnings, ValidationIssue{
-			Field:   "repo.cleanup.target",
-			Message: fmt.Sprintf("未知の対象が含まれています（cleanup実装時に影響する可能性があります）: %q", target),
+		validateRepoCleanupTargets(result, field+".cleanup.target", root.Cleanup.Target)
+	}
+}
+
+func validateRepoDiscovery(result *ValidationResult, discovery RepoDiscoveryConfig) {
+	if discovery.MaxDepth < 0 {
+		result.Errors = append(result.Errors, ValidationIssue{
+			Field:   "repo.discovery.max_depth",
+			Message: fmt.Sprintf("0以上を指定してください: %d", discovery.MaxDepth),
 		})
 	}
+
+	for _, group := range []struct {
+		field    string
+		patterns []string
+	}{
+		{field: "repo.discovery.include", patterns: discovery.Include},
+		{field: "repo.discovery.exclude", patterns: discovery.Exclude},
+	} {
+		for _, pattern := range group.patterns {
+			if _, err := path.Match(filepath.ToSlash(pattern), ""); err != nil {
+				result.Errors = append(result.Errors, ValidationIssue{
+					Field:   group.field,
+					Message: fmt.Sprintf("不正な glob パターンです: %q", pattern),
+				})
+			}
+		}
+	}
 }
 
 func validateSecrets(result *ValidationResult, cfg *Config) {
@@ -253,6 +347,139 @@ func validateSys(result *ValidationResult, cfg *Config, opts ValidateOptions) {
 	}
 }
 
+func validateSysManagers(result *ValidationResult, cfg *Config, opts ValidateOptions) {
+	names := make([]string, 0, len(cfg.Sys.Managers))
+	for name := range cfg.Sys.Managers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		managerCfg := cfg.Sys.Managers[name]
+
+		validateManagerAfter(result, cfg, name, managerCfg)
+		validateManagerRuntime(result, name, managerCfg)
+		validateManagerType(result, name, managerCfg)
+
+		for _, key := range []string{ManagerKeyHold, ManagerKeyIgnore} {
+			if _, err := managerCfg.StringList(key); err != nil {
+				result.Errors = append(result.Errors, ValidationIssue{
+					Field:   fmt.Sprintf("sys.managers.%s.%s", name, key),
+					Message: err.Error(),
+				})
+			}
+		}
+
+		installed, ok := opts.InstalledPackages[name]
+		if !ok {
+			continue
+		}
+
+		hold, err := managerCfg.Hold()
+		if err != nil {
+			continue
+		}
+
+		for _, pattern := range hold {
+			if matchesAnyPackage(pattern, installed) {
+				continue
+			}
+
+			result.Warnings = append(result.Warnings, ValidationIssue{
+				Field:   fmt.Sprintf("sys.managers.%s.%s", name, ManagerKeyHold),
+				Message: fmt.Sprintf("インストール済みのパッケージに一致しません（typoの可能性があります）: %q", pattern),
+			})
+		}
+	}
+}
+
+// validateManagerAfter は sys.managers.<name>.after の型と参照先を検証します。
+// 実行時に無視される参照（自分自身・sys.enable に含まれないマネージャ）は警告にとどめます。
+func validateManagerAfter(result *ValidationResult, cfg *Config, name string, managerCfg ManagerConfig) {
+	field := fmt.Sprintf("sys.managers.%s.%s", name, ManagerKeyAfter)
+
+	after, err := managerCfg.After()
+	if err != nil {
+		result.Errors = append(result.Errors, ValidationIssue{Field: field, Message: err.Error()})
+		return
+	}
+
+	enabled := make(map[string]struct{}, len(cfg.Sys.Enable))
+	for _, enabledName := range cfg.Sys.Enable {
+		enabled[enabledName] = struct{}{}
+	}
+
+	for _, dep := range after {
+		if dep == name {
+			result.Warnings = append(result.Warnings, ValidationIssue{
+				Field:   field,
+				Message: "自分自身は指定できません（無視されます）",
+			})
+
+			continue
+		}
+
+		if _, ok := enabled[dep]; !ok {
+			result.Warnings = append(result.Warnings, ValidationIssue{
+				Field:   field,
+				Message: fmt.Sprintf("sys.enable に含まれていないマネージャです（無視されます）: %q", dep),
+			})
+		}
+	}
+}
+
+// validateManagerRuntime は sys.managers.<name>.timeout / retries を検証します。
+func validateManagerRuntime(result *ValidationResult, name string, managerCfg ManagerConfig) {
+	if _, err := managerCfg.Timeout(); err != nil {
+		result.Errors = append(result.Errors, ValidationIssue{
+			Field:   fmt.Sprintf("sys.managers.%s.%s", name, ManagerKeyTimeout),
+			Message: err.Error(),
+		})
+	}
+
+	if _, err := managerCfg.Retries(); err != nil {
+		result.Errors = append(result.Errors, ValidationIssue{
+			Field:   fmt.Sprintf("sys.managers.%s.%s", name, ManagerKeyRetries),
+			Message: err.Error(),
+		})
+	}
+}
+
+// validateManagerType は sys.managers.<name>.type と、type: custom の場合はカスタムマネージャの定義を検証します。
+func validateManagerType(result *ValidationResult, name string, managerCfg ManagerConfig) {
+	raw, ok := managerCfg[ManagerKeyType]
+	if !ok || raw == nil {
+		return
+	}
+
+	if !managerCfg.IsCustom() {
+		result.Errors = append(result.Errors, ValidationIssue{
+			Field:   fmt.Sprintf("sys.managers.%s.%s", name, ManagerKeyType),
+			Message: fmt.Sprintf("未対応の種類です: %v（対応: %s）", raw, ManagerTypeCustom),
+		})
+
+		return
+	}
+
+	if _, err := managerCfg.CustomManager(); err != nil {
+		result.Errors = append(result.Errors, ValidationIssue{
+			Field:   fmt.Sprintf("sys.managers.%s", name),
+			Message: err.Error(),
+		})
+	}
+}
+
+func matchesAnyPackage(pattern string, packages []string) bool {
+	for _, pkg := range packages {
+		if MatchPackagePattern(pattern, pkg) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func keysOfStringSet(set map[string]struct{}) []string {
 	if len(set) == 0 {
 		return nil
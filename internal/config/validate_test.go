@@ -226,6 +226,142 @@ func TestValidate(t *testing.T) {
 			opts:               ValidateOptions{KnownSysManagers: knownManagers},
 			wantWarningSubstrs: []string{"sys.enable", "重複"},
 		},
+		{
+			name: "hold が文字列リストでなければエラー",
+			cfg: func() *Config {
+				c := newValidConfig(existingDir)
+				c.Sys.Managers["apt"] = ManagerConfig{"hold": []interface{}{"docker-ce", 1}}
+				return c
+			}(),
+			wantErrorSubstrs: []string{"sys.managers.apt.hold", "文字列"},
+		},
+		{
+			name: "インストール済みに一致しない hold は警告",
+			cfg: func() *Config {
+				c := newValidConfig(existingDir)
+				c.Sys.Managers["apt"] = ManagerConfig{"hold": []interface{}{"linux-*", "dokcer-ce"}}
+				return c
+			}(),
+			opts: ValidateOptions{
+				InstalledPackages: map[string][]string{"apt": {"linux-image-generic", "docker-ce"}},
+			},
+			wantWarningSubstrs: []string{"sys.managers.apt.hold", "dokcer-ce"},
+		},
+		{
+			name: "インストール済み一覧がないマネージャの hold は警告しない",
+			cfg: func() *Config {
+				c := newValidConfig(existingDir)
+				c.Sys.Managers["brew"] = ManagerConfig{"hold": []interface{}{"anything"}}
+				return c
+			}(),
+			opts: ValidateOptions{
+				KnownSysManagers:  knownManagers,
+				InstalledPackages: map[string][]string{"apt": {"curl"}},
+			},
+		},
+		{
+			name: "after が文字列リストでなければエラー",
+			cfg: func() *Config {
+				c := newValidConfig(existingDir)
+				c.Sys.Managers["npm"] = ManagerConfig{"after": map[string]interface{}{"nvm": true}}
+				return c
+			}(),
+			wantErrorSubstrs: []string{"sys.managers.npm.after"},
+		},
+		{
+			name: "sys.enable にない after は警告",
+			cfg: func() *Config {
+				c := newValidConfig(existingDir)
+				c.Sys.Enable = []string{"apt", "npm"}
+				c.Sys.Managers["npm"] = ManagerConfig{"after": []interface{}{"apt", "nvm", "npm"}}
+				return c
+			}(),
+			wantWarningSubstrs: []string{"sys.managers.npm.after", `"nvm"`, "自分自身"},
+		},
+		{
+			name: "timeout / retries は妥当なら問題なし",
+			cfg: func() *Config {
+				c := newValidConfig(existingDir)
+				c.Sys.Managers["snap"] = ManagerConfig{"timeout": "5m", "retries": 2}
+				c.Repo.Sync.Timeout = "2m"
+				return c
+			}(),
+		},
+		{
+			name: "不正な timeout / retries はエラー",
+			cfg: func() *Config {
+				c := newValidConfig(existingDir)
+				c.Sys.Managers["snap"] = ManagerConfig{"timeout": "soon", "retries": -1}
+				c.Repo.Sync.Timeout = "0s"
+				return c
+			}(),
+			wantErrorSubstrs: []string{"sys.managers.snap.timeout", "sys.managers.snap.retries", "repo.sync.timeout"},
+		},
+		{
+			name: "repo.discovery は妥当なら問題なし",
+			cfg: func() *Config {
+				c := newValidConfig(existingDir)
+				c.Repo.Discovery = RepoDiscoveryConfig{MaxDepth: 3, Include: []string{"github.com/*/*"}, Exclude: []string{"node_modules", "**/vendor"}, StopAtGit: true}
+				return c
+			}(),
+		},
+		{
+			name: "不正な repo.discovery はエラー",
+			cfg: func() *Config {
+				c := newValidConfig(existingDir)
+				c.Repo.Discovery = RepoDiscoveryConfig{MaxDepth: -1, Include: []string{"github.com/["}, Exclude: []string{"[a-"}}
+				return c
+			}(),
+			wantErrorSubstrs: []string{"repo.discovery.max_depth", "repo.discovery.include", "repo.discovery.exclude"},
+		},
+		{
+			name: "repo.roots は妥当なら repo.root が空でも問題なし",
+			cfg: func() *Config {
+				c := newValidConfig("")
+				c.Repo.Roots = []RepoRootConfig{
+					{Path: existingDir, Name: "work", GitHub: GitHubConfig{Owner: "acme", Protocol: "ssh"}, Sync: RepoRootSyncConfig{Timeout: "5m"}},
+					{Path: t.TempDir(), Cleanup: RepoRootCleanupConfig{Target: []string{"squashed"}}},
+				}
+				return c
+			}(),
+		},
+		{
+			name: "不正な repo.roots はエラー",
+			cfg: func() *Config {
+				c := newValidConfig(existingDir)
+				c.Repo.Roots = []RepoRootConfig{
+					{Path: existingDir, GitHub: GitHubConfig{Protocol: "ftp"}, Sync: RepoRootSyncConfig{Timeout: "soon"}},
+					{Path: existingDir + string(filepath.Separator), Cleanup: RepoRootCleanupConfig{Target: []string{"unknown"}}},
+					{Path: ""},
+				}
+				return c
+			}(),
+			wantErrorSubstrs: []string{
+				"repo.roots[0].github.protocol",
+				"repo.roots[0].sync.timeout",
+				"repo.roots[1].path",
+				"repo.roots[2].path",
+			},
+			wantWarningSubstrs: []string{"repo.roots[1].cleanup.target"},
+		},
+		{
+			name: "カスタムマネージャの定義は妥当なら問題なし",
+			cfg: func() *Config {
+				c := newValidConfig(existingDir)
+				c.Sys.Managers["foo"] = ManagerConfig{"type": "custom", "check": "foo outdated", "update": "foo self-update", "parse_regex": `^(?P<name>\S+) (?P<new>\S+)$`}
+				return c
+			}(),
+		},
+		{
+			name: "不正なカスタムマネージャの定義と未対応の type はエラー",
+			cfg: func() *Config {
+				c := newValidConfig(existingDir)
+				c.Sys.Managers["foo"] = ManagerConfig{"type": "custom", "check": "foo outdated"}
+				c.Sys.Managers["bar"] = ManagerConfig{"type": "plugin"}
+				return c
+			}(),
+			wantErrorSubstrs: []string{"sys.managers.foo: update は必須です", "sys.managers.bar.type"},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -60,22 +60,46 @@ func GetRecommendedManagers() []string {
 		}
 	case IsContainer():
 		// Container environment usually relies on apt/apk but user might not want to update system packages directly.
-		// Use apt if it's Debian/Ubuntu based.
+		// Use apt if it's Debian/Ubuntu based, dnf if it's Fedora/RHEL based, pacman if it's Arch based.
 		if isDebianLike() {
 			managers = append(managers, "apt")
 		}
+
+		if isRHELLike() {
+			managers = append(managers, "dnf")
+		}
+
+		if isArchLike() {
+			managers = append(managers, "pacman")
+		}
 	case IsWSL():
 		// WSL environment
 		if isDebianLike() {
 			managers = append(managers, "apt")
 		}
 
+		if isRHELLike() {
+			managers = append(managers, "dnf")
+		}
+
+		if isArchLike() {
+			managers = append(managers, "pacman")
+		}
+
 		managers = append(managers, "brew") // Linuxbrew is common in WSL
 	default:
 		// Host Linux/macOS
 		if isDebianLike() {
 			managers = append(managers, "apt", "snap")
 		}
+
+		if isRHELLike() {
+			managers = append(managers, "dnf")
+		}
+
+		if isArchLike() {
+			managers = append(managers, "pacman")
+		}
 		// Mac/Linux common
 		managers = append(managers, "brew")
 	}
@@ -83,6 +107,23 @@ func GetRecommendedManagers() []string {
 	return managers
 }
 
+// isRHELLike returns true if dnf or yum is available (Fedora/RHEL/CentOS/Rocky/Alma).
+func isRHELLike() bool {
+	for _, path := range []string{"/usr/bin/dnf", "/usr/bin/yum"} {
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isArchLike returns true if pacman is available (Arch Linux/Manjaro/EndeavourOS).
+func isArchLike() bool {
+	_, err := os.Stat("/usr/bin/pacman")
+	return err == nil
+}
+
 // isDebianLike returns true if apt-get is available.
 func isDebianLike() bool {
 	_, err := os.Stat("/usr/bin/apt-get")
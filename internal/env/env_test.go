@@ -83,6 +83,39 @@ func TestGetRecommendedManagers(t *testing.T) {
 	})
 }
 
+func TestIsRHELLike(t *testing.T) {
+	t.Run("/usr/bin/dnfまたは/usr/bin/yumが存在する場合はtrue", func(t *testing.T) {
+		_, dnfErr := os.Stat("/usr/bin/dnf")
+		_, yumErr := os.Stat("/usr/bin/yum")
+
+		assert.Equal(t, dnfErr == nil || yumErr == nil, isRHELLike())
+	})
+
+	t.Run("Fedora/RHEL系ではdnfが推奨される", func(t *testing.T) {
+		if !isRHELLike() {
+			t.Skip("Fedora/RHEL系環境でのみ実行")
+		}
+
+		assert.Contains(t, GetRecommendedManagers(), "dnf")
+	})
+}
+
+func TestIsArchLike(t *testing.T) {
+	t.Run("/usr/bin/pacmanが存在する場合はtrue", func(t *testing.T) {
+		_, err := os.Stat("/usr/bin/pacman")
+
+		assert.Equal(t, err == nil, isArchLike())
+	})
+
+	t.Run("Arch系ではpacmanが推奨される", func(t *testing.T) {
+		if !isArchLike() {
+			t.Skip("Arch系環境でのみ実行")
+		}
+
+		assert.Contains(t, GetRecommendedManagers(), "pacman")
+	})
+}
+
 func TestIsDebianLike(t *testing.T) {
 	t.Run("/usr/bin/apt-getが存在する場合はtrue", func(t *testing.T) {
 		result := isDebianLike()
@@ -0,0 +1,224 @@
+// Package ghretry は GitHub にアクセスする CLI（gh / kubectl krew など）を、
+// rate limit や一時的な障害に対するリトライとスロットリング付きで実行します。
+package ghretry
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// GitHub CLI(gh) の呼び出しは、repo cleanup の squashed 判定などで大量に発生し得る。
+// GitHub の secondary rate limit などにより 429/403 が返ると一時的に失敗するため、
+// ここで最小限のリトライとスロットリングを行う。
+
+const (
+	retryMaxAttempts            = 6
+	retryBaseDelay              = 2 * time.Second
+	retryMaxDelay               = 60 * time.Second
+	retryMaxDelayFromRetryAfter = 5 * time.Minute
+
+	// GitHub への同時アクセスを抑えて secondary rate limit を避ける（repo cleanup の並列数や sys update の並列数とは独立）。
+	concurrencyLimit = 1
+)
+
+// CommandFunc は実行するコマンドを生成する関数です。テストで差し替えられるよう呼び出し側から渡します。
+type CommandFunc func(ctx context.Context, name string, arg ...string) *exec.Cmd
+
+var (
+	limiter = make(chan struct{}, concurrencyLimit)
+
+	sleepStep = sleepWithContext
+
+	reRetryAfterSeconds = regexp.MustCompile(`(?i)retry[- ]after[: ]+(\d+)`)
+)
+
+// Output は gh を実行し、標準出力と（前後の空白を除いた）標準エラーを返します。
+// newCmd が nil の場合は exec.CommandContext を使います。
+func Output(ctx context.Context, newCmd CommandFunc, dir string, args ...string) (output []byte, stderr string, err error) {
+	return CommandOutput(ctx, newCmd, dir, "gh", args...)
+}
+
+// CommandOutput は GitHub にアクセスする任意のコマンドを実行します。
+// 標準エラーが rate limit や一時的な障害を示す場合は、Retry-After または指数バックオフで待機して再試行します。
+func CommandOutput(ctx context.Context, newCmd CommandFunc, dir, name string, args ...string) (output []byte, stderr string, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if newCmd == nil {
+		newCmd = exec.CommandContext
+	}
+
+	var lastErr error
+
+	for attempt := 1; attempt <= retryMaxAttempts; attempt++ {
+		output, stderr, err = runOnce(ctx, newCmd, dir, name, args...)
+		lastErr = err
+
+		if err == nil {
+			return output, stderr, nil
+		}
+
+		// ctx が死んでいる場合は即終了（リトライしない）
+		if ctx.Err() != nil {
+			return nil, stderr, ctx.Err()
+		}
+
+		if !isRetryable(stderr) {
+			return nil, stderr, err
+		}
+
+		if attempt == retryMaxAttempts {
+			break
+		}
+
+		delay := calcRetryDelay(attempt, stderr)
+		if sleepErr := sleepStep(ctx, delay); sleepErr != nil {
+			return nil, stderr, sleepErr
+		}
+	}
+
+	return nil, stderr, fmt.Errorf("%s のリトライ回数が上限に達しました: %w", name, lastErr)
+}
+
+// IsRateLimitError はエラーが GitHub の rate limit によるものかを返します。
+func IsRateLimitError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	msg := strings.ToLower(err.Error())
+	if msg == "" {
+		return false
+	}
+
+	return strings.Contains(msg, "too many requests") ||
+		strings.Contains(msg, "429") ||
+		strings.Contains(msg, "rate limit") ||
+		strings.Contains(msg, "secondary rate limit")
+}
+
+func acquireLimiter(ctx context.Context) (func(), error) {
+	select {
+	case limiter <- struct{}{}:
+		return func() { <-limiter }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func runOnce(ctx context.Context, newCmd CommandFunc, dir, name string, args ...string) (output []byte, stderr string, err error) {
+	release, err := acquireLimiter(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	defer release()
+
+	cmd := newCmd(ctx, name, args...)
+	if strings.TrimSpace(dir) != "" {
+		cmd.Dir = dir
+	}
+
+	var stderrBuf bytes.Buffer
+
+	cmd.Stderr = &stderrBuf
+
+	output, err = cmd.Output()
+
+	return output, strings.TrimSpace(stderrBuf.String()), err
+}
+
+func isRetryable(stderr string) bool {
+	msg := strings.ToLower(strings.TrimSpace(stderr))
+	if msg == "" {
+		return false
+	}
+
+	// GitHub の rate limit / secondary rate limit
+	if strings.Contains(msg, "too many requests") || strings.Contains(msg, "429") {
+		return true
+	}
+
+	if strings.Contains(msg, "rate limit") || strings.Contains(msg, "secondary rate limit") {
+		return true
+	}
+
+	// 一時的な障害（gh 内部で HTTP ステータスが見えることがある）
+	if strings.Contains(msg, "502") || strings.Contains(msg, "503") || strings.Contains(msg, "504") {
+		return true
+	}
+
+	if strings.Contains(msg, "bad gateway") || strings.Contains(msg, "service unavailable") || strings.Contains(msg, "gateway timeout") {
+		return true
+	}
+
+	return false
+}
+
+func calcRetryDelay(attempt int, stderr string) time.Duration {
+	if d, ok := parseRetryAfter(stderr); ok {
+		// Retry-After は「最低待機時間」なので少し余裕を持たせる。
+		// ただし無制限に待たないよう、最大値は別で制限する。
+		return clampDuration(d+time.Second, retryBaseDelay, retryMaxDelayFromRetryAfter)
+	}
+
+	delay := retryBaseDelay * time.Duration(1<<(attempt-1))
+
+	return clampDuration(delay, retryBaseDelay, retryMaxDelay)
+}
+
+func parseRetryAfter(stderr string) (time.Duration, bool) {
+	m := reRetryAfterSeconds.FindStringSubmatch(stderr)
+	if len(m) != 2 {
+		return 0, false
+	}
+
+	secs := strings.TrimSpace(m[1])
+	if secs == "" {
+		return 0, false
+	}
+
+	parsed, err := time.ParseDuration(secs + "s")
+	if err != nil {
+		return 0, false
+	}
+
+	if parsed <= 0 {
+		return 0, false
+	}
+
+	return parsed, true
+}
+
+func clampDuration(d, minDur, maxDur time.Duration) time.Duration {
+	if d < minDur {
+		return minDur
+	}
+
+	if d > maxDur {
+		return maxDur
+	}
+
+	return d
+}
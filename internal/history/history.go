@@ -0,0 +1,215 @@
+// Package history は sys update の実行履歴を追記専用の JSONL ファイルとして保存・検索します。
+// 1行が1回の実行（Run）に対応し、マネージャごとのパッケージ遷移（旧 → 新）・失敗・所要時間を記録します。
+package history
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// historyFileName は状態ディレクトリ配下の履歴ファイル名です。
+	historyFileName = "history.jsonl"
+	// runIDLayout は Run.ID の生成に使用する時刻フォーマットです。
+	runIDLayout = "20060102T150405Z"
+	// runIDSuffixBytes は Run.ID に付けるランダムな接尾辞のバイト数です（16進数で2倍の文字数）。
+	runIDSuffixBytes = 3
+	// maxLineSize は1行（1回の実行）の最大サイズです。
+	maxLineSize = 16 * 1024 * 1024
+)
+
+const (
+	// PhaseSelfUpdate はマネージャ本体更新フェーズの記録です。
+	PhaseSelfUpdate = "self_update"
+	// PhaseUpdate は通常更新フェーズの記録です。
+	PhaseUpdate = "update"
+)
+
+// Run は1回の sys update の記録です。
+type Run struct {
+	ID         string          `json:"id"`
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt time.Time       `json:"finished_at"`
+	Version    string          `json:"version"`
+	Success    bool            `json:"success"`
+	Updated    int             `json:"updated"`
+	Failed     int             `json:"failed"`
+	Managers   []ManagerRecord `json:"managers"`
+}
+
+// ManagerRecord は1マネージャ・1フェーズ分の記録です。
+// UpdatedCount は Packages を報告しないマネージャ（custom や一部のプラグイン）でも更新の有無を判断できるよう記録します。
+type ManagerRecord struct {
+	Name         string          `json:"name"`
+	Phase        string          `json:"phase"`
+	Status       string          `json:"status"`
+	DurationMs   int64           `json:"duration_ms"`
+	UpdatedCount int             `json:"updated_count,omitempty"`
+	Packages     []PackageChange `json:"packages"`
+	Held         []PackageChange `json:"held,omitempty"`
+	Errors       []string        `json:"errors,omitempty"`
+}
+
+// PackageChange はパッケージのバージョン遷移です。
+type PackageChange struct {
+	Name string `json:"name"`
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// Duration は実行全体の所要時間を返します。
+func (r *Run) Duration() time.Duration {
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// NewRunID は開始時刻とランダムな接尾辞から実行 ID を生成します（例: "20261016T101500Z-3f9a1c"）。
+// 同じ秒に複数回実行しても ID（sys rollback の指定や --log-dir のディレクトリ名）が重複しないよう、接尾辞を付けます。
+func NewRunID(startedAt time.Time) string {
+	suffix := make([]byte, runIDSuffixBytes)
+	if _, err := rand.Read(suffix); err != nil {
+		// crypto/rand は失敗しない想定だが、念のためナノ秒で代替する
+		return fmt.Sprintf("%s-%09d", startedAt.UTC().Format(runIDLayout), startedAt.Nanosecond())
+	}
+
+	return startedAt.UTC().Format(runIDLayout) + "-" + hex.EncodeToString(suffix)
+}
+
+// StateDir は dsx の状態ディレクトリを返します。
+// $XDG_STATE_HOME が設定されていればその配下、未設定なら ~/.local/state/dsx を使用します。
+func StateDir() (string, error) {
+	if stateHome := os.Getenv("XDG_STATE_HOME"); filepath.IsAbs(stateHome) {
+		return filepath.Join(stateHome, "dsx"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("ホームディレクトリの取得に失敗: %w", err)
+	}
+
+	return filepath.Join(home, ".local", "state", "dsx"), nil
+}
+
+// DefaultPath は既定の履歴ファイルのパスを返します。
+func DefaultPath() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, historyFileName), nil
+}
+
+// Store は履歴ファイルへの追記と読み込みを行います。
+type Store struct {
+	path string
+}
+
+// NewStore は指定パスの Store を返します。パスが空の場合は DefaultPath を使用します。
+func NewStore(path string) (*Store, error) {
+	if path == "" {
+		defaultPath, err := DefaultPath()
+		if err != nil {
+			return nil, err
+		}
+
+		path = defaultPath
+	}
+
+	return &Store{path: path}, nil
+}
+
+// Path は履歴ファイルのパスを返します。
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Append は1回分の実行を履歴ファイルの末尾に1行で追記します。
+func (s *Store) Append(run *Run) error {
+	data, err := json.Marshal(run)
+	if err != nil {
+		return fmt.Errorf("履歴のシリアライズに失敗: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("履歴ディレクトリの作成に失敗: %w", err)
+	}
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("履歴ファイルを開けません: %w", err)
+	}
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("履歴ファイルへの書き込みに失敗: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("履歴ファイルのクローズに失敗: %w", err)
+	}
+
+	return nil
+}
+
+// Load は履歴ファイルのすべての実行を記録順に返します。
+// ファイルが存在しない場合は空の結果を返します。
+// 書き込み途中で中断された行など、解析できない行は読み飛ばします。
+func (s *Store) Load() ([]Run, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("履歴ファイルを開けません: %w", err)
+	}
+	defer f.Close()
+
+	var runs []Run
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var run Run
+		if err := json.Unmarshal(line, &run); err != nil {
+			continue
+		}
+
+		runs = append(runs, run)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("履歴ファイルの読み込みに失敗: %w", err)
+	}
+
+	return runs, nil
+}
+
+// Find は指定 ID の実行を返します。
+func (s *Store) Find(id string) (*Run, error) {
+	runs, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range runs {
+		if runs[i].ID == id {
+			return &runs[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("実行 ID %q の履歴が見つかりません", id)
+}
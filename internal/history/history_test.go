@@ -0,0 +1,256 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleRun(startedAt time.Time) *Run {
+	return &Run{
+		ID:         NewRunID(startedAt),
+		StartedAt:  startedAt,
+		FinishedAt: startedAt.Add(90 * time.Second),
+		Version:    "v1.2.3",
+		Success:    false,
+		Updated:    2,
+		Failed:     1,
+		Managers: []ManagerRecord{
+			{
+				Name:       "nvm",
+				Phase:      PhaseUpdate,
+				Status:     "success",
+				DurationMs: 1200,
+				Packages:   []PackageChange{{Name: "node", From: "20.11.0", To: "22.3.0"}},
+			},
+			{
+				Name:     "apt",
+				Phase:    PhaseUpdate,
+				Status:   "success",
+				Packages: []PackageChange{{Name: "linux-image-generic", From: "6.8.0-1", To: "6.8.0-2"}},
+			},
+			{
+				Name:   "npm",
+				Phase:  PhaseUpdate,
+				Status: "failed",
+				Errors: []string{"exit status 1"},
+			},
+		},
+	}
+}
+
+func TestStateDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	tests := []struct {
+		name      string
+		stateHome string
+		want      string
+	}{
+		{name: "XDG_STATE_HOME 指定あり", stateHome: filepath.Join(home, "state"), want: filepath.Join(home, "state", "dsx")},
+		{name: "XDG_STATE_HOME 未設定", stateHome: "", want: filepath.Join(home, ".local", "state", "dsx")},
+		{name: "相対パスは無視", stateHome: "relative/state", want: filepath.Join(home, ".local", "state", "dsx")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_STATE_HOME", tt.stateHome)
+
+			got, err := StateDir()
+			if err != nil {
+				t.Fatalf("StateDir error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Fatalf("StateDir() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRunID(t *testing.T) {
+	t.Parallel()
+
+	startedAt := time.Date(2026, 10, 16, 10, 15, 0, 0, time.FixedZone("JST", 9*60*60))
+
+	first := NewRunID(startedAt)
+	second := NewRunID(startedAt)
+
+	// 同じ秒に開始した実行でも ID は重複しない
+	if first == second {
+		t.Fatalf("NewRunID() returned the same ID twice: %q", first)
+	}
+
+	if !strings.HasPrefix(first, "20261016T011500Z-") || len(first) != len("20261016T011500Z-")+2*runIDSuffixBytes {
+		t.Fatalf("NewRunID() = %q, want a UTC timestamp with a hex suffix", first)
+	}
+}
+
+func TestStore_AppendAndLoad(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "nested", "history.jsonl")
+
+	store, err := NewStore(path)
+	if err != nil {
+		t.Fatalf("NewStore error: %v", err)
+	}
+
+	// 同じ秒に開始した2回の実行も区別できること
+	first := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	second := first.Add(500 * time.Millisecond)
+	appended := []*Run{sampleRun(first), sampleRun(second)}
+
+	for _, run := range appended {
+		if err := store.Append(run); err != nil {
+			t.Fatalf("Append error: %v", err)
+		}
+	}
+
+	runs, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+
+	if len(runs) != 2 {
+		t.Fatalf("runs length = %d, want 2", len(runs))
+	}
+
+	if runs[0].ID != appended[0].ID || runs[1].ID != appended[1].ID {
+		t.Fatalf("run IDs = %q, %q, want append order", runs[0].ID, runs[1].ID)
+	}
+
+	if runs[0].Duration() != 90*time.Second || runs[0].Managers[0].Packages[0].To != "22.3.0" {
+		t.Fatalf("runs[0] = %+v, want round-tripped record", runs[0])
+	}
+
+	found, err := store.Find(appended[1].ID)
+	if err != nil || !found.StartedAt.Equal(second) {
+		t.Fatalf("Find = %+v, %v, want second run", found, err)
+	}
+
+	if _, err := store.Find("missing"); err == nil {
+		t.Fatal("Find(missing) error = nil, want error")
+	}
+}
+
+func TestStore_LoadMissingFile(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	if err != nil {
+		t.Fatalf("NewStore error: %v", err)
+	}
+
+	runs, err := store.Load()
+	if err != nil || len(runs) != 0 {
+		t.Fatalf("Load = %v, %v, want empty result", runs, err)
+	}
+}
+
+func TestStore_LoadSkipsMalformedLines(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	content := "{\"id\":\"a\"}\n\nnot json\n{\"id\":\"b\",\"managers\":[]}\n{\"id\":\"c\""
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+
+	store, err := NewStore(path)
+	if err != nil {
+		t.Fatalf("NewStore error: %v", err)
+	}
+
+	runs, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+
+	if len(runs) != 2 || runs[0].ID != "a" || runs[1].ID != "b" {
+		t.Fatalf("runs = %+v, want a and b only", runs)
+	}
+}
+
+func TestEntries(t *testing.T) {
+	t.Parallel()
+
+	first := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	second := first.Add(24 * time.Hour)
+	runs := []Run{*sampleRun(first), *sampleRun(second)}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{
+			name:   "条件なし",
+			filter: Filter{},
+			want:   []string{"nvm/node", "apt/linux-image-generic", "npm/", "nvm/node", "apt/linux-image-generic", "npm/"},
+		},
+		{
+			name:   "マネージャとパッケージ",
+			filter: Filter{Manager: "nvm", Package: "node"},
+			want:   []string{"nvm/node", "nvm/node"},
+		},
+		{
+			name:   "パッケージのグロブ",
+			filter: Filter{Package: "linux-*"},
+			want:   []string{"apt/linux-image-generic", "apt/linux-image-generic"},
+		},
+		{
+			name:   "期間指定",
+			filter: Filter{Manager: "nvm", Since: second, Until: second.Add(time.Hour)},
+			want:   []string{"nvm/node"},
+		},
+		{
+			name:   "Until と同じ開始時刻は含む",
+			filter: Filter{Until: first},
+			want:   []string{"nvm/node", "apt/linux-image-generic", "npm/"},
+		},
+		{
+			name:   "Until の直前までに開始した実行のみ",
+			filter: Filter{Until: first.Add(-time.Nanosecond)},
+			want:   nil,
+		},
+		{
+			name:   "Since と Until が同じ時刻",
+			filter: Filter{Manager: "nvm", Since: second, Until: second},
+			want:   []string{"nvm/node"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			entries := Entries(runs, tt.filter)
+
+			got := make([]string, 0, len(entries))
+			for _, e := range entries {
+				got = append(got, e.Manager+"/"+e.Package)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("entries = %v, want %v", got, tt.want)
+			}
+
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("entries = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	failed := Entries(runs, Filter{Manager: "npm"})
+	if len(failed) != 2 || failed[0].Error != "exit status 1" || failed[0].Status != "failed" {
+		t.Fatalf("npm entries = %+v, want failure rows", failed)
+	}
+}
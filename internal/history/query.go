@@ -0,0 +1,115 @@
+package history
+
+import (
+	"time"
+
+	"github.com/scottlz0310/dsx/internal/config"
+)
+
+// Filter は履歴検索の絞り込み条件です。ゼロ値のフィールドは条件として扱いません。
+type Filter struct {
+	// Manager はマネージャ名の完全一致条件です。
+	Manager string
+	// Package はパッケージ名のパターンです（hold / ignore と同じグロブ形式）。
+	Package string
+	// Since はこの時刻以降に開始した実行のみを対象にします。
+	Since time.Time
+	// Until はこの時刻以前に開始した実行のみを対象にします。
+	Until time.Time
+}
+
+// Entry はパッケージ単位に展開した履歴の1行です。
+type Entry struct {
+	RunID   string    `json:"run_id"`
+	Time    time.Time `json:"time"`
+	Manager string    `json:"manager"`
+	Phase   string    `json:"phase"`
+	Package string    `json:"package"`
+	From    string    `json:"from"`
+	To      string    `json:"to"`
+	Status  string    `json:"status"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// MatchRun は実行の開始時刻が期間条件に含まれるかを返します。
+func (f Filter) MatchRun(run *Run) bool {
+	if !f.Since.IsZero() && run.StartedAt.Before(f.Since) {
+		return false
+	}
+
+	if !f.Until.IsZero() && run.StartedAt.After(f.Until) {
+		return false
+	}
+
+	return true
+}
+
+// MatchManager はマネージャ名が条件に一致するかを返します。
+func (f Filter) MatchManager(name string) bool {
+	return f.Manager == "" || f.Manager == name
+}
+
+// MatchPackage はパッケージ名が条件に一致するかを返します。
+func (f Filter) MatchPackage(name string) bool {
+	return f.Package == "" || config.MatchPackagePattern(f.Package, name)
+}
+
+// Entries は実行の一覧を条件で絞り込み、パッケージ単位の行に展開して返します。
+// 行は実行の記録順、マネージャの実行順に並びます。
+// パッケージ条件がない場合、パッケージを更新せずに失敗したマネージャも1行として含めます。
+func Entries(runs []Run, filter Filter) []Entry {
+	var entries []Entry
+
+	for i := range runs {
+		run := &runs[i]
+		if !filter.MatchRun(run) {
+			continue
+		}
+
+		for _, record := range run.Managers {
+			if !filter.MatchManager(record.Name) {
+				continue
+			}
+
+			entries = append(entries, recordEntries(run, &record, filter)...)
+		}
+	}
+
+	return entries
+}
+
+func recordEntries(run *Run, record *ManagerRecord, filter Filter) []Entry {
+	errMessage := ""
+	if len(record.Errors) > 0 {
+		errMessage = record.Errors[0]
+	}
+
+	base := Entry{
+		RunID:   run.ID,
+		Time:    run.StartedAt,
+		Manager: record.Name,
+		Phase:   record.Phase,
+		Status:  record.Status,
+		Error:   errMessage,
+	}
+
+	var entries []Entry
+
+	for _, pkg := range record.Packages {
+		if !filter.MatchPackage(pkg.Name) {
+			continue
+		}
+
+		entry := base
+		entry.Package = pkg.Name
+		entry.From = pkg.From
+		entry.To = pkg.To
+		entries = append(entries, entry)
+	}
+
+	if len(record.Packages) == 0 && errMessage != "" && filter.Package == "" {
+		entries = append(entries, base)
+	}
+
+	return entries
+}
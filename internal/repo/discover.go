@@ -0,0 +1,274 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultDiscoverWorkers はディレクトリを並列に読み取るワーカー数の既定値です。
+const defaultDiscoverWorkers = 16
+
+// DiscoverOptions はリポジトリ探索の範囲を指定します。
+type DiscoverOptions struct {
+	// MaxDepth は root からの最大探索深さです（root 直下 = 1）。0 以下の場合は 1 として扱います。
+	MaxDepth int
+	// Include が空でない場合、root からの相対パスがいずれかに一致するリポジトリのみ検出します。
+	Include []string
+	// Exclude に一致するディレクトリは検出せず、配下も探索しません。
+	Exclude []string
+	// DescendIntoRepos が true の場合、リポジトリ配下も探索してネストしたリポジトリを検出します。
+	// false の場合は最初に見つかった .git で探索を打ち切ります（root 自体の配下は常に探索します）。
+	DescendIntoRepos bool
+	// Workers はディレクトリを並列に読み取るワーカー数です。0 以下の場合は既定値を使用します。
+	Workers int
+}
+
+// MatchDiscoverPattern は root からの相対パス（"/" 区切り）が探索パターンに一致するかを返します。
+// "/" を含まないパターンはディレクトリ名と照合し、"**" は 0 階層以上に一致します。
+// 不正なパターンは一致しないものとして扱います。
+func MatchDiscoverPattern(pattern, rel string) bool {
+	pattern = strings.Trim(filepath.ToSlash(strings.TrimSpace(pattern)), "/")
+	if pattern == "" {
+		return false
+	}
+
+	if !strings.Contains(pattern, "/") {
+		matched, err := path.Match(pattern, path.Base(rel))
+		return err == nil && matched
+	}
+
+	return matchPatternSegments(strings.Split(pattern, "/"), strings.Split(rel, "/"))
+}
+
+func matchPatternSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchPatternSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+
+		matched, err := path.Match(pattern[0], name[0])
+		if err != nil || !matched {
+			return false
+		}
+
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
+
+// DiscoverWithOptions は root 配下（root 自体を含む）で Git リポジトリを再帰的に検出します。
+// ディレクトリの読み取りは opts.Workers 個のワーカーで並列に行い、
+// root 以外で読み取れないディレクトリ（権限不足など）は無視します。
+func DiscoverWithOptions(ctx context.Context, root string, opts DiscoverOptions) ([]string, error) {
+	resolvedRoot, err := resolveRoot(root)
+	if err != nil {
+		return nil, err
+	}
+
+	if validateErr := validateRoot(resolvedRoot); validateErr != nil {
+		return nil, validateErr
+	}
+
+	entries, err := os.ReadDir(resolvedRoot)
+	if err != nil {
+		return nil, fmt.Errorf("ルートディレクトリの読み取りに失敗: %w", err)
+	}
+
+	walker := newDiscoveryWalker(resolvedRoot, opts)
+
+	if hasGitMetadata(resolvedRoot) && walker.included(filepath.Base(resolvedRoot)) {
+		walker.found = append(walker.found, resolvedRoot)
+	}
+
+	walker.enqueueChildren(resolvedRoot, entries, 1)
+	walker.run(ctx)
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, fmt.Errorf("リポジトリの探索が中断されました: %w", ctxErr)
+	}
+
+	sort.Strings(walker.found)
+
+	return walker.found, nil
+}
+
+type discoveryTask struct {
+	path  string
+	depth int
+}
+
+// discoveryWalker はディレクトリのキューを固定数のワーカーで処理します。
+// ワーカーは処理中のディレクトリの子をキューに追加するため、
+// キューが空で処理中のタスクもなくなった時点で探索が完了します。
+type discoveryWalker struct {
+	root    string
+	opts    DiscoverOptions
+	mu      sync.Mutex
+	cond    *sync.Cond
+	queue   []discoveryTask
+	pending int
+	found   []string
+}
+
+func newDiscoveryWalker(root string, opts DiscoverOptions) *discoveryWalker {
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = 1
+	}
+
+	if opts.Workers <= 0 {
+		opts.Workers = defaultDiscoverWorkers
+	}
+
+	w := &discoveryWalker{root: root, opts: opts}
+	w.cond = sync.NewCond(&w.mu)
+
+	return w
+}
+
+func (w *discoveryWalker) run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for range w.opts.Workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				task, ok := w.next()
+				if !ok {
+					return
+				}
+
+				if ctx.Err() == nil {
+					w.visit(task)
+				}
+
+				w.done()
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+// enqueueChildren は dir 直下のディレクトリを depth の探索対象として追加します。
+// シンボリックリンクは循環を避けるため辿りません。
+func (w *discoveryWalker) enqueueChildren(dir string, entries []os.DirEntry, depth int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == ".git" {
+			continue
+		}
+
+		w.queue = append(w.queue, discoveryTask{path: filepath.Join(dir, entry.Name()), depth: depth})
+		w.pending++
+	}
+
+	w.cond.Broadcast()
+}
+
+func (w *discoveryWalker) next() (discoveryTask, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for len(w.queue) == 0 && w.pending > 0 {
+		w.cond.Wait()
+	}
+
+	if len(w.queue) == 0 {
+		return discoveryTask{}, false
+	}
+
+	task := w.queue[len(w.queue)-1]
+	w.queue = w.queue[:len(w.queue)-1]
+
+	return task, true
+}
+
+func (w *discoveryWalker) done() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.pending--
+	if w.pending == 0 {
+		w.cond.Broadcast()
+	}
+}
+
+func (w *discoveryWalker) visit(task discoveryTask) {
+	rel := w.relPath(task.path)
+	if w.excluded(rel) {
+		return
+	}
+
+	isRepo := hasGitMetadata(task.path)
+	if isRepo && w.included(rel) {
+		w.mu.Lock()
+		w.found = append(w.found, task.path)
+		w.mu.Unlock()
+	}
+
+	if task.depth >= w.opts.MaxDepth || (isRepo && !w.opts.DescendIntoRepos) {
+		return
+	}
+
+	entries, err := os.ReadDir(task.path)
+	if err != nil {
+		return
+	}
+
+	w.enqueueChildren(task.path, entries, task.depth+1)
+}
+
+func (w *discoveryWalker) relPath(dir string) string {
+	rel, err := filepath.Rel(w.root, dir)
+	if err != nil {
+		return filepath.Base(dir)
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+func (w *discoveryWalker) included(rel string) bool {
+	if len(w.opts.Include) == 0 {
+		return true
+	}
+
+	for _, pattern := range w.opts.Include {
+		if MatchDiscoverPattern(pattern, rel) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (w *discoveryWalker) excluded(rel string) bool {
+	for _, pattern := range w.opts.Exclude {
+		if MatchDiscoverPattern(pattern, rel) {
+			return true
+		}
+	}
+
+	return false
+}
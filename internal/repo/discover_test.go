@@ -0,0 +1,211 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestDiscoverWithOptions(t *testing.T) {
+	t.Parallel()
+
+	// root/
+	//   github.com/alice/app        (リポジトリ)
+	//   github.com/alice/app/tools/gen (ネストしたリポジトリ)
+	//   github.com/bob/lib          (リポジトリ)
+	//   github.com/bob/node_modules/dep (リポジトリ)
+	//   gitlab.com/carol/site       (リポジトリ)
+	//   top                         (root 直下のリポジトリ)
+	root := t.TempDir()
+
+	app := filepath.Join(root, "github.com", "alice", "app")
+	gen := filepath.Join(app, "tools", "gen")
+	lib := filepath.Join(root, "github.com", "bob", "lib")
+	dep := filepath.Join(root, "github.com", "bob", "node_modules", "dep")
+	site := filepath.Join(root, "gitlab.com", "carol", "site")
+	top := filepath.Join(root, "top")
+
+	for _, repoPath := range []string{app, gen, lib, dep, site, top} {
+		createGitDir(t, repoPath)
+	}
+
+	testCases := []struct {
+		name string
+		opts DiscoverOptions
+		want []string
+	}{
+		{
+			name: "既定は root 直下のみ",
+			opts: DiscoverOptions{},
+			want: []string{top},
+		},
+		{
+			name: "max_depth=3 で host/owner/repo を検出",
+			opts: DiscoverOptions{MaxDepth: 3},
+			want: []string{app, lib, site, top},
+		},
+		{
+			name: "リポジトリ配下も探索するとネストしたリポジトリを検出",
+			opts: DiscoverOptions{MaxDepth: 5, DescendIntoRepos: true},
+			want: []string{app, gen, lib, dep, site, top},
+		},
+		{
+			name: "exclude に一致するディレクトリは配下も探索しない",
+			opts: DiscoverOptions{MaxDepth: 4, Exclude: []string{"node_modules", "gitlab.com"}},
+			want: []string{app, lib, top},
+		},
+		{
+			name: "include に一致するリポジトリのみ検出",
+			opts: DiscoverOptions{MaxDepth: 5, Include: []string{"github.com/*/*"}},
+			want: []string{app, lib},
+		},
+		{
+			name: "include の ** は任意の階層に一致",
+			opts: DiscoverOptions{MaxDepth: 5, DescendIntoRepos: true, Include: []string{"**/tools/*"}},
+			want: []string{gen},
+		},
+		{
+			name: "ワーカー数 1 でも同じ結果",
+			opts: DiscoverOptions{MaxDepth: 5, DescendIntoRepos: true, Workers: 1},
+			want: []string{app, gen, lib, dep, site, top},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := DiscoverWithOptions(context.Background(), root, tc.opts)
+			if err != nil {
+				t.Fatalf("DiscoverWithOptions() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("DiscoverWithOptions() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDiscoverWithOptions_RootRepoIsAlwaysDescended(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	createGitDir(t, root)
+
+	child := filepath.Join(root, "child")
+	createGitDir(t, child)
+
+	got, err := DiscoverWithOptions(context.Background(), root, DiscoverOptions{MaxDepth: 2})
+	if err != nil {
+		t.Fatalf("DiscoverWithOptions() error = %v", err)
+	}
+
+	want := []string{filepath.Clean(root), child}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("DiscoverWithOptions() = %v, want %v", got, want)
+	}
+}
+
+func TestDiscoverWithOptions_DoesNotFollowSymlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("シンボリックリンクの作成に権限が必要なため Windows ではスキップ")
+	}
+
+	t.Parallel()
+
+	root := t.TempDir()
+	repoPath := filepath.Join(root, "a", "repo")
+	createGitDir(t, repoPath)
+
+	// a/loop -> root の循環リンクを辿らないこと
+	if err := os.Symlink(root, filepath.Join(root, "a", "loop")); err != nil {
+		t.Fatalf("symlink failed: %v", err)
+	}
+
+	got, err := DiscoverWithOptions(context.Background(), root, DiscoverOptions{MaxDepth: 10})
+	if err != nil {
+		t.Fatalf("DiscoverWithOptions() error = %v", err)
+	}
+
+	want := []string{repoPath}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("DiscoverWithOptions() = %v, want %v", got, want)
+	}
+}
+
+func TestDiscoverWithOptions_ManyDirectories(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	want := make([]string, 0, 200)
+
+	for owner := range 20 {
+		for repo := range 10 {
+			repoPath := filepath.Join(root, "host", fmt.Sprintf("owner%02d", owner), fmt.Sprintf("repo%02d", repo))
+			createGitDir(t, repoPath)
+			mustMkdir(t, filepath.Join(repoPath, "src", "pkg"))
+
+			want = append(want, repoPath)
+		}
+	}
+
+	got, err := DiscoverWithOptions(context.Background(), root, DiscoverOptions{MaxDepth: 3, Workers: 4})
+	if err != nil {
+		t.Fatalf("DiscoverWithOptions() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("DiscoverWithOptions() len = %d, want %d", len(got), len(want))
+	}
+}
+
+func TestDiscoverWithOptions_Canceled(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	createGitDir(t, filepath.Join(root, "a", "repo"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := DiscoverWithOptions(ctx, root, DiscoverOptions{MaxDepth: 3}); err == nil {
+		t.Fatalf("DiscoverWithOptions() error = nil, want error")
+	}
+}
+
+func TestMatchDiscoverPattern(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		pattern string
+		rel     string
+		want    bool
+	}{
+		{name: "名前のみのパターンはディレクトリ名と照合", pattern: "node_modules", rel: "github.com/bob/node_modules", want: true},
+		{name: "名前のみのパターンのワイルドカード", pattern: "tmp-*", rel: "work/tmp-123", want: true},
+		{name: "パスパターンは階層数まで一致", pattern: "github.com/*/*", rel: "github.com/alice/app", want: true},
+		{name: "パスパターンは階層数が違えば不一致", pattern: "github.com/*/*", rel: "github.com/alice", want: false},
+		{name: "** は 0 階層にも一致", pattern: "**/vendor", rel: "vendor", want: true},
+		{name: "** は複数階層に一致", pattern: "github.com/**/gen", rel: "github.com/alice/app/tools/gen", want: true},
+		{name: "先頭と末尾の / は無視", pattern: "/github.com/alice/", rel: "github.com/alice", want: true},
+		{name: "不正なパターンは不一致", pattern: "github.com/[", rel: "github.com/[", want: false},
+		{name: "空のパターンは不一致", pattern: " ", rel: "a", want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := MatchDiscoverPattern(tc.pattern, tc.rel); got != tc.want {
+				t.Fatalf("MatchDiscoverPattern(%q, %q) = %v, want %v", tc.pattern, tc.rel, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package repo
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// languageMarkers は言語ごとの判定に使うリポジトリ直下のマーカーファイル（glob）です。
+var languageMarkers = map[string][]string{
+	"go":     {"go.mod"},
+	"node":   {"package.json"},
+	"python": {"pyproject.toml", "setup.py", "setup.cfg", "requirements.txt"},
+	"rust":   {"Cargo.toml"},
+	"ruby":   {"Gemfile"},
+	"java":   {"pom.xml", "build.gradle", "build.gradle.kts"},
+	"dotnet": {"*.sln", "*.csproj", "*.fsproj"},
+	"php":    {"composer.json"},
+}
+
+// KnownLanguages は DetectLanguages が判定できる言語名を名前順で返します。
+func KnownLanguages() []string {
+	names := make([]string, 0, len(languageMarkers))
+	for name := range languageMarkers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// DetectLanguages はリポジトリ直下のマーカーファイルから使用言語を判定し、名前順で返します。
+// 1つのリポジトリが複数の言語に該当する場合があります。
+func DetectLanguages(repoPath string) []string {
+	var detected []string
+
+	for _, name := range KnownLanguages() {
+		for _, marker := range languageMarkers[name] {
+			if hasMarker(repoPath, marker) {
+				detected = append(detected, name)
+				break
+			}
+		}
+	}
+
+	return detected
+}
+
+func hasMarker(repoPath, marker string) bool {
+	matches, err := filepath.Glob(filepath.Join(repoPath, marker))
+	if err != nil {
+		return false
+	}
+
+	for _, match := range matches {
+		if info, statErr := os.Stat(match); statErr == nil && !info.IsDir() {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,52 @@
+package repo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDetectLanguages(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name  string
+		files []string
+		dirs  []string
+		want  []string
+	}{
+		{name: "go.mod で go と判定", files: []string{"go.mod"}, want: []string{"go"}},
+		{name: "複数言語に該当", files: []string{"package.json", "pyproject.toml", "go.mod"}, want: []string{"go", "node", "python"}},
+		{name: "glob マーカーで dotnet と判定", files: []string{"App.csproj"}, want: []string{"dotnet"}},
+		{name: "マーカーと同名のディレクトリは無視", dirs: []string{"Cargo.toml"}},
+		{name: "マーカーなし", files: []string{"README.md"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			repoPath := t.TempDir()
+
+			for _, file := range tc.files {
+				if err := os.WriteFile(filepath.Join(repoPath, file), []byte("x"), 0o644); err != nil {
+					t.Fatalf("failed to write %s: %v", file, err)
+				}
+			}
+
+			for _, dir := range tc.dirs {
+				mustMkdir(t, filepath.Join(repoPath, dir))
+			}
+
+			got := DetectLanguages(repoPath)
+			if len(got) == 0 && len(tc.want) == 0 {
+				return
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("DetectLanguages() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
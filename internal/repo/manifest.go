@@ -0,0 +1,322 @@
+package repo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestVersion は対応しているマニフェスト（repos.yaml）の形式バージョンです。
+const ManifestVersion = 1
+
+// Manifest はワークスペースに含めるリポジトリを宣言する repos.yaml の内容です。
+type Manifest struct {
+	Version int `yaml:"version"`
+	// Root はリポジトリを配置するルートです。空の場合は repo.root（または --root）を使用します。
+	Root  string         `yaml:"root,omitempty"`
+	Repos []ManifestRepo `yaml:"repos"`
+}
+
+// ManifestRepo はマニフェストの1件です。
+type ManifestRepo struct {
+	URL string `yaml:"url"`
+	// Path はルートからの相対パスです。空の場合は URL のリポジトリ名を使用します。
+	Path string `yaml:"path,omitempty"`
+	// Branch は clone 時にチェックアウトするブランチです。空の場合はリモートの既定ブランチです。
+	Branch string `yaml:"branch,omitempty"`
+	// Depth は shallow clone の深さです。0 の場合は全履歴を取得します。
+	Depth      int  `yaml:"depth,omitempty"`
+	Submodules bool `yaml:"submodules,omitempty"`
+	LFS        bool `yaml:"lfs,omitempty"`
+}
+
+// LocalPath はルートからの相対パス（"/" 区切り）を返します。
+func (r ManifestRepo) LocalPath() string {
+	if p := strings.TrimSpace(r.Path); p != "" {
+		return path.Clean(filepath.ToSlash(p))
+	}
+
+	return repoNameFromURL(r.URL)
+}
+
+// CloneArgs は target へ clone する git の引数を返します。
+func (r ManifestRepo) CloneArgs(target string) []string {
+	args := []string{"clone"}
+
+	if branch := strings.TrimSpace(r.Branch); branch != "" {
+		args = append(args, "--branch", branch)
+	}
+
+	if r.Depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(r.Depth))
+	}
+
+	if r.Submodules {
+		args = append(args, "--recurse-submodules")
+		if r.Depth > 0 {
+			args = append(args, "--shallow-submodules")
+		}
+	}
+
+	return append(args, strings.TrimSpace(r.URL), target)
+}
+
+// repoNameFromURL は clone URL（https / ssh / scp 形式）からリポジトリ名を取り出します。
+func repoNameFromURL(url string) string {
+	name := strings.TrimRight(strings.TrimSpace(url), "/")
+	name = strings.TrimSuffix(name, ".git")
+
+	if idx := strings.LastIndexAny(name, "/:"); idx >= 0 {
+		name = name[idx+1:]
+	}
+
+	return name
+}
+
+// LoadManifest は path のマニフェストを読み込んで検証します。
+// 未知のキーは記述ミスの可能性が高いためエラーにします。
+func LoadManifest(manifestPath string) (*Manifest, error) {
+	resolved, err := resolveRoot(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("マニフェストの読み込みに失敗: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var manifest Manifest
+	if decodeErr := decoder.Decode(&manifest); decodeErr != nil && !errors.Is(decodeErr, io.EOF) {
+		return nil, fmt.Errorf("マニフェストの解析に失敗 (%s): %w", resolved, decodeErr)
+	}
+
+	if validateErr := manifest.Validate(); validateErr != nil {
+		return nil, fmt.Errorf("マニフェストが不正です (%s): %w", resolved, validateErr)
+	}
+
+	return &manifest, nil
+}
+
+// Validate はマニフェストの内容を検証し、問題をまとめたエラーを返します。
+func (m *Manifest) Validate() error {
+	var errs []error
+
+	if m.Version != 0 && m.Version != ManifestVersion {
+		errs = append(errs, fmt.Errorf("未対応の version です: %d（%d を指定してください）", m.Version, ManifestVersion))
+	}
+
+	seen := make(map[string]int, len(m.Repos))
+
+	for i, entry := range m.Repos {
+		field := fmt.Sprintf("repos[%d]", i)
+
+		if strings.TrimSpace(entry.URL) == "" {
+			errs = append(errs, fmt.Errorf("%s.url は必須です", field))
+			continue
+		}
+
+		localPath := entry.LocalPath()
+
+		switch {
+		case localPath == "" || localPath == ".":
+			errs = append(errs, fmt.Errorf("%s.path を解決できません: %q", field, entry.URL))
+		case path.IsAbs(localPath) || filepath.IsAbs(entry.Path) || localPath == ".." || strings.HasPrefix(localPath, "../"):
+			errs = append(errs, fmt.Errorf("%s.path はルートからの相対パスで指定してください: %q", field, entry.Path))
+		default:
+			if first, ok := seen[localPath]; ok {
+				errs = append(errs, fmt.Errorf("%s.path が repos[%d] と重複しています: %s", field, first, localPath))
+			} else {
+				seen[localPath] = i
+			}
+		}
+
+		if entry.Depth < 0 {
+			errs = append(errs, fmt.Errorf("%s.depth は0以上を指定してください: %d", field, entry.Depth))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ManifestPlan はマニフェストとローカルの状態の差分です。パスはすべてルートからの相対パス（"/" 区切り）です。
+type ManifestPlan struct {
+	// Root は解決済みのルートの絶対パスです。
+	Root string
+	// Missing はローカルに存在せず clone が必要なエントリです。
+	Missing []ManifestRepo
+	// Present はローカルに clone 済みのエントリです。
+	Present []ManifestRepo
+	// Conflicts はパスが存在するものの Git リポジトリではないエントリです。
+	Conflicts []ManifestRepo
+	// Unmanaged はマニフェストに含まれないローカルのリポジトリです。
+	Unmanaged []string
+	// Dropped は以前マニフェストで管理していたが、現在のマニフェストから削除されたリポジトリです。
+	Dropped []string
+}
+
+// PlanManifestSync は root 配下のリポジトリとマニフェストを比較します。
+// state に記録された前回の管理対象は、マニフェストから削除されたリポジトリの判定に使用します。
+// root が存在しない場合はすべてのエントリを Missing とします。
+func PlanManifestSync(ctx context.Context, root string, manifest *Manifest, opts DiscoverOptions, state *ManifestState) (*ManifestPlan, error) {
+	resolvedRoot, err := resolveRoot(root)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &ManifestPlan{Root: resolvedRoot}
+	managed := state.Managed(resolvedRoot)
+
+	var localRepos []string
+
+	if _, statErr := os.Stat(resolvedRoot); statErr == nil {
+		// マニフェストと前回管理していたパスのうち最も深いものまでは必ず探索する。
+		for _, entry := range manifest.Repos {
+			opts.MaxDepth = max(opts.MaxDepth, strings.Count(entry.LocalPath(), "/")+1)
+		}
+
+		for _, p := range managed {
+			opts.MaxDepth = max(opts.MaxDepth, strings.Count(path.Clean(p), "/")+1)
+		}
+
+		localRepos, err = DiscoverWithOptions(ctx, resolvedRoot, opts)
+		if err != nil {
+			return nil, err
+		}
+	} else if !errors.Is(statErr, os.ErrNotExist) {
+		return nil, fmt.Errorf("ルートディレクトリにアクセスできません: %w", statErr)
+	}
+
+	declared := make(map[string]struct{}, len(manifest.Repos))
+
+	for _, entry := range manifest.Repos {
+		localPath := entry.LocalPath()
+		declared[localPath] = struct{}{}
+
+		target := filepath.Join(resolvedRoot, filepath.FromSlash(localPath))
+
+		switch info, statErr := os.Stat(target); {
+		case statErr != nil:
+			plan.Missing = append(plan.Missing, entry)
+		case info.IsDir() && hasGitMetadata(target):
+			plan.Present = append(plan.Present, entry)
+		default:
+			plan.Conflicts = append(plan.Conflicts, entry)
+		}
+	}
+
+	previously := make(map[string]struct{}, len(managed))
+	for _, p := range managed {
+		previously[path.Clean(p)] = struct{}{}
+	}
+
+	for _, repoPath := range localRepos {
+		rel, relErr := filepath.Rel(resolvedRoot, repoPath)
+		if relErr != nil || rel == "." {
+			continue
+		}
+
+		rel = filepath.ToSlash(rel)
+		if _, ok := declared[rel]; ok {
+			continue
+		}
+
+		if _, ok := previously[rel]; ok {
+			plan.Dropped = append(plan.Dropped, rel)
+		} else {
+			plan.Unmanaged = append(plan.Unmanaged, rel)
+		}
+	}
+
+	return plan, nil
+}
+
+// ManagedPaths は同期後に管理対象として記録するパス（マニフェストのエントリと、残した Dropped）を返します。
+func (m *Manifest) ManagedPaths(keptDropped []string) []string {
+	paths := make([]string, 0, len(m.Repos)+len(keptDropped))
+	for _, entry := range m.Repos {
+		paths = append(paths, entry.LocalPath())
+	}
+
+	paths = append(paths, keptDropped...)
+	sort.Strings(paths)
+
+	return paths
+}
+
+// ManifestState はルートごとにマニフェストで管理しているパスを記録します。
+type ManifestState struct {
+	Roots map[string][]string `json:"roots"`
+}
+
+// LoadManifestState は状態ファイルを読み込みます。ファイルが存在しない場合は空の状態を返します。
+func LoadManifestState(statePath string) (*ManifestState, error) {
+	state := &ManifestState{Roots: map[string][]string{}}
+
+	data, err := os.ReadFile(statePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("マニフェスト状態の読み込みに失敗: %w", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("マニフェスト状態の解析に失敗 (%s): %w", statePath, err)
+	}
+
+	if state.Roots == nil {
+		state.Roots = map[string][]string{}
+	}
+
+	return state, nil
+}
+
+// Managed は root で管理しているパスを返します。
+func (s *ManifestState) Managed(root string) []string {
+	return s.Roots[filepath.Clean(root)]
+}
+
+// SetManaged は root で管理しているパスを更新します。
+func (s *ManifestState) SetManaged(root string, paths []string) {
+	s.Roots[filepath.Clean(root)] = paths
+}
+
+// Save は状態ファイルを一時ファイル経由で書き込みます。
+func (s *ManifestState) Save(statePath string) error {
+	if err := os.MkdirAll(filepath.Dir(statePath), 0o755); err != nil {
+		return fmt.Errorf("状態ディレクトリの作成に失敗: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("マニフェスト状態のエンコードに失敗: %w", err)
+	}
+
+	tmp := statePath + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("マニフェスト状態の書き込みに失敗: %w", err)
+	}
+
+	if err := os.Rename(tmp, statePath); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("マニフェスト状態の書き込みに失敗: %w", err)
+	}
+
+	return nil
+}
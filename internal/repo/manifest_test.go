@@ -0,0 +1,241 @@
+package repo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestManifestRepoLocalPathAndCloneArgs(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		entry    ManifestRepo
+		wantPath string
+		wantArgs []string
+	}{
+		{
+			name:     "https URL からリポジトリ名を導出",
+			entry:    ManifestRepo{URL: "https://github.com/acme/api.git"},
+			wantPath: "api",
+			wantArgs: []string{"clone", "https://github.com/acme/api.git", "/ws/api"},
+		},
+		{
+			name:     "scp 形式の URL からリポジトリ名を導出",
+			entry:    ManifestRepo{URL: "git@github.com:acme/web"},
+			wantPath: "web",
+			wantArgs: []string{"clone", "git@github.com:acme/web", "/ws/web"},
+		},
+		{
+			name:     "path・branch・depth・submodules を反映",
+			entry:    ManifestRepo{URL: "https://github.com/acme/app", Path: "work/app/", Branch: "develop", Depth: 1, Submodules: true},
+			wantPath: "work/app",
+			wantArgs: []string{"clone", "--branch", "develop", "--depth", "1", "--recurse-submodules", "--shallow-submodules", "https://github.com/acme/app", "/ws/work/app"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tc.entry.LocalPath(); got != tc.wantPath {
+				t.Fatalf("LocalPath() = %q, want %q", got, tc.wantPath)
+			}
+
+			if got := tc.entry.CloneArgs("/ws/" + tc.wantPath); !reflect.DeepEqual(got, tc.wantArgs) {
+				t.Fatalf("CloneArgs() = %v, want %v", got, tc.wantArgs)
+			}
+		})
+	}
+}
+
+func TestLoadManifest(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		body        string
+		wantRepos   int
+		wantErrSubs []string
+	}{
+		{
+			name: "妥当なマニフェスト",
+			body: `version: 1
+root: ~/work
+repos:
+  - url: https://github.com/acme/api.git
+    branch: main
+    depth: 1
+  - url: git@github.com:acme/assets.git
+    path: media/assets
+    lfs: true
+`,
+			wantRepos: 2,
+		},
+		{
+			name:        "未知のキーはエラー",
+			body:        "repos:\n  - url: https://github.com/acme/api\n    brnach: main\n",
+			wantErrSubs: []string{"brnach"},
+		},
+		{
+			name: "不正なエントリをまとめて報告",
+			body: `version: 2
+repos:
+  - path: lib
+  - url: https://github.com/acme/api
+  - url: https://github.com/other/api
+  - url: https://github.com/acme/escape
+    path: ../escape
+  - url: https://github.com/acme/deep
+    depth: -1
+`,
+			wantErrSubs: []string{"version", "repos[0].url", "repos[2].path が repos[1]", "repos[3].path", "repos[4].depth"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			manifestPath := filepath.Join(t.TempDir(), "repos.yaml")
+			if err := os.WriteFile(manifestPath, []byte(tc.body), 0o644); err != nil {
+				t.Fatalf("failed to write manifest: %v", err)
+			}
+
+			got, err := LoadManifest(manifestPath)
+
+			if len(tc.wantErrSubs) > 0 {
+				if err == nil {
+					t.Fatalf("LoadManifest() error = nil, want error")
+				}
+
+				for _, sub := range tc.wantErrSubs {
+					if !strings.Contains(err.Error(), sub) {
+						t.Fatalf("LoadManifest() error = %v, want it to contain %q", err, sub)
+					}
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("LoadManifest() error = %v", err)
+			}
+
+			if len(got.Repos) != tc.wantRepos {
+				t.Fatalf("len(Repos) = %d, want %d", len(got.Repos), tc.wantRepos)
+			}
+		})
+	}
+}
+
+func TestPlanManifestSync(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	createGitDir(t, filepath.Join(root, "api"))
+	createGitDir(t, filepath.Join(root, "work", "app"))
+	createGitDir(t, filepath.Join(root, "scratch"))
+	createGitDir(t, filepath.Join(root, "old", "tool"))
+	mustMkdir(t, filepath.Join(root, "docs"))
+
+	manifest := &Manifest{Repos: []ManifestRepo{
+		{URL: "https://github.com/acme/api"},
+		{URL: "https://github.com/acme/app", Path: "work/app"},
+		{URL: "https://github.com/acme/web", Path: "work/web"},
+		{URL: "https://github.com/acme/docs"},
+	}}
+
+	state := &ManifestState{Roots: map[string][]string{}}
+	state.SetManaged(root, []string{"api", "old/tool", "gone"})
+
+	got, err := PlanManifestSync(context.Background(), root, manifest, DiscoverOptions{MaxDepth: 1}, state)
+	if err != nil {
+		t.Fatalf("PlanManifestSync() error = %v", err)
+	}
+
+	localPaths := func(entries []ManifestRepo) []string {
+		paths := []string{}
+		for _, entry := range entries {
+			paths = append(paths, entry.LocalPath())
+		}
+
+		return paths
+	}
+
+	if paths := localPaths(got.Missing); !reflect.DeepEqual(paths, []string{"work/web"}) {
+		t.Fatalf("Missing = %v, want [work/web]", paths)
+	}
+
+	if paths := localPaths(got.Present); !reflect.DeepEqual(paths, []string{"api", "work/app"}) {
+		t.Fatalf("Present = %v, want [api work/app]", paths)
+	}
+
+	if paths := localPaths(got.Conflicts); !reflect.DeepEqual(paths, []string{"docs"}) {
+		t.Fatalf("Conflicts = %v, want [docs]", paths)
+	}
+
+	if !reflect.DeepEqual(got.Unmanaged, []string{"scratch"}) {
+		t.Fatalf("Unmanaged = %v, want [scratch]", got.Unmanaged)
+	}
+
+	// 前回管理していた old/tool は探索深さを超えていても削除済みエントリとして検出する
+	if !reflect.DeepEqual(got.Dropped, []string{"old/tool"}) {
+		t.Fatalf("Dropped = %v, want [old/tool]", got.Dropped)
+	}
+
+	if managed := manifest.ManagedPaths(got.Dropped); !reflect.DeepEqual(managed, []string{"api", "docs", "old/tool", "work/app", "work/web"}) {
+		t.Fatalf("ManagedPaths() = %v", managed)
+	}
+}
+
+func TestPlanManifestSync_RootNotExist(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), "new-workspace")
+	manifest := &Manifest{Repos: []ManifestRepo{{URL: "https://github.com/acme/api"}}}
+
+	got, err := PlanManifestSync(context.Background(), root, manifest, DiscoverOptions{}, &ManifestState{Roots: map[string][]string{}})
+	if err != nil {
+		t.Fatalf("PlanManifestSync() error = %v", err)
+	}
+
+	if len(got.Missing) != 1 || len(got.Present) != 0 || len(got.Unmanaged) != 0 {
+		t.Fatalf("PlanManifestSync() = %+v, want every entry missing", got)
+	}
+}
+
+func TestManifestState_SaveAndLoad(t *testing.T) {
+	t.Parallel()
+
+	statePath := filepath.Join(t.TempDir(), "state", "repo-manifest.json")
+
+	empty, err := LoadManifestState(statePath)
+	if err != nil {
+		t.Fatalf("LoadManifestState() error = %v", err)
+	}
+
+	if len(empty.Managed("/ws")) != 0 {
+		t.Fatalf("Managed() = %v, want empty for a missing file", empty.Managed("/ws"))
+	}
+
+	empty.SetManaged("/ws/", []string{"api", "work/app"})
+
+	if err := empty.Save(statePath); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadManifestState(statePath)
+	if err != nil {
+		t.Fatalf("LoadManifestState() error = %v", err)
+	}
+
+	if got := loaded.Managed("/ws"); !reflect.DeepEqual(got, []string{"api", "work/app"}) {
+		t.Fatalf("Managed() = %v, want [api work/app]", got)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // Status はリポジトリ状態を表します。
@@ -32,50 +33,51 @@ type Info struct {
 	Ahead       int
 	Behind      int
 	HasUpstream bool
+
+	// 以下は InspectDetail / ListDetail でのみ設定します。
+
+	// Branch は現在のブランチ名です（detached HEAD の場合は空）。
+	Branch   string
+	Detached bool
+	// Upstream は追跡ブランチ（例: origin/main）です。
+	Upstream string
+	// TrackedChanges は変更のある tracked ファイル数、UntrackedFiles は未追跡ファイル数です。
+	TrackedChanges int
+	UntrackedFiles int
+	StashCount     int
+	// Operation は進行中の rebase / merge などの操作です（なければ空）。
+	Operation        Operation
+	LastCommitHash   string
+	LastCommitAuthor string
+	LastCommitAt     time.Time
+	// LastCommitAge は LastCommitAt を「3日前」のような日本語の相対表現にしたものです。
+	LastCommitAge string
 }
 
 // Discover は root 配下（root 自体を含む）で Git リポジトリを検出します。
-// 現時点では root 直下のディレクトリを対象とします。
+// root 直下のディレクトリまでを対象とします。再帰的な探索は DiscoverWithOptions を使用してください。
 func Discover(root string) ([]string, error) {
-	resolvedRoot, err := resolveRoot(root)
-	if err != nil {
-		return nil, err
-	}
-
-	if validateErr := validateRoot(resolvedRoot); validateErr != nil {
-		return nil, validateErr
-	}
+	return DiscoverWithOptions(context.Background(), root, DiscoverOptions{MaxDepth: 1})
+}
 
-	result := make([]string, 0)
+// List は root 配下のリポジトリ一覧と状態を取得します。
+// Name には root からの相対パス（"/" 区切り。root 自体の場合はディレクトリ名）を設定します。
+func List(ctx context.Context, root string, opts DiscoverOptions) ([]Info, error) {
+	return listWith(ctx, root, opts, Inspect)
+}
 
-	if hasGitMetadata(resolvedRoot) {
-		result = append(result, resolvedRoot)
-	}
+// ListDetail は List と同様に一覧を取得し、各リポジトリの詳細（InspectDetail）を含めます。
+func ListDetail(ctx context.Context, root string, opts DiscoverOptions) ([]Info, error) {
+	return listWith(ctx, root, opts, InspectDetail)
+}
 
-	entries, err := os.ReadDir(resolvedRoot)
+func listWith(ctx context.Context, root string, opts DiscoverOptions, inspect func(context.Context, string) (Info, error)) ([]Info, error) {
+	paths, err := DiscoverWithOptions(ctx, root, opts)
 	if err != nil {
-		return nil, fmt.Errorf("ルートディレクトリの読み取りに失敗: %w", err)
-	}
-
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-
-		candidate := filepath.Join(resolvedRoot, entry.Name())
-		if hasGitMetadata(candidate) {
-			result = append(result, candidate)
-		}
+		return nil, err
 	}
 
-	sort.Strings(result)
-
-	return result, nil
-}
-
-// List は root 配下のリポジトリ一覧と状態を取得します。
-func List(ctx context.Context, root string) ([]Info, error) {
-	paths, err := Discover(root)
+	resolvedRoot, err := resolveRoot(root)
 	if err != nil {
 		return nil, err
 	}
@@ -83,11 +85,15 @@ func List(ctx context.Context, root string) ([]Info, error) {
 	infos := make([]Info, 0, len(paths))
 
 	for _, path := range paths {
-		info, inspectErr := Inspect(ctx, path)
+		info, inspectErr := inspect(ctx, path)
 		if inspectErr != nil {
 			return nil, inspectErr
 		}
 
+		if rel, relErr := filepath.Rel(resolvedRoot, info.Path); relErr == nil && rel != "." {
+			info.Name = filepath.ToSlash(rel)
+		}
+
 		infos = append(infos, info)
 	}
 
@@ -215,9 +221,16 @@ func isDirty(ctx context.Context, repoPath string) (bool, error) {
 // git pull --rebase --autostash は tracked の変更のみ退避するため、
 // untracked との区別が AutoStash 挙動の判定に必要です。
 func classifyDirtyState(ctx context.Context, repoPath string) (hasTracked, hasUntracked bool, err error) {
+	tracked, untracked, err := countDirtyEntries(ctx, repoPath)
+
+	return tracked > 0, untracked > 0, err
+}
+
+// countDirtyEntries は git status --porcelain の出力から tracked / untracked の変更件数を数えます。
+func countDirtyEntries(ctx context.Context, repoPath string) (tracked, untracked int, err error) {
 	output, err := runGitCommandOutput(ctx, repoPath, "status", "--porcelain")
 	if err != nil {
-		return false, false, err
+		return 0, 0, err
 	}
 
 	for _, line := range strings.Split(string(output), "\n") {
@@ -226,13 +239,13 @@ func classifyDirtyState(ctx context.Context, repoPath string) (hasTracked, hasUn
 		}
 
 		if line[:2] == "??" {
-			hasUntracked = true
+			untracked++
 		} else {
-			hasTracked = true
+			tracked++
 		}
 	}
 
-	return hasTracked, hasUntracked, nil
+	return tracked, untracked, nil
 }
 
 func getAheadBehindCount(ctx context.Context, repoPath string) (hasUpstream bool, ahead, behind int, err error) {
@@ -281,8 +294,10 @@ func isNoUpstreamError(err error) bool {
 
 	stderr := strings.ToLower(string(exitErr.Stderr))
 
+	// detached HEAD では追跡ブランチを持たないため、upstream 未設定として扱う
 	return strings.Contains(stderr, "no upstream configured") ||
-		strings.Contains(stderr, "no upstream branch")
+		strings.Contains(stderr, "no upstream branch") ||
+		strings.Contains(stderr, "does not point to a branch")
 }
 
 func classifyStatus(dirty, hasUpstream bool, ahead int) Status {
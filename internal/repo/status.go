@@ -0,0 +1,198 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Operation はリポジトリで進行中の Git 操作です。
+type Operation string
+
+const (
+	OperationNone       Operation = ""
+	OperationRebase     Operation = "rebase"
+	OperationMerge      Operation = "merge"
+	OperationCherryPick Operation = "cherry-pick"
+	OperationRevert     Operation = "revert"
+	OperationBisect     Operation = "bisect"
+)
+
+// operationMarkers は git ディレクトリ内で進行中の操作を示すファイルです。先に一致したものを優先します。
+var operationMarkers = []struct {
+	name      string
+	operation Operation
+}{
+	{name: "rebase-merge", operation: OperationRebase},
+	{name: "rebase-apply", operation: OperationRebase},
+	{name: "MERGE_HEAD", operation: OperationMerge},
+	{name: "CHERRY_PICK_HEAD", operation: OperationCherryPick},
+	{name: "REVERT_HEAD", operation: OperationRevert},
+	{name: "BISECT_LOG", operation: OperationBisect},
+}
+
+// OperationLabel は進行中の操作を日本語ラベルに変換します。
+func OperationLabel(operation Operation) string {
+	switch operation {
+	case OperationNone:
+		return ""
+	case OperationRebase:
+		return "リベース中"
+	case OperationMerge:
+		return "マージ中"
+	case OperationCherryPick:
+		return "cherry-pick 中"
+	case OperationRevert:
+		return "revert 中"
+	case OperationBisect:
+		return "bisect 中"
+	default:
+		return string(operation)
+	}
+}
+
+// InspectDetail は Inspect の情報に加えて、ブランチ・変更件数・stash・進行中の操作・最終コミットを取得します。
+func InspectDetail(ctx context.Context, repoPath string) (Info, error) {
+	info, err := Inspect(ctx, repoPath)
+	if err != nil {
+		return Info{}, err
+	}
+
+	if err := fillInfoDetail(ctx, &info, time.Now()); err != nil {
+		return Info{}, fmt.Errorf("%s の詳細取得に失敗: %w", info.Path, err)
+	}
+
+	return info, nil
+}
+
+func fillInfoDetail(ctx context.Context, info *Info, now time.Time) error {
+	branch, err := getCurrentBranchName(ctx, info.Path)
+	if err != nil {
+		return err
+	}
+
+	if branch == "HEAD" {
+		info.Detached = true
+	} else {
+		info.Branch = branch
+	}
+
+	if info.HasUpstream {
+		upstream, _, upstreamErr := getUpstreamRef(ctx, info.Path)
+		if upstreamErr != nil {
+			return upstreamErr
+		}
+
+		info.Upstream = upstream
+	}
+
+	if info.TrackedChanges, info.UntrackedFiles, err = countDirtyEntries(ctx, info.Path); err != nil {
+		return err
+	}
+
+	if info.StashCount, err = countStashes(ctx, info.Path); err != nil {
+		return err
+	}
+
+	if info.Operation, err = detectOperation(ctx, info.Path); err != nil {
+		return err
+	}
+
+	return fillLastCommit(ctx, info, now)
+}
+
+// detectOperation は git ディレクトリのマーカーファイルから進行中の操作を判定します。
+// worktree や submodule でも正しい場所を参照するため、git ディレクトリは git に問い合わせます。
+func detectOperation(ctx context.Context, repoPath string) (Operation, error) {
+	output, err := runGitCommandOutput(ctx, repoPath, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return OperationNone, err
+	}
+
+	gitDir := strings.TrimSpace(string(output))
+
+	for _, marker := range operationMarkers {
+		if _, statErr := os.Stat(filepath.Join(gitDir, marker.name)); statErr == nil {
+			return marker.operation, nil
+		}
+	}
+
+	return OperationNone, nil
+}
+
+// fillLastCommit は HEAD のコミットの短縮ハッシュ・作者・日時を設定します。
+// 日時は committer date を使用し、相対表現はロケール非依存にするため Go 側で算出します。
+func fillLastCommit(ctx context.Context, info *Info, now time.Time) error {
+	output, err := runGitCommandOutput(ctx, info.Path, "log", "-1", "--format=%h%x00%an%x00%ct")
+	if err != nil {
+		return err
+	}
+
+	parts := strings.Split(strings.TrimSpace(string(output)), "\x00")
+	if len(parts) != 3 {
+		return fmt.Errorf("最終コミット情報のパースに失敗: %q", output)
+	}
+
+	unix, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return fmt.Errorf("最終コミット日時のパースに失敗: %w", err)
+	}
+
+	info.LastCommitHash = parts[0]
+	info.LastCommitAuthor = parts[1]
+	info.LastCommitAt = time.Unix(unix, 0)
+	info.LastCommitAge = formatRelativeAgeJP(parts[2], now)
+
+	return nil
+}
+
+// LocalOnlyWork はリモートに存在せず、リポジトリを削除すると失われるローカルの作業です。
+type LocalOnlyWork struct {
+	// UnpushedCommits はどのリモート追跡ブランチからも到達できないローカルコミットの件数です
+	UnpushedCommits int
+	// Branches は upstream が未設定、または upstream がリモートから消えたローカルブランチです
+	Branches []string
+}
+
+// IsEmpty はローカルにしかない作業がない場合に true を返します。
+func (w LocalOnlyWork) IsEmpty() bool {
+	return w.UnpushedCommits == 0 && len(w.Branches) == 0
+}
+
+// FindLocalOnlyWork は全ローカルブランチを対象に、リモートに存在しないコミットとブランチを調べます。
+// Inspect の Ahead は現在のブランチしか見ないため、削除前の安全確認にはこちらを使用します。
+func FindLocalOnlyWork(ctx context.Context, repoPath string) (LocalOnlyWork, error) {
+	var work LocalOnlyWork
+
+	// git log --branches --not --remotes と同じ範囲の件数を数える
+	output, err := runGitCommandOutput(ctx, repoPath, "rev-list", "--count", "--branches", "--not", "--remotes")
+	if err != nil {
+		return LocalOnlyWork{}, fmt.Errorf("未プッシュのコミットの確認に失敗: %w", err)
+	}
+
+	if work.UnpushedCommits, err = strconv.Atoi(strings.TrimSpace(string(output))); err != nil {
+		return LocalOnlyWork{}, fmt.Errorf("未プッシュのコミット件数のパースに失敗: %w", err)
+	}
+
+	output, err = runGitCommandOutput(ctx, repoPath, "for-each-ref", "--format=%(refname:short)%00%(upstream)%00%(upstream:track)", "refs/heads")
+	if err != nil {
+		return LocalOnlyWork{}, fmt.Errorf("ローカルブランチの確認に失敗: %w", err)
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		parts := strings.Split(line, "\x00")
+		if len(parts) != 3 {
+			continue
+		}
+
+		if parts[1] == "" || parts[2] == "[gone]" {
+			work.Branches = append(work.Branches, parts[0])
+		}
+	}
+
+	return work, nil
+}
@@ -0,0 +1,220 @@
+package repo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInspectDetail(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name  string
+		setup func(t *testing.T) string
+		check func(t *testing.T, info Info)
+	}{
+		{
+			name:  "追跡ブランチと最終コミットを取得",
+			setup: createRepoWithUpstream,
+			check: func(t *testing.T, info Info) {
+				t.Helper()
+
+				if info.Branch == "" || info.Detached || info.Upstream != "origin/"+info.Branch {
+					t.Fatalf("Branch=%q Detached=%v Upstream=%q, want a branch tracking origin", info.Branch, info.Detached, info.Upstream)
+				}
+
+				if info.LastCommitHash == "" || info.LastCommitAuthor != "dsx-test" || info.LastCommitAge == "" {
+					t.Fatalf("last commit = %q %q %q, want hash, author dsx-test and age", info.LastCommitHash, info.LastCommitAuthor, info.LastCommitAge)
+				}
+
+				if time.Since(info.LastCommitAt) > time.Hour {
+					t.Fatalf("LastCommitAt = %v, want a recent commit", info.LastCommitAt)
+				}
+			},
+		},
+		{
+			name: "tracked と untracked の変更件数を区別",
+			setup: func(t *testing.T) string {
+				t.Helper()
+
+				repoPath := createRepoWithUpstreamAndDirtyTracked(t)
+				for _, name := range []string{"a.txt", "b.txt"} {
+					if err := os.WriteFile(filepath.Join(repoPath, name), []byte("x\n"), 0o644); err != nil {
+						t.Fatalf("failed to write file: %v", err)
+					}
+				}
+
+				return repoPath
+			},
+			check: func(t *testing.T, info Info) {
+				t.Helper()
+
+				if !info.Dirty || info.TrackedChanges != 1 || info.UntrackedFiles != 2 {
+					t.Fatalf("Dirty=%v TrackedChanges=%d UntrackedFiles=%d, want true/1/2", info.Dirty, info.TrackedChanges, info.UntrackedFiles)
+				}
+			},
+		},
+		{
+			name:  "stash の件数を取得",
+			setup: createRepoWithUpstreamAndStash,
+			check: func(t *testing.T, info Info) {
+				t.Helper()
+
+				if info.StashCount != 1 || info.Dirty {
+					t.Fatalf("StashCount=%d Dirty=%v, want 1/false", info.StashCount, info.Dirty)
+				}
+			},
+		},
+		{
+			name:  "detached HEAD は追跡なしとして扱う",
+			setup: createRepoWithUpstreamAndDetachedHEAD,
+			check: func(t *testing.T, info Info) {
+				t.Helper()
+
+				if !info.Detached || info.Branch != "" || info.HasUpstream || info.Status != StatusNoUpstream {
+					t.Fatalf("Detached=%v Branch=%q HasUpstream=%v Status=%s, want detached without upstream", info.Detached, info.Branch, info.HasUpstream, info.Status)
+				}
+			},
+		},
+		{
+			name: "マージ中を検出",
+			setup: func(t *testing.T) string {
+				t.Helper()
+
+				repoPath := createLocalRepoWithoutUpstream(t)
+				head := strings.TrimSpace(string(runGitCommandOutputOrFail(t, repoPath, "rev-parse", "HEAD")))
+
+				if err := os.WriteFile(filepath.Join(repoPath, ".git", "MERGE_HEAD"), []byte(head+"\n"), 0o644); err != nil {
+					t.Fatalf("failed to write MERGE_HEAD: %v", err)
+				}
+
+				return repoPath
+			},
+			check: func(t *testing.T, info Info) {
+				t.Helper()
+
+				if info.Operation != OperationMerge || OperationLabel(info.Operation) != "マージ中" {
+					t.Fatalf("Operation = %q, want merge", info.Operation)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			info, err := InspectDetail(context.Background(), tc.setup(t))
+			if err != nil {
+				t.Fatalf("InspectDetail() error = %v", err)
+			}
+
+			tc.check(t, info)
+		})
+	}
+}
+
+func TestListDetail(t *testing.T) {
+	t.Parallel()
+
+	repoPath := createRepoWithUpstreamAndStash(t)
+
+	infos, err := ListDetail(context.Background(), filepath.Dir(repoPath), DiscoverOptions{MaxDepth: 1})
+	if err != nil {
+		t.Fatalf("ListDetail() error = %v", err)
+	}
+
+	// createRepoWithUpstream は remote.git（bare）・source・work を作成する
+	var work *Info
+
+	for i := range infos {
+		if infos[i].Name == "work" {
+			work = &infos[i]
+		}
+	}
+
+	if work == nil || work.StashCount != 1 || work.LastCommitHash == "" {
+		t.Fatalf("ListDetail() = %+v, want work with details", infos)
+	}
+}
+
+func TestFindLocalOnlyWork(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		setup        func(t *testing.T) string
+		wantCommits  int
+		wantBranches string
+	}{
+		{
+			name:  "すべてプッシュ済みなら空",
+			setup: createRepoWithUpstream,
+		},
+		{
+			name: "現在のブランチ以外の未プッシュコミットを検出",
+			setup: func(t *testing.T) string {
+				t.Helper()
+
+				repoPath := createRepoWithUpstream(t)
+				runGit(t, repoPath, "checkout", "-q", "-b", "feature")
+				runGit(t, repoPath, "commit", "-q", "--allow-empty", "-m", "local only")
+				runGit(t, repoPath, "checkout", "-q", "-")
+
+				return repoPath
+			},
+			wantCommits:  1,
+			wantBranches: "feature",
+		},
+		{
+			name: "コミットがなくても upstream のないブランチを検出",
+			setup: func(t *testing.T) string {
+				t.Helper()
+
+				repoPath := createRepoWithUpstream(t)
+				runGit(t, repoPath, "branch", "wip")
+
+				return repoPath
+			},
+			wantBranches: "wip",
+		},
+		{
+			name: "upstream が消えたブランチを検出",
+			setup: func(t *testing.T) string {
+				t.Helper()
+
+				repoPath := createRepoWithUpstream(t)
+				runGit(t, repoPath, "checkout", "-q", "-b", "topic")
+				runGit(t, repoPath, "push", "-q", "-u", "origin", "topic")
+				runGit(t, repoPath, "push", "-q", "origin", "--delete", "topic")
+				runGit(t, repoPath, "checkout", "-q", "-")
+
+				return repoPath
+			},
+			wantBranches: "topic",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			work, err := FindLocalOnlyWork(context.Background(), tc.setup(t))
+			if err != nil {
+				t.Fatalf("FindLocalOnlyWork() error = %v", err)
+			}
+
+			if work.UnpushedCommits != tc.wantCommits || strings.Join(work.Branches, ",") != tc.wantBranches {
+				t.Fatalf("FindLocalOnlyWork() = %+v, want commits=%d branches=%q", work, tc.wantCommits, tc.wantBranches)
+			}
+
+			if work.IsEmpty() != (tc.wantCommits == 0 && tc.wantBranches == "") {
+				t.Fatalf("IsEmpty() = %v, want %v", work.IsEmpty(), !work.IsEmpty())
+			}
+		})
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+
+	"github.com/scottlz0310/dsx/internal/runner"
 )
 
 const (
@@ -208,11 +210,17 @@ func formatGitCommand(repoPath string, args []string) string {
 	return strings.Join(parts, " ")
 }
 
+// runGitCommand は git コマンドを実行します。
+// runner のジョブ出力ログが ctx に設定されている場合は、コマンドラインと出力をログに書き込みます。
 func runGitCommand(ctx context.Context, repoPath string, args ...string) error {
 	commandArgs := append([]string{"-C", repoPath}, args...)
 	cmd := exec.CommandContext(ctx, "git", commandArgs...)
 
 	output, err := cmd.CombinedOutput()
+	if jobOutput := runner.JobOutput(ctx); jobOutput != nil {
+		fmt.Fprintf(jobOutput, "$ %s\n%s", formatGitCommand(repoPath, args), output)
+	}
+
 	if err != nil {
 		message := strings.TrimSpace(string(output))
 		if message == "" {
@@ -446,12 +454,23 @@ func getCurrentBranchName(ctx context.Context, repoPath string) (string, error)
 }
 
 func hasStash(ctx context.Context, repoPath string) (bool, error) {
+	count, err := countStashes(ctx, repoPath)
+
+	return count > 0, err
+}
+
+func countStashes(ctx context.Context, repoPath string) (int, error) {
 	output, err := runGitCommandOutput(ctx, repoPath, "stash", "list")
 	if err != nil {
-		return false, err
+		return 0, err
+	}
+
+	trimmed := strings.TrimSpace(string(output))
+	if trimmed == "" {
+		return 0, nil
 	}
 
-	return strings.TrimSpace(string(output)) != "", nil
+	return len(strings.Split(trimmed, "\n")), nil
 }
 
 func isDetachedHEAD(ctx context.Context, repoPath string) (bool, error) {
@@ -1,6 +1,7 @@
 package repo
 
 import (
+	"bytes"
 	"context"
 	"os"
 	"os/exec"
@@ -8,6 +9,8 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/scottlz0310/dsx/internal/runner"
 )
 
 func TestBuildFetchArgs(t *testing.T) {
@@ -756,3 +759,27 @@ func hasMessageContaining(messages []string, needle string) bool {
 
 	return false
 }
+
+func TestRunGitCommand_WritesJobOutput(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	notRepo := t.TempDir()
+	ctx := runner.WithJobOutput(context.Background(), &buf)
+
+	if err := runGitCommand(ctx, notRepo, "fetch", "--all"); err == nil {
+		t.Fatal("runGitCommand() error = nil, want error for non-repository")
+	}
+
+	commandLine := "$ " + formatGitCommand(notRepo, []string{"fetch", "--all"}) + "\n"
+
+	got := buf.String()
+	if !strings.HasPrefix(got, commandLine) {
+		t.Fatalf("job output = %q, want command line prefix", got)
+	}
+
+	if strings.TrimSpace(strings.TrimPrefix(got, commandLine)) == "" {
+		t.Fatalf("job output = %q, want git error output", got)
+	}
+}
@@ -0,0 +1,157 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrDependencyFailed は依存先のジョブが成功しなかったためにジョブをスキップしたことを示します。
+var ErrDependencyFailed = errors.New("依存ジョブが失敗したためスキップしました")
+
+// jobGraph は Job.DependsOn を解決した依存関係です。
+type jobGraph struct {
+	// deps はジョブごとに完了を待つジョブのインデックスです（DependsOn と After）。
+	deps [][]int
+	// required はジョブごとに成功している必要がある依存先のインデックスです（DependsOn）。
+	required [][]int
+	// invalid は依存関係が不正なジョブのエラーです（未知の依存先・循環）。
+	invalid map[int]error
+}
+
+// buildJobGraph はジョブ名から依存先を解決し、未知の依存先と循環を検出します。
+// ジョブ名が重複している場合は先に登録されたジョブを依存先とします。
+func buildJobGraph(jobs []Job) jobGraph {
+	graph := jobGraph{
+		deps:     make([][]int, len(jobs)),
+		required: make([][]int, len(jobs)),
+		invalid:  make(map[int]error),
+	}
+
+	indexByName := make(map[string]int, len(jobs))
+
+	for i, job := range jobs {
+		name := normalizeJobName(i, job.Name)
+		if _, exists := indexByName[name]; !exists {
+			indexByName[name] = i
+		}
+	}
+
+	for i, job := range jobs {
+		required, err := resolveJobIndexes(i, job.DependsOn, indexByName)
+		if err != nil {
+			graph.invalid[i] = err
+			continue
+		}
+
+		after, err := resolveJobIndexes(i, job.After, indexByName)
+		if err != nil {
+			graph.invalid[i] = err
+			continue
+		}
+
+		graph.required[i] = required
+		graph.deps[i] = append(append([]int{}, required...), after...)
+	}
+
+	graph.markCycles(jobs)
+
+	return graph
+}
+
+// resolveJobIndexes は依存先のジョブ名をインデックスに変換します。
+func resolveJobIndexes(index int, names []string, indexByName map[string]int) ([]int, error) {
+	indexes := make([]int, 0, len(names))
+
+	for _, dep := range names {
+		depIndex, ok := indexByName[dep]
+		if !ok {
+			return nil, fmt.Errorf("未知の依存ジョブです: %s", dep)
+		}
+
+		if depIndex == index {
+			return nil, fmt.Errorf("自分自身には依存できません: %s", dep)
+		}
+
+		indexes = append(indexes, depIndex)
+	}
+
+	return indexes, nil
+}
+
+// markCycles は循環依存に含まれるジョブを invalid に記録します。
+func (g *jobGraph) markCycles(jobs []Job) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make([]int, len(g.deps))
+	stack := make([]int, 0, len(g.deps))
+
+	var visit func(i int)
+
+	visit = func(i int) {
+		state[i] = visiting
+		stack = append(stack, i)
+
+		for _, dep := range g.deps[i] {
+			switch state[dep] {
+			case unvisited:
+				visit(dep)
+			case visiting:
+				g.markCycle(jobs, stack, dep)
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[i] = visited
+	}
+
+	for i := range g.deps {
+		if state[i] == unvisited {
+			visit(i)
+		}
+	}
+}
+
+func (g *jobGraph) markCycle(jobs []Job, stack []int, start int) {
+	begin := 0
+
+	for pos, index := range stack {
+		if index == start {
+			begin = pos
+			break
+		}
+	}
+
+	cycle := stack[begin:]
+	names := make([]string, 0, len(cycle)+1)
+
+	for _, index := range cycle {
+		names = append(names, normalizeJobName(index, jobs[index].Name))
+	}
+
+	names = append(names, names[0])
+	err := fmt.Errorf("依存関係が循環しています: %s", strings.Join(names, " → "))
+
+	for _, index := range cycle {
+		if _, exists := g.invalid[index]; !exists {
+			g.invalid[index] = err
+		}
+	}
+}
+
+// failedDependencyNames は成功しなかった依存先のジョブ名を返します。
+func failedDependencyNames(deps []int, results []Result) []string {
+	var names []string
+
+	for _, dep := range deps {
+		if results[dep].Status != StatusSuccess {
+			names = append(names, results[dep].Name)
+		}
+	}
+
+	return names
+}
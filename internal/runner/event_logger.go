@@ -3,6 +3,7 @@ package runner
 import (
 	"fmt"
 	"os"
+	"strings"
 	"sync"
 	"time"
 )
@@ -45,6 +46,10 @@ func (l *EventLogger) LogEvent(event *Event) {
 		line = fmt.Sprintf("%s [QUEUED]   %s", ts, event.JobName)
 	case EventStarted:
 		line = fmt.Sprintf("%s [STARTED]  %s", ts, event.JobName)
+	case EventBlocked:
+		line = fmt.Sprintf("%s [BLOCKED]  %s (待機: %s)", ts, event.JobName, strings.Join(event.WaitingFor, ", "))
+	case EventRetrying:
+		line = fmt.Sprintf("%s [RETRY]    %s (%d/%d 回目, %s 後): %v", ts, event.JobName, event.Attempt, event.MaxAttempts, event.Delay.Round(time.Millisecond), event.Err)
 	case EventFinished:
 		status := statusLabel(event.Status)
 		dur := event.Duration.Round(time.Millisecond)
@@ -54,6 +59,10 @@ func (l *EventLogger) LogEvent(event *Event) {
 		} else {
 			line = fmt.Sprintf("%s [%s] %s (%s)", ts, status, event.JobName, dur)
 		}
+
+		if event.LogPath != "" {
+			line += fmt.Sprintf(" [ログ: %s]", event.LogPath)
+		}
 	default:
 		// 未知のイベントタイプはログ出力をスキップ
 		return
@@ -1,6 +1,7 @@
 package runner
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -100,6 +101,33 @@ func TestEventLogger_失敗イベント(t *testing.T) {
 	}
 }
 
+func TestEventLogger_出力ログのパス(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "fail.log")
+
+	logger, err := NewEventLogger(logPath)
+	if err != nil {
+		t.Fatalf("NewEventLogger() error = %v", err)
+	}
+
+	logger.LogEvent(&Event{
+		Type:      EventFinished,
+		JobIndex:  0,
+		JobName:   "brew",
+		Status:    StatusFailed,
+		Err:       errForTest("exit status 1"),
+		Duration:  5 * time.Second,
+		LogPath:   "/tmp/logs/20260102T030405Z/brew.log",
+		Timestamp: time.Now(),
+	})
+
+	content := closeAndRead(t, logger, logPath)
+
+	if !strings.Contains(content, "brew (5s): exit status 1 [ログ: /tmp/logs/20260102T030405Z/brew.log]") {
+		t.Errorf("ログに出力ログのパスが含まれていません: %s", content)
+	}
+}
+
 func TestEventLogger_スキップイベント(t *testing.T) {
 	dir := t.TempDir()
 	logPath := filepath.Join(dir, "skip.log")
@@ -125,6 +153,56 @@ func TestEventLogger_スキップイベント(t *testing.T) {
 	}
 }
 
+func TestEventLogger_依存待ちイベント(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "blocked.log")
+
+	logger, err := NewEventLogger(logPath)
+	if err != nil {
+		t.Fatalf("NewEventLogger() error = %v", err)
+	}
+
+	logger.LogEvent(&Event{
+		Type:       EventBlocked,
+		JobIndex:   1,
+		JobName:    "npm",
+		WaitingFor: []string{"nvm", "apt"},
+		Timestamp:  time.Now(),
+	})
+
+	content := closeAndRead(t, logger, logPath)
+
+	if !strings.Contains(content, "[BLOCKED]  npm (待機: nvm, apt)") {
+		t.Errorf("ログに依存待ちの行が含まれていません: %s", content)
+	}
+}
+
+func TestEventLogger_再試行イベント(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "retry.log")
+
+	logger, err := NewEventLogger(logPath)
+	if err != nil {
+		t.Fatalf("NewEventLogger() error = %v", err)
+	}
+
+	logger.LogEvent(&Event{
+		Type:        EventRetrying,
+		JobName:     "snap",
+		Err:         errors.New("snap refresh failed"),
+		Attempt:     2,
+		MaxAttempts: 3,
+		Delay:       5 * time.Second,
+		Timestamp:   time.Now(),
+	})
+
+	content := closeAndRead(t, logger, logPath)
+
+	if !strings.Contains(content, "[RETRY]    snap (2/3 回目, 5s 後): snap refresh failed") {
+		t.Errorf("ログに再試行の行が含まれていません: %s", content)
+	}
+}
+
 func TestNewEventLogger_無効なパス(t *testing.T) {
 	_, err := NewEventLogger(filepath.Join(t.TempDir(), "nonexistent", "deep", "dir", "test.log"))
 	if err == nil {
@@ -138,7 +216,7 @@ type errForTest string
 func (e errForTest) Error() string { return string(e) }
 
 // closeAndRead はロガーを閉じてログファイルの内容を文字列で返します。
-func closeAndRead(t *testing.T, logger *EventLogger, path string) string {
+func closeAndRead(t *testing.T, logger JobLogger, path string) string {
 	t.Helper()
 
 	if err := logger.Close(); err != nil {
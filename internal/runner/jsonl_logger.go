@@ -0,0 +1,142 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// JSONLEventLogger はジョブ実行イベントを JSON Lines 形式でファイルに記録します。
+// 1行が1イベント（最後の1行はサマリー）で、ログ収集基盤への転送やマシン間の比較に使います。
+type JSONLEventLogger struct {
+	file      *os.File
+	mu        sync.Mutex
+	command   string
+	startedAt time.Time
+	writeErr  error
+}
+
+// jsonlEventRecord は JSONL ログの1行分です。
+type jsonlEventRecord struct {
+	Time        string   `json:"time"`
+	Command     string   `json:"command,omitempty"`
+	Type        string   `json:"type"`
+	JobIndex    *int     `json:"job_index,omitempty"`
+	Job         string   `json:"job,omitempty"`
+	Status      string   `json:"status,omitempty"`
+	Error       string   `json:"error,omitempty"`
+	DurationMs  *int64   `json:"duration_ms,omitempty"`
+	WaitingFor  []string `json:"waiting_for,omitempty"`
+	Attempt     int      `json:"attempt,omitempty"`
+	MaxAttempts int      `json:"max_attempts,omitempty"`
+	DelayMs     int64    `json:"delay_ms,omitempty"`
+	LogPath     string   `json:"log_path,omitempty"`
+	Total       *int     `json:"total,omitempty"`
+	Success     *int     `json:"success,omitempty"`
+	Failed      *int     `json:"failed,omitempty"`
+	Skipped     *int     `json:"skipped,omitempty"`
+}
+
+const jsonlRecordTypeSummary = "summary"
+
+// NewJSONLEventLogger は指定パスにログファイルを作成し、JSONLEventLogger を返します。
+// command は各行の command フィールドに記録するコマンド名です。
+func NewJSONLEventLogger(path, command string) (*JSONLEventLogger, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("ログファイルを作成できません: %w", err)
+	}
+
+	return &JSONLEventLogger{
+		file:      f,
+		command:   command,
+		startedAt: time.Now(),
+	}, nil
+}
+
+// LogEvent はイベントを1行の JSON としてファイルに書き込みます。
+func (l *JSONLEventLogger) LogEvent(event *Event) {
+	index := event.JobIndex
+	record := jsonlEventRecord{
+		Time:        formatJSONLTime(event.Timestamp),
+		Command:     l.command,
+		Type:        string(event.Type),
+		JobIndex:    &index,
+		Job:         event.JobName,
+		Status:      string(event.Status),
+		WaitingFor:  event.WaitingFor,
+		Attempt:     event.Attempt,
+		MaxAttempts: event.MaxAttempts,
+		DelayMs:     event.Delay.Milliseconds(),
+		LogPath:     event.LogPath,
+	}
+
+	if event.Err != nil {
+		record.Error = event.Err.Error()
+	}
+
+	if event.Type == EventFinished {
+		durationMs := event.Duration.Milliseconds()
+		record.DurationMs = &durationMs
+	}
+
+	l.writeRecord(&record)
+}
+
+// WriteSummary はログ末尾にサマリーを1行の JSON として書き込みます。
+func (l *JSONLEventLogger) WriteSummary(summary Summary) {
+	durationMs := time.Since(l.startedAt).Milliseconds()
+
+	l.writeRecord(&jsonlEventRecord{
+		Time:       formatJSONLTime(time.Now()),
+		Command:    l.command,
+		Type:       jsonlRecordTypeSummary,
+		DurationMs: &durationMs,
+		Total:      &summary.Total,
+		Success:    &summary.Success,
+		Failed:     &summary.Failed,
+		Skipped:    &summary.Skipped,
+	})
+}
+
+// Close はログファイルを閉じます。書き込みエラーがあった場合はそれも報告します。
+func (l *JSONLEventLogger) Close() error {
+	closeErr := l.file.Close()
+
+	if l.writeErr != nil {
+		return l.writeErr
+	}
+
+	return closeErr
+}
+
+func (l *JSONLEventLogger) writeRecord(record *jsonlEventRecord) {
+	data, err := json.Marshal(record)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.writeErr != nil {
+		return
+	}
+
+	if err != nil {
+		l.writeErr = fmt.Errorf("ログの JSON 変換に失敗: %w", err)
+		return
+	}
+
+	if _, err := l.file.Write(append(data, '\n')); err != nil {
+		l.writeErr = fmt.Errorf("ログファイルへの書き込みに失敗: %w", err)
+	}
+}
+
+// formatJSONLTime はタイムスタンプを ISO 8601（RFC 3339、ミリ秒精度）で返します。
+func formatJSONLTime(t time.Time) string {
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	return t.Format("2006-01-02T15:04:05.000Z07:00")
+}
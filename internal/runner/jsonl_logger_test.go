@@ -0,0 +1,140 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONLEventLogger(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "events.jsonl")
+
+	logger, err := NewJSONLEventLogger(logPath, "sys")
+	if err != nil {
+		t.Fatalf("NewJSONLEventLogger() error = %v", err)
+	}
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 6_000_000, time.UTC)
+
+	logger.LogEvent(&Event{Type: EventQueued, JobIndex: 0, JobName: "apt", Timestamp: now})
+	logger.LogEvent(&Event{Type: EventBlocked, JobIndex: 1, JobName: "npm", WaitingFor: []string{"nvm"}, Timestamp: now})
+	logger.LogEvent(&Event{
+		Type:        EventRetrying,
+		JobIndex:    2,
+		JobName:     "snap",
+		Err:         errors.New("snap refresh failed"),
+		Attempt:     2,
+		MaxAttempts: 3,
+		Delay:       5 * time.Second,
+		Timestamp:   now,
+	})
+	logger.LogEvent(&Event{
+		Type:      EventFinished,
+		JobIndex:  0,
+		JobName:   "apt",
+		Status:    StatusFailed,
+		Err:       errors.New("boom"),
+		Duration:  1500 * time.Millisecond,
+		LogPath:   "/tmp/logs/apt.log",
+		Timestamp: now,
+	})
+	logger.WriteSummary(Summary{Total: 3, Success: 1, Failed: 1, Skipped: 1})
+
+	content := closeAndRead(t, logger, logPath)
+	lines := strings.Split(strings.TrimSpace(content), "\n")
+
+	if len(lines) != 5 {
+		t.Fatalf("lines = %d, want 5\n%s", len(lines), content)
+	}
+
+	records := make([]map[string]interface{}, 0, len(lines))
+
+	for i, line := range lines {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("line %d is not JSON: %v\n%s", i+1, err, line)
+		}
+
+		if record["command"] != "sys" {
+			t.Fatalf("line %d command = %v, want sys", i+1, record["command"])
+		}
+
+		records = append(records, record)
+	}
+
+	checks := []struct {
+		name   string
+		record map[string]interface{}
+		key    string
+		want   interface{}
+	}{
+		{"ISO タイムスタンプ", records[0], "time", "2026-01-02T03:04:05.006Z"},
+		{"キューのジョブ番号 0 を出力", records[0], "job_index", float64(0)},
+		{"キューの種別", records[0], "type", "queued"},
+		{"依存待ち", records[1], "waiting_for", []interface{}{"nvm"}},
+		{"再試行の試行番号", records[2], "attempt", float64(2)},
+		{"再試行の待機時間", records[2], "delay_ms", float64(5000)},
+		{"完了の状態", records[3], "status", "failed"},
+		{"完了のエラー", records[3], "error", "boom"},
+		{"完了の所要時間", records[3], "duration_ms", float64(1500)},
+		{"完了の出力ログ", records[3], "log_path", "/tmp/logs/apt.log"},
+		{"サマリー種別", records[4], "type", "summary"},
+		{"サマリーの失敗数", records[4], "failed", float64(1)},
+	}
+
+	for _, c := range checks {
+		got, _ := json.Marshal(c.record[c.key])
+		want, _ := json.Marshal(c.want)
+
+		if string(got) != string(want) {
+			t.Errorf("%s: %s = %s, want %s", c.name, c.key, got, want)
+		}
+	}
+
+	if _, ok := records[0]["duration_ms"]; ok {
+		t.Errorf("完了以外のイベントに duration_ms が含まれています: %v", records[0])
+	}
+}
+
+func TestNewJobLogger(t *testing.T) {
+	dir := t.TempDir()
+
+	testCases := []struct {
+		name     string
+		format   string
+		wantType string
+		wantErr  bool
+	}{
+		{name: "未指定はテキスト", format: "", wantType: "*runner.EventLogger"},
+		{name: "text", format: "text", wantType: "*runner.EventLogger"},
+		{name: "jsonl（大文字小文字を無視）", format: "JSONL", wantType: "*runner.JSONLEventLogger"},
+		{name: "未対応の形式はエラー", format: "xml", wantErr: true},
+	}
+
+	for i, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger, err := NewJobLogger(filepath.Join(dir, strings.Repeat("x", i+1)+".log"), tc.format, "repo")
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("NewJobLogger(%q) error = nil, want error", tc.format)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("NewJobLogger(%q) error = %v", tc.format, err)
+			}
+
+			defer logger.Close()
+
+			if got := fmt.Sprintf("%T", logger); got != tc.wantType {
+				t.Fatalf("NewJobLogger(%q) = %s, want %s", tc.format, got, tc.wantType)
+			}
+		})
+	}
+}
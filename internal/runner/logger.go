@@ -0,0 +1,52 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// LogFormatText は人間向けのテキスト形式（EventLogger）です。
+	LogFormatText = "text"
+	// LogFormatJSONL は1イベント1行の JSON Lines 形式（JSONLEventLogger）です。
+	LogFormatJSONL = "jsonl"
+)
+
+// JobLogger はジョブ実行イベントをファイルに記録するロガーの共通インターフェースです。
+type JobLogger interface {
+	LogEvent(event *Event)
+	WriteSummary(summary Summary)
+	Close() error
+}
+
+var (
+	_ JobLogger = (*EventLogger)(nil)
+	_ JobLogger = (*JSONLEventLogger)(nil)
+)
+
+// ParseLogFormat はログ形式を検証して正規化します。空文字はテキスト形式として扱います。
+func ParseLogFormat(format string) (string, error) {
+	switch normalized := strings.ToLower(strings.TrimSpace(format)); normalized {
+	case "", LogFormatText:
+		return LogFormatText, nil
+	case LogFormatJSONL:
+		return LogFormatJSONL, nil
+	default:
+		return "", fmt.Errorf("未対応のログ形式です: %q（%s / %s を指定してください）", format, LogFormatText, LogFormatJSONL)
+	}
+}
+
+// NewJobLogger は format に応じたロガーを作成します。
+// command は JSONL 形式の各行に記録するコマンド名（sys / repo / run）です。
+func NewJobLogger(path, format, command string) (JobLogger, error) {
+	parsed, err := ParseLogFormat(format)
+	if err != nil {
+		return nil, err
+	}
+
+	if parsed == LogFormatJSONL {
+		return NewJSONLEventLogger(path, command)
+	}
+
+	return NewEventLogger(path)
+}
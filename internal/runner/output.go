@@ -0,0 +1,82 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type jobOutputKey struct{}
+
+// WithJobOutput はジョブの出力ログの書き込み先を ctx に設定します。
+func WithJobOutput(ctx context.Context, w io.Writer) context.Context {
+	return context.WithValue(ctx, jobOutputKey{}, w)
+}
+
+// JobOutput は実行中のジョブの出力ログの書き込み先を返します。
+// Job.OutputPath が指定されていない場合は nil を返します。
+// 返す Writer は複数のゴルーチンから同時に書き込んでも安全です。
+func JobOutput(ctx context.Context) io.Writer {
+	w, _ := ctx.Value(jobOutputKey{}).(io.Writer)
+	return w
+}
+
+// jobOutputFile は Job.OutputPath に開いたログファイルです。
+// exec.Cmd の標準出力と標準エラーのコピーが並行して書き込むため、排他制御します。
+type jobOutputFile struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func (f *jobOutputFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.file.Write(p)
+}
+
+func (f *jobOutputFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.file.Close()
+}
+
+// openJobOutput は出力ログファイルを作成します（ディレクトリは 0700、ファイルは 0600）。
+func openJobOutput(path string) (*jobOutputFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("ジョブログのディレクトリを作成できません: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("ジョブログを作成できません: %w", err)
+	}
+
+	return &jobOutputFile{file: file}, nil
+}
+
+// runWithOutput は Job.OutputPath が指定されていればログファイルを開いて ctx に設定し、ジョブを実行します。
+// ログファイルを作成できない場合もジョブは実行し、logPath は空で返します。
+func runWithOutput(ctx context.Context, index int, name string, job Job, emit func(Event)) (logPath string, err error) {
+	if job.OutputPath == "" {
+		return "", runWithPolicy(ctx, index, name, job, emit)
+	}
+
+	output, openErr := openJobOutput(job.OutputPath)
+	if openErr != nil {
+		return "", runWithPolicy(ctx, index, name, job, emit)
+	}
+
+	defer output.Close()
+
+	err = runWithPolicy(WithJobOutput(ctx, output), index, name, job, emit)
+	if err != nil {
+		fmt.Fprintf(output, "\n# 失敗: %v\n", err)
+	}
+
+	return job.OutputPath, err
+}
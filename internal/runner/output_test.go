@@ -0,0 +1,143 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestExecuteWithEvents_OutputPath(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "20260102T030405Z")
+	okPath := filepath.Join(dir, "ok.log")
+	failPath := filepath.Join(dir, "fail.log")
+
+	jobs := []Job{
+		{
+			Name:       "ok",
+			OutputPath: okPath,
+			Run: func(ctx context.Context) error {
+				fmt.Fprintln(JobOutput(ctx), "hello")
+				return nil
+			},
+		},
+		{
+			Name:       "fail",
+			OutputPath: failPath,
+			Run: func(ctx context.Context) error {
+				fmt.Fprintln(JobOutput(ctx), "E: lock failed")
+				return errors.New("exit status 100")
+			},
+		},
+		{
+			Name: "no-output",
+			Run: func(ctx context.Context) error {
+				if JobOutput(ctx) != nil {
+					return errors.New("OutputPath 未指定なのに JobOutput が設定されています")
+				}
+
+				return nil
+			},
+		},
+	}
+
+	var mu sync.Mutex
+
+	finishedLogPaths := make(map[string]string)
+
+	summary := ExecuteWithEvents(context.Background(), 1, jobs, func(event Event) {
+		if event.Type == EventFinished {
+			mu.Lock()
+			finishedLogPaths[event.JobName] = event.LogPath
+			mu.Unlock()
+		}
+	})
+
+	if summary.Success != 2 || summary.Failed != 1 {
+		t.Fatalf("summary = %+v, want success=2 failed=1", summary)
+	}
+
+	wantPaths := map[string]string{"ok": okPath, "fail": failPath, "no-output": ""}
+	for i, result := range summary.Results {
+		if result.LogPath != wantPaths[result.Name] {
+			t.Errorf("Results[%d].LogPath = %q, want %q", i, result.LogPath, wantPaths[result.Name])
+		}
+
+		if finishedLogPaths[result.Name] != wantPaths[result.Name] {
+			t.Errorf("%s の EventFinished.LogPath = %q, want %q", result.Name, finishedLogPaths[result.Name], wantPaths[result.Name])
+		}
+	}
+
+	failLog, err := os.ReadFile(failPath)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	for _, want := range []string{"E: lock failed", "# 失敗: exit status 100"} {
+		if !strings.Contains(string(failLog), want) {
+			t.Errorf("出力ログに %q が含まれていません: %s", want, failLog)
+		}
+	}
+}
+
+func TestRunJob_OutputPathWithRetry(t *testing.T) {
+	t.Parallel()
+
+	logPath := filepath.Join(t.TempDir(), "flaky.log")
+	calls := 0
+
+	err := RunJob(context.Background(), Job{
+		Name:       "flaky",
+		OutputPath: logPath,
+		Retry:      RetryPolicy{Attempts: 2, Backoff: noBackoff},
+		Run: func(ctx context.Context) error {
+			calls++
+			fmt.Fprintf(JobOutput(ctx), "attempt %d\n", calls)
+
+			if calls == 1 {
+				return errors.New("temporary")
+			}
+
+			return nil
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("RunJob() error = %v", err)
+	}
+
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	want := "# 試行 1/2\nattempt 1\n# 試行 2/2\nattempt 2\n"
+	if string(content) != want {
+		t.Fatalf("出力ログ = %q, want %q", content, want)
+	}
+}
+
+func TestExecuteWithEvents_OutputPathUnwritable(t *testing.T) {
+	t.Parallel()
+
+	blocker := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocker, nil, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	// 親がファイルのためディレクトリを作成できないが、ジョブ自体は実行される
+	summary := ExecuteWithEvents(context.Background(), 1, []Job{{
+		Name:       "job",
+		OutputPath: filepath.Join(blocker, "job.log"),
+		Run:        func(context.Context) error { return nil },
+	}}, nil)
+
+	if summary.Success != 1 || summary.Results[0].LogPath != "" {
+		t.Fatalf("summary = %+v, want success with empty LogPath", summary)
+	}
+}
@@ -0,0 +1,150 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultRetryBaseDelay = 2 * time.Second
+	defaultRetryMaxDelay  = 30 * time.Second
+)
+
+// ErrJobTimeout は Job.Timeout を超えたためジョブを打ち切ったことを示します。
+// 全体の ctx によるキャンセルとは区別し、StatusFailed として扱います。
+var ErrJobTimeout = errors.New("ジョブがタイムアウトしました")
+
+// RetryPolicy はジョブ失敗時の再試行方針です。ゼロ値は再試行しません。
+type RetryPolicy struct {
+	// Attempts は初回を含む最大試行回数です。1 以下の場合は再試行しません。
+	Attempts int
+	// Backoff は attempt 回目の試行が err で失敗した後の待機時間を返します。
+	// nil の場合は 2 秒から倍々に増え 30 秒で頭打ちになる待機時間を使います。
+	Backoff func(attempt int, err error) time.Duration
+	// Retryable はエラーが再試行対象かを判定します。
+	// nil の場合はキャンセル以外のすべてのエラー（ErrJobTimeout を含む）を再試行します。
+	Retryable func(err error) bool
+}
+
+// ExponentialBackoff は base から倍々に増え、maxDelay で頭打ちになる Backoff を返します。
+func ExponentialBackoff(base, maxDelay time.Duration) func(attempt int, err error) time.Duration {
+	return func(attempt int, _ error) time.Duration {
+		delay := base
+
+		for i := 1; i < attempt && delay < maxDelay; i++ {
+			delay *= 2
+		}
+
+		return min(delay, maxDelay)
+	}
+}
+
+func (p RetryPolicy) maxAttempts() int {
+	return max(p.Attempts, 1)
+}
+
+func (p RetryPolicy) backoff(attempt int, err error) time.Duration {
+	if p.Backoff != nil {
+		return p.Backoff(attempt, err)
+	}
+
+	return ExponentialBackoff(defaultRetryBaseDelay, defaultRetryMaxDelay)(attempt, err)
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+
+	return true
+}
+
+// RunJob は単一ジョブを Job.Timeout と Job.Retry に従って実行します。
+// ExecuteWithEvents を使わずに逐次実行する経路向けで、再試行のたびに EventRetrying を通知します。
+func RunJob(ctx context.Context, job Job, onEvent EventHandler) error {
+	if job.Run == nil {
+		return fmt.Errorf("ジョブ実体が nil です")
+	}
+
+	emit := func(event Event) {
+		if onEvent != nil {
+			onEvent(event)
+		}
+	}
+
+	_, err := runWithOutput(ctx, 0, normalizeJobName(0, job.Name), job, emit)
+
+	return err
+}
+
+// runWithPolicy は試行ごとに Timeout を適用し、Retry に従って失敗した試行を再実行します。
+func runWithPolicy(ctx context.Context, index int, name string, job Job, emit func(Event)) error {
+	attempts := job.Retry.maxAttempts()
+
+	for attempt := 1; ; attempt++ {
+		if output := JobOutput(ctx); output != nil && attempts > 1 {
+			fmt.Fprintf(output, "# 試行 %d/%d\n", attempt, attempts)
+		}
+
+		err := runAttempt(ctx, job)
+		if err == nil || attempt >= attempts || ctx.Err() != nil || !job.Retry.retryable(err) {
+			return err
+		}
+
+		delay := job.Retry.backoff(attempt, err)
+
+		emit(Event{
+			Type:        EventRetrying,
+			JobIndex:    index,
+			JobName:     name,
+			Err:         err,
+			Attempt:     attempt + 1,
+			MaxAttempts: attempts,
+			Delay:       delay,
+			Timestamp:   time.Now(),
+		})
+
+		if sleepErr := sleepWithContext(ctx, delay); sleepErr != nil {
+			return sleepErr
+		}
+	}
+}
+
+func runAttempt(ctx context.Context, job Job) error {
+	if job.Timeout <= 0 {
+		return job.Run(ctx)
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, job.Timeout)
+	defer cancel()
+
+	err := job.Run(attemptCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+		// 元のエラーは %v で包み、全体キャンセル扱い（StatusSkipped）にならないようにする。
+		return fmt.Errorf("%w (%s): %v", ErrJobTimeout, job.Timeout, err)
+	}
+
+	return err
+}
+
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
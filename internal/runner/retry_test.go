@@ -0,0 +1,207 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func noBackoff(int, error) time.Duration {
+	return 0
+}
+
+func TestRunJob_Retry(t *testing.T) {
+	t.Parallel()
+
+	errTemporary := errors.New("temporary")
+	errPermanent := errors.New("permanent")
+
+	testCases := []struct {
+		name         string
+		failures     int
+		failErr      error
+		retry        RetryPolicy
+		wantErr      error
+		wantCalls    int32
+		wantRetrying int
+	}{
+		{
+			name:      "ゼロ値は再試行しない",
+			failures:  1,
+			failErr:   errTemporary,
+			wantErr:   errTemporary,
+			wantCalls: 1,
+		},
+		{
+			name:         "上限内で成功",
+			failures:     2,
+			failErr:      errTemporary,
+			retry:        RetryPolicy{Attempts: 3, Backoff: noBackoff},
+			wantCalls:    3,
+			wantRetrying: 2,
+		},
+		{
+			name:         "上限に達したら最後のエラー",
+			failures:     5,
+			failErr:      errTemporary,
+			retry:        RetryPolicy{Attempts: 2, Backoff: noBackoff},
+			wantErr:      errTemporary,
+			wantCalls:    2,
+			wantRetrying: 1,
+		},
+		{
+			name:     "再試行対象外のエラー",
+			failures: 5,
+			failErr:  errPermanent,
+			retry: RetryPolicy{Attempts: 3, Backoff: noBackoff, Retryable: func(err error) bool {
+				return errors.Is(err, errTemporary)
+			}},
+			wantErr:   errPermanent,
+			wantCalls: 1,
+		},
+		{
+			name:      "キャンセルは再試行しない",
+			failures:  5,
+			failErr:   context.Canceled,
+			retry:     RetryPolicy{Attempts: 3, Backoff: noBackoff},
+			wantErr:   context.Canceled,
+			wantCalls: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int32
+
+			var retrying []Event
+
+			err := RunJob(context.Background(), Job{
+				Name:  "snap",
+				Retry: tc.retry,
+				Run: func(context.Context) error {
+					if int(atomic.AddInt32(&calls, 1)) <= tc.failures {
+						return tc.failErr
+					}
+
+					return nil
+				},
+			}, func(event Event) {
+				if event.Type == EventRetrying {
+					retrying = append(retrying, event)
+				}
+			})
+
+			if !errors.Is(err, tc.wantErr) || (tc.wantErr == nil && err != nil) {
+				t.Fatalf("RunJob() error = %v, want %v", err, tc.wantErr)
+			}
+
+			if calls != tc.wantCalls {
+				t.Fatalf("calls = %d, want %d", calls, tc.wantCalls)
+			}
+
+			if len(retrying) != tc.wantRetrying {
+				t.Fatalf("retrying events = %d, want %d", len(retrying), tc.wantRetrying)
+			}
+
+			for i, event := range retrying {
+				if event.Attempt != i+2 || event.MaxAttempts != tc.retry.Attempts || event.Err == nil {
+					t.Fatalf("retrying[%d] = %+v, want attempt %d/%d with error", i, event, i+2, tc.retry.Attempts)
+				}
+			}
+		})
+	}
+}
+
+func TestRunJob_Timeout(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+
+	err := RunJob(context.Background(), Job{
+		Name:    "fwupdmgr",
+		Timeout: 10 * time.Millisecond,
+		Retry:   RetryPolicy{Attempts: 2, Backoff: noBackoff},
+		Run: func(ctx context.Context) error {
+			atomic.AddInt32(&calls, 1)
+			<-ctx.Done()
+
+			return ctx.Err()
+		},
+	}, nil)
+
+	if !errors.Is(err, ErrJobTimeout) {
+		t.Fatalf("RunJob() error = %v, want ErrJobTimeout", err)
+	}
+
+	if resolveStatus(err) != StatusFailed {
+		t.Fatalf("status = %s, want failed (ジョブ単位のタイムアウトはスキップ扱いにしない)", resolveStatus(err))
+	}
+
+	if calls != 2 {
+		t.Fatalf("calls = %d, want 2 (タイムアウトは再試行対象)", calls)
+	}
+}
+
+func TestExecuteWithEvents_TimeoutAndRetry(t *testing.T) {
+	t.Parallel()
+
+	var flakyCalls int32
+
+	var retrying []string
+
+	summary := ExecuteWithEvents(context.Background(), 2, []Job{
+		{
+			Name:    "hung",
+			Timeout: 10 * time.Millisecond,
+			Run: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		},
+		{
+			Name:  "flaky",
+			Retry: RetryPolicy{Attempts: 2, Backoff: noBackoff},
+			Run: func(context.Context) error {
+				if atomic.AddInt32(&flakyCalls, 1) == 1 {
+					return errors.New("temporary")
+				}
+
+				return nil
+			},
+		},
+	}, func(event Event) {
+		if event.Type == EventRetrying {
+			retrying = append(retrying, event.JobName)
+		}
+	})
+
+	if summary.Results[0].Status != StatusFailed || !errors.Is(summary.Results[0].Err, ErrJobTimeout) {
+		t.Fatalf("hung = %+v, want failed with ErrJobTimeout", summary.Results[0])
+	}
+
+	if summary.Results[1].Status != StatusSuccess {
+		t.Fatalf("flaky = %+v, want success", summary.Results[1])
+	}
+
+	if strings.Join(retrying, ",") != "flaky" {
+		t.Fatalf("retrying events = %v, want [flaky]", retrying)
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	t.Parallel()
+
+	backoff := ExponentialBackoff(time.Second, 5*time.Second)
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := backoff(i+1, nil); got != w {
+			t.Fatalf("backoff(%d) = %s, want %s", i+1, got, w)
+		}
+	}
+}
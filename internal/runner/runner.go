@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,6 +19,21 @@ const defaultMaxJobs = 1
 type Job struct {
 	Name string
 	Run  func(context.Context) error
+	// DependsOn は先に成功している必要があるジョブ名です。
+	// 依存先がすべて完了するまで待機し、いずれかが成功しなかった場合は
+	// ErrDependencyFailed を理由に StatusSkipped とします。
+	DependsOn []string
+	// After は完了を待つだけのジョブ名です。DependsOn と異なり、待機先の成否は問いません。
+	// 同時に実行してはならないジョブの順序付けに使用します。
+	After []string
+	// Timeout は1回の試行あたりの制限時間です。0 以下の場合は制限しません。
+	// 超過した場合は ErrJobTimeout を理由に StatusFailed とします。
+	Timeout time.Duration
+	// Retry は失敗時の再試行方針です。ゼロ値は再試行しません。
+	Retry RetryPolicy
+	// OutputPath はジョブの出力ログ（コマンドの標準出力・標準エラー）を保存するファイルパスです。
+	// 指定するとファイルを作成して JobOutput(ctx) で書き込めるようにし、Result.LogPath に記録します。
+	OutputPath string
 }
 
 // ResultStatus はジョブ実行結果の状態です。
@@ -35,6 +51,8 @@ type Result struct {
 	Status   ResultStatus
 	Err      error
 	Duration time.Duration
+	// LogPath はジョブの出力ログのパスです（Job.OutputPath を保存できた場合のみ）。
+	LogPath string
 }
 
 // Summary は全ジョブの実行集計です。
@@ -53,6 +71,12 @@ const (
 	EventQueued   EventType = "queued"
 	EventStarted  EventType = "started"
 	EventFinished EventType = "finished"
+	// EventBlocked は依存先の完了待ちでジョブが待機していることを示します。
+	// 待機中の依存先は Event.WaitingFor に格納されます。
+	EventBlocked EventType = "blocked"
+	// EventRetrying は失敗した試行を Job.Retry に従って再実行することを示します。
+	// 直前の失敗は Event.Err、次の試行番号は Event.Attempt に格納されます。
+	EventRetrying EventType = "retrying"
 )
 
 // Event はジョブ実行中に発火する通知イベントです。
@@ -64,6 +88,15 @@ type Event struct {
 	Err       error
 	Duration  time.Duration
 	Timestamp time.Time
+	// WaitingFor は EventBlocked で待機中の依存先ジョブ名です。
+	WaitingFor []string
+	// Attempt と MaxAttempts は EventRetrying で次に行う試行番号と最大試行回数です。
+	Attempt     int
+	MaxAttempts int
+	// Delay は EventRetrying で次の試行までの待機時間です。
+	Delay time.Duration
+	// LogPath は EventFinished でジョブの出力ログのパスです（保存した場合のみ）。
+	LogPath string
 }
 
 // EventHandler はジョブ実行イベントを受け取るコールバックです。
@@ -75,6 +108,7 @@ func Execute(ctx context.Context, maxJobs int, jobs []Job) Summary {
 }
 
 // ExecuteWithEvents はジョブを指定並列数で実行し、イベント通知つきで結果を返します。
+// Job.DependsOn が指定されたジョブは依存先の完了を待ってから実行します。
 func ExecuteWithEvents(ctx context.Context, maxJobs int, jobs []Job, onEvent EventHandler) Summary {
 	summary := Summary{
 		Total:   len(jobs),
@@ -86,6 +120,7 @@ func ExecuteWithEvents(ctx context.Context, maxJobs int, jobs []Job, onEvent Eve
 	}
 
 	maxJobs = normalizeMaxJobs(maxJobs)
+	graph := buildJobGraph(jobs)
 
 	sem := semaphore.NewWeighted(int64(maxJobs))
 	group, groupCtx := errgroup.WithContext(ctx)
@@ -95,6 +130,11 @@ func ExecuteWithEvents(ctx context.Context, maxJobs int, jobs []Job, onEvent Eve
 		eventMu sync.Mutex
 	)
 
+	done := make([]chan struct{}, len(jobs))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
 	emit := func(event Event) {
 		if onEvent == nil {
 			return
@@ -105,6 +145,42 @@ func ExecuteWithEvents(ctx context.Context, maxJobs int, jobs []Job, onEvent Eve
 		eventMu.Unlock()
 	}
 
+	finish := func(i int, result Result) {
+		recordResult(&mu, &summary, i, result)
+		emit(Event{
+			Type:      EventFinished,
+			JobIndex:  i,
+			JobName:   result.Name,
+			Status:    result.Status,
+			Err:       result.Err,
+			Duration:  result.Duration,
+			LogPath:   result.LogPath,
+			Timestamp: time.Now(),
+		})
+		close(done[i])
+	}
+
+	run := func(i int, name string, job Job, start time.Time) {
+		// Acquire 済みのため、完了時に必ず Release する。
+		defer sem.Release(1)
+
+		if err := groupCtx.Err(); err != nil {
+			finish(i, Result{Name: name, Status: StatusSkipped, Err: err, Duration: time.Since(start)})
+			return
+		}
+
+		emit(Event{
+			Type:      EventStarted,
+			JobIndex:  i,
+			JobName:   name,
+			Timestamp: time.Now(),
+		})
+
+		logPath, err := runWithOutput(groupCtx, i, name, job, emit)
+
+		finish(i, Result{Name: name, Status: resolveStatus(err), Err: err, Duration: time.Since(start), LogPath: logPath})
+	}
+
 	for index, job := range jobs {
 		i := index
 		currentJob := job
@@ -119,101 +195,60 @@ func ExecuteWithEvents(ctx context.Context, maxJobs int, jobs []Job, onEvent Eve
 		})
 
 		if currentJob.Run == nil {
-			result := Result{
-				Name:     name,
-				Status:   StatusFailed,
-				Err:      fmt.Errorf("ジョブ実体が nil です"),
-				Duration: time.Since(start),
-			}
-
-			recordResult(&mu, &summary, i, result)
-			emit(Event{
-				Type:      EventFinished,
-				JobIndex:  i,
-				JobName:   name,
-				Status:    result.Status,
-				Err:       result.Err,
-				Duration:  result.Duration,
-				Timestamp: time.Now(),
-			})
-
+			finish(i, Result{Name: name, Status: StatusFailed, Err: fmt.Errorf("ジョブ実体が nil です"), Duration: time.Since(start)})
 			continue
 		}
 
-		if err := sem.Acquire(groupCtx, 1); err != nil {
-			result := Result{
-				Name:     name,
-				Status:   StatusSkipped,
-				Err:      err,
-				Duration: time.Since(start),
-			}
-			recordResult(&mu, &summary, i, result)
-			emit(Event{
-				Type:      EventFinished,
-				JobIndex:  i,
-				JobName:   name,
-				Status:    result.Status,
-				Err:       result.Err,
-				Duration:  result.Duration,
-				Timestamp: time.Now(),
-			})
-
+		if err, invalid := graph.invalid[i]; invalid {
+			finish(i, Result{Name: name, Status: StatusFailed, Err: err, Duration: time.Since(start)})
 			continue
 		}
 
-		group.Go(func() error {
-			// Acquire 済みのため、完了時に必ず Release する。
-			defer sem.Release(1)
-
-			if err := groupCtx.Err(); err != nil {
-				result := Result{
-					Name:     name,
-					Status:   StatusSkipped,
-					Err:      err,
-					Duration: time.Since(start),
+		if len(graph.deps[i]) > 0 {
+			emitBlockedIfWaiting(emit, i, name, graph.deps[i], done, jobs)
+
+			group.Go(func() error {
+				if !waitDependencies(groupCtx, graph.deps[i], done) {
+					finish(i, Result{Name: name, Status: StatusSkipped, Err: groupCtx.Err(), Duration: time.Since(start)})
+					return nil
 				}
-				recordResult(&mu, &summary, i, result)
-				emit(Event{
-					Type:      EventFinished,
-					JobIndex:  i,
-					JobName:   name,
-					Status:    result.Status,
-					Err:       result.Err,
-					Duration:  result.Duration,
-					Timestamp: time.Now(),
-				})
 
-				return nil
-			}
+				mu.Lock()
+				failedDeps := failedDependencyNames(graph.required[i], summary.Results)
+				mu.Unlock()
 
-			emit(Event{
-				Type:      EventStarted,
-				JobIndex:  i,
-				JobName:   name,
-				Timestamp: time.Now(),
-			})
+				if len(failedDeps) > 0 {
+					finish(i, Result{
+						Name:     name,
+						Status:   StatusSkipped,
+						Err:      fmt.Errorf("%w: %s", ErrDependencyFailed, strings.Join(failedDeps, ", ")),
+						Duration: time.Since(start),
+					})
+
+					return nil
+				}
+
+				if err := sem.Acquire(groupCtx, 1); err != nil {
+					finish(i, Result{Name: name, Status: StatusSkipped, Err: err, Duration: time.Since(start)})
+					return nil
+				}
+
+				run(i, name, currentJob, start)
 
-			err := currentJob.Run(groupCtx)
-			status := resolveStatus(err)
-
-			result := Result{
-				Name:     name,
-				Status:   status,
-				Err:      err,
-				Duration: time.Since(start),
-			}
-
-			recordResult(&mu, &summary, i, result)
-			emit(Event{
-				Type:      EventFinished,
-				JobIndex:  i,
-				JobName:   name,
-				Status:    result.Status,
-				Err:       result.Err,
-				Duration:  result.Duration,
-				Timestamp: time.Now(),
+				return nil
 			})
 
+			continue
+		}
+
+		if err := sem.Acquire(groupCtx, 1); err != nil {
+			finish(i, Result{Name: name, Status: StatusSkipped, Err: err, Duration: time.Since(start)})
+			continue
+		}
+
+		group.Go(func() error {
+			run(i, name, currentJob, start)
+
 			// errgroup の fail-fast を無効化するため、エラーを返さない。
 			return nil
 		})
@@ -234,6 +269,44 @@ func ExecuteWithEvents(ctx context.Context, maxJobs int, jobs []Job, onEvent Eve
 	return summary
 }
 
+// emitBlockedIfWaiting は未完了の依存先がある場合に EventBlocked を通知します。
+func emitBlockedIfWaiting(emit func(Event), index int, name string, deps []int, done []chan struct{}, jobs []Job) {
+	var waiting []string
+
+	for _, dep := range deps {
+		select {
+		case <-done[dep]:
+		default:
+			waiting = append(waiting, normalizeJobName(dep, jobs[dep].Name))
+		}
+	}
+
+	if len(waiting) == 0 {
+		return
+	}
+
+	emit(Event{
+		Type:       EventBlocked,
+		JobIndex:   index,
+		JobName:    name,
+		WaitingFor: waiting,
+		Timestamp:  time.Now(),
+	})
+}
+
+// waitDependencies は依存先がすべて完了するまで待ちます。ctx が終了した場合は false を返します。
+func waitDependencies(ctx context.Context, deps []int, done []chan struct{}) bool {
+	for _, dep := range deps {
+		select {
+		case <-done[dep]:
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	return true
+}
+
 func normalizeMaxJobs(maxJobs int) int {
 	if maxJobs <= 0 {
 		return defaultMaxJobs
@@ -3,6 +3,8 @@ package runner
 import (
 	"context"
 	"errors"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -387,3 +389,201 @@ func TestExecuteWithEvents_EventTimestamp(t *testing.T) {
 		t.Fatalf("Success = %d, want 1", summary.Success)
 	}
 }
+
+func TestExecuteWithEvents_DependsOn(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+
+	record := func(name string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+
+			return err
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		jobs        []Job
+		wantStatus  map[string]ResultStatus
+		wantOrder   []string
+		wantDepSkip []string
+	}{
+		{
+			name: "依存先の完了後に実行",
+			jobs: []Job{
+				{Name: "npm", Run: record("npm", nil), DependsOn: []string{"nvm"}},
+				{Name: "nvm", Run: record("nvm", nil)},
+			},
+			wantStatus: map[string]ResultStatus{"npm": StatusSuccess, "nvm": StatusSuccess},
+			wantOrder:  []string{"nvm", "npm"},
+		},
+		{
+			name: "依存先の失敗は後続へ伝播してスキップ",
+			jobs: []Job{
+				{Name: "secrets", Run: record("secrets", errors.New("unlock failed"))},
+				{Name: "sys", Run: record("sys", nil), DependsOn: []string{"secrets"}},
+				{Name: "repo", Run: record("repo", nil), DependsOn: []string{"sys"}},
+				{Name: "other", Run: record("other", nil)},
+			},
+			wantStatus: map[string]ResultStatus{
+				"secrets": StatusFailed,
+				"sys":     StatusSkipped,
+				"repo":    StatusSkipped,
+				"other":   StatusSuccess,
+			},
+			wantDepSkip: []string{"sys", "repo"},
+		},
+		{
+			name: "未知の依存先は失敗",
+			jobs: []Job{
+				{Name: "npm", Run: record("npm", nil), DependsOn: []string{"missing"}},
+			},
+			wantStatus: map[string]ResultStatus{"npm": StatusFailed},
+		},
+		{
+			name: "循環依存は失敗し、依存するジョブはスキップ",
+			jobs: []Job{
+				{Name: "a", Run: record("a", nil), DependsOn: []string{"b"}},
+				{Name: "b", Run: record("b", nil), DependsOn: []string{"a"}},
+				{Name: "c", Run: record("c", nil), DependsOn: []string{"a"}},
+			},
+			wantStatus:  map[string]ResultStatus{"a": StatusFailed, "b": StatusFailed, "c": StatusSkipped},
+			wantDepSkip: []string{"c"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mu.Lock()
+			order = nil
+			mu.Unlock()
+
+			summary := Execute(context.Background(), 4, tc.jobs)
+
+			results := make(map[string]Result, len(summary.Results))
+			for _, result := range summary.Results {
+				results[result.Name] = result
+			}
+
+			for name, want := range tc.wantStatus {
+				if results[name].Status != want {
+					t.Fatalf("%s status = %s, want %s (err=%v)", name, results[name].Status, want, results[name].Err)
+				}
+			}
+
+			for _, name := range tc.wantDepSkip {
+				if !errors.Is(results[name].Err, ErrDependencyFailed) {
+					t.Fatalf("%s err = %v, want ErrDependencyFailed", name, results[name].Err)
+				}
+			}
+
+			if tc.wantOrder != nil {
+				mu.Lock()
+				got := strings.Join(order, ",")
+				mu.Unlock()
+
+				if want := strings.Join(tc.wantOrder, ","); got != want {
+					t.Fatalf("実行順 = %s, want %s", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteWithEvents_After(t *testing.T) {
+	t.Parallel()
+
+	var running, overlapped atomic.Int32
+
+	exclusive := func(err error) func(context.Context) error {
+		return func(context.Context) error {
+			if running.Add(1) > 1 {
+				overlapped.Store(1)
+			}
+
+			time.Sleep(10 * time.Millisecond)
+			running.Add(-1)
+
+			return err
+		}
+	}
+
+	summary := Execute(context.Background(), 4, []Job{
+		{Name: "apt", Run: exclusive(errors.New("lock failed"))},
+		{Name: "dnf", Run: exclusive(nil), After: []string{"apt"}},
+		{Name: "brew", Run: exclusive(nil), After: []string{"dnf"}},
+		{Name: "go", Run: exclusive(nil), After: []string{"brew"}, DependsOn: []string{"apt"}},
+	})
+
+	results := make(map[string]Result, len(summary.Results))
+	for _, result := range summary.Results {
+		results[result.Name] = result
+	}
+
+	// After は完了を待つだけで失敗を伝播しないが、DependsOn は伝播する
+	if results["dnf"].Status != StatusSuccess || results["brew"].Status != StatusSuccess {
+		t.Fatalf("dnf=%s brew=%s, want success after a failed predecessor", results["dnf"].Status, results["brew"].Status)
+	}
+
+	if !errors.Is(results["go"].Err, ErrDependencyFailed) {
+		t.Fatalf("go err = %v, want ErrDependencyFailed", results["go"].Err)
+	}
+
+	if overlapped.Load() != 0 {
+		t.Fatal("jobs ordered by After ran concurrently")
+	}
+
+	invalid := Execute(context.Background(), 1, []Job{{Name: "a", Run: exclusive(nil), After: []string{"missing"}}})
+	if invalid.Failed != 1 {
+		t.Fatalf("unknown After target: Failed = %d, want 1", invalid.Failed)
+	}
+}
+
+func TestExecuteWithEvents_BlockedEvent(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+
+	var blocked []Event
+
+	summary := ExecuteWithEvents(context.Background(), 2, []Job{
+		{
+			Name: "nvm",
+			Run: func(context.Context) error {
+				<-release
+				return nil
+			},
+		},
+		{
+			Name:      "npm",
+			DependsOn: []string{"nvm"},
+			Run: func(context.Context) error {
+				return nil
+			},
+		},
+	}, func(event Event) {
+		if event.Type == EventBlocked {
+			blocked = append(blocked, event)
+			close(release)
+		}
+	})
+
+	if summary.Success != 2 {
+		t.Fatalf("Success = %d, want 2", summary.Success)
+	}
+
+	if len(blocked) != 1 {
+		t.Fatalf("blocked events = %d, want 1", len(blocked))
+	}
+
+	if blocked[0].JobName != "npm" || strings.Join(blocked[0].WaitingFor, ",") != "nvm" {
+		t.Fatalf("blocked event = %+v, want npm waiting for nvm", blocked[0])
+	}
+}
@@ -22,6 +22,7 @@ type jobState string
 
 const (
 	jobPending jobState = "pending"
+	jobBlocked jobState = "blocked"
 	jobRunning jobState = "running"
 	jobSuccess jobState = "success"
 	jobFailed  jobState = "failed"
@@ -42,6 +43,13 @@ type jobProgress struct {
 	Duration  time.Duration
 	Err       string
 	StartedAt time.Time
+	// WaitingFor は依存先の完了待ちで待機しているジョブ名です。
+	WaitingFor []string
+	// Attempt と MaxAttempts は再試行中のジョブの試行番号と最大試行回数です。
+	Attempt     int
+	MaxAttempts int
+	// LogPath は失敗時に事後調査できるよう表示するジョブの出力ログのパスです。
+	LogPath string
 }
 
 type logEntry struct {
@@ -87,7 +95,7 @@ func RunJobProgress(ctx context.Context, title string, maxJobs int, jobs []runne
 
 // RunJobProgressWithLogger はジョブの実行進捗を Bubble Tea で表示し、
 // オプションでイベントをログファイルに記録します。
-func RunJobProgressWithLogger(ctx context.Context, title string, maxJobs int, jobs []runner.Job, logger *runner.EventLogger) (runner.Summary, error) {
+func RunJobProgressWithLogger(ctx context.Context, title string, maxJobs int, jobs []runner.Job, logger runner.JobLogger) (runner.Summary, error) {
 	m := newModel(title, jobs)
 	program := tea.NewProgram(m, tea.WithContext(ctx))
 	summaryCh := make(chan runner.Summary, 1)
@@ -212,11 +220,24 @@ func (m *model) View() tea.View {
 		builder.WriteString("\n")
 		builder.WriteString(styleSuccess.Render(fmt.Sprintf("完了: 成功 %d / 失敗 %d / スキップ %d", m.summary.Success, m.summary.Failed, m.summary.Skipped)))
 		builder.WriteString("\n")
+		m.renderFailedLogPaths(&builder)
 	}
 
 	return tea.NewView(builder.String())
 }
 
+// renderFailedLogPaths は失敗したジョブの出力ログのパスを完了サマリーの下に表示します。
+func (m *model) renderFailedLogPaths(builder *strings.Builder) {
+	for _, job := range m.jobs {
+		if job.State != jobFailed || job.LogPath == "" {
+			continue
+		}
+
+		builder.WriteString(styleError.Render(fmt.Sprintf("  出力ログ: %s → %s", job.Name, job.LogPath)))
+		builder.WriteString("\n")
+	}
+}
+
 func (m *model) applyEvent(event *runner.Event) {
 	index := m.resolveJobIndex(event.JobIndex, event.JobName)
 	if index < 0 || index >= len(m.jobs) {
@@ -228,12 +249,22 @@ func (m *model) applyEvent(event *runner.Event) {
 	switch event.Type {
 	case runner.EventQueued:
 		job.State = jobPending
+	case runner.EventBlocked:
+		job.State = jobBlocked
+		job.WaitingFor = event.WaitingFor
+		m.appendLog(logInfo, fmt.Sprintf("依存待ち: %s (%s)", event.JobName, strings.Join(event.WaitingFor, ", ")))
 	case runner.EventStarted:
 		job.State = jobRunning
+		job.WaitingFor = nil
 		job.StartedAt = event.Timestamp
 		m.appendLog(logInfo, fmt.Sprintf("開始: %s", event.JobName))
+	case runner.EventRetrying:
+		job.Attempt = event.Attempt
+		job.MaxAttempts = event.MaxAttempts
+		m.appendLog(logWarn, fmt.Sprintf("再試行: %s (%d/%d 回目, %s 後): %v", event.JobName, event.Attempt, event.MaxAttempts, event.Delay.Round(time.Millisecond), event.Err))
 	case runner.EventFinished:
 		job.Duration = event.Duration
+		job.LogPath = event.LogPath
 		m.applyFinishedState(&job, event)
 	}
 
@@ -252,7 +283,11 @@ func (m *model) applyFinishedState(job *jobProgress, event *runner.Event) {
 			job.Err = event.Err.Error()
 		}
 
-		m.appendLog(logError, fmt.Sprintf("失敗: %s (%v)", event.JobName, event.Err))
+		if event.LogPath != "" {
+			m.appendLog(logError, fmt.Sprintf("失敗: %s (%v) 出力ログ: %s", event.JobName, event.Err, event.LogPath))
+		} else {
+			m.appendLog(logError, fmt.Sprintf("失敗: %s (%v)", event.JobName, event.Err))
+		}
 	case runner.StatusSkipped:
 		job.State = jobSkipped
 		if event.Err != nil {
@@ -311,7 +346,7 @@ func summarizeStates(jobs []jobProgress) (success, failed, skipped, running int)
 
 func progressPercent(state jobState, frame int) float64 {
 	switch state {
-	case jobPending:
+	case jobPending, jobBlocked:
 		return 0
 	case jobRunning:
 		phase := frame % 6
@@ -347,7 +382,13 @@ func renderStatus(job *jobProgress) string {
 	switch job.State {
 	case jobPending:
 		return styleMuted.Render("待機中")
+	case jobBlocked:
+		return styleMuted.Render("依存待ち: " + truncate(strings.Join(job.WaitingFor, ", "), 30))
 	case jobRunning:
+		if job.Attempt > 1 {
+			return styleWarn.Render(fmt.Sprintf("実行中 (%d/%d 回目)", job.Attempt, job.MaxAttempts))
+		}
+
 		return styleInfo.Render("実行中")
 	case jobSuccess:
 		return styleSuccess.Render("成功")
@@ -39,6 +39,43 @@ func TestModelApplyEvent(t *testing.T) {
 			wantLogContains:   "失敗: job-1",
 			wantErrorContains: "boom",
 		},
+		{
+			name: "失敗終了で出力ログのパスを表示",
+			events: []runner.Event{
+				{Type: runner.EventFinished, JobIndex: 0, JobName: "job-1", Status: runner.StatusFailed, Err: errors.New("boom"), LogPath: "/tmp/logs/job-1.log", Timestamp: time.Now()},
+			},
+			wantState:         jobFailed,
+			wantLogContains:   "失敗: job-1 (boom) 出力ログ: /tmp/logs/job-1.log",
+			wantErrorContains: "boom",
+		},
+		{
+			name: "依存待ち",
+			events: []runner.Event{
+				{Type: runner.EventQueued, JobIndex: 0, JobName: "job-1", Timestamp: time.Now()},
+				{Type: runner.EventBlocked, JobIndex: 0, JobName: "job-1", WaitingFor: []string{"nvm"}, Timestamp: time.Now()},
+			},
+			wantState:       jobBlocked,
+			wantLogContains: "依存待ち: job-1 (nvm)",
+		},
+		{
+			name: "依存先の失敗でスキップ",
+			events: []runner.Event{
+				{Type: runner.EventBlocked, JobIndex: 0, JobName: "job-1", WaitingFor: []string{"nvm"}, Timestamp: time.Now()},
+				{Type: runner.EventFinished, JobIndex: 0, JobName: "job-1", Status: runner.StatusSkipped, Err: runner.ErrDependencyFailed, Timestamp: time.Now()},
+			},
+			wantState:         jobSkipped,
+			wantLogContains:   "スキップ: job-1",
+			wantErrorContains: "依存ジョブが失敗",
+		},
+		{
+			name: "再試行中は実行中のまま",
+			events: []runner.Event{
+				{Type: runner.EventStarted, JobIndex: 0, JobName: "job-1", Timestamp: time.Now()},
+				{Type: runner.EventRetrying, JobIndex: 0, JobName: "job-1", Err: errors.New("flaky"), Attempt: 2, MaxAttempts: 3, Timestamp: time.Now()},
+			},
+			wantState:       jobRunning,
+			wantLogContains: "再試行: job-1 (2/3 回目",
+		},
 		{
 			name: "スキップ終了",
 			events: []runner.Event{
@@ -233,6 +270,33 @@ func TestAppendLog_Capped(t *testing.T) {
 	}
 }
 
+func TestRenderFailedLogPaths(t *testing.T) {
+	t.Parallel()
+
+	m := newModel("test", []runner.Job{{Name: "apt"}, {Name: "brew"}, {Name: "npm"}})
+
+	events := []runner.Event{
+		{Type: runner.EventFinished, JobIndex: 0, JobName: "apt", Status: runner.StatusSuccess, LogPath: "/tmp/logs/apt.log"},
+		{Type: runner.EventFinished, JobIndex: 1, JobName: "brew", Status: runner.StatusFailed, Err: errors.New("boom"), LogPath: "/tmp/logs/brew.log"},
+		{Type: runner.EventFinished, JobIndex: 2, JobName: "npm", Status: runner.StatusFailed, Err: errors.New("boom")},
+	}
+	for i := range events {
+		m.applyEvent(&events[i])
+	}
+
+	builder := strings.Builder{}
+	m.renderFailedLogPaths(&builder)
+	got := builder.String()
+
+	if !strings.Contains(got, "出力ログ: brew → /tmp/logs/brew.log") {
+		t.Fatalf("rendered = %q, want brew log path", got)
+	}
+
+	if strings.Contains(got, "apt") || strings.Contains(got, "npm") {
+		t.Fatalf("rendered = %q, want only failed jobs with log path", got)
+	}
+}
+
 func TestApplyEvent_WithDuplicateNamesUsesJobIndex(t *testing.T) {
 	t.Parallel()
 
@@ -429,7 +493,9 @@ func TestRenderStatus(t *testing.T) {
 		wantSub string
 	}{
 		{"待機中", &jobProgress{State: jobPending}, "待機中"},
+		{"依存待ち", &jobProgress{State: jobBlocked, WaitingFor: []string{"nvm", "rustup"}}, "依存待ち: nvm, rustup"},
 		{"実行中", &jobProgress{State: jobRunning}, "実行中"},
+		{"再試行中", &jobProgress{State: jobRunning, Attempt: 2, MaxAttempts: 3}, "実行中 (2/3 回目)"},
 		{"成功", &jobProgress{State: jobSuccess}, "成功"},
 		{"スキップ", &jobProgress{State: jobSkipped}, "スキップ"},
 		{"失敗（エラーなし）", &jobProgress{State: jobFailed}, "失敗"},
@@ -461,6 +527,7 @@ func TestProgressPercent_AllStates(t *testing.T) {
 	}{
 		{"失敗は1", jobFailed, 0, 1},
 		{"スキップは1", jobSkipped, 0, 1},
+		{"依存待ちは0", jobBlocked, 3, 0},
 		{"不明stateは0", "unknown", 0, 0},
 		{"実行中frame=0", jobRunning, 0, 0.2},
 		{"実行中frame=5", jobRunning, 5, 0.7},
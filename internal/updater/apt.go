@@ -12,6 +12,8 @@ import (
 
 // AptUpdater は APT パッケージマネージャ (Debian/Ubuntu) の実装です。
 type AptUpdater struct {
+	packageFilterSupport
+
 	useSudo bool
 }
 
@@ -38,6 +40,10 @@ func (a *AptUpdater) Configure(cfg config.ManagerConfig) error {
 		return nil
 	}
 
+	if err := a.configurePackageFilter(cfg); err != nil {
+		return err
+	}
+
 	if useSudo, ok := cfg["use_sudo"].(bool); ok {
 		a.useSudo = useSudo
 		return nil
@@ -67,21 +73,21 @@ func (a *AptUpdater) Check(ctx context.Context) (*CheckResult, error) {
 
 	packages := a.parseUpgradableList(string(output))
 
-	return &CheckResult{
+	return a.filterCheckResult(&CheckResult{
 		AvailableUpdates: len(packages),
 		Packages:         packages,
-	}, nil
+	}), nil
 }
 
 func (a *AptUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
-	result := &UpdateResult{}
-
 	// まず更新確認
 	checkResult, err := a.Check(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	result := &UpdateResult{Held: checkResult.Held}
+
 	if checkResult.AvailableUpdates == 0 {
 		result.Message = allPackagesUpToDateMessage
 		return result, nil
@@ -94,8 +100,8 @@ func (a *AptUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateRes
 		return result, nil
 	}
 
-	// 実際の更新を実行
-	args := []string{upgradeCommand, "-y"}
+	// 実際の更新を実行（hold / ignore で除外がある場合は残りのパッケージのみ更新）
+	args := selectUpdateArgs(checkResult, []string{upgradeCommand, "-y"}, []string{"install", "--only-upgrade", "-y"})
 	if err := a.runCommand(ctx, args...); err != nil {
 		result.Errors = append(result.Errors, err)
 		return result, fmt.Errorf("apt upgrade に失敗: %w", err)
@@ -108,6 +114,29 @@ func (a *AptUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateRes
 	return result, nil
 }
 
+// ListInstalled は dpkg-query でインストール済みパッケージを返します。
+func (a *AptUpdater) ListInstalled(ctx context.Context) ([]PackageInfo, error) {
+	output, err := runCommandOutputWithLocaleC(ctx, "dpkg-query", []string{"-W", "-f", "${Package}\t${Version}\n"}, "dpkg-query の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseTabSeparatedPackages(string(output)), nil
+}
+
+// RollbackCommand は apt install --allow-downgrades で更新前のバージョンを指定して入れ直すコマンドを返します。
+// 旧バージョンがリポジトリやキャッシュに残っていない場合、実行時に失敗します。
+func (a *AptUpdater) RollbackCommand(pkg PackageInfo) (*RollbackCommand, error) {
+	cmd, err := newRollbackCommand(pkg, "apt", func(version string) []string {
+		return []string{"install", "--allow-downgrades", "-y", pkg.Name + "=" + version}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return withSudo(cmd, a.useSudo), nil
+}
+
 // runCommand は apt コマンドを実行します（必要に応じて sudo を使用）
 func (a *AptUpdater) runCommand(ctx context.Context, args ...string) error {
 	var cmd *exec.Cmd
@@ -119,8 +148,7 @@ func (a *AptUpdater) runCommand(ctx context.Context, args ...string) error {
 		cmd = exec.CommandContext(ctx, "apt", args...)
 	}
 
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	attachCommandOutput(ctx, cmd)
 	cmd.Stdin = os.Stdin
 
 	return cmd.Run()
@@ -5,7 +5,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
 	"os/exec"
 	"strings"
 
@@ -14,6 +13,8 @@ import (
 
 // BrewUpdater は Homebrew パッケージマネージャの実装です。
 type BrewUpdater struct {
+	packageFilterSupport
+
 	// cleanup が true の場合、更新後に古いバージョンを削除
 	cleanup bool
 	// greedy が true の場合、auto_updates が有効な Cask も更新対象に含める
@@ -43,6 +44,10 @@ func (b *BrewUpdater) Configure(cfg config.ManagerConfig) error {
 		return nil
 	}
 
+	if err := b.configurePackageFilter(cfg); err != nil {
+		return err
+	}
+
 	if cleanup, ok := cfg["cleanup"].(bool); ok {
 		b.cleanup = cleanup
 	}
@@ -57,8 +62,7 @@ func (b *BrewUpdater) Configure(cfg config.ManagerConfig) error {
 func (b *BrewUpdater) Check(ctx context.Context) (*CheckResult, error) {
 	// brew update でフォーミュラ情報を更新
 	updateCmd := exec.CommandContext(ctx, "brew", updateCommand)
-	updateCmd.Stdout = os.Stdout
-	updateCmd.Stderr = os.Stderr
+	attachCommandOutput(ctx, updateCmd)
 
 	if err := updateCmd.Run(); err != nil {
 		return nil, fmt.Errorf("brew update に失敗: %w", err)
@@ -78,21 +82,21 @@ func (b *BrewUpdater) Check(ctx context.Context) (*CheckResult, error) {
 
 	packages := b.parseOutdatedList(string(output))
 
-	return &CheckResult{
+	return b.filterCheckResult(&CheckResult{
 		AvailableUpdates: len(packages),
 		Packages:         packages,
-	}, nil
+	}), nil
 }
 
 func (b *BrewUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
-	result := &UpdateResult{}
-
 	// まず更新確認
 	checkResult, err := b.Check(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	result := &UpdateResult{Held: checkResult.Held}
+
 	if checkResult.AvailableUpdates == 0 {
 		result.Message = allPackagesUpToDateMessage
 		return result, nil
@@ -105,10 +109,35 @@ func (b *BrewUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateRe
 		return result, nil
 	}
 
+	b.runUpgrade(ctx, checkResult, result)
+	b.runCleanup(ctx, result)
+
+	result.UpdatedCount = checkResult.AvailableUpdates
+	result.Packages = checkResult.Packages
+	result.Message = fmt.Sprintf("%d 件のパッケージを更新しました", result.UpdatedCount)
+
+	return result, nil
+}
+
+// runUpgrade はフォーミュラと Cask を更新します。
+// hold / ignore で除外がある場合は、残りのパッケージ名を指定して1回で更新します。
+func (b *BrewUpdater) runUpgrade(ctx context.Context, checkResult *CheckResult, result *UpdateResult) {
+	if checkResult.hasFilteredPackages() {
+		args := selectUpdateArgs(checkResult, nil, []string{upgradeCommand})
+
+		upgradeCmd := exec.CommandContext(ctx, "brew", args...)
+		attachCommandOutput(ctx, upgradeCmd)
+
+		if err := upgradeCmd.Run(); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("brew upgrade に失敗: %w", err))
+		}
+
+		return
+	}
+
 	// フォーミュラの更新
 	upgradeCmd := exec.CommandContext(ctx, "brew", upgradeCommand)
-	upgradeCmd.Stdout = os.Stdout
-	upgradeCmd.Stderr = os.Stderr
+	attachCommandOutput(ctx, upgradeCmd)
 
 	if err := upgradeCmd.Run(); err != nil {
 		result.Errors = append(result.Errors, fmt.Errorf("brew upgrade に失敗: %w", err))
@@ -121,30 +150,34 @@ func (b *BrewUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateRe
 	}
 
 	caskCmd := exec.CommandContext(ctx, "brew", caskArgs...)
-	caskCmd.Stdout = os.Stdout
-	caskCmd.Stderr = os.Stderr
+	attachCommandOutput(ctx, caskCmd)
 
 	if err := caskCmd.Run(); err != nil {
 		// Cask がない環境もあるため、エラーは警告として記録
 		result.Errors = append(result.Errors, fmt.Errorf("brew upgrade --cask: %w", err))
 	}
+}
 
-	// クリーンアップ
+// runCleanup は cleanup 設定が有効な場合に古いバージョンを削除します。
+func (b *BrewUpdater) runCleanup(ctx context.Context, result *UpdateResult) {
 	if b.cleanup {
 		cleanupCmd := exec.CommandContext(ctx, "brew", "cleanup")
-		cleanupCmd.Stdout = os.Stdout
-		cleanupCmd.Stderr = os.Stderr
+		attachCommandOutput(ctx, cleanupCmd)
 
 		if err := cleanupCmd.Run(); err != nil {
 			result.Errors = append(result.Errors, fmt.Errorf("brew cleanup: %w", err))
 		}
 	}
+}
 
-	result.UpdatedCount = checkResult.AvailableUpdates
-	result.Packages = checkResult.Packages
-	result.Message = fmt.Sprintf("%d 件のパッケージを更新しました", result.UpdatedCount)
+// ListInstalled はインストール済みのフォーミュラと Cask を返します。
+func (b *BrewUpdater) ListInstalled(ctx context.Context) ([]PackageInfo, error) {
+	output, err := runCommandOutputWithLocaleC(ctx, "brew", []string{"list", "--versions"}, "brew list --versions の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
 
-	return result, nil
+	return parseFieldPackages(string(output), false), nil
 }
 
 // parseOutdatedList は "brew outdated --verbose" の出力をパースします
@@ -30,6 +30,8 @@ type bunInteractiveRunner func(context.Context, ...string) error
 
 // BunUpdater は Bun のグローバルパッケージと Bun 本体を更新します。
 type BunUpdater struct {
+	packageFilterSupport
+
 	lookPathStep       func(string) (string, error)
 	detectOwnerStep    func(string) bunInstallOwner
 	fetchReleaseStep   bunReleaseFetcher
@@ -55,8 +57,8 @@ func (b *BunUpdater) IsAvailable() bool {
 	return err == nil
 }
 
-func (b *BunUpdater) Configure(config.ManagerConfig) error {
-	return nil
+func (b *BunUpdater) Configure(cfg config.ManagerConfig) error {
+	return b.configurePackageFilter(cfg)
 }
 
 func (b *BunUpdater) Check(ctx context.Context) (*CheckResult, error) {
@@ -70,10 +72,10 @@ func (b *BunUpdater) Check(ctx context.Context) (*CheckResult, error) {
 		return nil, fmt.Errorf("bun outdated -g の出力解析に失敗: %w", err)
 	}
 
-	return &CheckResult{
+	return b.filterCheckResult(&CheckResult{
 		AvailableUpdates: len(packages),
 		Packages:         packages,
-	}, nil
+	}), nil
 }
 
 func (b *BunUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
@@ -84,6 +86,7 @@ func (b *BunUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateRes
 
 	result := &UpdateResult{
 		Packages: checkResult.Packages,
+		Held:     checkResult.Held,
 	}
 
 	if checkResult.AvailableUpdates == 0 {
@@ -98,7 +101,9 @@ func (b *BunUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateRes
 		return result, nil
 	}
 
-	if err := b.runInteractive(ctx, "update", "-g", "--latest"); err != nil {
+	// hold / ignore で除外がある場合は残りのパッケージのみ更新
+	args := append([]string{"update", "-g", "--latest"}, packageNamesIfFiltered(checkResult)...)
+	if err := b.runInteractive(ctx, args...); err != nil {
 		result.Errors = append(result.Errors, err)
 
 		return result, fmt.Errorf("bun update -g --latest に失敗: %w", err)
@@ -110,6 +115,14 @@ func (b *BunUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateRes
 	return result, nil
 }
 
+// RollbackCommand は bun add -g で更新前のバージョンを入れ直すコマンドを返します。
+// Bun 本体の更新はロールバックの対象外です。
+func (b *BunUpdater) RollbackCommand(pkg PackageInfo) (*RollbackCommand, error) {
+	return newRollbackCommand(pkg, "bun", func(version string) []string {
+		return []string{"add", "-g", pkg.Name + "@" + version}
+	})
+}
+
 func (b *BunUpdater) CheckSelfUpdate(ctx context.Context) (*CheckResult, error) {
 	bunPath, err := b.lookPath("bun")
 	if err != nil {
@@ -428,8 +441,7 @@ func (b *BunUpdater) runInteractive(ctx context.Context, args ...string) error {
 	}
 
 	cmd := exec.CommandContext(ctx, "bun", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	attachCommandOutput(ctx, cmd)
 	cmd.Stdin = os.Stdin
 
 	return cmd.Run()
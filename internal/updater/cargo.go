@@ -17,7 +17,7 @@ import (
 
 // CargoUpdater は cargo (Rust パッケージ) の実装です。
 type CargoUpdater struct {
-	// 将来的な拡張のための設定フィールド
+	packageFilterSupport
 }
 
 // 起動時にレジストリに登録
@@ -38,13 +38,32 @@ func (c *CargoUpdater) IsAvailable() bool {
 	return err == nil
 }
 
+// DependsOn は rustup によるツールチェーン更新の後にツールを再ビルドするための依存を返します。
+func (c *CargoUpdater) DependsOn() []string {
+	return []string{"rustup"}
+}
+
 func (c *CargoUpdater) Configure(cfg config.ManagerConfig) error {
-	// 現時点では設定項目なし
-	return nil
+	return c.configurePackageFilter(cfg)
 }
 
 func (c *CargoUpdater) Check(ctx context.Context) (*CheckResult, error) {
-	// cargo install --list でインストール済みパッケージを取得
+	installed, err := c.ListInstalled(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// cargo は個別の outdated チェックがないため、
+	// AvailableUpdates は 0 とし、インストール済みパッケージのみ返す
+	// 実際の更新可否は update 実行時に判定される
+	result := c.filterCheckResult(&CheckResult{Packages: installed})
+	result.Message = fmt.Sprintf("%d 件のインストール済みパッケージを確認（更新可否は実行時に判定）", len(result.Packages))
+
+	return result, nil
+}
+
+// ListInstalled は "cargo install --list" でインストール済みパッケージを返します。
+func (c *CargoUpdater) ListInstalled(ctx context.Context) ([]PackageInfo, error) {
 	cmd := exec.CommandContext(ctx, "cargo", "install", "--list")
 
 	output, err := cmd.Output()
@@ -52,16 +71,14 @@ func (c *CargoUpdater) Check(ctx context.Context) (*CheckResult, error) {
 		return nil, fmt.Errorf("cargo install --list の実行に失敗: %w", err)
 	}
 
-	packages := c.parseInstallList(string(output))
+	return c.parseInstallList(string(output)), nil
+}
 
-	// cargo は個別の outdated チェックがないため、
-	// AvailableUpdates は 0 とし、インストール済みパッケージのみ返す
-	// 実際の更新可否は update 実行時に判定される
-	return &CheckResult{
-		AvailableUpdates: 0,
-		Packages:         packages,
-		Message:          fmt.Sprintf("%d 件のインストール済みパッケージを確認（更新可否は実行時に判定）", len(packages)),
-	}, nil
+// RollbackCommand は cargo install --version で更新前のバージョンを入れ直すコマンドを返します。
+func (c *CargoUpdater) RollbackCommand(pkg PackageInfo) (*RollbackCommand, error) {
+	return newRollbackCommand(pkg, "cargo", func(version string) []string {
+		return []string{"install", pkg.Name, "--version", version, "--force"}
+	})
 }
 
 func (c *CargoUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
@@ -84,6 +101,24 @@ func (c *CargoUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateR
 		return result, nil
 	}
 
+	// hold / ignore が設定されている場合は、除外後のパッケージのみを個別指定で更新する
+	var targets []string
+
+	if !c.filter.IsEmpty() {
+		checkResult, err := c.Check(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Held = checkResult.Held
+		targets = packageNamesIfFiltered(checkResult)
+
+		if checkResult.hasFilteredPackages() && len(targets) == 0 {
+			result.Message = "hold / ignore により更新対象の cargo パッケージがありません"
+			return result, nil
+		}
+	}
+
 	// cargo-update がなければ自動インストール、フルパスを取得
 	updateBin, err := c.ensureCargoUpdate(ctx)
 	if err != nil {
@@ -93,16 +128,18 @@ func (c *CargoUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateR
 	// フルパスで直接実行（PATH に ~/.cargo/bin がない環境でも動作）
 	var buf bytes.Buffer
 
-	cmd := exec.CommandContext(ctx, updateBin, cargoUpdateArgs(updateBin)...)
-	cmd.Stdout = io.MultiWriter(os.Stdout, &buf)
-	cmd.Stderr = os.Stderr
+	cmd := exec.CommandContext(ctx, updateBin, cargoTargetArgs(cargoUpdateArgs(updateBin), targets)...)
+	attachCommandOutput(ctx, cmd)
+	cmd.Stdout = io.MultiWriter(cmd.Stdout, &buf)
 
 	if err := cmd.Run(); err != nil {
 		result.Errors = append(result.Errors, err)
 		return result, fmt.Errorf("cargo install-update -a に失敗: %w", err)
 	}
 
-	result.UpdatedCount = c.parseUpdateCount(buf.String())
+	// 旧 → 新のバージョンは履歴と sys rollback で使うため、更新前に表示される一覧表から取得する
+	result.Packages = c.parseUpdatedPackages(buf.String())
+	result.UpdatedCount = max(c.parseUpdateCount(buf.String()), len(result.Packages))
 
 	if result.UpdatedCount > 0 {
 		result.Message = fmt.Sprintf("%d 件のパッケージを更新しました", result.UpdatedCount)
@@ -123,8 +160,7 @@ func (c *CargoUpdater) ensureCargoUpdate(ctx context.Context) (string, error) {
 	fmt.Println("ℹ️  cargo-update が見つかりません。自動インストールします...")
 
 	cmd := exec.CommandContext(ctx, "cargo", "install", "cargo-update")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	attachCommandOutput(ctx, cmd)
 
 	if err := cmd.Run(); err != nil {
 		return "", fmt.Errorf("cargo-update のインストールに失敗: %w", err)
@@ -160,6 +196,22 @@ func cargoUpdateArgs(bin string) []string {
 	return []string{"install-update", "-a"}
 }
 
+// cargoTargetArgs は targets が指定された場合、全件更新の "-a" をパッケージ名の列挙に置き換えます。
+func cargoTargetArgs(args, targets []string) []string {
+	if len(targets) == 0 {
+		return args
+	}
+
+	selective := make([]string, 0, len(args)+len(targets))
+	for _, arg := range args {
+		if arg != "-a" {
+			selective = append(selective, arg)
+		}
+	}
+
+	return append(selective, targets...)
+}
+
 // cargoInstallUpdateBinPath は CARGO_HOME/bin の cargo-install-update のパスを返します。
 // PATH に ~/.cargo/bin が含まれていない環境での自動インストール後のフォールバックに使用します。
 func cargoInstallUpdateBinPath() (string, error) {
@@ -227,6 +279,33 @@ func (c *CargoUpdater) parseUpdateCount(output string) int {
 	return 0
 }
 
+// parseUpdatedPackages は "cargo install-update" が更新前に表示する一覧表から、更新が必要なパッケージを返します。
+// 形式（バージョンの "v" は cargo-update のバージョンにより省略されます）:
+// Package         Installed  Latest   Needs update
+// ripgrep         v13.0.0    v14.0.0  Yes
+// bat             v0.24.0    v0.24.0  No
+func (c *CargoUpdater) parseUpdatedPackages(output string) []PackageInfo {
+	output = strings.ReplaceAll(output, "\r\n", "\n")
+	packages := make([]PackageInfo, 0)
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 4 || fields[3] != "Yes" {
+			continue
+		}
+
+		// 未インストールの場合は Installed 列が "No" になるため、バージョン表記の行のみ対象にする
+		current, latest := strings.TrimPrefix(fields[1], "v"), strings.TrimPrefix(fields[2], "v")
+		if !startsWithDigit(current) || !startsWithDigit(latest) {
+			continue
+		}
+
+		packages = append(packages, PackageInfo{Name: fields[0], CurrentVersion: current, NewVersion: latest})
+	}
+
+	return packages
+}
+
 // parseInstallList は "cargo install --list" の出力をパースします
 // 形式:
 // package-name v1.0.0:
@@ -274,3 +353,7 @@ func (c *CargoUpdater) parseInstallList(output string) []PackageInfo {
 
 	return packages
 }
+
+func startsWithDigit(s string) bool {
+	return s != "" && s[0] >= '0' && s[0] <= '9'
+}
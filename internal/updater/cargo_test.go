@@ -115,6 +115,38 @@ func TestCargoUpdater_parseUpdateCount(t *testing.T) {
 	}
 }
 
+func TestCargoUpdater_parseUpdatedPackages(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []PackageInfo
+	}{
+		{name: "空の出力", output: "", want: []PackageInfo{}},
+		{
+			name:   "v20/Yes の行のみ",
+			output: "Package       Installed  Latest   Needs update\nripgrep       v13.0.0    v14.0.0  Yes\nbat           v0.24.0    v0.24.0  No\n\nOverall updated 1 package.\n",
+			want:   []PackageInfo{{Name: "ripgrep", CurrentVersion: "13.0.0", NewVersion: "14.0.0"}},
+		},
+		{
+			name:   "v19/v なし・CRLF改行",
+			output: "  ripgrep  14.0.0  14.1.0  Yes\r\n  bat      0.24.0  0.24.0  No\r\nUpdated 1 package.\r\n",
+			want:   []PackageInfo{{Name: "ripgrep", CurrentVersion: "14.0.0", NewVersion: "14.1.0"}},
+		},
+		{
+			name:   "未インストールの行は除外",
+			output: "Package  Installed  Latest   Needs update\nfoo      No         v1.0.0   Yes\n",
+			want:   []PackageInfo{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &CargoUpdater{}
+			assert.Equal(t, tt.want, c.parseUpdatedPackages(tt.output))
+		})
+	}
+}
+
 func TestCargoUpdater_Check(t *testing.T) {
 	testCases := []struct {
 		name        string
@@ -184,6 +216,7 @@ func TestCargoUpdater_Update(t *testing.T) {
 		errContains string
 		msgContains string
 		wantUpdated int
+		wantPkgs    []PackageInfo
 	}{
 		{
 			name:        "DryRunは更新せず計画表示",
@@ -206,6 +239,10 @@ func TestCargoUpdater_Update(t *testing.T) {
 			wantErr:     false,
 			msgContains: "件のパッケージを更新しました",
 			wantUpdated: 2,
+			wantPkgs: []PackageInfo{
+				{Name: "ripgrep", CurrentVersion: "13.0.0", NewVersion: "14.0.0"},
+				{Name: "bat", CurrentVersion: "0.23.0", NewVersion: "0.24.0"},
+			},
 		},
 		{
 			name:        "更新失敗",
@@ -313,6 +350,10 @@ func TestCargoUpdater_Update(t *testing.T) {
 			if !tc.opts.DryRun {
 				assert.Equal(t, tc.wantUpdated, got.UpdatedCount)
 			}
+
+			if tc.wantPkgs != nil {
+				assert.Equal(t, tc.wantPkgs, got.Packages)
+			}
 		})
 	}
 }
@@ -382,7 +423,7 @@ exit /b 0
 			return
 		}
 
-		updateContent := "@echo off\r\nset mode=%DSX_TEST_CARGO_MODE%\r\nif \"%1\"==\"install-update\" goto doinstallupdate\r\necho invalid args 1>&2\r\nexit /b 1\r\n:doinstallupdate\r\nif \"%2\"==\"-a\" goto doupdate\r\necho invalid args 1>&2\r\nexit /b 1\r\n:doupdate\r\nif \"%mode%\"==\"update_error\" (\r\n  echo cargo install-update failed 1>&2\r\n  exit /b 1\r\n)\r\nif \"%mode%\"==\"updates\" (\r\n  echo Overall updated 2 packages.\r\n)\r\nexit /b 0\r\n"
+		updateContent := "@echo off\r\nset mode=%DSX_TEST_CARGO_MODE%\r\nif \"%1\"==\"install-update\" goto doinstallupdate\r\necho invalid args 1>&2\r\nexit /b 1\r\n:doinstallupdate\r\nif \"%2\"==\"-a\" goto doupdate\r\necho invalid args 1>&2\r\nexit /b 1\r\n:doupdate\r\nif \"%mode%\"==\"update_error\" (\r\n  echo cargo install-update failed 1>&2\r\n  exit /b 1\r\n)\r\nif \"%mode%\"==\"updates\" (\r\n  echo Package  Installed  Latest   Needs update\r\n  echo ripgrep  v13.0.0    v14.0.0  Yes\r\n  echo bat      v0.23.0    v0.24.0  Yes\r\n  echo fd-find  v9.0.0     v9.0.0   No\r\n  echo Overall updated 2 packages.\r\n)\r\nexit /b 0\r\n"
 
 		updatePath := filepath.Join(dir, "cargo-install-update.cmd")
 		if err := os.WriteFile(updatePath, []byte(updateContent), 0o755); err != nil {
@@ -459,7 +500,7 @@ esac
 			return
 		}
 
-		updateContent := "#!/bin/sh\nmode=\"${DSX_TEST_CARGO_MODE}\"\ncase \"$1\" in\n  install-update)\n    case \"$2\" in\n      -a)\n        if [ \"${mode}\" = \"update_error\" ]; then\n          echo \"cargo install-update failed\" 1>&2\n          exit 1\n        fi\n        if [ \"${mode}\" = \"updates\" ]; then\n          echo \"Overall updated 2 packages.\"\n        fi\n        exit 0\n        ;;\n      *)\n        echo \"invalid args\" 1>&2\n        exit 1\n        ;;\n    esac\n    ;;\n  *)\n    echo \"invalid args\" 1>&2\n    exit 1\n    ;;\nesac\n"
+		updateContent := "#!/bin/sh\nmode=\"${DSX_TEST_CARGO_MODE}\"\ncase \"$1\" in\n  install-update)\n    case \"$2\" in\n      -a)\n        if [ \"${mode}\" = \"update_error\" ]; then\n          echo \"cargo install-update failed\" 1>&2\n          exit 1\n        fi\n        if [ \"${mode}\" = \"updates\" ]; then\n          echo \"Package  Installed  Latest   Needs update\"\n          echo \"ripgrep  v13.0.0    v14.0.0  Yes\"\n          echo \"bat      v0.23.0    v0.24.0  Yes\"\n          echo \"fd-find  v9.0.0     v9.0.0   No\"\n          echo \"Overall updated 2 packages.\"\n        fi\n        exit 0\n        ;;\n      *)\n        echo \"invalid args\" 1>&2\n        exit 1\n        ;;\n    esac\n    ;;\n  *)\n    echo \"invalid args\" 1>&2\n    exit 1\n    ;;\nesac\n"
 
 		updatePath := filepath.Join(dir, "cargo-install-update")
 		if err := os.WriteFile(updatePath, []byte(updateContent), 0o755); err != nil {
@@ -2,10 +2,33 @@ package updater
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"os"
+	"os/exec"
 	"strings"
+
+	"github.com/scottlz0310/dsx/internal/runner"
 )
 
+// attachCommandOutput はコマンドの標準出力・標準エラーを端末に接続します。
+// runner のジョブ出力ログが ctx に設定されている場合は、実行コマンドラインとともにログにも書き込みます。
+func attachCommandOutput(ctx context.Context, cmd *exec.Cmd) {
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	output := runner.JobOutput(ctx)
+	if output == nil {
+		return
+	}
+
+	fmt.Fprintf(output, "$ %s\n", strings.Join(cmd.Args, " "))
+
+	cmd.Stdout = io.MultiWriter(os.Stdout, output)
+	cmd.Stderr = io.MultiWriter(os.Stderr, output)
+}
+
 // buildCommandOutputErr はコマンドエラーに出力内容を付加します。
 func buildCommandOutputErr(baseErr error, output []byte) error {
 	trimmed := strings.TrimSpace(string(output))
@@ -0,0 +1,36 @@
+package updater
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/scottlz0310/dsx/internal/runner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttachCommandOutput(t *testing.T) {
+	t.Run("ジョブ出力ログがなければ端末にのみ接続", func(t *testing.T) {
+		cmd := exec.CommandContext(context.Background(), "brew", "upgrade")
+
+		attachCommandOutput(context.Background(), cmd)
+
+		assert.Equal(t, os.Stdout, cmd.Stdout)
+		assert.Equal(t, os.Stderr, cmd.Stderr)
+	})
+
+	t.Run("ジョブ出力ログにコマンドラインと出力を書き込む", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		ctx := runner.WithJobOutput(context.Background(), &buf)
+		cmd := exec.CommandContext(ctx, "brew", "upgrade")
+
+		attachCommandOutput(ctx, cmd)
+
+		_, err := cmd.Stderr.Write([]byte("Error: brew failed\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, "$ brew upgrade\nError: brew failed\n", buf.String())
+	})
+}
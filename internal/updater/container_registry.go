@@ -0,0 +1,253 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	dockerHubRegistry      = "registry-1.docker.io"
+	containerDefaultTag    = "latest"
+	containerShortDigest   = 12
+	registryRequestTimeout = 30 * time.Second
+)
+
+// registryManifestAcceptTypes は manifest list / OCI index を含めて受け付けるメディアタイプです。
+// マルチアーキテクチャのイメージでは、pull 時に RepoDigests へ記録される index のダイジェストと比較するために必要です。
+var registryManifestAcceptTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+var containerRegistryHTTPClient = &http.Client{Timeout: registryRequestTimeout}
+
+// imageReference はタグ付きイメージ参照をレジストリ・リポジトリ・タグに分解したものです。
+type imageReference struct {
+	Registry   string
+	Repository string
+	Tag        string
+}
+
+// parseImageReference は "postgres:16" や "ghcr.io/owner/app:1" をレジストリ API 用に分解します。
+// ダイジェスト固定（"@sha256:..."）のイメージは更新対象外のため ok=false を返します。
+func parseImageReference(image string) (ref imageReference, ok bool) {
+	image = strings.TrimSpace(image)
+	if image == "" || strings.Contains(image, "@") {
+		return imageReference{}, false
+	}
+
+	name, tag := image, containerDefaultTag
+	if idx := strings.LastIndex(image, ":"); idx > strings.LastIndex(image, "/") {
+		name, tag = image[:idx], image[idx+1:]
+	}
+
+	registry, repository := dockerHubRegistry, name
+
+	if first, rest, found := strings.Cut(name, "/"); found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		registry, repository = first, rest
+	}
+
+	if registry == "docker.io" || registry == "index.docker.io" {
+		registry = dockerHubRegistry
+	}
+
+	// Docker Hub の公式イメージは library/ 配下
+	if registry == dockerHubRegistry && !strings.Contains(repository, "/") {
+		repository = "library/" + repository
+	}
+
+	if repository == "" || tag == "" {
+		return imageReference{}, false
+	}
+
+	return imageReference{Registry: registry, Repository: repository, Tag: tag}, true
+}
+
+// fetchRemoteDigest はレジストリの manifest API（HEAD）から現在のダイジェストを取得します。
+// 401 の場合は WWW-Authenticate の Bearer チャレンジに従って匿名トークンを取得し、再試行します。
+func fetchRemoteDigest(ctx context.Context, ref imageReference) (string, error) {
+	manifestURL := fmt.Sprintf("%s://%s/v2/%s/manifests/%s", registryScheme(ref.Registry), ref.Registry, ref.Repository, ref.Tag)
+
+	resp, err := headManifest(ctx, manifestURL, "")
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		token, tokenErr := fetchRegistryToken(ctx, resp.Header.Get("WWW-Authenticate"))
+		if tokenErr != nil {
+			return "", tokenErr
+		}
+
+		resp, err = headManifest(ctx, manifestURL, token)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("レジストリが HTTP %d を返しました", resp.StatusCode)
+	}
+
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", fmt.Errorf("レジストリの応答にダイジェストがありません")
+	}
+
+	return digest, nil
+}
+
+func headManifest(ctx context.Context, manifestURL, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("レジストリへのリクエスト作成に失敗: %w", err)
+	}
+
+	req.Header.Set("Accept", strings.Join(registryManifestAcceptTypes, ", "))
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := containerRegistryHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("レジストリへの接続に失敗: %w", err)
+	}
+
+	resp.Body.Close()
+
+	return resp, nil
+}
+
+// fetchRegistryToken は Bearer チャレンジ（realm / service / scope）から匿名のアクセストークンを取得します。
+func fetchRegistryToken(ctx context.Context, challenge string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", fmt.Errorf("レジストリの認証方式に対応していません: %q", challenge)
+	}
+
+	values := parseAuthChallengeParams(params)
+
+	realm := values["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("レジストリの認証チャレンジに realm がありません")
+	}
+
+	tokenURL, err := url.Parse(realm)
+	if err != nil {
+		return "", fmt.Errorf("トークン取得 URL が不正です: %w", err)
+	}
+
+	query := tokenURL.Query()
+
+	for _, key := range []string{"service", "scope"} {
+		if values[key] != "" {
+			query.Set(key, values[key])
+		}
+	}
+
+	tokenURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), http.NoBody)
+	if err != nil {
+		return "", fmt.Errorf("トークン取得リクエストの作成に失敗: %w", err)
+	}
+
+	resp, err := containerRegistryHTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("トークンの取得に失敗: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("トークンの取得に失敗: HTTP %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("トークン応答の解析に失敗: %w", err)
+	}
+
+	if body.Token != "" {
+		return body.Token, nil
+	}
+
+	if body.AccessToken != "" {
+		return body.AccessToken, nil
+	}
+
+	return "", fmt.Errorf("トークン応答にトークンがありません")
+}
+
+// parseAuthChallengeParams は `realm="...",service="...",scope="..."` をキーと値に分解します。
+func parseAuthChallengeParams(params string) map[string]string {
+	values := make(map[string]string)
+
+	for params != "" {
+		key, rest, found := strings.Cut(params, "=")
+		if !found {
+			break
+		}
+
+		key = strings.ToLower(strings.TrimSpace(key))
+
+		var value string
+
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+			rest = "," + rest
+		}
+
+		values[key] = value
+		params = strings.TrimLeft(rest, ", ")
+	}
+
+	return values
+}
+
+// registryScheme は Docker と同じく、ループバックのレジストリのみ HTTP で接続します。
+func registryScheme(registry string) string {
+	host := registry
+	if h, _, err := net.SplitHostPort(registry); err == nil {
+		host = h
+	}
+
+	if host == "localhost" {
+		return "http"
+	}
+
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return "http"
+	}
+
+	return "https"
+}
+
+// shortDigest は "sha256:0123..." を先頭 12 文字の "0123456789ab" に短縮します。
+func shortDigest(digest string) string {
+	_, hex, found := strings.Cut(digest, ":")
+	if !found {
+		hex = digest
+	}
+
+	return hex[:min(len(hex), containerShortDigest)]
+}
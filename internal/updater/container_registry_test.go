@@ -0,0 +1,185 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImageReference(t *testing.T) {
+	tests := []struct {
+		name   string
+		image  string
+		want   imageReference
+		wantOK bool
+	}{
+		{
+			name:   "Docker Hub 公式イメージ",
+			image:  "postgres:16",
+			want:   imageReference{Registry: dockerHubRegistry, Repository: "library/postgres", Tag: "16"},
+			wantOK: true,
+		},
+		{
+			name:   "タグ省略時は latest",
+			image:  "redis",
+			want:   imageReference{Registry: dockerHubRegistry, Repository: "library/redis", Tag: "latest"},
+			wantOK: true,
+		},
+		{
+			name:   "Docker Hub のユーザーイメージ",
+			image:  "docker.io/bitnami/redis:7.2",
+			want:   imageReference{Registry: dockerHubRegistry, Repository: "bitnami/redis", Tag: "7.2"},
+			wantOK: true,
+		},
+		{
+			name:   "他のレジストリ",
+			image:  "mcr.microsoft.com/devcontainers/base:ubuntu",
+			want:   imageReference{Registry: "mcr.microsoft.com", Repository: "devcontainers/base", Tag: "ubuntu"},
+			wantOK: true,
+		},
+		{
+			name:   "ポート付きのローカルレジストリ",
+			image:  "localhost:5000/dev/app",
+			want:   imageReference{Registry: "localhost:5000", Repository: "dev/app", Tag: "latest"},
+			wantOK: true,
+		},
+		{name: "ダイジェスト固定は対象外", image: "postgres@sha256:0123456789abcdef", wantOK: false},
+		{name: "空文字", image: " ", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseImageReference(tt.image)
+			assert.Equal(t, tt.wantOK, ok)
+
+			if tt.wantOK {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseAuthChallengeParams(t *testing.T) {
+	got := parseAuthChallengeParams(`realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/postgres:pull"`)
+
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.docker.io/token",
+		"service": "registry.docker.io",
+		"scope":   "repository:library/postgres:pull",
+	}, got)
+
+	assert.Equal(t, map[string]string{"realm": "https://example.com/token", "error": "invalid_token"},
+		parseAuthChallengeParams(`realm="https://example.com/token", error=invalid_token`))
+}
+
+func TestRegistryScheme(t *testing.T) {
+	tests := []struct {
+		registry string
+		want     string
+	}{
+		{registry: dockerHubRegistry, want: "https"},
+		{registry: "ghcr.io", want: "https"},
+		{registry: "localhost:5000", want: "http"},
+		{registry: "127.0.0.1:5000", want: "http"},
+		{registry: "[::1]:5000", want: "http"},
+		{registry: "192.168.1.10:5000", want: "https"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.registry, func(t *testing.T) {
+			assert.Equal(t, tt.want, registryScheme(tt.registry))
+		})
+	}
+}
+
+func TestShortDigest(t *testing.T) {
+	assert.Equal(t, "0123456789ab", shortDigest("sha256:0123456789abcdef0123456789abcdef"))
+	assert.Equal(t, "abc", shortDigest("sha256:abc"))
+	assert.Equal(t, "0123456789ab", shortDigest("0123456789abcdef"))
+}
+
+func TestFetchRemoteDigest(t *testing.T) {
+	registry := newFakeRegistry(t, map[string]string{
+		"dev/postgres:16": "sha256:1111111111111111111111111111111111111111111111111111111111111111",
+	})
+
+	t.Run("Bearer トークンを取得してダイジェストを返す", func(t *testing.T) {
+		ref, ok := parseImageReference(registry.host + "/dev/postgres:16")
+		require.True(t, ok)
+
+		digest, err := fetchRemoteDigest(context.Background(), ref)
+		require.NoError(t, err)
+		assert.Equal(t, "sha256:1111111111111111111111111111111111111111111111111111111111111111", digest)
+	})
+
+	t.Run("存在しないタグはエラー", func(t *testing.T) {
+		ref, ok := parseImageReference(registry.host + "/dev/postgres:99")
+		require.True(t, ok)
+
+		_, err := fetchRemoteDigest(context.Background(), ref)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "HTTP 404")
+	})
+}
+
+// fakeRegistry はローカルレジストリの代わりに manifest API とトークン API を提供する httptest サーバーです。
+type fakeRegistry struct {
+	host    string
+	digests map[string]string
+}
+
+// newFakeRegistry は "repository:tag" → ダイジェストを返すレジストリを起動します。
+// manifest API は Docker Hub と同じく、トークンなしの要求に Bearer チャレンジ付きの 401 を返します。
+func newFakeRegistry(t *testing.T, digests map[string]string) *fakeRegistry {
+	t.Helper()
+
+	const token = "dsx-test-token"
+
+	registry := &fakeRegistry{digests: digests}
+
+	var server *httptest.Server
+
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if !strings.HasPrefix(r.URL.Query().Get("scope"), "repository:") {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]string{"token": token})
+
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="fake-registry",scope="repository:%s:pull"`,
+				server.URL, strings.Split(strings.TrimPrefix(r.URL.Path, "/v2/"), "/manifests/")[0]))
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		repository, tag, found := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v2/"), "/manifests/")
+		digest, exists := registry.digests[repository+":"+tag]
+
+		if r.Method != http.MethodHead || !found || !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Docker-Content-Digest", digest)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	registry.host = strings.TrimPrefix(server.URL, "http://")
+
+	return registry
+}
@@ -0,0 +1,341 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/scottlz0310/dsx/internal/config"
+)
+
+const containersTargetsEmptyMessage = "更新対象のイメージがありません（sys.managers.containers.images または all: true を設定してください）"
+
+// ContainersUpdater はコンテナイメージ（docker / podman）の実装です。
+// ローカルイメージの RepoDigests とレジストリ上の現在のダイジェストを比較し、新しいダイジェストがあれば pull します。
+type ContainersUpdater struct {
+	packageFilterSupport
+
+	// images は更新対象のイメージ（"postgres:16" など）です。
+	images []string
+	// all は true の場合、タグ付きのローカルイメージをすべて対象にします。
+	all bool
+	// prune は true の場合、pull 後に dangling イメージを削除します。
+	prune bool
+}
+
+// 起動時にレジストリに登録
+func init() {
+	Register(&ContainersUpdater{})
+}
+
+func (c *ContainersUpdater) Name() string {
+	return "containers"
+}
+
+func (c *ContainersUpdater) DisplayName() string {
+	return "コンテナイメージ (docker / podman)"
+}
+
+func (c *ContainersUpdater) IsAvailable() bool {
+	return c.command() != ""
+}
+
+func (c *ContainersUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	if err := c.configurePackageFilter(cfg); err != nil {
+		return err
+	}
+
+	images, err := cfg.StringList("images")
+	if err != nil {
+		return err
+	}
+
+	c.images = images
+
+	if all, ok := cfg["all"].(bool); ok {
+		c.all = all
+	}
+
+	if prune, ok := cfg["prune"].(bool); ok {
+		c.prune = prune
+	}
+
+	return nil
+}
+
+func (c *ContainersUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	if len(c.images) == 0 && !c.all {
+		return &CheckResult{Packages: []PackageInfo{}, Message: containersTargetsEmptyMessage}, nil
+	}
+
+	command := c.command()
+	if command == "" {
+		return nil, fmt.Errorf("docker / podman が見つかりません")
+	}
+
+	images, err := c.targetImages(ctx, command)
+	if err != nil {
+		return nil, err
+	}
+
+	packages := make([]PackageInfo, 0, len(images))
+
+	var (
+		unchecked []string
+		errs      []error
+	)
+
+	for _, image := range images {
+		pkg, outdated, checkErr := c.checkImage(ctx, command, image)
+		if checkErr != nil {
+			unchecked = append(unchecked, image)
+			errs = append(errs, fmt.Errorf("%s: %w", image, checkErr))
+
+			continue
+		}
+
+		if outdated {
+			packages = append(packages, pkg)
+		}
+	}
+
+	// 認証・ネットワークの問題などですべて確認できなかった場合は、更新なしと区別できるようエラーにする
+	if len(images) > 0 && len(errs) == len(images) {
+		return nil, fmt.Errorf("すべてのイメージをレジストリで確認できませんでした: %w", errors.Join(errs...))
+	}
+
+	result := c.filterCheckResult(&CheckResult{
+		AvailableUpdates: len(packages),
+		Packages:         packages,
+	})
+
+	if len(unchecked) > 0 {
+		result.Message = fmt.Sprintf("レジストリで確認できなかったイメージ: %s", strings.Join(unchecked, ", "))
+		result.Errors = errs
+	}
+
+	return result, nil
+}
+
+func (c *ContainersUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	if len(c.images) == 0 && !c.all {
+		return &UpdateResult{Message: containersTargetsEmptyMessage}, nil
+	}
+
+	// まず更新確認
+	checkResult, err := c.Check(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UpdateResult{Held: checkResult.Held}
+
+	if checkResult.AvailableUpdates == 0 {
+		result.Message = "すべてのイメージは最新です"
+		return result, nil
+	}
+
+	if opts.DryRun {
+		result.Message = fmt.Sprintf("%d 件のイメージが更新可能です（DryRunモード）", checkResult.AvailableUpdates)
+		result.Packages = checkResult.Packages
+
+		return result, nil
+	}
+
+	command := c.command()
+
+	// 各イメージを順番に pull（1件の失敗で他のイメージの更新は止めない）
+	for _, pkg := range checkResult.Packages {
+		if err := c.runCommand(ctx, command, "pull", pkg.Name); err != nil {
+			result.FailedCount++
+			result.Errors = append(result.Errors, fmt.Errorf("%s: %w", pkg.Name, err))
+
+			continue
+		}
+
+		result.UpdatedCount++
+		result.Packages = append(result.Packages, pkg)
+	}
+
+	if result.FailedCount > 0 {
+		result.Message = fmt.Sprintf("%d 件更新、%d 件失敗", result.UpdatedCount, result.FailedCount)
+	} else {
+		result.Message = fmt.Sprintf("%d 件のイメージを更新しました", result.UpdatedCount)
+	}
+
+	if c.prune && result.UpdatedCount > 0 {
+		// pull 自体は成功しているため、dangling イメージの削除に失敗しても更新失敗にはしない
+		if err := c.runCommand(ctx, command, "image", "prune", "-f"); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("%s image prune に失敗: %w", command, err))
+			result.Message += "（古いイメージの削除に失敗しました）"
+		}
+	}
+
+	return result, nil
+}
+
+// ListInstalled はタグ付きのローカルイメージを短縮ダイジェストとともに返します。
+func (c *ContainersUpdater) ListInstalled(ctx context.Context) ([]PackageInfo, error) {
+	command := c.command()
+	if command == "" {
+		return nil, fmt.Errorf("docker / podman が見つかりません")
+	}
+
+	images, err := c.listTaggedImages(ctx, command)
+	if err != nil {
+		return nil, err
+	}
+
+	packages := make([]PackageInfo, 0, len(images))
+
+	for _, image := range images {
+		digests, _ := c.localDigests(ctx, command, image)
+
+		pkg := PackageInfo{Name: image}
+		if len(digests) > 0 {
+			pkg.CurrentVersion = shortDigest(digests[0])
+		}
+
+		packages = append(packages, pkg)
+	}
+
+	return packages, nil
+}
+
+// command は使用するコマンド（docker 優先、なければ podman）を返します。どちらもなければ空文字です。
+func (c *ContainersUpdater) command() string {
+	for _, name := range []string{"docker", "podman"} {
+		if _, err := exec.LookPath(name); err == nil {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// targetImages は設定の images と（all: true の場合）タグ付きのローカルイメージを重複なく返します。
+func (c *ContainersUpdater) targetImages(ctx context.Context, command string) ([]string, error) {
+	images := append([]string{}, c.images...)
+
+	if c.all {
+		local, err := c.listTaggedImages(ctx, command)
+		if err != nil {
+			return nil, err
+		}
+
+		images = append(images, local...)
+	}
+
+	seen := make(map[string]bool, len(images))
+	unique := make([]string, 0, len(images))
+
+	for _, image := range images {
+		if seen[image] {
+			continue
+		}
+
+		seen[image] = true
+		unique = append(unique, image)
+	}
+
+	return unique, nil
+}
+
+// checkImage はローカルとレジストリのダイジェストを比較します。
+// ローカルにない設定済みイメージは、現在のバージョンを空として pull 対象にします。
+func (c *ContainersUpdater) checkImage(ctx context.Context, command, image string) (pkg PackageInfo, outdated bool, err error) {
+	ref, ok := parseImageReference(image)
+	if !ok {
+		// ダイジェスト固定のイメージは更新しない
+		return PackageInfo{}, false, nil
+	}
+
+	remote, err := fetchRemoteDigest(ctx, ref)
+	if err != nil {
+		return PackageInfo{}, false, err
+	}
+
+	pkg = PackageInfo{Name: image, NewVersion: shortDigest(remote)}
+
+	local, err := c.localDigests(ctx, command, image)
+	if err != nil {
+		return pkg, true, nil
+	}
+
+	if len(local) == 0 {
+		// ローカルでビルドしたイメージなどレジストリ由来でないものは対象外
+		return PackageInfo{}, false, nil
+	}
+
+	for _, digest := range local {
+		if digest == remote {
+			return PackageInfo{}, false, nil
+		}
+	}
+
+	pkg.CurrentVersion = shortDigest(local[0])
+
+	return pkg, true, nil
+}
+
+// localDigests は image inspect の RepoDigests（"repo@sha256:..."）からダイジェストを返します。
+func (c *ContainersUpdater) localDigests(ctx context.Context, command, image string) ([]string, error) {
+	output, err := runCommandOutputWithLocaleC(ctx, command, []string{"image", "inspect", "--format", "{{json .RepoDigests}}", image},
+		command+" image inspect の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	var repoDigests []string
+	if err := json.Unmarshal([]byte(strings.TrimSpace(string(output))), &repoDigests); err != nil {
+		return nil, fmt.Errorf("%s image inspect の出力の解析に失敗: %w", command, err)
+	}
+
+	digests := make([]string, 0, len(repoDigests))
+
+	for _, repoDigest := range repoDigests {
+		if _, digest, found := strings.Cut(repoDigest, "@"); found {
+			digests = append(digests, digest)
+		}
+	}
+
+	return digests, nil
+}
+
+// listTaggedImages は "<none>" を除いたタグ付きのローカルイメージを返します。
+func (c *ContainersUpdater) listTaggedImages(ctx context.Context, command string) ([]string, error) {
+	output, err := runCommandOutputWithLocaleC(ctx, command, []string{"images", "--format", "{{.Repository}}:{{.Tag}}"},
+		command+" images の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	var images []string
+
+	for _, line := range strings.Split(string(output), "\n") {
+		image := strings.TrimSpace(line)
+		if image == "" || strings.Contains(image, "<none>") {
+			continue
+		}
+
+		images = append(images, image)
+	}
+
+	return images, nil
+}
+
+func (c *ContainersUpdater) runCommand(ctx context.Context, command string, args ...string) error {
+	cmd := exec.CommandContext(ctx, command, args...)
+	attachCommandOutput(ctx, cmd)
+	cmd.Stdin = os.Stdin
+
+	return cmd.Run()
+}
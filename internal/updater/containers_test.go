@@ -0,0 +1,354 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	containerTestDigestOld = "sha256:aaaaaaaaaaaa0000000000000000000000000000000000000000000000000000"
+	containerTestDigestNew = "sha256:bbbbbbbbbbbb0000000000000000000000000000000000000000000000000000"
+	containerTestDigestCur = "sha256:cccccccccccc0000000000000000000000000000000000000000000000000000"
+)
+
+func TestContainersUpdater_Configure(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        config.ManagerConfig
+		wantImages []string
+		wantAll    bool
+		wantPrune  bool
+		wantErr    bool
+	}{
+		{name: "nilの設定はデフォルトのまま", cfg: nil},
+		{
+			name:       "images / all / prune",
+			cfg:        config.ManagerConfig{"images": []interface{}{"postgres:16", "redis:7"}, "all": true, "prune": true},
+			wantImages: []string{"postgres:16", "redis:7"},
+			wantAll:    true,
+			wantPrune:  true,
+		},
+		{name: "文字列以外のimagesはエラー", cfg: config.ManagerConfig{"images": []interface{}{"postgres:16", 1}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &ContainersUpdater{}
+
+			err := c.Configure(tt.cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantImages, c.images)
+			assert.Equal(t, tt.wantAll, c.all)
+			assert.Equal(t, tt.wantPrune, c.prune)
+		})
+	}
+}
+
+func TestContainersUpdater_Check(t *testing.T) {
+	registry := newFakeRegistry(t, map[string]string{
+		"dev/postgres:16":  containerTestDigestNew,
+		"dev/redis:7":      containerTestDigestCur,
+		"dev/devbase:main": containerTestDigestNew,
+		"dev/devbase:next": containerTestDigestNew,
+	})
+
+	postgres := registry.host + "/dev/postgres:16"
+	redis := registry.host + "/dev/redis:7"
+	missing := registry.host + "/dev/missing:1"
+
+	testCases := []struct {
+		name        string
+		cfg         config.ManagerConfig
+		want        []PackageInfo
+		msgContains string
+		wantErrs    int
+		wantErr     string
+	}{
+		{
+			name: "設定したイメージのダイジェストを比較",
+			cfg:  config.ManagerConfig{"images": []interface{}{postgres, redis}},
+			want: []PackageInfo{{Name: postgres, CurrentVersion: "aaaaaaaaaaaa", NewVersion: "bbbbbbbbbbbb"}},
+		},
+		{
+			// devbase は RepoDigests がない（ローカルでビルドした）ため対象外
+			name: "all: true はタグ付きのローカルイメージを対象にする",
+			cfg:  config.ManagerConfig{"all": true},
+			want: []PackageInfo{{Name: postgres, CurrentVersion: "aaaaaaaaaaaa", NewVersion: "bbbbbbbbbbbb"}},
+		},
+		{
+			name: "ローカルにない設定済みイメージは pull 対象",
+			cfg:  config.ManagerConfig{"images": []interface{}{registry.host + "/dev/devbase:next"}},
+			want: []PackageInfo{{Name: registry.host + "/dev/devbase:next", NewVersion: "bbbbbbbbbbbb"}},
+		},
+		{
+			name:        "レジストリで見つからないイメージは確認失敗として報告",
+			cfg:         config.ManagerConfig{"images": []interface{}{missing, postgres}},
+			want:        []PackageInfo{{Name: postgres, CurrentVersion: "aaaaaaaaaaaa", NewVersion: "bbbbbbbbbbbb"}},
+			msgContains: missing,
+			wantErrs:    1,
+		},
+		{
+			name:    "すべて確認できなければエラー",
+			cfg:     config.ManagerConfig{"images": []interface{}{missing}},
+			wantErr: "すべてのイメージをレジストリで確認できませんでした",
+		},
+		{
+			name:        "対象未設定",
+			cfg:         config.ManagerConfig{},
+			want:        []PackageInfo{},
+			msgContains: containersTargetsEmptyMessage,
+		},
+		{
+			name: "hold したイメージは保留",
+			cfg:  config.ManagerConfig{"images": []interface{}{postgres}, "hold": []interface{}{"*/dev/postgres:*"}},
+			want: []PackageInfo{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			setupFakeContainerRuntime(t, "docker", registry.host, "")
+
+			c := &ContainersUpdater{}
+			require.NoError(t, c.Configure(tc.cfg))
+
+			got, err := c.Check(context.Background())
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.want, got.Packages)
+			assert.Equal(t, len(tc.want), got.AvailableUpdates)
+			assert.Len(t, got.Errors, tc.wantErrs)
+
+			if tc.msgContains != "" {
+				assert.Contains(t, got.Message, tc.msgContains)
+			}
+		})
+	}
+}
+
+func TestContainersUpdater_Update(t *testing.T) {
+	registry := newFakeRegistry(t, map[string]string{
+		"dev/postgres:16": containerTestDigestNew,
+		"dev/redis:7":     containerTestDigestNew,
+	})
+
+	postgres := registry.host + "/dev/postgres:16"
+	redis := registry.host + "/dev/redis:7"
+
+	testCases := []struct {
+		name        string
+		runtime     string
+		mode        string
+		cfg         config.ManagerConfig
+		opts        UpdateOptions
+		wantUpdated int
+		wantFailed  int
+		wantCalls   []string
+		msgContains string
+	}{
+		{
+			name:        "対象未設定",
+			runtime:     "docker",
+			cfg:         config.ManagerConfig{},
+			msgContains: containersTargetsEmptyMessage,
+		},
+		{
+			name:        "DryRunは pull しない",
+			runtime:     "docker",
+			cfg:         config.ManagerConfig{"images": []interface{}{postgres}},
+			opts:        UpdateOptions{DryRun: true},
+			msgContains: "DryRunモード",
+		},
+		{
+			name:        "新しいダイジェストを pull して prune",
+			runtime:     "docker",
+			cfg:         config.ManagerConfig{"images": []interface{}{postgres, redis}, "prune": true},
+			wantUpdated: 2,
+			wantCalls:   []string{"docker pull " + postgres, "docker pull " + redis, "docker image prune -f"},
+			msgContains: "2 件のイメージを更新しました",
+		},
+		{
+			name:        "docker がなければ podman を使う",
+			runtime:     "podman",
+			cfg:         config.ManagerConfig{"images": []interface{}{postgres}},
+			wantUpdated: 1,
+			wantCalls:   []string{"podman pull " + postgres},
+		},
+		{
+			name:        "pull の失敗は他のイメージを止めない",
+			runtime:     "docker",
+			mode:        "pull_error_postgres",
+			cfg:         config.ManagerConfig{"images": []interface{}{postgres, redis}},
+			wantUpdated: 1,
+			wantFailed:  1,
+			wantCalls:   []string{"docker pull " + postgres, "docker pull " + redis},
+			msgContains: "1 件更新、1 件失敗",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := setupFakeContainerRuntime(t, tc.runtime, registry.host, tc.mode)
+
+			c := &ContainersUpdater{}
+			require.NoError(t, c.Configure(tc.cfg))
+
+			got, err := c.Update(context.Background(), tc.opts)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.wantUpdated, got.UpdatedCount)
+			assert.Equal(t, tc.wantFailed, got.FailedCount)
+			assert.Contains(t, got.Message, tc.msgContains)
+			assert.Equal(t, tc.wantCalls, readFakeContainerCalls(t, dir))
+		})
+	}
+}
+
+// readFakeContainerCalls は pull / prune の呼び出しのみを返します。
+func readFakeContainerCalls(t *testing.T, dir string) []string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(dir, "calls"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	require.NoError(t, err)
+
+	var calls []string
+
+	// Windows の .cmd は CRLF で追記するため改行を揃える
+	for _, line := range strings.Split(strings.TrimSpace(strings.ReplaceAll(string(data), "\r\n", "\n")), "\n") {
+		if strings.Contains(line, " pull ") || strings.Contains(line, " prune ") {
+			calls = append(calls, line)
+		}
+	}
+
+	return calls
+}
+
+// setupFakeContainerRuntime は偽の docker / podman だけを PATH に置きます。
+// ローカルイメージは postgres（古いダイジェスト）、redis（最新）、devbase（RepoDigests なし）、<none> の4件です。
+func setupFakeContainerRuntime(t *testing.T, name, registryHost, mode string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	writeFakeContainerRuntimeCommand(t, dir, name)
+
+	t.Setenv("PATH", dir)
+	t.Setenv("DSX_TEST_CONTAINER_DIR", dir)
+	t.Setenv("DSX_TEST_CONTAINER_MODE", mode)
+	t.Setenv("DSX_TEST_REGISTRY_HOST", registryHost)
+
+	return dir
+}
+
+func writeFakeContainerRuntimeCommand(t *testing.T, dir, name string) {
+	t.Helper()
+
+	var (
+		fileName string
+		content  string
+	)
+
+	if runtime.GOOS == "windows" {
+		fileName = name + ".cmd"
+		content = `@echo off
+set host=%DSX_TEST_REGISTRY_HOST%
+>>"%DSX_TEST_CONTAINER_DIR%\calls" echo %~n0 %*
+if "%1"=="images" goto doimages
+if "%1"=="image" goto doimage
+if "%1"=="pull" goto dopull
+echo invalid args 1>&2
+exit /b 1
+:doimages
+echo %host%/dev/postgres:16
+echo %host%/dev/redis:7
+echo %host%/dev/devbase:main
+echo ^<none^>:^<none^>
+exit /b 0
+:doimage
+if "%2"=="prune" exit /b 0
+if "%~5"=="%host%/dev/postgres:16" (
+  echo ["%host%/dev/postgres@` + containerTestDigestOld + `"]
+  exit /b 0
+)
+if "%~5"=="%host%/dev/redis:7" (
+  echo ["%host%/dev/redis@` + containerTestDigestCur + `"]
+  exit /b 0
+)
+if "%~5"=="%host%/dev/devbase:main" (
+  echo []
+  exit /b 0
+)
+echo Error: No such image: %~5 1>&2
+exit /b 1
+:dopull
+if "%DSX_TEST_CONTAINER_MODE%"=="pull_error_postgres" if "%2"=="%host%/dev/postgres:16" (
+  echo Error response from daemon: manifest unknown 1>&2
+  exit /b 1
+)
+exit /b 0
+`
+	} else {
+		fileName = name
+		content = `#!/bin/sh
+host="${DSX_TEST_REGISTRY_HOST}"
+echo "${0##*/} $*" >> "${DSX_TEST_CONTAINER_DIR}/calls"
+case "$1" in
+images)
+  echo "${host}/dev/postgres:16"
+  echo "${host}/dev/redis:7"
+  echo "${host}/dev/devbase:main"
+  echo "<none>:<none>"
+  exit 0
+  ;;
+image)
+  if [ "$2" = "prune" ]; then
+    exit 0
+  fi
+  case "$5" in
+  "${host}/dev/postgres:16") echo "[\"${host}/dev/postgres@` + containerTestDigestOld + `\"]" ;;
+  "${host}/dev/redis:7") echo "[\"${host}/dev/redis@` + containerTestDigestCur + `\"]" ;;
+  "${host}/dev/devbase:main") echo "[]" ;;
+  *) echo "Error: No such image: $5" 1>&2; exit 1 ;;
+  esac
+  exit 0
+  ;;
+pull)
+  if [ "${DSX_TEST_CONTAINER_MODE}" = "pull_error_postgres" ] && [ "$2" = "${host}/dev/postgres:16" ]; then
+    echo "Error response from daemon: manifest unknown" 1>&2
+    exit 1
+  fi
+  exit 0
+  ;;
+esac
+echo "invalid args" 1>&2
+exit 1
+`
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, fileName), []byte(content), 0o755); err != nil {
+		t.Fatalf("fake %s command write failed: %v", name, err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strings"
 )
 
 func runCountBasedUpdate(
@@ -18,7 +19,7 @@ func runCountBasedUpdate(
 	runErrFormat string,
 	successMessageFn func(count int) string,
 ) (*UpdateResult, error) {
-	result := &UpdateResult{}
+	result := &UpdateResult{Held: checkResult.Held}
 
 	if checkResult.AvailableUpdates == 0 {
 		result.Message = noUpdatesMessage
@@ -33,8 +34,7 @@ func runCountBasedUpdate(
 	}
 
 	cmd := exec.CommandContext(ctx, command, args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	attachCommandOutput(ctx, cmd)
 	cmd.Stdin = os.Stdin
 
 	if err := cmd.Run(); err != nil {
@@ -48,3 +48,38 @@ func runCountBasedUpdate(
 
 	return result, nil
 }
+
+// runPerPackageUpdate は checkResult.Packages を1件ずつ更新します。
+// 一括更新コマンドに除外指定がなく、複数パッケージを1回で指定できないマネージャで
+// hold / ignore を反映するために使用します。失敗したパッケージは FailedCount に計上します。
+func runPerPackageUpdate(
+	ctx context.Context,
+	checkResult *CheckResult,
+	result *UpdateResult,
+	command string,
+	argsFn func(name string) []string,
+) error {
+	for _, pkg := range checkResult.Packages {
+		args := argsFn(pkg.Name)
+
+		cmd := exec.CommandContext(ctx, command, args...)
+		attachCommandOutput(ctx, cmd)
+		cmd.Stdin = os.Stdin
+
+		if err := cmd.Run(); err != nil {
+			result.FailedCount++
+			result.Errors = append(result.Errors, fmt.Errorf("%s %s に失敗: %w", command, strings.Join(args, " "), err))
+
+			continue
+		}
+
+		result.UpdatedCount++
+		result.Packages = append(result.Packages, pkg)
+	}
+
+	if result.FailedCount > 0 {
+		return fmt.Errorf("%d 件のパッケージ更新に失敗しました", result.FailedCount)
+	}
+
+	return nil
+}
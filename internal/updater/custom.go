@@ -0,0 +1,408 @@
+package updater
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scottlz0310/dsx/internal/config"
+)
+
+const (
+	customAvailableTimeout = 10 * time.Second
+	// customPackagesEnv は update コマンドに更新対象のパッケージ名（空白区切り）を渡す環境変数です。
+	customPackagesEnv = "DSX_PACKAGES"
+)
+
+// CustomUpdater は sys.managers に type: custom で宣言された、シェルコマンドで定義するマネージャの実装です。
+// Go のコードを追加せずに、社内ツールの "foo self-update" などを sys update に組み込むために使います。
+type CustomUpdater struct {
+	packageFilterSupport
+
+	name string
+	spec *config.CustomManagerSpec
+}
+
+// NewCustomUpdater は sys.managers.<name> の定義からカスタムマネージャを生成します。
+func NewCustomUpdater(name string, cfg config.ManagerConfig) (*CustomUpdater, error) {
+	spec, err := cfg.CustomManager()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &CustomUpdater{name: name, spec: spec}
+	if err := c.Configure(cfg); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// RegisterCustomManagers は sys.managers で type: custom と宣言されたマネージャをレジストリに登録します。
+// 組み込みマネージャと同じ名前や不正な定義は登録せず、まとめてエラーとして返します（他のマネージャの登録は続行します）。
+func RegisterCustomManagers(managers map[string]config.ManagerConfig) error {
+	names := make([]string, 0, len(managers))
+	for name, managerCfg := range managers {
+		if managerCfg.IsCustom() {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	var problems []string
+
+	for _, name := range names {
+		if existing, ok := Get(name); ok {
+			if _, isCustom := existing.(*CustomUpdater); !isCustom {
+				problems = append(problems, fmt.Sprintf("%s: 組み込みマネージャと同じ名前は使用できません", name))
+				continue
+			}
+		}
+
+		u, err := NewCustomUpdater(name, managers[name])
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+
+		Register(u)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("カスタムマネージャの登録に失敗: %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+func (c *CustomUpdater) Name() string {
+	return c.name
+}
+
+func (c *CustomUpdater) DisplayName() string {
+	if c.spec.DisplayName != "" {
+		return c.spec.DisplayName
+	}
+
+	return c.name + " (カスタム)"
+}
+
+func (c *CustomUpdater) IsAvailable() bool {
+	return c.UnavailableReason() == ""
+}
+
+// UnavailableReason は available コマンドの失敗、または update のコマンドが PATH にないことを理由として返します。
+func (c *CustomUpdater) UnavailableReason() string {
+	if c.spec.Available != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), customAvailableTimeout)
+		defer cancel()
+
+		if err := customShellCommand(ctx, c.spec.Available, false).Run(); err != nil {
+			return fmt.Sprintf("available コマンドが失敗しました: %v", err)
+		}
+
+		return ""
+	}
+
+	fields := strings.Fields(c.spec.Update)
+	if len(fields) == 0 {
+		return "update コマンドが空です"
+	}
+
+	if _, err := exec.LookPath(fields[0]); err != nil {
+		return fmt.Sprintf("%s が PATH にありません", fields[0])
+	}
+
+	return ""
+}
+
+func (c *CustomUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	return c.configurePackageFilter(cfg)
+}
+
+func (c *CustomUpdater) RequiresSudo() bool {
+	return c.spec.Sudo
+}
+
+func (c *CustomUpdater) RunsExclusively() bool {
+	return c.spec.Exclusive
+}
+
+func (c *CustomUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	if c.spec.Check == "" {
+		return &CheckResult{
+			Packages: []PackageInfo{},
+			Message:  "check が未設定のため更新可否は実行時に判定",
+		}, nil
+	}
+
+	cmd := customShellCommand(ctx, c.spec.Check, false)
+
+	var stderr bytes.Buffer
+
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s の check コマンドに失敗: %w", c.name, buildCommandOutputErr(err, combineCommandOutputs(output, stderr.Bytes())))
+	}
+
+	packages, err := parseCustomCheckOutput(c.name, c.spec, output)
+	if err != nil {
+		return nil, fmt.Errorf("%s の check 出力の解析に失敗: %w", c.name, err)
+	}
+
+	return c.filterCheckResult(&CheckResult{
+		AvailableUpdates: len(packages),
+		Packages:         packages,
+	}), nil
+}
+
+func (c *CustomUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	// まず更新確認
+	checkResult, err := c.Check(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UpdateResult{Held: checkResult.Held}
+	hasCheck := c.spec.Check != ""
+
+	if hasCheck && checkResult.AvailableUpdates == 0 {
+		result.Message = allPackagesUpToDateMessage
+		return result, nil
+	}
+
+	if opts.DryRun {
+		if hasCheck {
+			result.Message = fmt.Sprintf("%d 件のパッケージが更新可能です（DryRunモード）", checkResult.AvailableUpdates)
+			result.Packages = checkResult.Packages
+		} else {
+			result.Message = "check が未設定のため update を毎回実行します（DryRunモード）"
+		}
+
+		return result, nil
+	}
+
+	// hold / ignore を除いた更新対象は環境変数で update コマンドに渡す
+	cmd := customShellCommand(ctx, c.spec.Update, c.spec.Sudo,
+		customPackagesEnv+"="+strings.Join(packageNames(checkResult.Packages), " "))
+	attachCommandOutput(ctx, cmd)
+	cmd.Stdin = os.Stdin
+
+	if err := cmd.Run(); err != nil {
+		result.Errors = append(result.Errors, err)
+		return result, fmt.Errorf("%s の update コマンドに失敗: %w", c.name, err)
+	}
+
+	if !hasCheck {
+		result.Message = "update コマンドを実行しました"
+		return result, nil
+	}
+
+	result.UpdatedCount = checkResult.AvailableUpdates
+	result.Packages = checkResult.Packages
+	result.Message = fmt.Sprintf("%d 件のパッケージを更新しました", result.UpdatedCount)
+
+	return result, nil
+}
+
+// customShellCommand はシェル（Windows では cmd /C）でスクリプトを実行するコマンドを返します。
+// sudo の場合、sudo は環境変数を引き継がないため env 経由で渡します。
+func customShellCommand(ctx context.Context, script string, sudo bool, env ...string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		cmd := exec.CommandContext(ctx, "cmd", "/C", script)
+		cmd.Env = append(os.Environ(), env...)
+
+		return cmd
+	}
+
+	if sudo {
+		args := append([]string{"env"}, env...)
+		args = append(args, "sh", "-c", script)
+
+		return exec.CommandContext(ctx, "sudo", args...)
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", script)
+	cmd.Env = append(os.Environ(), env...)
+
+	return cmd
+}
+
+// parseCustomCheckOutput は check の出力を parse_regex / parse_json に従ってパッケージ一覧に変換します。
+// どちらも未指定の場合は、空でない各行をパッケージ名として扱います。
+func parseCustomCheckOutput(managerName string, spec *config.CustomManagerSpec, output []byte) ([]PackageInfo, error) {
+	if spec.ParseJSON != "" {
+		return parseCustomCheckJSON(spec, output)
+	}
+
+	packages := make([]PackageInfo, 0)
+
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		if spec.ParseRegex == nil {
+			if name := strings.TrimSpace(line); name != "" {
+				packages = append(packages, PackageInfo{Name: name})
+			}
+
+			continue
+		}
+
+		m := spec.ParseRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		// name グループがない場合は、マネージャ自体の更新として扱う
+		pkg := PackageInfo{Name: managerName}
+
+		for i, group := range spec.ParseRegex.SubexpNames() {
+			value := strings.TrimSpace(m[i])
+
+			switch group {
+			case "name":
+				if value != "" {
+					pkg.Name = value
+				}
+			case "current":
+				pkg.CurrentVersion = value
+			case "new":
+				pkg.NewVersion = value
+			}
+		}
+
+		packages = append(packages, pkg)
+	}
+
+	return packages, nil
+}
+
+// parseCustomCheckJSON は parse_json のパスが指す配列（またはパッケージ名をキーとするオブジェクト）をパッケージ一覧に変換します。
+func parseCustomCheckJSON(spec *config.CustomManagerSpec, output []byte) ([]PackageInfo, error) {
+	packages := make([]PackageInfo, 0)
+
+	trimmed := bytes.TrimSpace(output)
+	if len(trimmed) == 0 {
+		return packages, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	decoder.UseNumber()
+
+	var root interface{}
+	if err := decoder.Decode(&root); err != nil {
+		return nil, err
+	}
+
+	items, ok := lookupJSONPath(root, spec.ParseJSON)
+	if !ok || items == nil {
+		return packages, nil
+	}
+
+	toPackage := func(item interface{}, key string) PackageInfo {
+		if name, isString := item.(string); isString {
+			return PackageInfo{Name: name}
+		}
+
+		pkg := PackageInfo{Name: key}
+
+		if value, found := lookupJSONPath(item, spec.JSONName); found {
+			pkg.Name = jsonValueString(value)
+		}
+
+		if value, found := lookupJSONPath(item, spec.JSONCurrent); found {
+			pkg.CurrentVersion = jsonValueString(value)
+		}
+
+		if value, found := lookupJSONPath(item, spec.JSONNew); found {
+			pkg.NewVersion = jsonValueString(value)
+		}
+
+		return pkg
+	}
+
+	switch v := items.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if pkg := toPackage(item, ""); pkg.Name != "" {
+				packages = append(packages, pkg)
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if pkg := toPackage(v[key], key); pkg.Name != "" {
+				packages = append(packages, pkg)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("%q は配列またはオブジェクトではありません", spec.ParseJSON)
+	}
+
+	return packages, nil
+}
+
+// lookupJSONPath はドット区切りのパス（"updates.0.name" など）で JSON の値を取り出します。"." はルートを指します。
+func lookupJSONPath(value interface{}, path string) (interface{}, bool) {
+	path = strings.TrimSpace(path)
+	if path == "" || path == "." {
+		return value, true
+	}
+
+	current := value
+
+	for _, key := range strings.Split(strings.Trim(path, "."), ".") {
+		switch v := current.(type) {
+		case map[string]interface{}:
+			next, ok := v[key]
+			if !ok {
+				return nil, false
+			}
+
+			current = next
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, false
+			}
+
+			current = v[index]
+		default:
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+func jsonValueString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
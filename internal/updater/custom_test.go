@@ -0,0 +1,381 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCustomCheckOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    config.ManagerConfig
+		output string
+		want   []PackageInfo
+	}{
+		{
+			name:   "既定は各行をパッケージ名として扱う",
+			cfg:    config.ManagerConfig{},
+			output: "foo\n\n  bar  \r\n",
+			want:   []PackageInfo{{Name: "foo"}, {Name: "bar"}},
+		},
+		{
+			name:   "正規表現の名前付きグループ",
+			cfg:    config.ManagerConfig{"parse_regex": `^(?P<name>\S+)\s+(?P<current>\S+)\s+->\s+(?P<new>\S+)$`},
+			output: "Checking...\nfoo 1.0.0 -> 1.1.0\nbar 2.0 -> 3.0\n",
+			want: []PackageInfo{
+				{Name: "foo", CurrentVersion: "1.0.0", NewVersion: "1.1.0"},
+				{Name: "bar", CurrentVersion: "2.0", NewVersion: "3.0"},
+			},
+		},
+		{
+			name:   "name グループがなければマネージャ名",
+			cfg:    config.ManagerConfig{"parse_regex": `new version (?P<new>\S+) available`},
+			output: "new version 2.4.0 available\n",
+			want:   []PackageInfo{{Name: "mytool", NewVersion: "2.4.0"}},
+		},
+		{
+			name:   "JSON のルート配列",
+			cfg:    config.ManagerConfig{"parse_json": "."},
+			output: `[{"name":"foo","current":"1.0","latest":"1.1"},{"name":"bar"},{"version":"9"}]`,
+			want:   []PackageInfo{{Name: "foo", CurrentVersion: "1.0", NewVersion: "1.1"}, {Name: "bar"}},
+		},
+		{
+			name: "JSON のネストしたパスと json_fields",
+			cfg: config.ManagerConfig{
+				"parse_json":  "result.updates",
+				"json_fields": map[string]interface{}{"name": "pkg.id", "current": "installed", "new": "available.0"},
+			},
+			output: `{"result":{"updates":[{"pkg":{"id":"foo"},"installed":1,"available":["1.2","1.1"]}]}}`,
+			want:   []PackageInfo{{Name: "foo", CurrentVersion: "1", NewVersion: "1.2"}},
+		},
+		{
+			name:   "JSON のパッケージ名をキーとするオブジェクト",
+			cfg:    config.ManagerConfig{"parse_json": "outdated"},
+			output: `{"outdated":{"zeta":{"current":"1","latest":"2"},"alpha":{"current":"3","latest":"4"}}}`,
+			want: []PackageInfo{
+				{Name: "alpha", CurrentVersion: "3", NewVersion: "4"},
+				{Name: "zeta", CurrentVersion: "1", NewVersion: "2"},
+			},
+		},
+		{
+			name:   "JSON の文字列配列",
+			cfg:    config.ManagerConfig{"parse_json": "."},
+			output: `["foo","bar"]`,
+			want:   []PackageInfo{{Name: "foo"}, {Name: "bar"}},
+		},
+		{
+			name:   "JSON のパスが存在しない",
+			cfg:    config.ManagerConfig{"parse_json": "updates"},
+			output: `{}`,
+			want:   []PackageInfo{},
+		},
+		{
+			name:   "JSON の空の出力",
+			cfg:    config.ManagerConfig{"parse_json": "."},
+			output: "\n",
+			want:   []PackageInfo{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg["type"] = "custom"
+			tt.cfg["update"] = "mytool upgrade"
+
+			spec, err := tt.cfg.CustomManager()
+			require.NoError(t, err)
+
+			got, err := parseCustomCheckOutput("mytool", spec, []byte(tt.output))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseCustomCheckOutput_JSONError(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+	}{
+		{name: "不正なJSON", output: "{"},
+		{name: "配列でもオブジェクトでもない", output: `"foo"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := config.ManagerConfig{"type": "custom", "update": "x", "parse_json": "."}.CustomManager()
+			require.NoError(t, err)
+
+			_, err = parseCustomCheckOutput("mytool", spec, []byte(tt.output))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRegisterCustomManagers(t *testing.T) {
+	t.Cleanup(clearRegistry)
+	clearRegistry()
+
+	Register(&mockUpdater{name: "apt", displayName: "APT"})
+
+	err := RegisterCustomManagers(map[string]config.ManagerConfig{
+		"apt":    {"type": "custom", "update": "apt-get upgrade"},
+		"broken": {"type": "custom"},
+		"brew":   {"enabled": true},
+		"foo":    {"type": "custom", "update": "foo upgrade", "display_name": "Foo ツール"},
+		"bar":    {"type": "custom", "update": "bar upgrade"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "apt: 組み込みマネージャと同じ名前は使用できません")
+	assert.Contains(t, err.Error(), "broken: update は必須です")
+
+	apt, ok := Get("apt")
+	require.True(t, ok)
+	assert.Equal(t, "APT", apt.DisplayName())
+
+	_, ok = Get("broken")
+	assert.False(t, ok)
+
+	_, ok = Get("brew")
+	assert.False(t, ok)
+
+	foo, ok := Get("foo")
+	require.True(t, ok)
+	assert.Equal(t, "Foo ツール", foo.DisplayName())
+
+	bar, ok := Get("bar")
+	require.True(t, ok)
+	assert.Equal(t, "bar (カスタム)", bar.DisplayName())
+
+	// 設定の再読み込みでカスタムマネージャ同士は上書きできる
+	require.NoError(t, RegisterCustomManagers(map[string]config.ManagerConfig{
+		"foo": {"type": "custom", "update": "foo upgrade"},
+	}))
+
+	foo, ok = Get("foo")
+	require.True(t, ok)
+	assert.Equal(t, "foo (カスタム)", foo.DisplayName())
+}
+
+func TestCustomUpdater_UnavailableReason(t *testing.T) {
+	setupFakeCustomTool(t, "")
+
+	tests := []struct {
+		name    string
+		cfg     config.ManagerConfig
+		wantMsg string
+	}{
+		{name: "available が成功", cfg: config.ManagerConfig{"available": "mytool outdated"}},
+		{name: "available が失敗", cfg: config.ManagerConfig{"available": "mytool unknown"}, wantMsg: "available コマンドが失敗しました"},
+		{name: "update のコマンドが PATH にある", cfg: config.ManagerConfig{"update": "mytool upgrade"}},
+		{name: "update のコマンドが PATH にない", cfg: config.ManagerConfig{"update": "dsx-no-such-tool upgrade"}, wantMsg: "dsx-no-such-tool が PATH にありません"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg["type"] = "custom"
+			if _, ok := tt.cfg["update"]; !ok {
+				tt.cfg["update"] = "mytool upgrade"
+			}
+
+			c, err := NewCustomUpdater("mytool", tt.cfg)
+			require.NoError(t, err)
+
+			reason := c.UnavailableReason()
+			if tt.wantMsg == "" {
+				assert.Empty(t, reason)
+				assert.True(t, c.IsAvailable())
+
+				return
+			}
+
+			assert.Contains(t, reason, tt.wantMsg)
+			assert.False(t, c.IsAvailable())
+		})
+	}
+}
+
+func TestCustomUpdater_Update(t *testing.T) {
+	testCases := []struct {
+		name         string
+		mode         string
+		cfg          config.ManagerConfig
+		opts         UpdateOptions
+		wantUpdated  int
+		wantPackages string
+		wantErr      string
+		msgContains  string
+	}{
+		{
+			name:        "DryRunは update を実行しない",
+			opts:        UpdateOptions{DryRun: true},
+			msgContains: "2 件のパッケージが更新可能です（DryRunモード）",
+		},
+		{
+			name:         "更新対象を DSX_PACKAGES で渡す",
+			wantUpdated:  2,
+			wantPackages: "foo bar",
+			msgContains:  "2 件のパッケージを更新しました",
+		},
+		{
+			name:         "hold したパッケージは渡さない",
+			cfg:          config.ManagerConfig{"hold": []interface{}{"foo"}},
+			wantUpdated:  1,
+			wantPackages: "bar",
+		},
+		{
+			name:        "更新対象がなければ update を実行しない",
+			mode:        "empty",
+			msgContains: allPackagesUpToDateMessage,
+		},
+		{
+			name:         "check がなければ毎回 update を実行",
+			cfg:          config.ManagerConfig{"check": nil},
+			wantPackages: "",
+			msgContains:  "update コマンドを実行しました",
+		},
+		{
+			name:    "check の失敗",
+			mode:    "check_error",
+			wantErr: "mytool の check コマンドに失敗",
+		},
+		{
+			name:    "update の失敗",
+			mode:    "update_error",
+			wantErr: "mytool の update コマンドに失敗",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := setupFakeCustomTool(t, tc.mode)
+
+			cfg := config.ManagerConfig{
+				"type":        "custom",
+				"check":       "mytool outdated",
+				"update":      "mytool upgrade",
+				"parse_regex": `^(?P<name>\S+) (?P<current>\S+) (?P<new>\S+)$`,
+			}
+			for key, value := range tc.cfg {
+				cfg[key] = value
+			}
+
+			c, err := NewCustomUpdater("mytool", cfg)
+			require.NoError(t, err)
+
+			got, err := c.Update(context.Background(), tc.opts)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantUpdated, got.UpdatedCount)
+			assert.Contains(t, got.Message, tc.msgContains)
+
+			upgraded, readErr := os.ReadFile(filepath.Join(dir, "upgraded"))
+			if tc.opts.DryRun || tc.mode == "empty" {
+				assert.True(t, os.IsNotExist(readErr), "update は実行されないこと")
+				return
+			}
+
+			require.NoError(t, readErr)
+			assert.Equal(t, tc.wantPackages, strings.TrimSpace(string(upgraded)))
+		})
+	}
+}
+
+// setupFakeCustomTool は PATH の先頭に偽の mytool コマンドを配置します。
+// outdated は foo と bar を更新可能として出力し、upgrade は受け取った DSX_PACKAGES を upgraded に記録します。
+func setupFakeCustomTool(t *testing.T, mode string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	writeFakeCustomToolCommand(t, dir)
+
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("DSX_TEST_CUSTOM_DIR", dir)
+	t.Setenv("DSX_TEST_CUSTOM_MODE", mode)
+
+	return dir
+}
+
+func writeFakeCustomToolCommand(t *testing.T, dir string) {
+	t.Helper()
+
+	var (
+		fileName string
+		content  string
+	)
+
+	if runtime.GOOS == "windows" {
+		// DSX_PACKAGES が空でも "ECHO is off." を書き込まないよう echo( を使う
+		fileName = "mytool.cmd"
+		content = `@echo off
+set mode=%DSX_TEST_CUSTOM_MODE%
+if "%1"=="outdated" goto dooutdated
+if "%1"=="upgrade" goto doupgrade
+echo invalid args 1>&2
+exit /b 1
+:dooutdated
+if "%mode%"=="check_error" (
+  echo registry unreachable 1>&2
+  exit /b 1
+)
+if "%mode%"=="empty" exit /b 0
+echo foo 1.0.0 1.1.0
+echo bar 2.0.0 2.1.0
+exit /b 0
+:doupgrade
+if "%mode%"=="update_error" (
+  echo permission denied 1>&2
+  exit /b 1
+)
+>"%DSX_TEST_CUSTOM_DIR%\upgraded" echo(%DSX_PACKAGES%
+exit /b 0
+`
+	} else {
+		fileName = "mytool"
+		content = `#!/bin/sh
+mode="${DSX_TEST_CUSTOM_MODE}"
+case "$1" in
+outdated)
+  if [ "${mode}" = "check_error" ]; then
+    echo "registry unreachable" 1>&2
+    exit 1
+  fi
+  if [ "${mode}" = "empty" ]; then
+    exit 0
+  fi
+  echo "foo 1.0.0 1.1.0"
+  echo "bar 2.0.0 2.1.0"
+  exit 0
+  ;;
+upgrade)
+  if [ "${mode}" = "update_error" ]; then
+    echo "permission denied" 1>&2
+    exit 1
+  fi
+  echo "${DSX_PACKAGES}" > "${DSX_TEST_CUSTOM_DIR}/upgraded"
+  exit 0
+  ;;
+esac
+echo "invalid args" 1>&2
+exit 1
+`
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, fileName), []byte(content), 0o755); err != nil {
+		t.Fatalf("fake mytool command write failed: %v", err)
+	}
+}
@@ -0,0 +1,246 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/scottlz0310/dsx/internal/config"
+)
+
+// dnfCheckUpdateExitCode は dnf/yum check-update が更新ありを示す終了コードです。
+const dnfCheckUpdateExitCode = 100
+
+// DnfUpdater は DNF パッケージマネージャ (Fedora/RHEL) の実装です。
+// dnf がない古い RHEL 系では yum を使用します。
+type DnfUpdater struct {
+	packageFilterSupport
+
+	useSudo bool
+}
+
+// 起動時にレジストリに登録
+func init() {
+	Register(&DnfUpdater{useSudo: true})
+}
+
+func (d *DnfUpdater) Name() string {
+	return "dnf"
+}
+
+func (d *DnfUpdater) DisplayName() string {
+	return "DNF (Fedora/RHEL)"
+}
+
+func (d *DnfUpdater) IsAvailable() bool {
+	return d.command() != ""
+}
+
+func (d *DnfUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	if err := d.configurePackageFilter(cfg); err != nil {
+		return err
+	}
+
+	if useSudo, ok := cfg["use_sudo"].(bool); ok {
+		d.useSudo = useSudo
+		return nil
+	}
+
+	// 旧キー `sudo` との後方互換
+	if useSudo, ok := cfg["sudo"].(bool); ok {
+		d.useSudo = useSudo
+	}
+
+	return nil
+}
+
+func (d *DnfUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	command := d.command()
+	if command == "" {
+		return nil, fmt.Errorf("dnf / yum が見つかりません")
+	}
+
+	// check-update はメタデータを更新したうえで、更新ありの場合に終了コード 100 を返す
+	output, err := runCommandOutputWithLocaleCAllowExitCodes(ctx, command, []string{"check-update", "--refresh"},
+		command+" check-update の実行に失敗: %w", dnfCheckUpdateExitCode)
+	if err != nil {
+		return nil, err
+	}
+
+	packages := parseDnfCheckUpdate(string(output))
+	d.fillCurrentVersions(ctx, packages)
+
+	return d.filterCheckResult(&CheckResult{
+		AvailableUpdates: len(packages),
+		Packages:         packages,
+	}), nil
+}
+
+func (d *DnfUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	// まず更新確認
+	checkResult, err := d.Check(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UpdateResult{Held: checkResult.Held}
+
+	if checkResult.AvailableUpdates == 0 {
+		result.Message = allPackagesUpToDateMessage
+		return result, nil
+	}
+
+	if opts.DryRun {
+		result.Message = fmt.Sprintf("%d 件のパッケージが更新可能です（DryRunモード）", checkResult.AvailableUpdates)
+		result.Packages = checkResult.Packages
+
+		return result, nil
+	}
+
+	// 実際の更新を実行（hold / ignore で除外がある場合は残りのパッケージのみ更新）
+	args := selectUpdateArgs(checkResult, []string{upgradeCommand, "-y"}, []string{upgradeCommand, "-y"})
+	if err := d.runCommand(ctx, args...); err != nil {
+		result.Errors = append(result.Errors, err)
+		return result, fmt.Errorf("%s upgrade に失敗: %w", d.command(), err)
+	}
+
+	result.UpdatedCount = checkResult.AvailableUpdates
+	result.Packages = checkResult.Packages
+	result.Message = fmt.Sprintf("%d 件のパッケージを更新しました", result.UpdatedCount)
+
+	return result, nil
+}
+
+// ListInstalled は rpm でインストール済みパッケージを返します。
+// バージョンは check-update と同じ [epoch:]version-release 形式です。
+func (d *DnfUpdater) ListInstalled(ctx context.Context) ([]PackageInfo, error) {
+	output, err := runCommandOutputWithLocaleC(ctx, "rpm", []string{"-qa", "--qf", rpmInstalledQueryFormat}, "rpm -qa の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseTabSeparatedPackages(string(output)), nil
+}
+
+const rpmInstalledQueryFormat = "%{NAME}\t%|EPOCH?{%{EPOCH}:}:{}|%{VERSION}-%{RELEASE}\n"
+
+// fillCurrentVersions は check-update に含まれない現在のバージョンを rpm から補完します。
+// 取得に失敗した場合は現在のバージョンを空のままにします。
+func (d *DnfUpdater) fillCurrentVersions(ctx context.Context, packages []PackageInfo) {
+	if len(packages) == 0 {
+		return
+	}
+
+	installed, err := d.ListInstalled(ctx)
+	if err != nil {
+		return
+	}
+
+	versions := make(map[string]string, len(installed))
+	for _, pkg := range installed {
+		if _, exists := versions[pkg.Name]; !exists {
+			versions[pkg.Name] = pkg.CurrentVersion
+		}
+	}
+
+	for i := range packages {
+		packages[i].CurrentVersion = versions[packages[i].Name]
+	}
+}
+
+// command は使用するコマンド（dnf 優先、なければ yum）を返します。どちらもなければ空文字です。
+func (d *DnfUpdater) command() string {
+	for _, name := range []string{"dnf", "yum"} {
+		if _, err := exec.LookPath(name); err == nil {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// runCommand は dnf / yum コマンドを実行します（必要に応じて sudo を使用）
+func (d *DnfUpdater) runCommand(ctx context.Context, args ...string) error {
+	command := d.command()
+
+	var cmd *exec.Cmd
+
+	if d.useSudo {
+		cmd = exec.CommandContext(ctx, "sudo", append([]string{command}, args...)...)
+	} else {
+		cmd = exec.CommandContext(ctx, command, args...)
+	}
+
+	attachCommandOutput(ctx, cmd)
+	cmd.Stdin = os.Stdin
+
+	return cmd.Run()
+}
+
+// parseDnfCheckUpdate は "dnf check-update" の出力をパースします。
+// 形式: "name.arch  [epoch:]version-release  repo"
+// パッケージ名が長い場合は名前の後で折り返され、バージョンとリポジトリが次の行に出力されます。
+func parseDnfCheckUpdate(output string) []PackageInfo {
+	lines := strings.Split(output, "\n")
+	packages := make([]PackageInfo, 0, len(lines))
+	seen := make(map[string]bool)
+	pendingName := ""
+
+	for _, line := range lines {
+		// "Obsoleting Packages" 以降は置き換え対象の一覧のため読まない
+		if strings.HasPrefix(strings.TrimSpace(line), "Obsoleting") {
+			break
+		}
+
+		fields := strings.Fields(line)
+
+		var nameArch, version string
+
+		switch {
+		case pendingName != "" && len(fields) == 2 && startsWithSpace(line):
+			nameArch, version = pendingName, fields[0]
+		case len(fields) == 1 && !startsWithSpace(line) && isDnfNameArch(fields[0]):
+			pendingName = fields[0]
+			continue
+		case len(fields) == 3 && !startsWithSpace(line) && isDnfNameArch(fields[0]):
+			nameArch, version = fields[0], fields[1]
+		default:
+			pendingName = ""
+			continue
+		}
+
+		pendingName = ""
+		name := nameArch[:strings.LastIndex(nameArch, ".")]
+
+		// 複数アーキテクチャ（x86_64 と i686 など）は同じパッケージとして扱う
+		if seen[name] {
+			continue
+		}
+
+		seen[name] = true
+		packages = append(packages, PackageInfo{Name: name, NewVersion: version})
+	}
+
+	return packages
+}
+
+// isDnfNameArch は "name.arch" 形式のトークンかを判定します。
+// "Last metadata expiration check:" などのメッセージ行を除外するために使います。
+func isDnfNameArch(token string) bool {
+	idx := strings.LastIndex(token, ".")
+	if idx <= 0 || idx == len(token)-1 {
+		return false
+	}
+
+	return !strings.HasSuffix(token, ":")
+}
+
+func startsWithSpace(line string) bool {
+	return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+}
@@ -0,0 +1,284 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDnfUpdater_Name(t *testing.T) {
+	d := &DnfUpdater{}
+	assert.Equal(t, "dnf", d.Name())
+	assert.Equal(t, "DNF (Fedora/RHEL)", d.DisplayName())
+}
+
+func TestDnfUpdater_Configure(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        config.ManagerConfig
+		expectSudo bool
+	}{
+		{name: "nilの設定はデフォルトのまま", cfg: nil, expectSudo: true},
+		{name: "use_sudo=false", cfg: config.ManagerConfig{"use_sudo": false}, expectSudo: false},
+		{name: "旧キーsudo=false", cfg: config.ManagerConfig{"sudo": false}, expectSudo: false},
+		{name: "use_sudoを優先", cfg: config.ManagerConfig{"use_sudo": true, "sudo": false}, expectSudo: true},
+		{name: "不正な型の値は無視", cfg: config.ManagerConfig{"use_sudo": "false"}, expectSudo: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &DnfUpdater{useSudo: true}
+			assert.NoError(t, d.Configure(tt.cfg))
+			assert.Equal(t, tt.expectSudo, d.useSudo)
+		})
+	}
+}
+
+func TestParseDnfCheckUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected []PackageInfo
+	}{
+		{
+			name:     "空の出力",
+			output:   "",
+			expected: []PackageInfo{},
+		},
+		{
+			name: "メタデータ確認行のみ",
+			output: `Last metadata expiration check: 0:12:34 ago on Mon 01 Jan 2026 10:00:00 AM UTC.
+`,
+			expected: []PackageInfo{},
+		},
+		{
+			name: "dnf4 の出力",
+			output: `Last metadata expiration check: 0:12:34 ago on Mon 01 Jan 2026 10:00:00 AM UTC.
+
+kernel.x86_64                         6.8.9-300.fc40                updates
+vim-enhanced.x86_64                   2:9.1.393-1.fc40              updates
+python3.11.x86_64                     3.11.9-2.fc40                 updates
+`,
+			expected: []PackageInfo{
+				{Name: "kernel", NewVersion: "6.8.9-300.fc40"},
+				{Name: "vim-enhanced", NewVersion: "2:9.1.393-1.fc40"},
+				{Name: "python3.11", NewVersion: "3.11.9-2.fc40"},
+			},
+		},
+		{
+			name: "dnf5 の出力",
+			output: `Updating and loading repositories:
+Repositories loaded.
+git-core.x86_64    2.45.2-1.fc40    updates
+`,
+			expected: []PackageInfo{
+				{Name: "git-core", NewVersion: "2.45.2-1.fc40"},
+			},
+		},
+		{
+			name: "長いパッケージ名の折り返し",
+			output: `texlive-collection-latexrecommended.noarch
+                                      11:svn65512-72.fc40           updates
+curl.x86_64                           8.6.0-10.fc40                 updates
+`,
+			expected: []PackageInfo{
+				{Name: "texlive-collection-latexrecommended", NewVersion: "11:svn65512-72.fc40"},
+				{Name: "curl", NewVersion: "8.6.0-10.fc40"},
+			},
+		},
+		{
+			name: "複数アーキテクチャは1件にまとめる",
+			output: `glibc.i686                            2.39-15.fc40                  updates
+glibc.x86_64                          2.39-15.fc40                  updates
+`,
+			expected: []PackageInfo{
+				{Name: "glibc", NewVersion: "2.39-15.fc40"},
+			},
+		},
+		{
+			name: "Obsoleting Packages 以降は読まない",
+			output: `openssl.x86_64                        1:3.2.1-6.fc40                updates
+Obsoleting Packages
+grub2-tools.x86_64                    1:2.06-121.fc40               updates
+    grub2-tools.x86_64                1:2.06-120.fc40               @updates
+`,
+			expected: []PackageInfo{
+				{Name: "openssl", NewVersion: "1:3.2.1-6.fc40"},
+			},
+		},
+		{
+			name: "セキュリティ通知行は無視",
+			output: `Security: kernel-core-6.8.9-300.fc40.x86_64 is an installed security update
+Security: kernel-core-6.8.8-300.fc40.x86_64 is the currently running version
+`,
+			expected: []PackageInfo{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseDnfCheckUpdate(tt.output))
+		})
+	}
+}
+
+func TestDnfUpdater_Check(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mode        string
+		want        []PackageInfo
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "終了コード100は更新あり",
+			mode: "updates",
+			want: []PackageInfo{
+				{Name: "vim-enhanced", CurrentVersion: "2:9.1.350-1.fc40", NewVersion: "2:9.1.393-1.fc40"},
+				{Name: "curl", CurrentVersion: "8.6.0-8.fc40", NewVersion: "8.6.0-10.fc40"},
+			},
+		},
+		{
+			name: "終了コード0は更新なし",
+			mode: "none",
+			want: []PackageInfo{},
+		},
+		{
+			name:        "終了コード1は失敗",
+			mode:        "check_error",
+			wantErr:     true,
+			errContains: "dnf check-update の実行に失敗",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			setupFakeDnf(t, tc.mode)
+
+			d := &DnfUpdater{useSudo: false}
+			got, err := d.Check(context.Background())
+
+			if tc.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errContains)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, len(tc.want), got.AvailableUpdates)
+			assert.Equal(t, tc.want, got.Packages)
+		})
+	}
+}
+
+func TestDnfUpdater_Update(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mode        string
+		opts        UpdateOptions
+		wantUpdated int
+		wantErr     bool
+		errContains string
+		msgContains string
+	}{
+		{
+			name:        "DryRunは更新せず計画表示",
+			mode:        "updates",
+			opts:        UpdateOptions{DryRun: true},
+			msgContains: "DryRunモード",
+		},
+		{
+			name:        "対象なし",
+			mode:        "none",
+			msgContains: "すべてのパッケージは最新です",
+		},
+		{
+			name:        "更新成功",
+			mode:        "updates",
+			wantUpdated: 2,
+			msgContains: "2 件のパッケージを更新しました",
+		},
+		{
+			name:        "更新失敗",
+			mode:        "upgrade_error",
+			wantErr:     true,
+			errContains: "dnf upgrade に失敗",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			setupFakeDnf(t, tc.mode)
+
+			d := &DnfUpdater{useSudo: false}
+			got, err := d.Update(context.Background(), tc.opts)
+
+			if tc.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errContains)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantUpdated, got.UpdatedCount)
+			assert.Contains(t, got.Message, tc.msgContains)
+		})
+	}
+}
+
+// setupFakeDnf は PATH の先頭に偽の dnf / rpm コマンドを配置します。
+func setupFakeDnf(t *testing.T, mode string) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("dnf は Linux 専用のため Windows ではスキップ")
+	}
+
+	dir := t.TempDir()
+
+	dnf := `#!/bin/sh
+mode="${DSX_TEST_DNF_MODE}"
+if [ "$1" = "check-update" ]; then
+  echo "Last metadata expiration check: 0:00:01 ago on Mon 01 Jan 2026 10:00:00 AM UTC."
+  if [ "${mode}" = "check_error" ]; then
+    echo "Error: Failed to download metadata for repo 'updates'" 1>&2
+    exit 1
+  fi
+  if [ "${mode}" = "none" ]; then
+    exit 0
+  fi
+  echo ""
+  echo "vim-enhanced.x86_64     2:9.1.393-1.fc40     updates"
+  echo "curl.x86_64             8.6.0-10.fc40        updates"
+  exit 100
+fi
+if [ "$1" = "upgrade" ]; then
+  if [ "${mode}" = "upgrade_error" ]; then
+    echo "Error: Transaction test error" 1>&2
+    exit 1
+  fi
+  exit 0
+fi
+echo "invalid args" 1>&2
+exit 1
+`
+	rpm := `#!/bin/sh
+printf 'vim-enhanced\t2:9.1.350-1.fc40\ncurl\t8.6.0-8.fc40\nbash\t5.2.26-3.fc40\n'
+`
+
+	for name, content := range map[string]string{"dnf": dnf, "rpm": rpm} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o755); err != nil {
+			t.Fatalf("fake %s command write failed: %v", name, err)
+		}
+	}
+
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("DSX_TEST_DNF_MODE", mode)
+}
@@ -13,6 +13,8 @@ import (
 
 // FlatpakUpdater は Flatpak パッケージマネージャの実装です。
 type FlatpakUpdater struct {
+	packageFilterSupport
+
 	useUser bool
 }
 
@@ -39,6 +41,10 @@ func (f *FlatpakUpdater) Configure(cfg config.ManagerConfig) error {
 		return nil
 	}
 
+	if err := f.configurePackageFilter(cfg); err != nil {
+		return err
+	}
+
 	if useUser, ok := cfg["use_user"].(bool); ok {
 		f.useUser = useUser
 		return nil
@@ -72,20 +78,32 @@ func (f *FlatpakUpdater) Check(ctx context.Context) (*CheckResult, error) {
 
 	packages := f.parseRemoteLSOutput(string(output))
 
-	return &CheckResult{
+	return f.filterCheckResult(&CheckResult{
 		AvailableUpdates: len(packages),
 		Packages:         packages,
-	}, nil
+	}), nil
 }
 
-func (f *FlatpakUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
-	result := &UpdateResult{}
+// ListInstalled は "flatpak list --app" でインストール済みアプリケーションを返します。
+func (f *FlatpakUpdater) ListInstalled(ctx context.Context) ([]PackageInfo, error) {
+	args := f.buildCommandArgs("list", "--app", "--columns=application,version")
+
+	output, err := runCommandOutputWithLocaleC(ctx, "flatpak", args, "flatpak list の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseTabSeparatedPackages(string(output)), nil
+}
 
+func (f *FlatpakUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
 	checkResult, err := f.Check(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	result := &UpdateResult{Held: checkResult.Held}
+
 	if checkResult.AvailableUpdates == 0 {
 		result.Message = "すべての Flatpak パッケージは最新です"
 		return result, nil
@@ -98,10 +116,11 @@ func (f *FlatpakUpdater) Update(ctx context.Context, opts UpdateOptions) (*Updat
 		return result, nil
 	}
 
-	args := f.buildCommandArgs("update", "-y", "--noninteractive")
+	// hold / ignore で除外がある場合は残りのアプリケーション ID のみ更新
+	updateArgs := append([]string{"update", "-y", "--noninteractive"}, packageNamesIfFiltered(checkResult)...)
+	args := f.buildCommandArgs(updateArgs...)
 	cmd := exec.CommandContext(ctx, "flatpak", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	attachCommandOutput(ctx, cmd)
 	cmd.Stdin = os.Stdin
 
 	if err := cmd.Run(); err != nil {
@@ -14,7 +14,13 @@ import (
 )
 
 // FwupdmgrUpdater は fwupdmgr (Linux Firmware 更新) の実装です。
-type FwupdmgrUpdater struct{}
+type FwupdmgrUpdater struct {
+	packageFilterSupport
+
+	// deviceIDs は直近の Check で取得したデバイス名から DeviceId への対応です。
+	// hold / ignore で除外がある場合に、残りのデバイスを個別に更新するために使用します。
+	deviceIDs map[string]string
+}
 
 // 起動時にレジストリへ登録します。
 func init() {
@@ -35,11 +41,19 @@ func (f *FwupdmgrUpdater) IsAvailable() bool {
 }
 
 func (f *FwupdmgrUpdater) Configure(cfg config.ManagerConfig) error {
-	// 現時点では設定項目なし
-	return nil
+	return f.configurePackageFilter(cfg)
 }
 
 func (f *FwupdmgrUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	result, err := f.checkUpdates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.filterCheckResult(result), nil
+}
+
+func (f *FwupdmgrUpdater) checkUpdates(ctx context.Context) (*CheckResult, error) {
 	cmd := exec.CommandContext(ctx, "fwupdmgr", "get-updates", "--json")
 
 	cmd.Env = append(os.Environ(), "LANG=C", "LC_ALL=C")
@@ -72,6 +86,8 @@ func (f *FwupdmgrUpdater) Check(ctx context.Context) (*CheckResult, error) {
 		)
 	}
 
+	f.deviceIDs = parseFwupdDeviceIDs(output)
+
 	return &CheckResult{
 		AvailableUpdates: len(packages),
 		Packages:         packages,
@@ -85,23 +101,45 @@ func (f *FwupdmgrUpdater) Update(ctx context.Context, opts UpdateOptions) (*Upda
 	}
 
 	if checkResult.AvailableUpdates == 0 {
-		return &UpdateResult{Message: "適用可能なファームウェア更新はありません"}, nil
+		return &UpdateResult{Message: "適用可能なファームウェア更新はありません", Held: checkResult.Held}, nil
 	}
 
 	if opts.DryRun {
 		return &UpdateResult{
 			Message:  fmt.Sprintf("%d 件のファームウェア更新が適用可能です（DryRunモード）", checkResult.AvailableUpdates),
 			Packages: checkResult.Packages,
+			Held:     checkResult.Held,
 		}, nil
 	}
 
+	if checkResult.hasFilteredPackages() {
+		return f.runDeviceUpdates(ctx, checkResult)
+	}
+
 	return f.runUpdateCommand(ctx, checkResult)
 }
 
+// runDeviceUpdates は hold / ignore で除外されなかったデバイスのみを DeviceId 指定で更新します。
+func (f *FwupdmgrUpdater) runDeviceUpdates(ctx context.Context, checkResult *CheckResult) (*UpdateResult, error) {
+	result := &UpdateResult{Held: checkResult.Held}
+
+	err := runPerPackageUpdate(ctx, checkResult, result, "fwupdmgr", func(name string) []string {
+		id := f.deviceIDs[name]
+		if id == "" {
+			id = name
+		}
+
+		return []string{"update", "-y", id}
+	})
+
+	result.Message = fmt.Sprintf("%d 件のファームウェア更新を実行しました", result.UpdatedCount)
+
+	return result, err
+}
+
 func (f *FwupdmgrUpdater) runUpdateCommand(ctx context.Context, checkResult *CheckResult) (*UpdateResult, error) {
 	cmd := exec.CommandContext(ctx, "fwupdmgr", "update", "-y")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	attachCommandOutput(ctx, cmd)
 	cmd.Stdin = os.Stdin
 
 	result := &UpdateResult{
@@ -172,6 +210,35 @@ func (f *FwupdmgrUpdater) parseGetUpdatesJSON(output []byte) ([]PackageInfo, err
 	return packages, nil
 }
 
+// parseFwupdDeviceIDs は get-updates の JSON からデバイス名と DeviceId の対応を取得します。
+func parseFwupdDeviceIDs(output []byte) map[string]string {
+	var payload map[string]interface{}
+	if err := json.Unmarshal(output, &payload); err != nil {
+		return nil
+	}
+
+	rawDevices, _ := lookupMapValueIgnoreCase(payload, "devices")
+	devices, _ := rawDevices.([]interface{})
+
+	ids := make(map[string]string, len(devices))
+
+	for _, raw := range devices {
+		device, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		name := strings.TrimSpace(lookupMapStringIgnoreCase(device, "name", "deviceName", "guid"))
+		id := strings.TrimSpace(lookupMapStringIgnoreCase(device, "deviceId", "id"))
+
+		if name != "" && id != "" {
+			ids[name] = id
+		}
+	}
+
+	return ids
+}
+
 func isNoFwupdmgrUpdatesMessage(output string) bool {
 	normalized := strings.ToLower(output)
 
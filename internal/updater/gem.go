@@ -10,7 +10,9 @@ import (
 )
 
 // GemUpdater は gem (Ruby Gems) の実装です。
-type GemUpdater struct{}
+type GemUpdater struct {
+	packageFilterSupport
+}
 
 // 起動時にレジストリに登録
 func init() {
@@ -31,8 +33,7 @@ func (g *GemUpdater) IsAvailable() bool {
 }
 
 func (g *GemUpdater) Configure(cfg config.ManagerConfig) error {
-	// 現時点では設定項目なし
-	return nil
+	return g.configurePackageFilter(cfg)
 }
 
 func (g *GemUpdater) Check(ctx context.Context) (*CheckResult, error) {
@@ -43,10 +44,27 @@ func (g *GemUpdater) Check(ctx context.Context) (*CheckResult, error) {
 
 	packages := g.parseOutdatedOutput(string(output))
 
-	return &CheckResult{
+	return g.filterCheckResult(&CheckResult{
 		AvailableUpdates: len(packages),
 		Packages:         packages,
-	}, nil
+	}), nil
+}
+
+// ListInstalled は "gem list --local" でインストール済みの gem を返します。
+func (g *GemUpdater) ListInstalled(ctx context.Context) ([]PackageInfo, error) {
+	output, err := runCommandOutputWithLocaleC(ctx, "gem", []string{"list", "--local"}, "gem list の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseGemListOutput(string(output)), nil
+}
+
+// RollbackCommand は gem install -v で更新前のバージョンを入れ直すコマンドを返します。
+func (g *GemUpdater) RollbackCommand(pkg PackageInfo) (*RollbackCommand, error) {
+	return newRollbackCommand(pkg, "gem", func(version string) []string {
+		return []string{"install", pkg.Name, "-v", version}
+	})
 }
 
 func (g *GemUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
@@ -64,7 +82,7 @@ func (g *GemUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateRes
 			return fmt.Sprintf("%d 件の gem パッケージが更新可能です（DryRunモード）", count)
 		},
 		"gem",
-		[]string{updateCommand},
+		selectUpdateArgs(checkResult, []string{updateCommand}, []string{updateCommand}),
 		"gem update に失敗: %w",
 		func(count int) string {
 			return fmt.Sprintf("%d 件の gem パッケージを更新しました", count)
@@ -97,6 +115,28 @@ func (g *GemUpdater) parseOutdatedOutput(output string) []PackageInfo {
 	return packages
 }
 
+// parseGemListOutput は "gem list --local" の出力（例: "rake (13.1.0, 13.0.6)"）をパースします。
+func parseGemListOutput(output string) []PackageInfo {
+	lines := strings.Split(output, "\n")
+	packages := make([]PackageInfo, 0, len(lines))
+
+	for _, line := range lines {
+		name, versions, ok := strings.Cut(strings.TrimSpace(line), " (")
+		if !ok || name == "" {
+			continue
+		}
+
+		current, _, _ := strings.Cut(strings.TrimSuffix(versions, ")"), ",")
+
+		packages = append(packages, PackageInfo{
+			Name:           name,
+			CurrentVersion: normalizeGemVersion(current),
+		})
+	}
+
+	return packages
+}
+
 func parseGemOutdatedLine(line string) (name, current, next string, ok bool) {
 	openIdx := strings.Index(line, "(")
 
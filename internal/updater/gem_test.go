@@ -293,3 +293,14 @@ exit 1
 		}
 	}
 }
+
+func TestParseGemListOutput(t *testing.T) {
+	output := "\n*** LOCAL GEMS ***\n\nbundler (2.5.6, default: 2.4.19)\nrake (13.1.0, 13.0.6)\n"
+
+	got := parseGemListOutput(output)
+
+	assert.Equal(t, []PackageInfo{
+		{Name: "bundler", CurrentVersion: "2.5.6"},
+		{Name: "rake", CurrentVersion: "13.1.0"},
+	}, got)
+}
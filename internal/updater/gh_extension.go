@@ -0,0 +1,166 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/scottlz0310/dsx/internal/config"
+)
+
+// reGhExtensionUpgraded は "gh extension upgrade" の "[dash]: would have upgraded from v3.9.0 to v4.0.0" 行に一致します。
+var reGhExtensionUpgraded = regexp.MustCompile(`^\[([^\]]+)\]:\s+(?:would have )?upgraded from (\S+) to (\S+)`)
+
+// GhExtensionUpdater は GitHub CLI 拡張機能（gh extension）の実装です。
+// gh の呼び出しは repo コマンドと同じく rate limit 時にバックオフして再試行します。
+type GhExtensionUpdater struct {
+	packageFilterSupport
+}
+
+// 起動時にレジストリに登録
+func init() {
+	Register(&GhExtensionUpdater{})
+}
+
+func (g *GhExtensionUpdater) Name() string {
+	return "gh-ext"
+}
+
+func (g *GhExtensionUpdater) DisplayName() string {
+	return "GitHub CLI 拡張機能 (gh extension)"
+}
+
+func (g *GhExtensionUpdater) IsAvailable() bool {
+	_, err := exec.LookPath("gh")
+	return err == nil
+}
+
+func (g *GhExtensionUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	return g.configurePackageFilter(cfg)
+}
+
+func (g *GhExtensionUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	installed, err := g.ListInstalled(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 拡張機能がない場合 gh extension upgrade --all はエラーになるため呼び出さない
+	if len(installed) == 0 {
+		return &CheckResult{Packages: []PackageInfo{}}, nil
+	}
+
+	output, err := runGitHubCommandOutput(ctx, "gh", []string{"extension", upgradeCommand, "--all", "--dry-run"},
+		"gh extension upgrade --dry-run の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	packages := parseGhExtensionUpgradeOutput(string(output))
+
+	return g.filterCheckResult(&CheckResult{
+		AvailableUpdates: len(packages),
+		Packages:         packages,
+	}), nil
+}
+
+func (g *GhExtensionUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	// まず更新確認
+	checkResult, err := g.Check(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UpdateResult{Held: checkResult.Held}
+
+	if checkResult.AvailableUpdates == 0 {
+		result.Message = "すべての拡張機能は最新です"
+		return result, nil
+	}
+
+	if opts.DryRun {
+		result.Message = fmt.Sprintf("%d 件の拡張機能が更新可能です（DryRunモード）", checkResult.AvailableUpdates)
+		result.Packages = checkResult.Packages
+
+		return result, nil
+	}
+
+	// gh extension upgrade は名前を1つしか受け付けないため、hold / ignore で除外がある場合は1件ずつ更新
+	argsList := [][]string{{"extension", upgradeCommand, "--all"}}
+	if checkResult.hasFilteredPackages() {
+		argsList = argsList[:0]
+		for _, name := range packageNames(checkResult.Packages) {
+			argsList = append(argsList, []string{"extension", upgradeCommand, name})
+		}
+	}
+
+	for _, args := range argsList {
+		if _, err := runGitHubCommandOutput(ctx, "gh", args, "%w"); err != nil {
+			result.Errors = append(result.Errors, err)
+			return result, fmt.Errorf("gh extension upgrade に失敗: %w", err)
+		}
+	}
+
+	result.UpdatedCount = checkResult.AvailableUpdates
+	result.Packages = checkResult.Packages
+	result.Message = fmt.Sprintf("%d 件の拡張機能を更新しました", result.UpdatedCount)
+
+	return result, nil
+}
+
+// ListInstalled は "gh extension list" でインストール済みの拡張機能を返します。
+func (g *GhExtensionUpdater) ListInstalled(ctx context.Context) ([]PackageInfo, error) {
+	output, err := runGitHubCommandOutput(ctx, "gh", []string{"extension", "list"}, "gh extension list の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseGhExtensionList(string(output)), nil
+}
+
+// parseGhExtensionList は "gh dash\tdlvhdr/gh-dash\tv4.0.0" 形式（非 TTY 時のタブ区切り）の行を解析します。
+// 名前は gh extension upgrade に渡せるよう "gh " を除いた形で返します。
+func parseGhExtensionList(output string) []PackageInfo {
+	packages := make([]PackageInfo, 0)
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+
+		name := strings.TrimSpace(strings.TrimPrefix(fields[0], "gh "))
+		if name == "" {
+			continue
+		}
+
+		pkg := PackageInfo{Name: name}
+		if len(fields) >= 3 {
+			pkg.CurrentVersion = strings.TrimSpace(fields[2])
+		}
+
+		packages = append(packages, pkg)
+	}
+
+	return packages
+}
+
+// parseGhExtensionUpgradeOutput は gh extension upgrade（--dry-run を含む）の出力から更新される拡張機能を返します。
+// "already up to date" やピン留め・ローカル拡張機能の行は対象外です。
+func parseGhExtensionUpgradeOutput(output string) []PackageInfo {
+	packages := make([]PackageInfo, 0)
+
+	for _, line := range strings.Split(output, "\n") {
+		m := reGhExtensionUpgraded.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+
+		packages = append(packages, PackageInfo{Name: m[1], CurrentVersion: m[2], NewVersion: m[3]})
+	}
+
+	return packages
+}
@@ -0,0 +1,234 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGhExtensionList(t *testing.T) {
+	output := "gh dash\tdlvhdr/gh-dash\tv4.0.0\ngh poi\tseachicken/gh-poi\t0f1e2d3\ngh local\t\t\n\n"
+
+	assert.Equal(t, []PackageInfo{
+		{Name: "dash", CurrentVersion: "v4.0.0"},
+		{Name: "poi", CurrentVersion: "0f1e2d3"},
+		{Name: "local"},
+	}, parseGhExtensionList(output))
+
+	assert.Empty(t, parseGhExtensionList(""))
+}
+
+func TestParseGhExtensionUpgradeOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected []PackageInfo
+	}{
+		{name: "空の出力", output: "", expected: []PackageInfo{}},
+		{
+			name: "DryRun の出力",
+			output: `[dash]: would have upgraded from v3.9.0 to v4.0.0
+[poi]: already up to date
+[pinned]: pinned extensions can not be upgraded
+[local]: local extensions can not be upgraded
+[copilot]: would have upgraded from 0f1e2d3 to 9a8b7c6
+`,
+			expected: []PackageInfo{
+				{Name: "dash", CurrentVersion: "v3.9.0", NewVersion: "v4.0.0"},
+				{Name: "copilot", CurrentVersion: "0f1e2d3", NewVersion: "9a8b7c6"},
+			},
+		},
+		{
+			name:     "実際の更新の出力",
+			output:   "[dash]: upgraded from v3.9.0 to v4.0.0\n",
+			expected: []PackageInfo{{Name: "dash", CurrentVersion: "v3.9.0", NewVersion: "v4.0.0"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseGhExtensionUpgradeOutput(tt.output))
+		})
+	}
+}
+
+func TestGhExtensionUpdater_Update(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mode        string
+		cfg         config.ManagerConfig
+		opts        UpdateOptions
+		wantUpdated int
+		wantCalls   []string
+		wantErr     string
+		msgContains string
+	}{
+		{
+			name:        "拡張機能がなければ upgrade を呼ばない",
+			mode:        "empty",
+			wantCalls:   []string{"gh extension list"},
+			msgContains: "すべての拡張機能は最新です",
+		},
+		{
+			name:        "DryRunは --dry-run の結果のみ",
+			opts:        UpdateOptions{DryRun: true},
+			wantCalls:   []string{"gh extension list", "gh extension upgrade --all --dry-run"},
+			msgContains: "2 件の拡張機能が更新可能です（DryRunモード）",
+		},
+		{
+			name:        "すべて更新",
+			wantUpdated: 2,
+			wantCalls:   []string{"gh extension list", "gh extension upgrade --all --dry-run", "gh extension upgrade --all"},
+			msgContains: "2 件の拡張機能を更新しました",
+		},
+		{
+			name:        "hold 以外を1件ずつ更新",
+			cfg:         config.ManagerConfig{"hold": []interface{}{"dash"}},
+			wantUpdated: 1,
+			wantCalls:   []string{"gh extension list", "gh extension upgrade --all --dry-run", "gh extension upgrade copilot"},
+		},
+		{
+			name:    "更新失敗",
+			mode:    "upgrade_error",
+			wantErr: "gh extension upgrade に失敗",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := setupFakeGhExtension(t, tc.mode)
+
+			g := &GhExtensionUpdater{}
+			require.NoError(t, g.Configure(tc.cfg))
+
+			got, err := g.Update(context.Background(), tc.opts)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantUpdated, got.UpdatedCount)
+			assert.Contains(t, got.Message, tc.msgContains)
+			assert.Equal(t, tc.wantCalls, readFakeGhCalls(t, dir))
+		})
+	}
+}
+
+func readFakeGhCalls(t *testing.T, dir string) []string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(dir, "calls"))
+	require.NoError(t, err)
+
+	// Windows の .cmd は CRLF で追記するため改行を揃える
+	return strings.Split(strings.TrimSpace(strings.ReplaceAll(string(data), "\r\n", "\n")), "\n")
+}
+
+// setupFakeGhExtension は PATH の先頭に偽の gh コマンドを配置し、呼び出しを calls に記録します。
+// dash と copilot が更新可能、poi は最新です。
+func setupFakeGhExtension(t *testing.T, mode string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	writeFakeGhCommand(t, dir)
+
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("DSX_TEST_GH_DIR", dir)
+	t.Setenv("DSX_TEST_GH_MODE", mode)
+
+	return dir
+}
+
+func writeFakeGhCommand(t *testing.T, dir string) {
+	t.Helper()
+
+	var (
+		fileName string
+		content  string
+	)
+
+	if runtime.GOOS == "windows" {
+		// 一覧の区切りはタブのため、echo の行に直接埋め込む
+		fileName = "gh.cmd"
+		content = `@echo off
+set mode=%DSX_TEST_GH_MODE%
+>>"%DSX_TEST_GH_DIR%\calls" echo gh %*
+if not "%1"=="extension" goto invalid
+if "%2"=="list" goto dolist
+if "%2"=="upgrade" goto doupgrade
+:invalid
+echo invalid args 1>&2
+exit /b 1
+:dolist
+if "%mode%"=="empty" exit /b 0
+echo gh dash` + "\t" + `dlvhdr/gh-dash` + "\t" + `v3.9.0
+echo gh copilot` + "\t" + `github/gh-copilot` + "\t" + `v1.0.0
+echo gh poi` + "\t" + `seachicken/gh-poi` + "\t" + `v0.9.0
+exit /b 0
+:doupgrade
+if "%4"=="--dry-run" (
+  echo [dash]: would have upgraded from v3.9.0 to v4.0.0
+  echo [copilot]: would have upgraded from v1.0.0 to v1.0.5
+  echo [poi]: already up to date
+  exit /b 0
+)
+if "%mode%"=="upgrade_error" (
+  echo [dash]: failed to download asset
+  echo some extensions failed to upgrade 1>&2
+  exit /b 1
+)
+exit /b 0
+`
+	} else {
+		fileName = "gh"
+		content = `#!/bin/sh
+mode="${DSX_TEST_GH_MODE}"
+echo "gh $*" >> "${DSX_TEST_GH_DIR}/calls"
+if [ "$1" != "extension" ]; then
+  echo "invalid args" 1>&2
+  exit 1
+fi
+case "$2" in
+list)
+  if [ "${mode}" = "empty" ]; then
+    exit 0
+  fi
+  printf 'gh dash\tdlvhdr/gh-dash\tv3.9.0\n'
+  printf 'gh copilot\tgithub/gh-copilot\tv1.0.0\n'
+  printf 'gh poi\tseachicken/gh-poi\tv0.9.0\n'
+  exit 0
+  ;;
+upgrade)
+  if [ "$4" = "--dry-run" ]; then
+    echo "[dash]: would have upgraded from v3.9.0 to v4.0.0"
+    echo "[copilot]: would have upgraded from v1.0.0 to v1.0.5"
+    echo "[poi]: already up to date"
+    exit 0
+  fi
+  if [ "${mode}" = "upgrade_error" ]; then
+    echo "[dash]: failed to download asset"
+    echo "some extensions failed to upgrade" 1>&2
+    exit 1
+  fi
+  exit 0
+  ;;
+esac
+echo "invalid args" 1>&2
+exit 1
+`
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, fileName), []byte(content), 0o755); err != nil {
+		t.Fatalf("fake gh command write failed: %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/scottlz0310/dsx/internal/ghretry"
+	"github.com/scottlz0310/dsx/internal/runner"
+)
+
+// runGitHubCommandOutput は GitHub にアクセスするコマンド（gh / kubectl krew）を LANG/LC_ALL=C で実行し、標準出力を返します。
+// rate limit や一時的な障害の場合は、repo コマンドの gh 呼び出しと同じく ghretry でバックオフしながら再試行します。
+// runner のジョブ出力ログが ctx に設定されている場合は、出力をログにも書き込みます。
+func runGitHubCommandOutput(ctx context.Context, command string, args []string, errFormat string) ([]byte, error) {
+	output, stderr, err := ghretry.CommandOutput(ctx, commandWithLocaleC, "", command, args...)
+
+	if jobOutput := runner.JobOutput(ctx); jobOutput != nil {
+		fmt.Fprintf(jobOutput, "$ %s %s\n%s\n", command, strings.Join(args, " "), combineCommandOutputs(output, []byte(stderr)))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf(errFormat, buildCommandOutputErr(err, []byte(stderr)))
+	}
+
+	return output, nil
+}
+
+func commandWithLocaleC(ctx context.Context, name string, arg ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, arg...)
+	cmd.Env = append(os.Environ(), "LANG=C", "LC_ALL=C")
+
+	return cmd
+}
@@ -23,6 +23,8 @@ const goTargetsEmptyMessage = "Go updater の targets は未設定です。\n" +
 // GoUpdater は Go ツール (go install) の更新を管理します。
 // go install コマンドでインストールしたバイナリを最新版に更新します。
 type GoUpdater struct {
+	packageFilterSupport
+
 	// targets は更新対象のパッケージパス一覧
 	// 例: ["golang.org/x/tools/gopls@latest", "github.com/golangci/golangci-lint/cmd/golangci-lint@latest"]
 	targets []string
@@ -57,6 +59,10 @@ func (g *GoUpdater) Configure(cfg config.ManagerConfig) error {
 		return nil
 	}
 
+	if err := g.configurePackageFilter(cfg); err != nil {
+		return err
+	}
+
 	// targets の設定を読み込む
 	if targets, ok := cfg["targets"]; ok {
 		switch v := targets.(type) {
@@ -88,12 +94,14 @@ func (g *GoUpdater) Check(ctx context.Context) (*CheckResult, error) {
 		return nil, err
 	}
 
+	held := g.applyPackageFilter(plan)
 	packages := planPackages(plan.installTargets())
 
 	return &CheckResult{
 		AvailableUpdates: len(packages),
 		Packages:         packages,
 		Message:          plan.checkMessage(),
+		Held:             held,
 	}, nil
 }
 
@@ -115,6 +123,8 @@ func (g *GoUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResu
 		return nil, err
 	}
 
+	result.Held = g.applyPackageFilter(plan)
+
 	if opts.DryRun {
 		result.Packages = planPackages(plan.installTargets())
 		result.Message = plan.dryRunMessage()
@@ -149,6 +159,89 @@ func (g *GoUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResu
 	return result, nil
 }
 
+// applyPackageFilter は hold / ignore に一致するインストール対象を plan から取り除き、保留分を返します。
+// Go ツールはツール名（例: "gopls"）とパッケージパスのどちらでも指定できます。
+func (g *GoUpdater) applyPackageFilter(plan *goUpdatePlan) []PackageInfo {
+	if g.filter.IsEmpty() {
+		return nil
+	}
+
+	var held []PackageInfo
+
+	decisions := make([]*goTargetDecision, 0, len(plan.Decisions))
+
+	for _, decision := range plan.Decisions {
+		if !decision.shouldInstall() {
+			decisions = append(decisions, decision)
+			continue
+		}
+
+		switch {
+		case g.filter.IsIgnored(decision.ToolName) || g.filter.IsIgnored(decision.PackagePath):
+		case g.filter.IsHeld(decision.ToolName) || g.filter.IsHeld(decision.PackagePath):
+			held = append(held, decision.PackageInfo())
+		default:
+			decisions = append(decisions, decision)
+		}
+	}
+
+	plan.Decisions = decisions
+
+	return held
+}
+
+// ListInstalled は go.targets に登録されたツールを返します。
+// hold はツール名とパッケージパスのどちらでも指定できるため、両方を返します。
+func (g *GoUpdater) ListInstalled(context.Context) ([]PackageInfo, error) {
+	packages := make([]PackageInfo, 0, len(g.targets)*2)
+
+	for _, raw := range g.targets {
+		target, err := parseGoTarget(raw)
+		if err != nil {
+			continue
+		}
+
+		packages = append(packages,
+			PackageInfo{Name: extractToolName(target.PackagePath), CurrentVersion: target.Version},
+			PackageInfo{Name: target.PackagePath, CurrentVersion: target.Version},
+		)
+	}
+
+	return packages, nil
+}
+
+// RollbackCommand は go.targets のパッケージを更新前のバージョンで go install し直すコマンドを返します。
+// パッケージはツール名（例: "gopls"）とパッケージパスのどちらでも指定できます。
+func (g *GoUpdater) RollbackCommand(pkg PackageInfo) (*RollbackCommand, error) {
+	packagePath := g.resolveTargetPackagePath(pkg.Name)
+	if packagePath == "" {
+		return nil, fmt.Errorf("%s は go.targets に見つかりません", pkg.Name)
+	}
+
+	if pkg.CurrentVersion == selfupdate.DevelVersion {
+		return nil, fmt.Errorf("%s: %w", pkg.Name, ErrRollbackVersionUnknown)
+	}
+
+	return newRollbackCommand(pkg, "go", func(version string) []string {
+		return []string{"install", packagePath + "@" + version}
+	})
+}
+
+func (g *GoUpdater) resolveTargetPackagePath(name string) string {
+	for _, raw := range g.targets {
+		target, err := parseGoTarget(raw)
+		if err != nil {
+			continue
+		}
+
+		if target.PackagePath == name || extractToolName(target.PackagePath) == name {
+			return target.PackagePath
+		}
+	}
+
+	return ""
+}
+
 // ConfigureRuntimeVersion は実行中 dsx のバージョンを Go updater に渡します。
 func (g *GoUpdater) ConfigureRuntimeVersion(currentVersion string) {
 	g.currentVersion = currentVersion
@@ -481,8 +574,7 @@ func (g *GoUpdater) runGoInstall(ctx context.Context, target string) error {
 	}
 
 	cmd := exec.CommandContext(ctx, "go", "install", target)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	attachCommandOutput(ctx, cmd)
 	cmd.Env = os.Environ()
 
 	return cmd.Run()
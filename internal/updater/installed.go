@@ -0,0 +1,54 @@
+package updater
+
+import (
+	"strings"
+)
+
+// parseTabSeparatedPackages は "名前<TAB>バージョン" 形式の行をパースします。
+// dpkg-query や flatpak list --columns の出力に使用します。
+func parseTabSeparatedPackages(output string) []PackageInfo {
+	packages := make([]PackageInfo, 0)
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+		if fields[0] == "" {
+			continue
+		}
+
+		pkg := PackageInfo{Name: strings.TrimSpace(fields[0])}
+		if len(fields) > 1 {
+			pkg.CurrentVersion = strings.TrimSpace(fields[1])
+		}
+
+		packages = append(packages, pkg)
+	}
+
+	return packages
+}
+
+// parseFieldPackages は "名前 バージョン ..." 形式（空白区切り）の行をパースします。
+// skipHeader が true の場合は先頭行を見出しとして読み飛ばします。
+func parseFieldPackages(output string, skipHeader bool) []PackageInfo {
+	lines := strings.Split(output, "\n")
+	if skipHeader && len(lines) > 0 {
+		lines = lines[1:]
+	}
+
+	packages := make([]PackageInfo, 0, len(lines))
+
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		pkg := PackageInfo{Name: fields[0]}
+		if len(fields) > 1 {
+			pkg.CurrentVersion = fields[1]
+		}
+
+		packages = append(packages, pkg)
+	}
+
+	return packages
+}
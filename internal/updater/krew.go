@@ -0,0 +1,211 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/scottlz0310/dsx/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+const krewDefaultIndex = "default"
+
+// KrewUpdater は kubectl krew プラグインの実装です。
+// インデックスとプラグインの取得先は GitHub のため、krew の呼び出しは gh-ext と同じく rate limit 時にバックオフして再試行します。
+type KrewUpdater struct {
+	packageFilterSupport
+}
+
+// 起動時にレジストリに登録
+func init() {
+	Register(&KrewUpdater{})
+}
+
+func (k *KrewUpdater) Name() string {
+	return "krew"
+}
+
+func (k *KrewUpdater) DisplayName() string {
+	return "kubectl krew プラグイン"
+}
+
+func (k *KrewUpdater) IsAvailable() bool {
+	for _, name := range []string{"kubectl", "kubectl-krew"} {
+		if _, err := exec.LookPath(name); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (k *KrewUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	return k.configurePackageFilter(cfg)
+}
+
+// Check はローカルのプラグインインデックスと比較して更新可能なプラグインを返します。
+// 確認だけで状態を変えないよう、インデックスの更新（kubectl krew update）は Update で行います。
+func (k *KrewUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	installed, err := k.ListInstalled(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	packages := make([]PackageInfo, 0, len(installed))
+
+	for _, pkg := range installed {
+		output, err := runCommandOutputWithLocaleC(ctx, "kubectl", []string{"krew", "info", pkg.Name},
+			"kubectl krew info の実行に失敗: %w")
+		if err != nil {
+			// インデックスから削除されたプラグインなどは更新対象外
+			continue
+		}
+
+		latest := parseKrewInfoVersion(string(output))
+		if latest == "" || latest == pkg.CurrentVersion {
+			continue
+		}
+
+		packages = append(packages, PackageInfo{Name: pkg.Name, CurrentVersion: pkg.CurrentVersion, NewVersion: latest})
+	}
+
+	return k.filterCheckResult(&CheckResult{
+		AvailableUpdates: len(packages),
+		Packages:         packages,
+	}), nil
+}
+
+func (k *KrewUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	// インデックスを更新してから更新確認
+	if _, err := runGitHubCommandOutput(ctx, "kubectl", []string{"krew", updateCommand}, "kubectl krew update の実行に失敗: %w"); err != nil {
+		return nil, err
+	}
+
+	checkResult, err := k.Check(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UpdateResult{Held: checkResult.Held}
+
+	if checkResult.AvailableUpdates == 0 {
+		result.Message = "すべてのプラグインは最新です"
+		return result, nil
+	}
+
+	if opts.DryRun {
+		result.Message = fmt.Sprintf("%d 件のプラグインが更新可能です（DryRunモード）", checkResult.AvailableUpdates)
+		result.Packages = checkResult.Packages
+
+		return result, nil
+	}
+
+	// インデックスは更新済み。hold / ignore で除外がある場合は残りのプラグインのみ更新
+	baseArgs := []string{"krew", upgradeCommand, "--no-update-index"}
+
+	args := selectUpdateArgs(checkResult, baseArgs, baseArgs)
+	if _, err := runGitHubCommandOutput(ctx, "kubectl", args, "%w"); err != nil {
+		result.Errors = append(result.Errors, err)
+		return result, fmt.Errorf("kubectl krew upgrade に失敗: %w", err)
+	}
+
+	result.UpdatedCount = checkResult.AvailableUpdates
+	result.Packages = checkResult.Packages
+	result.Message = fmt.Sprintf("%d 件のプラグインを更新しました", result.UpdatedCount)
+
+	return result, nil
+}
+
+// ListInstalled は krew のレシート（$KREW_ROOT/receipts/*.yaml）からインストール済みのプラグインを返します。
+// "kubectl krew list" は端末以外への出力ではバージョンを表示しないため、レシートを直接読み取ります。
+func (k *KrewUpdater) ListInstalled(_ context.Context) ([]PackageInfo, error) {
+	receiptsDir := filepath.Join(krewRoot(), "receipts")
+
+	paths, err := filepath.Glob(filepath.Join(receiptsDir, "*.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("krew のレシートの列挙に失敗: %w", err)
+	}
+
+	packages := make([]PackageInfo, 0, len(paths))
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("krew のレシートの読み込みに失敗: %w", err)
+		}
+
+		pkg, ok := parseKrewReceipt(data)
+		if !ok {
+			continue
+		}
+
+		packages = append(packages, pkg)
+	}
+
+	sort.Slice(packages, func(i, j int) bool { return packages[i].Name < packages[j].Name })
+
+	return packages, nil
+}
+
+// krewRoot は KREW_ROOT（未設定の場合は ~/.krew）を返します。
+func krewRoot() string {
+	if root := os.Getenv("KREW_ROOT"); root != "" {
+		return root
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".krew"
+	}
+
+	return filepath.Join(home, ".krew")
+}
+
+// parseKrewReceipt はレシートからプラグイン名とバージョンを返します。
+// default 以外のインデックスのプラグインは krew のコマンドと同じく "index/name" 形式の名前にします。
+func parseKrewReceipt(data []byte) (PackageInfo, bool) {
+	var receipt struct {
+		Metadata struct {
+			Name string `yaml:"name"`
+		} `yaml:"metadata"`
+		Spec struct {
+			Version string `yaml:"version"`
+		} `yaml:"spec"`
+		Status struct {
+			Source struct {
+				Name string `yaml:"name"`
+			} `yaml:"source"`
+		} `yaml:"status"`
+	}
+
+	if err := yaml.Unmarshal(data, &receipt); err != nil || receipt.Metadata.Name == "" {
+		return PackageInfo{}, false
+	}
+
+	name := receipt.Metadata.Name
+	if index := receipt.Status.Source.Name; index != "" && index != krewDefaultIndex {
+		name = index + "/" + name
+	}
+
+	return PackageInfo{Name: name, CurrentVersion: receipt.Spec.Version}, true
+}
+
+// parseKrewInfoVersion は "kubectl krew info" の "VERSION: v0.9.5" 行からインデックス上のバージョンを返します。
+func parseKrewInfoVersion(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if version, found := strings.CutPrefix(strings.TrimSpace(line), "VERSION:"); found {
+			return strings.TrimSpace(version)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,317 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKrewReceipt(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		want   PackageInfo
+		wantOK bool
+	}{
+		{
+			name: "default インデックス",
+			data: `apiVersion: krew.googlecontainertools.github.com/v1alpha2
+kind: Plugin
+metadata:
+  name: ctx
+spec:
+  version: v0.9.5
+status:
+  source:
+    name: default
+`,
+			want:   PackageInfo{Name: "ctx", CurrentVersion: "v0.9.5"},
+			wantOK: true,
+		},
+		{
+			name:   "カスタムインデックスは index/name",
+			data:   "metadata:\n  name: foo\nspec:\n  version: v1.0.0\nstatus:\n  source:\n    name: company\n",
+			want:   PackageInfo{Name: "company/foo", CurrentVersion: "v1.0.0"},
+			wantOK: true,
+		},
+		{
+			name:   "source のない古いレシート",
+			data:   "metadata:\n  name: ns\nspec:\n  version: v0.9.4\n",
+			want:   PackageInfo{Name: "ns", CurrentVersion: "v0.9.4"},
+			wantOK: true,
+		},
+		{name: "名前のないレシート", data: "spec:\n  version: v1.0.0\n"},
+		{name: "不正なYAML", data: "metadata: ["},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseKrewReceipt([]byte(tt.data))
+			assert.Equal(t, tt.wantOK, ok)
+
+			if tt.wantOK {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseKrewInfoVersion(t *testing.T) {
+	output := `NAME: ctx
+INDEX: default
+URI: https://github.com/ahmetb/kubectx/releases/download/v0.9.5/kubectx
+SHA256: 0123456789abcdef
+VERSION: v0.9.5
+HOMEPAGE: https://github.com/ahmetb/kubectx
+`
+
+	assert.Equal(t, "v0.9.5", parseKrewInfoVersion(output))
+	assert.Empty(t, parseKrewInfoVersion("error: plugin not found"))
+}
+
+func TestKrewUpdater_Check(t *testing.T) {
+	dir := setupFakeKrew(t, "")
+
+	k := &KrewUpdater{}
+	got, err := k.Check(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, got.AvailableUpdates)
+	assert.Equal(t, []PackageInfo{
+		{Name: "company/foo", CurrentVersion: "v1.0.0", NewVersion: "v1.1.0"},
+		{Name: "ctx", CurrentVersion: "v0.9.4", NewVersion: "v0.9.5"},
+	}, got.Packages)
+
+	// 確認ではローカルのインデックスを更新しない
+	assert.NotContains(t, readFakeKrewCalls(t, dir), "kubectl krew update")
+}
+
+func TestKrewUpdater_Update(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mode        string
+		cfg         config.ManagerConfig
+		opts        UpdateOptions
+		wantUpdated int
+		wantPkgs    []PackageInfo
+		wantUpgrade string
+		wantErr     string
+		msgContains string
+	}{
+		{
+			name:        "DryRunは更新しない",
+			opts:        UpdateOptions{DryRun: true},
+			wantPkgs:    []PackageInfo{{Name: "company/foo", CurrentVersion: "v1.0.0", NewVersion: "v1.1.0"}, {Name: "ctx", CurrentVersion: "v0.9.4", NewVersion: "v0.9.5"}},
+			msgContains: "2 件のプラグインが更新可能です（DryRunモード）",
+		},
+		{
+			name:        "インデックス更新後にまとめて更新",
+			wantUpdated: 2,
+			wantUpgrade: "kubectl krew upgrade --no-update-index",
+			msgContains: "2 件のプラグインを更新しました",
+		},
+		{
+			name:        "hold 以外を指定して更新",
+			cfg:         config.ManagerConfig{"hold": []interface{}{"company/*"}},
+			wantUpdated: 1,
+			wantUpgrade: "kubectl krew upgrade --no-update-index ctx",
+		},
+		{
+			name:    "インデックス更新の失敗",
+			mode:    "update_error",
+			wantErr: "kubectl krew update の実行に失敗",
+		},
+		{
+			name:    "更新失敗",
+			mode:    "upgrade_error",
+			wantErr: "kubectl krew upgrade に失敗",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := setupFakeKrew(t, tc.mode)
+
+			k := &KrewUpdater{}
+			require.NoError(t, k.Configure(tc.cfg))
+
+			got, err := k.Update(context.Background(), tc.opts)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantUpdated, got.UpdatedCount)
+			assert.Contains(t, got.Message, tc.msgContains)
+
+			if tc.wantPkgs != nil {
+				assert.Equal(t, tc.wantPkgs, got.Packages)
+			}
+
+			calls := readFakeKrewCalls(t, dir)
+			assert.Equal(t, "kubectl krew update", calls[0])
+
+			var upgrade string
+
+			for _, call := range calls {
+				if strings.HasPrefix(call, "kubectl krew upgrade") {
+					upgrade = call
+				}
+			}
+
+			assert.Equal(t, tc.wantUpgrade, upgrade)
+		})
+	}
+}
+
+func readFakeKrewCalls(t *testing.T, dir string) []string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(dir, "calls"))
+	require.NoError(t, err)
+
+	// Windows の .cmd は CRLF で追記するため改行を揃える
+	return strings.Split(strings.TrimSpace(strings.ReplaceAll(string(data), "\r\n", "\n")), "\n")
+}
+
+// setupFakeKrew は偽の kubectl / kubectl-krew と KREW_ROOT のレシートを用意します。
+// ctx（v0.9.4 → v0.9.5）と company/foo（v1.0.0 → v1.1.0）が更新可能、ns は最新です。
+func setupFakeKrew(t *testing.T, mode string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	writeFakeKrewCommand(t, dir)
+
+	receipts := map[string]string{
+		"ctx.yaml": "metadata:\n  name: ctx\nspec:\n  version: v0.9.4\nstatus:\n  source:\n    name: default\n",
+		"ns.yaml":  "metadata:\n  name: ns\nspec:\n  version: v0.9.5\nstatus:\n  source:\n    name: default\n",
+		"foo.yaml": "metadata:\n  name: foo\nspec:\n  version: v1.0.0\nstatus:\n  source:\n    name: company\n",
+	}
+
+	krewRoot := filepath.Join(dir, "krew")
+	require.NoError(t, os.MkdirAll(filepath.Join(krewRoot, "receipts"), 0o755))
+
+	for name, content := range receipts {
+		require.NoError(t, os.WriteFile(filepath.Join(krewRoot, "receipts", name), []byte(content), 0o644))
+	}
+
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("KREW_ROOT", krewRoot)
+	t.Setenv("DSX_TEST_KREW_DIR", dir)
+	t.Setenv("DSX_TEST_KREW_MODE", mode)
+
+	return dir
+}
+
+// writeFakeKrewCommand は偽の kubectl と、krew の導入判定に使う kubectl-krew を配置します。
+func writeFakeKrewCommand(t *testing.T, dir string) {
+	t.Helper()
+
+	var (
+		ext     string
+		kubectl string
+		krew    string
+	)
+
+	if runtime.GOOS == "windows" {
+		ext = ".cmd"
+		kubectl = `@echo off
+set mode=%DSX_TEST_KREW_MODE%
+>>"%DSX_TEST_KREW_DIR%\calls" echo kubectl %*
+if not "%1"=="krew" goto invalid
+if "%2"=="update" goto doupdate
+if "%2"=="info" goto doinfo
+if "%2"=="upgrade" goto doupgrade
+:invalid
+echo invalid args 1>&2
+exit /b 1
+:doupdate
+if "%mode%"=="update_error" (
+  echo failed to update the local index: exit status 128 1>&2
+  exit /b 1
+)
+echo Updated the local copy of plugin index. 1>&2
+exit /b 0
+:doinfo
+if "%3"=="ctx" (
+  echo NAME: ctx
+  echo VERSION: v0.9.5
+  exit /b 0
+)
+if "%3"=="ns" (
+  echo NAME: ns
+  echo VERSION: v0.9.5
+  exit /b 0
+)
+if "%3"=="company/foo" (
+  echo NAME: foo
+  echo INDEX: company
+  echo VERSION: v1.1.0
+  exit /b 0
+)
+echo error: plugin "%3" not found 1>&2
+exit /b 1
+:doupgrade
+if "%mode%"=="upgrade_error" (
+  echo failed to upgrade plugin "ctx" 1>&2
+  exit /b 1
+)
+exit /b 0
+`
+		krew = "@echo off\nexit /b 0\n"
+	} else {
+		kubectl = `#!/bin/sh
+mode="${DSX_TEST_KREW_MODE}"
+echo "kubectl $*" >> "${DSX_TEST_KREW_DIR}/calls"
+if [ "$1" != "krew" ]; then
+  echo "invalid args" 1>&2
+  exit 1
+fi
+case "$2" in
+update)
+  if [ "${mode}" = "update_error" ]; then
+    echo "failed to update the local index: exit status 128" 1>&2
+    exit 1
+  fi
+  echo "Updated the local copy of plugin index." 1>&2
+  exit 0
+  ;;
+info)
+  case "$3" in
+  ctx) echo "NAME: ctx"; echo "VERSION: v0.9.5" ;;
+  ns) echo "NAME: ns"; echo "VERSION: v0.9.5" ;;
+  company/foo) echo "NAME: foo"; echo "INDEX: company"; echo "VERSION: v1.1.0" ;;
+  *) echo "error: plugin \"$3\" not found" 1>&2; exit 1 ;;
+  esac
+  exit 0
+  ;;
+upgrade)
+  if [ "${mode}" = "upgrade_error" ]; then
+    echo "failed to upgrade plugin \"ctx\"" 1>&2
+    exit 1
+  fi
+  exit 0
+  ;;
+esac
+echo "invalid args" 1>&2
+exit 1
+`
+		krew = "#!/bin/sh\nexit 0\n"
+	}
+
+	for name, content := range map[string]string{"kubectl": kubectl, "kubectl-krew": krew} {
+		if err := os.WriteFile(filepath.Join(dir, name+ext), []byte(content), 0o755); err != nil {
+			t.Fatalf("fake %s command write failed: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/scottlz0310/dsx/internal/config"
+)
+
+const miseSelfUpdateUnknownMessage = "mise 本体の最新バージョンを確認できないため本体更新をスキップします"
+
+// MiseUpdater は mise (ランタイムバージョン管理) の実装です。
+// mise は asdf の .tool-versions も読み込むため、asdf から移行した環境でも同じ設定で更新できます。
+type MiseUpdater struct {
+	packageFilterSupport
+
+	// bump は true の場合、設定ファイルのバージョン指定を超えて最新版へ更新し、指定も書き換えます（mise upgrade --bump）。
+	bump bool
+	// prune は true の場合、更新後に使われなくなった古いバージョンを削除します（mise prune）。
+	prune bool
+}
+
+// 起動時にレジストリに登録
+func init() {
+	Register(&MiseUpdater{})
+}
+
+func (m *MiseUpdater) Name() string {
+	return "mise"
+}
+
+func (m *MiseUpdater) DisplayName() string {
+	return "mise (ランタイムバージョン管理)"
+}
+
+func (m *MiseUpdater) IsAvailable() bool {
+	_, err := exec.LookPath("mise")
+	return err == nil
+}
+
+func (m *MiseUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	if err := m.configurePackageFilter(cfg); err != nil {
+		return err
+	}
+
+	if bump, ok := cfg["bump"].(bool); ok {
+		m.bump = bump
+	}
+
+	if prune, ok := cfg["prune"].(bool); ok {
+		m.prune = prune
+	}
+
+	return nil
+}
+
+func (m *MiseUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	args := []string{"outdated", "--json"}
+	if m.bump {
+		args = append(args, "--bump")
+	}
+
+	output, err := runCommandOutputWithLocaleC(ctx, "mise", args, "mise outdated の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	packages, err := parseMiseOutdated(output)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.filterCheckResult(&CheckResult{
+		AvailableUpdates: len(packages),
+		Packages:         packages,
+	}), nil
+}
+
+func (m *MiseUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	// まず更新確認
+	checkResult, err := m.Check(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UpdateResult{Held: checkResult.Held}
+
+	if checkResult.AvailableUpdates == 0 {
+		result.Message = "すべてのツールは最新です"
+		return result, nil
+	}
+
+	if opts.DryRun {
+		result.Message = fmt.Sprintf("%d 件のツールが更新可能です（DryRunモード）", checkResult.AvailableUpdates)
+		result.Packages = checkResult.Packages
+
+		return result, nil
+	}
+
+	// hold / ignore で除外がある場合は残りのツールのみ更新
+	baseArgs := []string{upgradeCommand}
+	if m.bump {
+		baseArgs = append(baseArgs, "--bump")
+	}
+
+	args := selectUpdateArgs(checkResult, baseArgs, baseArgs)
+	if err := m.runCommand(ctx, args...); err != nil {
+		result.Errors = append(result.Errors, err)
+		return result, fmt.Errorf("mise upgrade に失敗: %w", err)
+	}
+
+	result.UpdatedCount = checkResult.AvailableUpdates
+	result.Packages = checkResult.Packages
+	result.Message = fmt.Sprintf("%d 件のツールを更新しました", result.UpdatedCount)
+
+	if m.prune {
+		// 更新自体は成功しているため、古いバージョンの削除に失敗しても更新失敗にはしない
+		pruneArgs := append([]string{"prune"}, packageNames(checkResult.Packages)...)
+		if err := m.runCommand(ctx, pruneArgs...); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("mise prune に失敗: %w", err))
+			result.Message += "（古いバージョンの削除に失敗しました）"
+		}
+	}
+
+	return result, nil
+}
+
+// ListInstalled は mise ls --installed --json でインストール済みツールを返します。
+// 同じツールに複数バージョンがある場合は、それぞれを1件として返します。
+func (m *MiseUpdater) ListInstalled(ctx context.Context) ([]PackageInfo, error) {
+	output, err := runCommandOutputWithLocaleC(ctx, "mise", []string{"ls", "--installed", "--json"}, "mise ls の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	var tools map[string][]struct {
+		Version string `json:"version"`
+	}
+
+	if err := json.Unmarshal(output, &tools); err != nil {
+		return nil, fmt.Errorf("mise ls の出力の解析に失敗: %w", err)
+	}
+
+	names := make([]string, 0, len(tools))
+	for name := range tools {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	packages := make([]PackageInfo, 0, len(names))
+
+	for _, name := range names {
+		for _, installed := range tools[name] {
+			packages = append(packages, PackageInfo{Name: name, CurrentVersion: installed.Version})
+		}
+	}
+
+	return packages, nil
+}
+
+// CheckSelfUpdate は mise version --json の version / latest から本体の更新可否を確認します。
+// latest を返さない古い mise では更新可否を判定できないため、更新なしとして扱います。
+func (m *MiseUpdater) CheckSelfUpdate(ctx context.Context) (*CheckResult, error) {
+	output, err := runCommandOutputWithLocaleC(ctx, "mise", []string{"version", "--json"}, "mise version の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	current, latest := parseMiseVersionJSON(output)
+	if current == "" || latest == "" {
+		return &CheckResult{Message: miseSelfUpdateUnknownMessage}, nil
+	}
+
+	if current == latest {
+		return &CheckResult{Message: "mise 本体は最新です"}, nil
+	}
+
+	return &CheckResult{
+		AvailableUpdates: 1,
+		Packages:         []PackageInfo{{Name: "mise", CurrentVersion: current, NewVersion: latest}},
+		Message:          "mise 本体の更新が可能です",
+	}, nil
+}
+
+func (m *MiseUpdater) SelfUpdate(ctx context.Context, opts UpdateOptions) (*SelfUpdateResult, error) {
+	checkResult, err := m.CheckSelfUpdate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SelfUpdateResult{
+		Continuation: ContinueNormalUpdate,
+	}
+
+	if checkResult.AvailableUpdates == 0 {
+		result.Message = checkResult.Message
+		return result, nil
+	}
+
+	if opts.DryRun {
+		result.Packages = checkResult.Packages
+		result.Message = "mise 本体の更新が可能です（DryRunモード）"
+
+		return result, nil
+	}
+
+	if err := m.runCommand(ctx, "self-update", "--yes"); err != nil {
+		result.Errors = append(result.Errors, err)
+
+		return result, fmt.Errorf("mise self-update に失敗: %w", err)
+	}
+
+	result.UpdatedCount = checkResult.AvailableUpdates
+	result.Packages = checkResult.Packages
+	result.Message = "mise 本体を更新しました"
+
+	return result, nil
+}
+
+var _ ManagerSelfUpdater = (*MiseUpdater)(nil)
+
+func (m *MiseUpdater) runCommand(ctx context.Context, args ...string) error {
+	cmd := exec.CommandContext(ctx, "mise", args...)
+	attachCommandOutput(ctx, cmd)
+	cmd.Stdin = os.Stdin
+
+	return cmd.Run()
+}
+
+// miseOutdatedEntry は mise outdated --json の1ツール分です。
+type miseOutdatedEntry struct {
+	Name    string `json:"name"`
+	Current string `json:"current"`
+	Latest  string `json:"latest"`
+}
+
+// parseMiseOutdated は mise outdated --json の出力をパースします。
+// 形式: {"<tool>": {"name": ..., "requested": ..., "current": ..., "latest": ..., "bump": ...}}
+// 未インストールのツールは current が空のため、更新ではなくインストール対象として除外します。
+func parseMiseOutdated(output []byte) ([]PackageInfo, error) {
+	if strings.TrimSpace(string(output)) == "" {
+		return []PackageInfo{}, nil
+	}
+
+	var entries map[string]miseOutdatedEntry
+	if err := json.Unmarshal(output, &entries); err != nil {
+		return nil, fmt.Errorf("mise outdated の出力の解析に失敗: %w", err)
+	}
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	packages := make([]PackageInfo, 0, len(keys))
+
+	for _, key := range keys {
+		entry := entries[key]
+		if entry.Current == "" || entry.Latest == "" || entry.Current == entry.Latest {
+			continue
+		}
+
+		name := entry.Name
+		if name == "" {
+			name = key
+		}
+
+		packages = append(packages, PackageInfo{Name: name, CurrentVersion: entry.Current, NewVersion: entry.Latest})
+	}
+
+	return packages, nil
+}
+
+// parseMiseVersionJSON は mise version --json の出力から現在のバージョンと最新バージョンを返します。
+// version は "2024.12.0 linux-x64 (2024-12-01)" のような形式のため、先頭のトークンのみを使います。
+func parseMiseVersionJSON(output []byte) (current, latest string) {
+	var info struct {
+		Version string `json:"version"`
+		Latest  string `json:"latest"`
+	}
+
+	if err := json.Unmarshal(output, &info); err != nil {
+		return "", ""
+	}
+
+	if fields := strings.Fields(info.Version); len(fields) > 0 {
+		current = strings.TrimPrefix(fields[0], "v")
+	}
+
+	return current, strings.TrimPrefix(strings.TrimSpace(info.Latest), "v")
+}
@@ -0,0 +1,341 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiseUpdater_Configure(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       config.ManagerConfig
+		wantBump  bool
+		wantPrune bool
+	}{
+		{name: "nilの設定はデフォルトのまま", cfg: nil},
+		{name: "bump=true", cfg: config.ManagerConfig{"bump": true}, wantBump: true},
+		{name: "prune=true", cfg: config.ManagerConfig{"prune": true}, wantPrune: true},
+		{name: "不正な型の値は無視", cfg: config.ManagerConfig{"bump": "yes", "prune": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &MiseUpdater{}
+			require.NoError(t, m.Configure(tt.cfg))
+			assert.Equal(t, tt.wantBump, m.bump)
+			assert.Equal(t, tt.wantPrune, m.prune)
+		})
+	}
+}
+
+func TestParseMiseOutdated(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected []PackageInfo
+		wantErr  bool
+	}{
+		{name: "空の出力", output: "", expected: []PackageInfo{}},
+		{name: "空のオブジェクト", output: "{}", expected: []PackageInfo{}},
+		{
+			name: "複数ツール",
+			output: `{
+  "python": {"name": "python", "requested": "3.12", "current": "3.12.3", "latest": "3.12.4", "source": {"type": "mise.toml", "path": "/home/user/.config/mise/config.toml"}},
+  "node": {"name": "node", "requested": "20", "current": "20.14.0", "latest": "20.15.0", "bump": "22"},
+  "terraform": {"name": "terraform", "requested": "latest", "current": null, "latest": "1.9.0"}
+}`,
+			expected: []PackageInfo{
+				{Name: "node", CurrentVersion: "20.14.0", NewVersion: "20.15.0"},
+				{Name: "python", CurrentVersion: "3.12.3", NewVersion: "3.12.4"},
+			},
+		},
+		{
+			name:     "name がない場合はキーを使う",
+			output:   `{"go": {"current": "1.22.4", "latest": "1.22.5"}}`,
+			expected: []PackageInfo{{Name: "go", CurrentVersion: "1.22.4", NewVersion: "1.22.5"}},
+		},
+		{name: "不正なJSONはエラー", output: "not json", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseMiseOutdated([]byte(tt.output))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestParseMiseVersionJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		output      string
+		wantCurrent string
+		wantLatest  string
+	}{
+		{
+			name:        "version と latest",
+			output:      `{"version": "2024.6.0 linux-x64 (2024-06-01)", "latest": "2024.6.6", "os": "linux", "arch": "x64"}`,
+			wantCurrent: "2024.6.0",
+			wantLatest:  "2024.6.6",
+		},
+		{
+			name:        "latest がない古い mise",
+			output:      `{"version": "2024.1.0 linux-x64 (2024-01-01)"}`,
+			wantCurrent: "2024.1.0",
+		},
+		{name: "JSON 非対応の出力", output: "2024.1.0 linux-x64 (2024-01-01)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, latest := parseMiseVersionJSON([]byte(tt.output))
+			assert.Equal(t, tt.wantCurrent, current)
+			assert.Equal(t, tt.wantLatest, latest)
+		})
+	}
+}
+
+func TestMiseUpdater_Update(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mode        string
+		cfg         config.ManagerConfig
+		opts        UpdateOptions
+		wantUpdated int
+		wantCalls   []string
+		wantErr     string
+		msgContains string
+	}{
+		{
+			name:        "DryRunは更新しない",
+			opts:        UpdateOptions{DryRun: true},
+			msgContains: "DryRunモード",
+		},
+		{
+			name:        "対象なし",
+			mode:        "none",
+			msgContains: "すべてのツールは最新です",
+		},
+		{
+			name:        "指定範囲内で更新",
+			wantUpdated: 2,
+			wantCalls:   []string{"mise outdated --json", "mise upgrade"},
+			msgContains: "2 件のツールを更新しました",
+		},
+		{
+			name:        "bump で指定を超えて更新",
+			cfg:         config.ManagerConfig{"bump": true},
+			wantUpdated: 2,
+			wantCalls:   []string{"mise outdated --json --bump", "mise upgrade --bump"},
+		},
+		{
+			name:        "hold 以外を個別に更新して prune",
+			cfg:         config.ManagerConfig{"hold": []interface{}{"python"}, "prune": true},
+			wantUpdated: 1,
+			wantCalls:   []string{"mise outdated --json", "mise upgrade node", "mise prune node"},
+		},
+		{
+			name:        "prune の失敗は警告のみ",
+			mode:        "prune_error",
+			cfg:         config.ManagerConfig{"prune": true},
+			wantUpdated: 2,
+			msgContains: "古いバージョンの削除に失敗しました",
+		},
+		{
+			name:    "更新失敗",
+			mode:    "upgrade_error",
+			wantErr: "mise upgrade に失敗",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := setupFakeMise(t, tc.mode)
+
+			m := &MiseUpdater{}
+			require.NoError(t, m.Configure(tc.cfg))
+
+			got, err := m.Update(context.Background(), tc.opts)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantUpdated, got.UpdatedCount)
+			assert.Contains(t, got.Message, tc.msgContains)
+
+			if tc.wantCalls != nil {
+				assert.Equal(t, tc.wantCalls, readFakeMiseCalls(t, dir))
+			}
+		})
+	}
+}
+
+func TestMiseUpdater_SelfUpdate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mode        string
+		opts        UpdateOptions
+		wantUpdated int
+		wantSelf    bool
+		msgContains string
+	}{
+		{name: "最新版", mode: "self_latest", msgContains: "mise 本体は最新です"},
+		{name: "判定不能はスキップ", mode: "self_unknown", msgContains: miseSelfUpdateUnknownMessage},
+		{name: "DryRunは更新しない", opts: UpdateOptions{DryRun: true}, msgContains: "DryRunモード"},
+		{name: "更新あり", wantUpdated: 1, wantSelf: true, msgContains: "mise 本体を更新しました"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := setupFakeMise(t, tc.mode)
+
+			m := &MiseUpdater{}
+			got, err := m.SelfUpdate(context.Background(), tc.opts)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.wantUpdated, got.UpdatedCount)
+			assert.Contains(t, got.Message, tc.msgContains)
+			assert.True(t, got.ShouldContinueNormalUpdate())
+			assert.Equal(t, tc.wantSelf, strings.Contains(strings.Join(readFakeMiseCalls(t, dir), "\n"), "mise self-update --yes"))
+		})
+	}
+}
+
+func readFakeMiseCalls(t *testing.T, dir string) []string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(dir, "calls"))
+	require.NoError(t, err)
+
+	// Windows の .cmd は CRLF で追記するため改行を揃える
+	return strings.Split(strings.TrimSpace(strings.ReplaceAll(string(data), "\r\n", "\n")), "\n")
+}
+
+// setupFakeMise は PATH の先頭に偽の mise コマンドを配置し、呼び出しを calls に記録します。
+func setupFakeMise(t *testing.T, mode string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	writeFakeMiseCommand(t, dir)
+
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("DSX_TEST_MISE_DIR", dir)
+	t.Setenv("DSX_TEST_MISE_MODE", mode)
+
+	return dir
+}
+
+func writeFakeMiseCommand(t *testing.T, dir string) {
+	t.Helper()
+
+	var (
+		fileName string
+		content  string
+	)
+
+	if runtime.GOOS == "windows" {
+		fileName = "mise.cmd"
+		content = `@echo off
+set mode=%DSX_TEST_MISE_MODE%
+>>"%DSX_TEST_MISE_DIR%\calls" echo mise %*
+if "%1"=="outdated" goto dooutdated
+if "%1"=="upgrade" goto doupgrade
+if "%1"=="prune" goto doprune
+if "%1"=="version" goto doversion
+if "%1"=="self-update" exit /b 0
+echo invalid args 1>&2
+exit /b 1
+:dooutdated
+if "%mode%"=="none" (
+  echo {}
+  exit /b 0
+)
+echo {"node": {"name": "node", "requested": "20", "current": "20.14.0", "latest": "20.15.0"}, "python": {"name": "python", "requested": "3.12", "current": "3.12.3", "latest": "3.12.4"}}
+exit /b 0
+:doupgrade
+if "%mode%"=="upgrade_error" (
+  echo mise ERROR failed to install node@20.15.0 1>&2
+  exit /b 1
+)
+exit /b 0
+:doprune
+if "%mode%"=="prune_error" exit /b 1
+exit /b 0
+:doversion
+if "%mode%"=="self_latest" (
+  echo {"version": "2024.6.6 windows-x64 (2024-06-20)", "latest": "2024.6.6"}
+  exit /b 0
+)
+if "%mode%"=="self_unknown" (
+  echo {"version": "2024.1.0 windows-x64 (2024-01-01)"}
+  exit /b 0
+)
+echo {"version": "2024.6.0 windows-x64 (2024-06-01)", "latest": "2024.6.6"}
+exit /b 0
+`
+	} else {
+		fileName = "mise"
+		content = `#!/bin/sh
+mode="${DSX_TEST_MISE_MODE}"
+echo "mise $*" >> "${DSX_TEST_MISE_DIR}/calls"
+case "$1" in
+outdated)
+  if [ "${mode}" = "none" ]; then
+    echo "{}"
+    exit 0
+  fi
+  echo '{"node": {"name": "node", "requested": "20", "current": "20.14.0", "latest": "20.15.0"}, "python": {"name": "python", "requested": "3.12", "current": "3.12.3", "latest": "3.12.4"}}'
+  exit 0
+  ;;
+upgrade)
+  if [ "${mode}" = "upgrade_error" ]; then
+    echo "mise ERROR failed to install node@20.15.0" 1>&2
+    exit 1
+  fi
+  exit 0
+  ;;
+prune)
+  if [ "${mode}" = "prune_error" ]; then
+    exit 1
+  fi
+  exit 0
+  ;;
+version)
+  case "${mode}" in
+  self_latest) echo '{"version": "2024.6.6 linux-x64 (2024-06-20)", "latest": "2024.6.6"}' ;;
+  self_unknown) echo '{"version": "2024.1.0 linux-x64 (2024-01-01)"}' ;;
+  *) echo '{"version": "2024.6.0 linux-x64 (2024-06-01)", "latest": "2024.6.6"}' ;;
+  esac
+  exit 0
+  ;;
+self-update)
+  exit 0
+  ;;
+esac
+echo "invalid args" 1>&2
+exit 1
+`
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, fileName), []byte(content), 0o755); err != nil {
+		t.Fatalf("fake mise command write failed: %v", err)
+	}
+}
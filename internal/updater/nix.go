@@ -0,0 +1,468 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/scottlz0310/dsx/internal/config"
+)
+
+// nixFlakeInputPrefix は home-manager の flake input を profile のパッケージと区別するための名前の接頭辞です。
+// hold / ignore では "flake:nixpkgs" のように指定します。
+const nixFlakeInputPrefix = "flake:"
+
+// NixUpdater は Nix (nix profile / home-manager) の実装です。
+// sys.managers.nix.home_manager_flake を指定すると、flake input を更新してから home-manager switch を実行します。
+type NixUpdater struct {
+	packageFilterSupport
+
+	homeManagerFlake string
+}
+
+// 起動時にレジストリに登録
+func init() {
+	Register(&NixUpdater{})
+}
+
+func (n *NixUpdater) Name() string {
+	return "nix"
+}
+
+func (n *NixUpdater) DisplayName() string {
+	return "Nix (nix profile / home-manager)"
+}
+
+func (n *NixUpdater) IsAvailable() bool {
+	_, err := exec.LookPath("nix")
+	return err == nil
+}
+
+func (n *NixUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	if err := n.configurePackageFilter(cfg); err != nil {
+		return err
+	}
+
+	value, ok := cfg["home_manager_flake"]
+	if !ok {
+		return nil
+	}
+
+	flake, isString := value.(string)
+	if !isString {
+		return fmt.Errorf("home_manager_flake はディレクトリのパスを文字列で指定してください: %v", value)
+	}
+
+	resolved, err := resolveNixFlakeDir(flake)
+	if err != nil {
+		return err
+	}
+
+	n.homeManagerFlake = resolved
+
+	return nil
+}
+
+func (n *NixUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	packages, err := n.checkProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.homeManagerFlake != "" {
+		inputs, inputErr := n.checkFlakeInputs(ctx)
+		if inputErr != nil {
+			return nil, inputErr
+		}
+
+		packages = append(packages, inputs...)
+	}
+
+	return n.filterCheckResult(&CheckResult{
+		AvailableUpdates: len(packages),
+		Packages:         packages,
+	}), nil
+}
+
+func (n *NixUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	// まず更新確認
+	checkResult, err := n.Check(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UpdateResult{Held: checkResult.Held}
+
+	if checkResult.AvailableUpdates == 0 {
+		result.Message = allPackagesUpToDateMessage
+		return result, nil
+	}
+
+	if opts.DryRun {
+		result.Message = fmt.Sprintf("%d 件のパッケージが更新可能です（DryRunモード）", checkResult.AvailableUpdates)
+		result.Packages = checkResult.Packages
+
+		return result, nil
+	}
+
+	profilePackages, inputs := splitNixPackages(checkResult.Packages)
+	filtered := checkResult.hasFilteredPackages()
+
+	if len(profilePackages) > 0 {
+		// hold / ignore で除外がある場合は残りの要素のみ更新
+		args := []string{"profile", "upgrade", "--all"}
+		if filtered {
+			args = append([]string{"profile", "upgrade"}, packageNames(profilePackages)...)
+		}
+
+		if err := n.runCommand(ctx, "nix", args...); err != nil {
+			result.Errors = append(result.Errors, err)
+			return result, fmt.Errorf("nix profile upgrade に失敗: %w", err)
+		}
+
+		result.UpdatedCount += len(profilePackages)
+		result.Packages = append(result.Packages, profilePackages...)
+	}
+
+	if len(inputs) > 0 {
+		if err := n.switchHomeManager(ctx, inputs, filtered); err != nil {
+			result.Errors = append(result.Errors, err)
+			return result, err
+		}
+
+		result.UpdatedCount += len(inputs)
+		result.Packages = append(result.Packages, inputs...)
+	}
+
+	result.Message = fmt.Sprintf("%d 件のパッケージを更新しました", result.UpdatedCount)
+
+	return result, nil
+}
+
+// ListInstalled は nix profile list でインストール済みの要素を返します。
+// バージョンはストアパスの名前（name-version）から取得します。
+func (n *NixUpdater) ListInstalled(ctx context.Context) ([]PackageInfo, error) {
+	elements, err := n.listProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	packages := make([]PackageInfo, 0, len(elements))
+	for _, element := range elements {
+		packages = append(packages, PackageInfo{Name: element.Name, CurrentVersion: element.version()})
+	}
+
+	return packages, nil
+}
+
+func (n *NixUpdater) listProfile(ctx context.Context) ([]nixProfileElement, error) {
+	output, err := runCommandOutputWithLocaleC(ctx, "nix", []string{"profile", "list", "--json"}, "nix profile list の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseNixProfileList(output)
+}
+
+// checkProfile は flake からインストールした要素について、現在のストアパスのバージョンと
+// flake の最新リビジョンで評価したバージョン（<originalUrl>#<attrPath>.version）を比較します。
+// 評価できない要素（version 属性がないなど）は更新確認の対象外とします。
+func (n *NixUpdater) checkProfile(ctx context.Context) ([]PackageInfo, error) {
+	elements, err := n.listProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	packages := make([]PackageInfo, 0, len(elements))
+
+	for _, element := range elements {
+		if element.OriginalURL == "" || element.AttrPath == "" {
+			continue
+		}
+
+		installable := element.OriginalURL + "#" + element.AttrPath + ".version"
+
+		output, evalErr := runCommandOutputWithLocaleC(ctx, "nix", []string{"eval", "--raw", installable}, "nix eval の実行に失敗: %w")
+		if evalErr != nil {
+			continue
+		}
+
+		current := element.version()
+		proposed := strings.TrimSpace(string(output))
+
+		if proposed == "" || proposed == current {
+			continue
+		}
+
+		packages = append(packages, PackageInfo{Name: element.Name, CurrentVersion: current, NewVersion: proposed})
+	}
+
+	return packages, nil
+}
+
+// checkFlakeInputs は flake.lock を書き換えずに更新後の lock ファイルを一時ファイルへ出力し、
+// ルートの input ごとに現在と更新後のリビジョンを比較します。
+func (n *NixUpdater) checkFlakeInputs(ctx context.Context) ([]PackageInfo, error) {
+	current, err := os.ReadFile(filepath.Join(n.homeManagerFlake, "flake.lock"))
+	if err != nil {
+		return nil, fmt.Errorf("flake.lock の読み込みに失敗: %w", err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "dsx-nix-")
+	if err != nil {
+		return nil, fmt.Errorf("一時ディレクトリの作成に失敗: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	proposedPath := filepath.Join(tmpDir, "flake.lock")
+	args := []string{"flake", "update", "--flake", n.homeManagerFlake, "--output-lock-file", proposedPath}
+
+	if _, err := runCommandOutputWithLocaleC(ctx, "nix", args, "nix flake update の実行に失敗: %w"); err != nil {
+		return nil, err
+	}
+
+	proposed, err := os.ReadFile(proposedPath)
+	if err != nil {
+		return nil, fmt.Errorf("更新後の flake.lock の読み込みに失敗: %w", err)
+	}
+
+	return diffNixFlakeLocks(current, proposed)
+}
+
+// switchHomeManager は flake input を更新してから home-manager switch を実行します。
+func (n *NixUpdater) switchHomeManager(ctx context.Context, inputs []PackageInfo, filtered bool) error {
+	args := []string{"flake", "update", "--flake", n.homeManagerFlake}
+	if filtered {
+		for _, input := range inputs {
+			args = append(args, strings.TrimPrefix(input.Name, nixFlakeInputPrefix))
+		}
+	}
+
+	if err := n.runCommand(ctx, "nix", args...); err != nil {
+		return fmt.Errorf("nix flake update に失敗: %w", err)
+	}
+
+	if err := n.runCommand(ctx, "home-manager", "switch", "--flake", n.homeManagerFlake); err != nil {
+		return fmt.Errorf("home-manager switch に失敗: %w", err)
+	}
+
+	return nil
+}
+
+func (n *NixUpdater) runCommand(ctx context.Context, command string, args ...string) error {
+	cmd := exec.CommandContext(ctx, command, args...)
+	attachCommandOutput(ctx, cmd)
+	cmd.Stdin = os.Stdin
+
+	return cmd.Run()
+}
+
+// nixProfileElement は nix profile list --json の要素です。
+type nixProfileElement struct {
+	Name        string   `json:"-"`
+	AttrPath    string   `json:"attrPath"`
+	OriginalURL string   `json:"originalUrl"`
+	StorePaths  []string `json:"storePaths"`
+}
+
+// version はストアパスの名前からバージョンを返します。
+// 複数出力（bin / man など）がある場合は接尾辞のない最も短い名前を使います。
+func (e nixProfileElement) version() string {
+	name := ""
+
+	for _, storePath := range e.StorePaths {
+		base := filepath.Base(storePath)
+
+		// 先頭の "<hash>-" を取り除く
+		if idx := strings.Index(base, "-"); idx >= 0 {
+			base = base[idx+1:]
+		}
+
+		if name == "" || len(base) < len(name) {
+			name = base
+		}
+	}
+
+	return nixDrvNameVersion(name)
+}
+
+// nixDrvNameVersion は "name-version" から version を返します。
+// Nix と同じく、最初の「"-" の直後が数字でない」部分までを名前とみなします。
+func nixDrvNameVersion(drvName string) string {
+	for i := 0; i+1 < len(drvName); i++ {
+		if drvName[i] == '-' && drvName[i+1] >= '0' && drvName[i+1] <= '9' {
+			return drvName[i+1:]
+		}
+	}
+
+	return ""
+}
+
+// parseNixProfileList は nix profile list --json の出力をパースします。
+// Nix 2.20 以降の要素名をキーとするマップ形式と、それ以前の配列形式の両方に対応します。
+func parseNixProfileList(output []byte) ([]nixProfileElement, error) {
+	var raw struct {
+		Elements json.RawMessage `json:"elements"`
+	}
+
+	if err := json.Unmarshal(output, &raw); err != nil {
+		return nil, fmt.Errorf("nix profile list の出力の解析に失敗: %w", err)
+	}
+
+	if len(raw.Elements) == 0 {
+		return []nixProfileElement{}, nil
+	}
+
+	var named map[string]nixProfileElement
+	if err := json.Unmarshal(raw.Elements, &named); err == nil {
+		elements := make([]nixProfileElement, 0, len(named))
+		for name, element := range named {
+			element.Name = name
+			elements = append(elements, element)
+		}
+
+		sort.Slice(elements, func(i, j int) bool { return elements[i].Name < elements[j].Name })
+
+		return elements, nil
+	}
+
+	var list []nixProfileElement
+	if err := json.Unmarshal(raw.Elements, &list); err != nil {
+		return nil, fmt.Errorf("nix profile list の出力の解析に失敗: %w", err)
+	}
+
+	// 配列形式には要素名がないため、attrPath の末尾を名前として扱う
+	for i := range list {
+		list[i].Name = list[i].AttrPath[strings.LastIndex(list[i].AttrPath, ".")+1:]
+	}
+
+	return list, nil
+}
+
+// nixFlakeLock は flake.lock の必要な部分です。
+type nixFlakeLock struct {
+	Root  string `json:"root"`
+	Nodes map[string]struct {
+		// 値はノード名（文字列）か follows（文字列の配列）
+		Inputs map[string]json.RawMessage `json:"inputs"`
+		Locked *struct {
+			Rev          string `json:"rev"`
+			LastModified int64  `json:"lastModified"`
+			NarHash      string `json:"narHash"`
+		} `json:"locked"`
+	} `json:"nodes"`
+}
+
+// rootInputVersions はルートの input ごとのバージョン表記を返します。
+// follows で他の input に追従するものは対象外です。
+func (l nixFlakeLock) rootInputVersions() map[string]string {
+	versions := make(map[string]string)
+
+	root, ok := l.Nodes[l.Root]
+	if !ok {
+		return versions
+	}
+
+	for input, ref := range root.Inputs {
+		var nodeName string
+		if err := json.Unmarshal(ref, &nodeName); err != nil {
+			continue
+		}
+
+		node, exists := l.Nodes[nodeName]
+		if !exists || node.Locked == nil {
+			continue
+		}
+
+		switch {
+		case node.Locked.Rev != "":
+			versions[input] = node.Locked.Rev[:min(len(node.Locked.Rev), 7)]
+		case node.Locked.LastModified > 0:
+			versions[input] = time.Unix(node.Locked.LastModified, 0).UTC().Format("2006-01-02")
+		default:
+			versions[input] = node.Locked.NarHash
+		}
+	}
+
+	return versions
+}
+
+// diffNixFlakeLocks は現在と更新後の flake.lock を比較し、変更のある input を返します。
+func diffNixFlakeLocks(current, proposed []byte) ([]PackageInfo, error) {
+	var before, after nixFlakeLock
+
+	if err := json.Unmarshal(current, &before); err != nil {
+		return nil, fmt.Errorf("flake.lock の解析に失敗: %w", err)
+	}
+
+	if err := json.Unmarshal(proposed, &after); err != nil {
+		return nil, fmt.Errorf("更新後の flake.lock の解析に失敗: %w", err)
+	}
+
+	beforeVersions := before.rootInputVersions()
+	afterVersions := after.rootInputVersions()
+
+	inputs := make([]string, 0, len(afterVersions))
+	for input := range afterVersions {
+		inputs = append(inputs, input)
+	}
+
+	sort.Strings(inputs)
+
+	packages := make([]PackageInfo, 0, len(inputs))
+
+	for _, input := range inputs {
+		if beforeVersions[input] == afterVersions[input] {
+			continue
+		}
+
+		packages = append(packages, PackageInfo{
+			Name:           nixFlakeInputPrefix + input,
+			CurrentVersion: beforeVersions[input],
+			NewVersion:     afterVersions[input],
+		})
+	}
+
+	return packages, nil
+}
+
+// splitNixPackages は更新対象を profile の要素と flake input に分けます。
+func splitNixPackages(packages []PackageInfo) (profilePackages, inputs []PackageInfo) {
+	for _, pkg := range packages {
+		if strings.HasPrefix(pkg.Name, nixFlakeInputPrefix) {
+			inputs = append(inputs, pkg)
+		} else {
+			profilePackages = append(profilePackages, pkg)
+		}
+	}
+
+	return profilePackages, inputs
+}
+
+// resolveNixFlakeDir は "~" を展開した flake ディレクトリのパスを返します。
+func resolveNixFlakeDir(dir string) (string, error) {
+	if strings.TrimSpace(dir) == "" {
+		return "", nil
+	}
+
+	if dir == "~" || strings.HasPrefix(dir, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("ホームディレクトリの取得に失敗: %w", err)
+		}
+
+		dir = filepath.Join(home, strings.TrimPrefix(dir, "~"))
+	}
+
+	return filepath.Clean(dir), nil
+}
@@ -0,0 +1,404 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const nixTestProfileJSON = `{
+  "elements": {
+    "ripgrep": {
+      "active": true,
+      "attrPath": "legacyPackages.x86_64-linux.ripgrep",
+      "originalUrl": "flake:nixpkgs",
+      "storePaths": ["/nix/store/0c6ih3a3rqrjyl2dmbi8ckkc0pqdz8cf-ripgrep-14.1.0"],
+      "url": "github:NixOS/nixpkgs/a0b1c2d3"
+    },
+    "jq": {
+      "active": true,
+      "attrPath": "legacyPackages.x86_64-linux.jq",
+      "originalUrl": "flake:nixpkgs",
+      "storePaths": [
+        "/nix/store/6k2cr1bzq0v3b5m5jl3g0j3x1y9nb1a2-jq-1.7.1-bin",
+        "/nix/store/9f1w0c2g5mhbz4s3l4ysq6a7y3z0x2r1-jq-1.7.1",
+        "/nix/store/2b7k1j4z6w8m9n0p3q5r7s9t1v3x5y7a-jq-1.7.1-man"
+      ]
+    },
+    "hello": {
+      "active": true,
+      "attrPath": "packages.x86_64-linux.default",
+      "originalUrl": "github:example/hello",
+      "storePaths": ["/nix/store/1a2b3c4d5e6f7g8h9i0j1k2l3m4n5o6p-hello-2.12.1"]
+    },
+    "local": {
+      "active": true,
+      "storePaths": ["/nix/store/7q8r9s0t1u2v3w4x5y6z7a8b9c0d1e2f-local-tool-0.1.0"]
+    }
+  },
+  "version": 3
+}`
+
+const nixTestLockBefore = `{
+  "nodes": {
+    "home-manager": {
+      "inputs": {"nixpkgs": ["nixpkgs"]},
+      "locked": {"lastModified": 1718000000, "narHash": "sha256-hm1", "owner": "nix-community", "repo": "home-manager", "rev": "1111111aaaaaaa", "type": "github"}
+    },
+    "nixpkgs": {
+      "locked": {"lastModified": 1718000000, "narHash": "sha256-np1", "owner": "NixOS", "repo": "nixpkgs", "rev": "2222222bbbbbbb", "type": "github"}
+    },
+    "dotfiles": {
+      "locked": {"lastModified": 1718000000, "narHash": "sha256-df1", "path": "/home/user/dotfiles", "type": "path"}
+    },
+    "root": {
+      "inputs": {"home-manager": "home-manager", "nixpkgs": "nixpkgs", "dotfiles": "dotfiles"}
+    }
+  },
+  "root": "root",
+  "version": 7
+}`
+
+const nixTestLockAfter = `{
+  "nodes": {
+    "home-manager": {
+      "inputs": {"nixpkgs": ["nixpkgs"]},
+      "locked": {"lastModified": 1718000000, "narHash": "sha256-hm1", "owner": "nix-community", "repo": "home-manager", "rev": "1111111aaaaaaa", "type": "github"}
+    },
+    "nixpkgs": {
+      "locked": {"lastModified": 1718600000, "narHash": "sha256-np2", "owner": "NixOS", "repo": "nixpkgs", "rev": "3333333ccccccc", "type": "github"}
+    },
+    "dotfiles": {
+      "locked": {"lastModified": 1718900000, "narHash": "sha256-df2", "path": "/home/user/dotfiles", "type": "path"}
+    },
+    "root": {
+      "inputs": {"home-manager": "home-manager", "nixpkgs": "nixpkgs", "dotfiles": "dotfiles"}
+    }
+  },
+  "root": "root",
+  "version": 7
+}`
+
+func TestNixUpdater_Configure(t *testing.T) {
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		cfg       config.ManagerConfig
+		wantFlake string
+		wantErr   bool
+	}{
+		{name: "nilの設定はhome-managerなし", cfg: nil, wantFlake: ""},
+		{name: "絶対パス", cfg: config.ManagerConfig{"home_manager_flake": "/etc/nixos/"}, wantFlake: "/etc/nixos"},
+		{name: "チルダを展開", cfg: config.ManagerConfig{"home_manager_flake": "~/.config/home-manager"}, wantFlake: filepath.Join(home, ".config/home-manager")},
+		{name: "文字列以外はエラー", cfg: config.ManagerConfig{"home_manager_flake": true}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &NixUpdater{}
+
+			err := n.Configure(tt.cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFlake, n.homeManagerFlake)
+		})
+	}
+}
+
+func TestParseNixProfileList(t *testing.T) {
+	t.Run("マップ形式（Nix 2.20 以降）", func(t *testing.T) {
+		elements, err := parseNixProfileList([]byte(nixTestProfileJSON))
+		require.NoError(t, err)
+
+		got := make(map[string]string, len(elements))
+		for _, element := range elements {
+			got[element.Name] = element.version()
+		}
+
+		assert.Equal(t, map[string]string{
+			"hello":   "2.12.1",
+			"jq":      "1.7.1",
+			"local":   "0.1.0",
+			"ripgrep": "14.1.0",
+		}, got)
+	})
+
+	t.Run("配列形式（Nix 2.19 以前）", func(t *testing.T) {
+		output := `{"elements":[{"active":true,"attrPath":"legacyPackages.x86_64-linux.fd","originalUrl":"flake:nixpkgs","storePaths":["/nix/store/abc-fd-9.0.0"]}],"version":2}`
+
+		elements, err := parseNixProfileList([]byte(output))
+		require.NoError(t, err)
+		require.Len(t, elements, 1)
+		assert.Equal(t, "fd", elements[0].Name)
+		assert.Equal(t, "9.0.0", elements[0].version())
+	})
+
+	t.Run("不正なJSONはエラー", func(t *testing.T) {
+		_, err := parseNixProfileList([]byte("not json"))
+		assert.Error(t, err)
+	})
+}
+
+func TestNixDrvNameVersion(t *testing.T) {
+	tests := []struct {
+		drvName string
+		want    string
+	}{
+		{drvName: "ripgrep-14.1.0", want: "14.1.0"},
+		{drvName: "python3-3.12.4", want: "3.12.4"},
+		{drvName: "nix-index-database-0-unstable-2024-06-01", want: "0-unstable-2024-06-01"},
+		{drvName: "hello", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.drvName, func(t *testing.T) {
+			assert.Equal(t, tt.want, nixDrvNameVersion(tt.drvName))
+		})
+	}
+}
+
+func TestDiffNixFlakeLocks(t *testing.T) {
+	got, err := diffNixFlakeLocks([]byte(nixTestLockBefore), []byte(nixTestLockAfter))
+	require.NoError(t, err)
+
+	assert.Equal(t, []PackageInfo{
+		{Name: "flake:dotfiles", CurrentVersion: "2024-06-10", NewVersion: "2024-06-20"},
+		{Name: "flake:nixpkgs", CurrentVersion: "2222222", NewVersion: "3333333"},
+	}, got)
+
+	_, err = diffNixFlakeLocks([]byte("{"), []byte(nixTestLockAfter))
+	assert.Error(t, err)
+}
+
+func TestNixUpdater_Check(t *testing.T) {
+	dir := setupFakeNix(t, "")
+
+	t.Run("profile のみ", func(t *testing.T) {
+		n := &NixUpdater{}
+		got, err := n.Check(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, []PackageInfo{
+			{Name: "ripgrep", CurrentVersion: "14.1.0", NewVersion: "14.1.1"},
+		}, got.Packages)
+	})
+
+	t.Run("home_manager_flake 指定時は flake input も含める", func(t *testing.T) {
+		n := &NixUpdater{homeManagerFlake: writeNixTestFlake(t)}
+		got, err := n.Check(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"ripgrep", "flake:dotfiles", "flake:nixpkgs"}, packageNames(got.Packages))
+		assert.Equal(t, 3, got.AvailableUpdates)
+
+		// 確認では flake.lock を書き換えない
+		assert.NotContains(t, readNixTestCalls(t, dir), "flake update --flake "+n.homeManagerFlake+"\n")
+	})
+}
+
+func TestNixUpdater_Update(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mode        string
+		homeManager bool
+		cfg         config.ManagerConfig
+		opts        UpdateOptions
+		wantUpdated int
+		wantCalls   []string
+		wantErr     string
+		msgContains string
+	}{
+		{
+			name:        "DryRunは更新しない",
+			opts:        UpdateOptions{DryRun: true},
+			msgContains: "DryRunモード",
+		},
+		{
+			name:        "profile を一括更新",
+			wantUpdated: 1,
+			wantCalls:   []string{"nix profile upgrade --all"},
+			msgContains: "1 件のパッケージを更新しました",
+		},
+		{
+			name:        "flake input を更新してから home-manager switch",
+			homeManager: true,
+			wantUpdated: 3,
+			wantCalls: []string{
+				"nix profile upgrade --all",
+				"nix flake update --flake FLAKE",
+				"home-manager switch --flake FLAKE",
+			},
+		},
+		{
+			name:        "hold した input 以外を個別に更新",
+			homeManager: true,
+			cfg:         config.ManagerConfig{"hold": []interface{}{"flake:nixpkgs"}},
+			wantUpdated: 2,
+			wantCalls: []string{
+				"nix profile upgrade ripgrep",
+				"nix flake update --flake FLAKE dotfiles",
+				"home-manager switch --flake FLAKE",
+			},
+		},
+		{
+			name:    "profile upgrade の失敗",
+			mode:    "upgrade_error",
+			wantErr: "nix profile upgrade に失敗",
+		},
+		{
+			name:        "home-manager switch の失敗",
+			mode:        "switch_error",
+			homeManager: true,
+			wantErr:     "home-manager switch に失敗",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := setupFakeNix(t, tc.mode)
+
+			n := &NixUpdater{}
+			require.NoError(t, n.Configure(tc.cfg))
+
+			flake := ""
+			if tc.homeManager {
+				flake = writeNixTestFlake(t)
+				n.homeManagerFlake = flake
+			}
+
+			got, err := n.Update(context.Background(), tc.opts)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantUpdated, got.UpdatedCount)
+			assert.Contains(t, got.Message, tc.msgContains)
+
+			var updateCalls []string
+
+			for _, line := range strings.Split(readNixTestCalls(t, dir), "\n") {
+				if strings.Contains(line, "upgrade") || strings.Contains(line, "switch") ||
+					(strings.Contains(line, "flake update") && !strings.Contains(line, "--output-lock-file")) {
+					if flake != "" {
+						line = strings.ReplaceAll(line, flake, "FLAKE")
+					}
+
+					updateCalls = append(updateCalls, line)
+				}
+			}
+
+			assert.Equal(t, tc.wantCalls, updateCalls)
+		})
+	}
+}
+
+// writeNixTestFlake は更新前の flake.lock を置いた flake ディレクトリを作成します。
+func writeNixTestFlake(t *testing.T) string {
+	t.Helper()
+
+	flake := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(flake, "flake.lock"), []byte(nixTestLockBefore), 0o644))
+
+	return flake
+}
+
+func readNixTestCalls(t *testing.T, dir string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(dir, "calls"))
+	if os.IsNotExist(err) {
+		return ""
+	}
+
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+// setupFakeNix は PATH の先頭に偽の nix / home-manager コマンドを配置し、呼び出しを calls に記録します。
+func setupFakeNix(t *testing.T, mode string) string {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("偽の nix コマンドは sh スクリプトのため Windows ではスキップ")
+	}
+
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "profile.json"), []byte(nixTestProfileJSON), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "after.lock"), []byte(nixTestLockAfter), 0o644))
+
+	nix := `#!/bin/sh
+mode="${DSX_TEST_NIX_MODE}"
+echo "nix $*" >> "${DSX_TEST_NIX_DIR}/calls"
+case "$1 $2" in
+"profile list")
+  cat "${DSX_TEST_NIX_DIR}/profile.json"
+  exit 0
+  ;;
+"profile upgrade")
+  if [ "${mode}" = "upgrade_error" ]; then
+    echo "error: cannot upgrade" 1>&2
+    exit 1
+  fi
+  exit 0
+  ;;
+"flake update")
+  while [ $# -gt 0 ]; do
+    if [ "$1" = "--output-lock-file" ]; then
+      cp "${DSX_TEST_NIX_DIR}/after.lock" "$2"
+    fi
+    shift
+  done
+  exit 0
+  ;;
+esac
+if [ "$1" = "eval" ]; then
+  case "$3" in
+  *ripgrep.version) echo "14.1.1" ;;
+  *jq.version) echo "1.7.1" ;;
+  *) echo "error: attribute 'version' missing" 1>&2; exit 1 ;;
+  esac
+  exit 0
+fi
+echo "invalid args" 1>&2
+exit 1
+`
+	homeManager := `#!/bin/sh
+echo "home-manager $*" >> "${DSX_TEST_NIX_DIR}/calls"
+if [ "${DSX_TEST_NIX_MODE}" = "switch_error" ]; then
+  echo "error: builder failed" 1>&2
+  exit 1
+fi
+exit 0
+`
+
+	for name, content := range map[string]string{"nix": nix, "home-manager": homeManager} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o755); err != nil {
+			t.Fatalf("fake %s command write failed: %v", name, err)
+		}
+	}
+
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("DSX_TEST_NIX_DIR", dir)
+	t.Setenv("DSX_TEST_NIX_MODE", mode)
+
+	return dir
+}
@@ -12,7 +12,7 @@ import (
 
 // NpmUpdater は npm グローバルパッケージマネージャの実装です。
 type NpmUpdater struct {
-	// 将来的な拡張のための設定フィールド
+	packageFilterSupport
 }
 
 // 起動時にレジストリに登録
@@ -33,9 +33,13 @@ func (n *NpmUpdater) IsAvailable() bool {
 	return err == nil
 }
 
+// DependsOn は nvm による Node.js 更新の後にグローバルパッケージを更新するための依存を返します。
+func (n *NpmUpdater) DependsOn() []string {
+	return []string{"nvm"}
+}
+
 func (n *NpmUpdater) Configure(cfg config.ManagerConfig) error {
-	// 現時点では設定項目なし
-	return nil
+	return n.configurePackageFilter(cfg)
 }
 
 func (n *NpmUpdater) Check(ctx context.Context) (*CheckResult, error) {
@@ -52,21 +56,21 @@ func (n *NpmUpdater) Check(ctx context.Context) (*CheckResult, error) {
 
 	packages := n.parseOutdatedJSON(output)
 
-	return &CheckResult{
+	return n.filterCheckResult(&CheckResult{
 		AvailableUpdates: len(packages),
 		Packages:         packages,
-	}, nil
+	}), nil
 }
 
 func (n *NpmUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
-	result := &UpdateResult{}
-
 	// まず更新確認
 	checkResult, err := n.Check(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	result := &UpdateResult{Held: checkResult.Held}
+
 	if checkResult.AvailableUpdates == 0 {
 		result.Message = allPackagesUpToDateMessage
 		return result, nil
@@ -79,10 +83,10 @@ func (n *NpmUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateRes
 		return result, nil
 	}
 
-	// 実際の更新を実行
-	cmd := exec.CommandContext(ctx, "npm", updateCommand, "-g")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	// 実際の更新を実行（hold / ignore で除外がある場合は残りのパッケージのみ更新）
+	args := selectUpdateArgs(checkResult, []string{updateCommand, "-g"}, []string{updateCommand, "-g"})
+	cmd := exec.CommandContext(ctx, "npm", args...)
+	attachCommandOutput(ctx, cmd)
 	cmd.Stdin = os.Stdin
 
 	if err := cmd.Run(); err != nil {
@@ -129,3 +133,35 @@ func (n *NpmUpdater) parseOutdatedJSON(output []byte) []PackageInfo {
 
 	return packages
 }
+
+// ListInstalled は "npm ls -g --depth=0 --json" でインストール済みグローバルパッケージを返します。
+func (n *NpmUpdater) ListInstalled(ctx context.Context) ([]PackageInfo, error) {
+	output, err := runCommandOutputWithLocaleC(ctx, "npm", []string{"ls", "-g", "--depth=0", "--json"}, "npm ls -g の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	var listed struct {
+		Dependencies map[string]struct {
+			Version string `json:"version"`
+		} `json:"dependencies"`
+	}
+
+	if err := json.Unmarshal(output, &listed); err != nil {
+		return nil, fmt.Errorf("npm ls -g の出力解析に失敗: %w", err)
+	}
+
+	packages := make([]PackageInfo, 0, len(listed.Dependencies))
+	for name, info := range listed.Dependencies {
+		packages = append(packages, PackageInfo{Name: name, CurrentVersion: info.Version})
+	}
+
+	return packages, nil
+}
+
+// RollbackCommand は npm install -g で更新前のバージョンを入れ直すコマンドを返します。
+func (n *NpmUpdater) RollbackCommand(pkg PackageInfo) (*RollbackCommand, error) {
+	return newRollbackCommand(pkg, "npm", func(version string) []string {
+		return []string{"install", "-g", pkg.Name + "@" + version}
+	})
+}
@@ -20,7 +20,9 @@ var semverPattern = regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
 const windowsOS = "windows"
 
 // NvmUpdater は nvm (Node.js バージョン管理) の実装です。
-type NvmUpdater struct{}
+type NvmUpdater struct {
+	packageFilterSupport
+}
 
 // 起動時にレジストリへ登録します。
 func init() {
@@ -47,11 +49,20 @@ func (n *NvmUpdater) IsAvailable() bool {
 }
 
 func (n *NvmUpdater) Configure(cfg config.ManagerConfig) error {
-	// 現時点では設定項目なし
-	return nil
+	return n.configurePackageFilter(cfg)
 }
 
+// Check は Node.js の更新可否を確認します。hold / ignore には "node" を指定できます。
 func (n *NvmUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	result, err := n.checkNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return n.filterCheckResult(result), nil
+}
+
+func (n *NvmUpdater) checkNode(ctx context.Context) (*CheckResult, error) {
 	currentVersion, err := n.currentVersion(ctx)
 	if err != nil {
 		return nil, err
@@ -105,7 +116,13 @@ func (n *NvmUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateRes
 		return nil, err
 	}
 
-	result := &UpdateResult{}
+	result := &UpdateResult{Held: checkResult.Held}
+
+	if len(checkResult.Held) > 0 {
+		result.Message = "Node.js は hold 設定により更新を保留しました"
+
+		return result, nil
+	}
 
 	if checkResult.AvailableUpdates == 0 {
 		result.Message = "nvm 管理下の Node.js は最新です"
@@ -129,8 +146,7 @@ func (n *NvmUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateRes
 		return result, fmt.Errorf("nvm install %s の準備に失敗: %w", targetVersion, err)
 	}
 
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	attachCommandOutput(ctx, cmd)
 	cmd.Stdin = os.Stdin
 
 	if err := cmd.Run(); err != nil {
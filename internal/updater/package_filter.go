@@ -0,0 +1,148 @@
+package updater
+
+import (
+	"fmt"
+
+	"github.com/scottlz0310/dsx/internal/config"
+)
+
+// PackageFilter は sys.managers.<name> の hold / ignore 設定に基づいて更新対象を絞り込みます。
+// hold に一致したパッケージは「保留」として結果に残し、ignore に一致したパッケージは黙って除外します。
+type PackageFilter struct {
+	hold   []string
+	ignore []string
+}
+
+// NewPackageFilter は ManagerConfig から hold / ignore を読み取って PackageFilter を生成します。
+func NewPackageFilter(cfg config.ManagerConfig) (PackageFilter, error) {
+	hold, err := cfg.Hold()
+	if err != nil {
+		return PackageFilter{}, err
+	}
+
+	ignore, err := cfg.Ignore()
+	if err != nil {
+		return PackageFilter{}, err
+	}
+
+	return PackageFilter{hold: hold, ignore: ignore}, nil
+}
+
+// IsEmpty は絞り込み条件が1つも設定されていないかを返します。
+func (f PackageFilter) IsEmpty() bool {
+	return len(f.hold) == 0 && len(f.ignore) == 0
+}
+
+// IsHeld はパッケージが hold に一致するかを返します。
+func (f PackageFilter) IsHeld(name string) bool {
+	return matchAnyPattern(f.hold, name)
+}
+
+// IsIgnored はパッケージが ignore に一致するかを返します。
+// hold と ignore の両方に一致する場合は ignore を優先します。
+func (f PackageFilter) IsIgnored(name string) bool {
+	return matchAnyPattern(f.ignore, name)
+}
+
+// Apply は CheckResult から hold / ignore に一致するパッケージを取り除いた新しい結果を返します。
+// AvailableUpdates は除外した件数だけ減算し、保留したパッケージは Held に格納します。
+func (f PackageFilter) Apply(result *CheckResult) *CheckResult {
+	if result == nil || f.IsEmpty() {
+		return result
+	}
+
+	filtered := *result
+	filtered.Packages = make([]PackageInfo, 0, len(result.Packages))
+	filtered.Held = append([]PackageInfo{}, result.Held...)
+
+	removed := 0
+
+	for _, pkg := range result.Packages {
+		switch {
+		case f.IsIgnored(pkg.Name):
+			removed++
+		case f.IsHeld(pkg.Name):
+			filtered.Held = append(filtered.Held, pkg)
+			removed++
+		default:
+			filtered.Packages = append(filtered.Packages, pkg)
+		}
+	}
+
+	filtered.AvailableUpdates = max(result.AvailableUpdates-removed, 0)
+	filtered.filtered = result.filtered + removed
+
+	return &filtered
+}
+
+// packageFilterSupport は各 Updater に埋め込んで hold / ignore を扱うための共通実装です。
+type packageFilterSupport struct {
+	filter PackageFilter
+}
+
+// PackageFilter は現在適用されている hold / ignore の設定を返します。
+func (s *packageFilterSupport) PackageFilter() PackageFilter {
+	return s.filter
+}
+
+func (s *packageFilterSupport) configurePackageFilter(cfg config.ManagerConfig) error {
+	filter, err := NewPackageFilter(cfg)
+	if err != nil {
+		return fmt.Errorf("hold / ignore の設定が不正です: %w", err)
+	}
+
+	s.filter = filter
+
+	return nil
+}
+
+func (s *packageFilterSupport) filterCheckResult(result *CheckResult) *CheckResult {
+	return s.filter.Apply(result)
+}
+
+// hasFilteredPackages は hold / ignore により更新対象から外れたパッケージがあるかを返します。
+// true の場合、一括更新コマンドでは除外対象まで更新されるため、個別指定で更新する必要があります。
+func (r *CheckResult) hasFilteredPackages() bool {
+	return r != nil && r.filtered > 0
+}
+
+func matchAnyPattern(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if config.MatchPackagePattern(pattern, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func packageNames(packages []PackageInfo) []string {
+	names := make([]string, 0, len(packages))
+	for _, pkg := range packages {
+		names = append(names, pkg.Name)
+	}
+
+	return names
+}
+
+// selectUpdateArgs は除外がなければ一括更新用の引数を、
+// 除外があれば baseArgs に残りのパッケージ名を付け足した個別更新用の引数を返します。
+func selectUpdateArgs(checkResult *CheckResult, bulkArgs, baseArgs []string) []string {
+	if !checkResult.hasFilteredPackages() {
+		return bulkArgs
+	}
+
+	args := append([]string{}, baseArgs...)
+
+	return append(args, packageNames(checkResult.Packages)...)
+}
+
+// packageNamesIfFiltered は除外があった場合のみ、残りのパッケージ名を返します。
+// 除外がなければ nil を返し、呼び出し側は一括更新を行います。
+func packageNamesIfFiltered(checkResult *CheckResult) []string {
+	if !checkResult.hasFilteredPackages() {
+		return nil
+	}
+
+	return packageNames(checkResult.Packages)
+}
@@ -0,0 +1,199 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPackageFilter(t *testing.T) {
+	filter, err := NewPackageFilter(config.ManagerConfig{
+		"hold":   []interface{}{"docker-ce", "linux-*"},
+		"ignore": []interface{}{"snapd"},
+	})
+	assert.NoError(t, err)
+	assert.False(t, filter.IsEmpty())
+	assert.True(t, filter.IsHeld("linux-image-generic"))
+	assert.False(t, filter.IsHeld("curl"))
+	assert.True(t, filter.IsIgnored("snapd"))
+
+	empty, err := NewPackageFilter(nil)
+	assert.NoError(t, err)
+	assert.True(t, empty.IsEmpty())
+
+	_, err = NewPackageFilter(config.ManagerConfig{"ignore": 1})
+	assert.Error(t, err)
+}
+
+func TestPackageFilter_Apply(t *testing.T) {
+	source := &CheckResult{
+		AvailableUpdates: 4,
+		Packages: []PackageInfo{
+			{Name: "curl", CurrentVersion: "8.5.0", NewVersion: "8.6.0"},
+			{Name: "docker-ce", CurrentVersion: "25.0.0", NewVersion: "26.0.0"},
+			{Name: "linux-image-generic", NewVersion: "6.8.0"},
+			{Name: "snapd", NewVersion: "2.62"},
+		},
+		Message: "info",
+	}
+
+	testCases := []struct {
+		name         string
+		cfg          config.ManagerConfig
+		wantUpdates  int
+		wantPackages []string
+		wantHeld     []string
+		wantFiltered bool
+	}{
+		{
+			name:         "設定なしはそのまま",
+			cfg:          nil,
+			wantUpdates:  4,
+			wantPackages: []string{"curl", "docker-ce", "linux-image-generic", "snapd"},
+		},
+		{
+			name:         "hold は保留、ignore は黙って除外",
+			cfg:          config.ManagerConfig{"hold": []interface{}{"docker-ce", "linux-*"}, "ignore": []interface{}{"snapd"}},
+			wantUpdates:  1,
+			wantPackages: []string{"curl"},
+			wantHeld:     []string{"docker-ce", "linux-image-generic"},
+			wantFiltered: true,
+		},
+		{
+			name:         "hold と ignore の両方に一致する場合は ignore を優先",
+			cfg:          config.ManagerConfig{"hold": []interface{}{"curl"}, "ignore": []interface{}{"curl"}},
+			wantUpdates:  3,
+			wantPackages: []string{"docker-ce", "linux-image-generic", "snapd"},
+			wantFiltered: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filter, err := NewPackageFilter(tc.cfg)
+			assert.NoError(t, err)
+
+			got := filter.Apply(source)
+
+			assert.Equal(t, tc.wantUpdates, got.AvailableUpdates)
+			assert.Equal(t, tc.wantPackages, packageNames(got.Packages))
+			assert.Equal(t, tc.wantHeld, packageNamesOrNil(got.Held))
+			assert.Equal(t, tc.wantFiltered, got.hasFilteredPackages())
+			assert.Equal(t, "info", got.Message)
+		})
+	}
+
+	// 元の結果は変更しない
+	assert.Len(t, source.Packages, 4)
+	assert.Equal(t, 4, source.AvailableUpdates)
+}
+
+func TestSelectUpdateArgs(t *testing.T) {
+	filter, err := NewPackageFilter(config.ManagerConfig{"hold": []interface{}{"vim"}})
+	assert.NoError(t, err)
+
+	unfiltered := &CheckResult{AvailableUpdates: 1, Packages: []PackageInfo{{Name: "curl"}}}
+	assert.Equal(t, []string{"upgrade", "-y"}, selectUpdateArgs(unfiltered, []string{"upgrade", "-y"}, []string{"install", "--only-upgrade", "-y"}))
+
+	filtered := filter.Apply(&CheckResult{AvailableUpdates: 2, Packages: []PackageInfo{{Name: "curl"}, {Name: "vim"}}})
+	assert.Equal(t, []string{"install", "--only-upgrade", "-y", "curl"}, selectUpdateArgs(filtered, []string{"upgrade", "-y"}, []string{"install", "--only-upgrade", "-y"}))
+	assert.Equal(t, []string{"curl"}, packageNamesIfFiltered(filtered))
+	assert.Nil(t, packageNamesIfFiltered(unfiltered))
+}
+
+func TestRustupUpdateArgs(t *testing.T) {
+	testCases := []struct {
+		name string
+		hold string
+		want []string
+	}{
+		{name: "除外なしは rustup update", hold: "", want: []string{"update"}},
+		{name: "本体を保留すると --no-self-update", hold: "rustup", want: []string{"update", "--no-self-update", "stable-x86_64-unknown-linux-gnu"}},
+		{name: "ツールチェーンを保留して本体のみ残ると self update", hold: "stable-*", want: []string{"self", "update"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := config.ManagerConfig{}
+			if tc.hold != "" {
+				cfg["hold"] = []interface{}{tc.hold}
+			}
+
+			filter, err := NewPackageFilter(cfg)
+			assert.NoError(t, err)
+
+			checkResult := filter.Apply(&CheckResult{
+				AvailableUpdates: 2,
+				Packages:         []PackageInfo{{Name: "stable-x86_64-unknown-linux-gnu"}, {Name: "rustup"}},
+			})
+
+			assert.Equal(t, tc.want, rustupUpdateArgs(checkResult))
+		})
+	}
+}
+
+func TestCargoTargetArgs(t *testing.T) {
+	assert.Equal(t, []string{"install-update", "-a"}, cargoTargetArgs([]string{"install-update", "-a"}, nil))
+	assert.Equal(t, []string{"install-update", "ripgrep", "bat"}, cargoTargetArgs([]string{"install-update", "-a"}, []string{"ripgrep", "bat"}))
+	assert.Equal(t, []string{"ripgrep"}, cargoTargetArgs([]string{"-a"}, []string{"ripgrep"}))
+}
+
+func TestGoUpdater_applyPackageFilter(t *testing.T) {
+	g := &GoUpdater{}
+	assert.NoError(t, g.Configure(config.ManagerConfig{
+		"hold":   []interface{}{"golang.org/x/tools/gopls"},
+		"ignore": []interface{}{"dlv"},
+	}))
+
+	plan := &goUpdatePlan{
+		Decisions: []*goTargetDecision{
+			{ToolName: "gopls", PackagePath: "golang.org/x/tools/gopls", Action: goTargetInstallUpdate},
+			{ToolName: "dlv", PackagePath: "github.com/go-delve/delve/cmd/dlv", Action: goTargetInstallUpdate},
+			{ToolName: "impl", PackagePath: "github.com/josharian/impl", Action: goTargetInstallUnknown},
+			{ToolName: "gotests", PackagePath: "github.com/cweill/gotests/gotests", Action: goTargetSkipLatest},
+		},
+	}
+
+	held := g.applyPackageFilter(plan)
+
+	assert.Equal(t, []string{"gopls"}, packageNames(held))
+	assert.Equal(t, []string{"impl"}, packageNames(planPackages(plan.installTargets())))
+	assert.Len(t, plan.Decisions, 2)
+}
+
+func TestFlatpakUpdater_UpdateWithHold(t *testing.T) {
+	fakeDir := t.TempDir()
+	writeFakeFlatpakCommand(t, fakeDir)
+
+	t.Setenv("PATH", fakeDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("DSX_TEST_FLATPAK_MODE", "updates")
+
+	f := &FlatpakUpdater{}
+	assert.NoError(t, f.Configure(config.ManagerConfig{"hold": []interface{}{"org.mozilla.*"}}))
+
+	got, err := f.Update(context.Background(), UpdateOptions{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, got.UpdatedCount)
+	assert.Equal(t, []string{"org.gnome.Calculator"}, packageNames(got.Packages))
+	assert.Equal(t, []string{"org.mozilla.firefox"}, packageNames(got.Held))
+}
+
+func TestUpdater_ConfigureRejectsInvalidHold(t *testing.T) {
+	cfg := config.ManagerConfig{"hold": []interface{}{"ok", 42}}
+
+	for _, u := range []Updater{&AptUpdater{}, &BrewUpdater{}, &NpmUpdater{}, &GoUpdater{}, &NvmUpdater{}, &BunUpdater{}} {
+		assert.Error(t, u.Configure(cfg), u.Name())
+	}
+}
+
+func packageNamesOrNil(packages []PackageInfo) []string {
+	if len(packages) == 0 {
+		return nil
+	}
+
+	return packageNames(packages)
+}
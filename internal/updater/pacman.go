@@ -0,0 +1,305 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/scottlz0310/dsx/internal/config"
+)
+
+const (
+	// pacmanNoUpdatesExitCode は pacman -Qu / paru -Qua / yay -Qua が更新なしで返す終了コードです。
+	pacmanNoUpdatesExitCode = 1
+	// checkupdatesNoUpdatesExitCode は checkupdates (pacman-contrib) が更新なしで返す終了コードです。
+	checkupdatesNoUpdatesExitCode = 2
+	pacnewSuffix                  = ".pacnew"
+	defaultPacnewSearchDir        = "/etc"
+)
+
+// PacmanUpdater は pacman (Arch Linux) の実装です。
+// sys.managers.pacman.aur_helper に paru / yay を指定すると、AUR パッケージも含めて更新します。
+type PacmanUpdater struct {
+	packageFilterSupport
+
+	useSudo   bool
+	aurHelper string
+	// pacnewDir は更新後に .pacnew を探すディレクトリです（テストで差し替え）。
+	pacnewDir string
+}
+
+// 起動時にレジストリに登録
+func init() {
+	Register(&PacmanUpdater{useSudo: true, pacnewDir: defaultPacnewSearchDir})
+}
+
+func (p *PacmanUpdater) Name() string {
+	return "pacman"
+}
+
+func (p *PacmanUpdater) DisplayName() string {
+	return "pacman (Arch Linux)"
+}
+
+func (p *PacmanUpdater) IsAvailable() bool {
+	_, err := exec.LookPath("pacman")
+	return err == nil
+}
+
+func (p *PacmanUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	if err := p.configurePackageFilter(cfg); err != nil {
+		return err
+	}
+
+	if helper, ok := cfg["aur_helper"]; ok {
+		name, isString := helper.(string)
+		switch {
+		case isString && (name == "" || name == "paru" || name == "yay"):
+			p.aurHelper = name
+		default:
+			return fmt.Errorf("aur_helper は paru / yay のいずれかを指定してください: %v", helper)
+		}
+	}
+
+	if useSudo, ok := cfg["use_sudo"].(bool); ok {
+		p.useSudo = useSudo
+		return nil
+	}
+
+	// 旧キー `sudo` との後方互換
+	if useSudo, ok := cfg["sudo"].(bool); ok {
+		p.useSudo = useSudo
+	}
+
+	return nil
+}
+
+func (p *PacmanUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	packages, err := p.listUpgradable(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.filterCheckResult(&CheckResult{
+		AvailableUpdates: len(packages),
+		Packages:         packages,
+	}), nil
+}
+
+func (p *PacmanUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	// まず更新確認
+	upgradable, err := p.listUpgradable(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	checkResult := p.filterCheckResult(&CheckResult{
+		AvailableUpdates: len(upgradable),
+		Packages:         upgradable,
+	})
+
+	result := &UpdateResult{Held: checkResult.Held}
+
+	if checkResult.AvailableUpdates == 0 {
+		result.Message = allPackagesUpToDateMessage
+		return result, nil
+	}
+
+	if opts.DryRun {
+		result.Message = fmt.Sprintf("%d 件のパッケージが更新可能です（DryRunモード）", checkResult.AvailableUpdates)
+		result.Packages = checkResult.Packages
+
+		return result, nil
+	}
+
+	pacnewBefore := findPacnewFiles(p.pacnewDir)
+
+	if err := p.runUpgrade(ctx, excludedPackageNames(upgradable, checkResult.Packages)); err != nil {
+		result.Errors = append(result.Errors, err)
+		return result, fmt.Errorf("%s -Syu に失敗: %w", p.upgradeCommand(), err)
+	}
+
+	result.UpdatedCount = checkResult.AvailableUpdates
+	result.Packages = checkResult.Packages
+	result.Message = fmt.Sprintf("%d 件のパッケージを更新しました", result.UpdatedCount)
+
+	if created := newPacnewFiles(pacnewBefore, findPacnewFiles(p.pacnewDir)); len(created) > 0 {
+		result.Message += fmt.Sprintf("（⚠️ .pacnew が %d 件作成されました。設定ファイルのマージが必要です: %s）",
+			len(created), strings.Join(created, ", "))
+	}
+
+	return result, nil
+}
+
+// ListInstalled は pacman -Q でインストール済みパッケージを返します。
+func (p *PacmanUpdater) ListInstalled(ctx context.Context) ([]PackageInfo, error) {
+	output, err := runCommandOutputWithLocaleC(ctx, "pacman", []string{"-Q"}, "pacman -Q の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseFieldPackages(string(output), false), nil
+}
+
+// listUpgradable は公式リポジトリと（aur_helper 指定時は）AUR の更新候補を返します。
+// 公式リポジトリは部分更新を避けるため、同期 DB を書き換えない checkupdates を優先します。
+func (p *PacmanUpdater) listUpgradable(ctx context.Context) ([]PackageInfo, error) {
+	var (
+		output []byte
+		err    error
+	)
+
+	if _, lookErr := exec.LookPath("checkupdates"); lookErr == nil {
+		output, err = runCommandOutputWithLocaleCAllowExitCodes(ctx, "checkupdates", nil,
+			"checkupdates の実行に失敗: %w", checkupdatesNoUpdatesExitCode)
+	} else {
+		output, err = runCommandOutputWithLocaleCAllowExitCodes(ctx, "pacman", []string{"-Qu"},
+			"pacman -Qu の実行に失敗: %w", pacmanNoUpdatesExitCode)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	packages := parsePacmanUpgradable(string(output))
+
+	if p.aurHelper == "" {
+		return packages, nil
+	}
+
+	aurOutput, err := runCommandOutputWithLocaleCAllowExitCodes(ctx, p.aurHelper, []string{"-Qua"},
+		p.aurHelper+" -Qua の実行に失敗: %w", pacmanNoUpdatesExitCode)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(packages, parsePacmanUpgradable(string(aurOutput))...), nil
+}
+
+// upgradeCommand は更新に使うコマンド（aur_helper 指定時はその helper）を返します。
+func (p *PacmanUpdater) upgradeCommand() string {
+	if p.aurHelper != "" {
+		return p.aurHelper
+	}
+
+	return "pacman"
+}
+
+// runUpgrade は -Syu で全体を更新します。
+// Arch Linux では部分更新が非推奨のため、hold / ignore で除外したパッケージは個別指定ではなく --ignore で渡します。
+// paru / yay は root での実行を拒否し内部で sudo を呼ぶため、use_sudo に関わらず sudo を付けません。
+func (p *PacmanUpdater) runUpgrade(ctx context.Context, excluded []string) error {
+	args := []string{"-Syu", "--noconfirm"}
+	if len(excluded) > 0 {
+		args = append(args, "--ignore", strings.Join(excluded, ","))
+	}
+
+	var cmd *exec.Cmd
+
+	if p.aurHelper == "" && p.useSudo {
+		cmd = exec.CommandContext(ctx, "sudo", append([]string{"pacman"}, args...)...)
+	} else {
+		cmd = exec.CommandContext(ctx, p.upgradeCommand(), args...)
+	}
+
+	attachCommandOutput(ctx, cmd)
+	cmd.Stdin = os.Stdin
+
+	return cmd.Run()
+}
+
+// parsePacmanUpgradable は "pacman -Qu" / "checkupdates" / "paru -Qua" の出力をパースします。
+// 形式: "name old-version -> new-version"（IgnorePkg の対象には末尾に "[ignored]" が付く）
+func parsePacmanUpgradable(output string) []PackageInfo {
+	lines := strings.Split(output, "\n")
+	packages := make([]PackageInfo, 0, len(lines))
+
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[2] != "->" {
+			continue
+		}
+
+		// pacman.conf の IgnorePkg は -Syu でも更新されないため候補に含めない
+		if len(fields) > 4 && fields[4] == "[ignored]" {
+			continue
+		}
+
+		packages = append(packages, PackageInfo{
+			Name:           fields[0],
+			CurrentVersion: fields[1],
+			NewVersion:     fields[3],
+		})
+	}
+
+	return packages
+}
+
+// excludedPackageNames は hold / ignore で更新対象から外れたパッケージ名を返します。
+func excludedPackageNames(all, remaining []PackageInfo) []string {
+	keep := make(map[string]bool, len(remaining))
+	for _, pkg := range remaining {
+		keep[pkg.Name] = true
+	}
+
+	var excluded []string
+
+	for _, pkg := range all {
+		if !keep[pkg.Name] {
+			excluded = append(excluded, pkg.Name)
+		}
+	}
+
+	return excluded
+}
+
+// findPacnewFiles は dir 配下の .pacnew ファイルを返します。読めないディレクトリは無視します。
+func findPacnewFiles(dir string) map[string]bool {
+	found := make(map[string]bool)
+
+	if dir == "" {
+		return found
+	}
+
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		if !d.IsDir() && strings.HasSuffix(d.Name(), pacnewSuffix) {
+			found[path] = true
+		}
+
+		return nil
+	})
+
+	return found
+}
+
+// newPacnewFiles は更新前になかった .pacnew ファイルをパス順に返します。
+func newPacnewFiles(before, after map[string]bool) []string {
+	var created []string
+
+	for path := range after {
+		if !before[path] {
+			created = append(created, path)
+		}
+	}
+
+	sort.Strings(created)
+
+	return created
+}
@@ -0,0 +1,317 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacmanUpdater_Configure(t *testing.T) {
+	tests := []struct {
+		name          string
+		cfg           config.ManagerConfig
+		wantSudo      bool
+		wantAURHelper string
+		wantErr       bool
+	}{
+		{name: "nilの設定はデフォルトのまま", cfg: nil, wantSudo: true},
+		{name: "aur_helper=paru", cfg: config.ManagerConfig{"aur_helper": "paru"}, wantSudo: true, wantAURHelper: "paru"},
+		{name: "aur_helper=yay", cfg: config.ManagerConfig{"aur_helper": "yay"}, wantSudo: true, wantAURHelper: "yay"},
+		{name: "未知のaur_helperはエラー", cfg: config.ManagerConfig{"aur_helper": "trizen"}, wantErr: true},
+		{name: "文字列以外のaur_helperはエラー", cfg: config.ManagerConfig{"aur_helper": true}, wantErr: true},
+		{name: "use_sudo=false", cfg: config.ManagerConfig{"use_sudo": false}, wantSudo: false},
+		{name: "旧キーsudo=false", cfg: config.ManagerConfig{"sudo": false}, wantSudo: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &PacmanUpdater{useSudo: true}
+
+			err := p.Configure(tt.cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSudo, p.useSudo)
+			assert.Equal(t, tt.wantAURHelper, p.aurHelper)
+		})
+	}
+}
+
+func TestParsePacmanUpgradable(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected []PackageInfo
+	}{
+		{
+			name:     "空の出力",
+			output:   "",
+			expected: []PackageInfo{},
+		},
+		{
+			name: "pacman -Qu / checkupdates の出力",
+			output: `linux 6.9.7.arch1-1 -> 6.9.8.arch1-1
+python 3.12.3-1 -> 3.12.4-1
+`,
+			expected: []PackageInfo{
+				{Name: "linux", CurrentVersion: "6.9.7.arch1-1", NewVersion: "6.9.8.arch1-1"},
+				{Name: "python", CurrentVersion: "3.12.3-1", NewVersion: "3.12.4-1"},
+			},
+		},
+		{
+			name: "IgnorePkg の対象は除外",
+			output: `linux 6.9.7.arch1-1 -> 6.9.8.arch1-1 [ignored]
+git 2.45.1-1 -> 2.45.2-1
+`,
+			expected: []PackageInfo{
+				{Name: "git", CurrentVersion: "2.45.1-1", NewVersion: "2.45.2-1"},
+			},
+		},
+		{
+			name: "警告やエラー行は無視",
+			output: `warning: database file for 'core' does not exist (use '-Sy' to download)
+:: Looking for devel upgrades...
+visual-studio-code-bin 1.90.0-1 -> 1.90.1-1
+`,
+			expected: []PackageInfo{
+				{Name: "visual-studio-code-bin", CurrentVersion: "1.90.0-1", NewVersion: "1.90.1-1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parsePacmanUpgradable(tt.output))
+		})
+	}
+}
+
+func TestPacmanUpdater_Check(t *testing.T) {
+	testCases := []struct {
+		name         string
+		mode         string
+		aurHelper    string
+		checkupdates bool
+		wantNames    []string
+		wantErr      bool
+	}{
+		{name: "pacman -Qu の更新候補", mode: "updates", wantNames: []string{"linux", "git"}},
+		{name: "pacman -Qu の終了コード1は更新なし", mode: "none", wantNames: []string{}},
+		{name: "checkupdates を優先", mode: "updates", checkupdates: true, wantNames: []string{"linux"}},
+		{name: "checkupdates の終了コード2は更新なし", mode: "none", checkupdates: true, wantNames: []string{}},
+		{name: "checkupdates の終了コード1は失敗", mode: "check_error", checkupdates: true, wantErr: true},
+		{name: "aur_helper で AUR の更新候補を追加", mode: "updates", aurHelper: "paru", wantNames: []string{"linux", "git", "paru-bin"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			setupFakePacman(t, tc.mode, tc.checkupdates)
+
+			p := &PacmanUpdater{aurHelper: tc.aurHelper}
+			got, err := p.Check(context.Background())
+
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantNames, packageNames(got.Packages))
+			assert.Equal(t, len(tc.wantNames), got.AvailableUpdates)
+		})
+	}
+}
+
+func TestPacmanUpdater_Update(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mode        string
+		cfg         config.ManagerConfig
+		opts        UpdateOptions
+		wantUpdated int
+		wantArgs    string
+		wantErr     string
+		msgContains []string
+		msgExcludes string
+	}{
+		{
+			name:        "DryRunは更新しない",
+			mode:        "updates",
+			opts:        UpdateOptions{DryRun: true},
+			msgContains: []string{"DryRunモード"},
+		},
+		{
+			name:        "対象なし",
+			mode:        "none",
+			msgContains: []string{"すべてのパッケージは最新です"},
+		},
+		{
+			name:        "更新成功",
+			mode:        "updates",
+			wantUpdated: 2,
+			wantArgs:    "pacman -Syu --noconfirm",
+			msgContains: []string{"2 件のパッケージを更新しました"},
+			msgExcludes: ".pacnew",
+		},
+		{
+			name:        "hold は --ignore で渡す",
+			mode:        "updates",
+			cfg:         config.ManagerConfig{"hold": []interface{}{"linux"}},
+			wantUpdated: 1,
+			wantArgs:    "pacman -Syu --noconfirm --ignore linux",
+		},
+		{
+			name:        "aur_helper 指定時は helper で更新",
+			mode:        "updates",
+			cfg:         config.ManagerConfig{"aur_helper": "yay"},
+			wantUpdated: 3,
+			wantArgs:    "yay -Syu --noconfirm",
+		},
+		{
+			name:        "作成された .pacnew を警告",
+			mode:        "pacnew",
+			wantUpdated: 2,
+			msgContains: []string{".pacnew が 1 件作成されました", "pacman.conf.pacnew"},
+		},
+		{
+			name:    "更新失敗",
+			mode:    "upgrade_error",
+			wantErr: "pacman -Syu に失敗",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := setupFakePacman(t, tc.mode, false)
+			etcDir := filepath.Join(dir, "etc")
+			require.NoError(t, os.MkdirAll(etcDir, 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(etcDir, "mkinitcpio.conf.pacnew"), nil, 0o644))
+			t.Setenv("DSX_TEST_PACMAN_ETC", etcDir)
+
+			p := &PacmanUpdater{useSudo: false, pacnewDir: etcDir}
+			require.NoError(t, p.Configure(tc.cfg))
+
+			got, err := p.Update(context.Background(), tc.opts)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantUpdated, got.UpdatedCount)
+
+			for _, want := range tc.msgContains {
+				assert.Contains(t, got.Message, want)
+			}
+
+			// 更新前から存在した .pacnew は報告しない
+			assert.NotContains(t, got.Message, "mkinitcpio.conf.pacnew")
+
+			if tc.msgExcludes != "" {
+				assert.NotContains(t, got.Message, tc.msgExcludes)
+			}
+
+			if tc.wantArgs != "" {
+				args, readErr := os.ReadFile(filepath.Join(dir, "upgrade-args"))
+				require.NoError(t, readErr)
+				assert.Equal(t, tc.wantArgs, strings.TrimSpace(string(args)))
+			}
+		})
+	}
+}
+
+// setupFakePacman は PATH を偽の pacman / paru / yay（と任意で checkupdates）だけに差し替えます。
+// 実行環境に本物の checkupdates があっても影響を受けないよう、PATH には偽コマンドのディレクトリのみを設定します。
+func setupFakePacman(t *testing.T, mode string, withCheckupdates bool) string {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("pacman は Linux 専用のため Windows ではスキップ")
+	}
+
+	dir := t.TempDir()
+
+	pacman := `#!/bin/sh
+mode="${DSX_TEST_PACMAN_MODE}"
+case "$1" in
+-Qu)
+  if [ "${mode}" = "none" ]; then
+    exit 1
+  fi
+  echo "linux 6.9.7.arch1-1 -> 6.9.8.arch1-1"
+  echo "git 2.45.1-1 -> 2.45.2-1"
+  echo "nvidia 550.90-1 -> 555.58-1 [ignored]"
+  exit 0
+  ;;
+-Q)
+  echo "linux 6.9.7.arch1-1"
+  exit 0
+  ;;
+-Syu)
+  echo "${0##*/} $*" > "${DSX_TEST_PACMAN_DIR}/upgrade-args"
+  if [ "${mode}" = "upgrade_error" ]; then
+    echo "error: failed to commit transaction (conflicting files)" 1>&2
+    exit 1
+  fi
+  if [ "${mode}" = "pacnew" ]; then
+    : > "${DSX_TEST_PACMAN_ETC}/pacman.conf.pacnew"
+  fi
+  exit 0
+  ;;
+esac
+echo "invalid args" 1>&2
+exit 1
+`
+	aurHelper := `#!/bin/sh
+if [ "$1" = "-Qua" ]; then
+  echo "paru-bin 2.0.2-1 -> 2.0.3-1"
+  exit 0
+fi
+if [ "$1" = "-Syu" ]; then
+  echo "${0##*/} $*" > "${DSX_TEST_PACMAN_DIR}/upgrade-args"
+  exit 0
+fi
+exec "${DSX_TEST_PACMAN_DIR}/pacman" "$@"
+`
+	checkupdates := `#!/bin/sh
+mode="${DSX_TEST_PACMAN_MODE}"
+if [ "${mode}" = "check_error" ]; then
+  echo "==> ERROR: Cannot fetch updates" 1>&2
+  exit 1
+fi
+if [ "${mode}" = "none" ]; then
+  exit 2
+fi
+echo "linux 6.9.7.arch1-1 -> 6.9.8.arch1-1"
+exit 0
+`
+
+	scripts := map[string]string{"pacman": pacman, "paru": aurHelper, "yay": aurHelper}
+	if withCheckupdates {
+		scripts["checkupdates"] = checkupdates
+	}
+
+	for name, content := range scripts {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o755); err != nil {
+			t.Fatalf("fake %s command write failed: %v", name, err)
+		}
+	}
+
+	t.Setenv("PATH", dir)
+	t.Setenv("DSX_TEST_PACMAN_DIR", dir)
+	t.Setenv("DSX_TEST_PACMAN_MODE", mode)
+
+	return dir
+}
@@ -12,7 +12,7 @@ import (
 
 // PipxUpdater は pipx (Python CLI ツール) の実装です。
 type PipxUpdater struct {
-	// 将来的な拡張のための設定フィールド
+	packageFilterSupport
 }
 
 // 起動時にレジストリに登録
@@ -34,12 +34,28 @@ func (p *PipxUpdater) IsAvailable() bool {
 }
 
 func (p *PipxUpdater) Configure(cfg config.ManagerConfig) error {
-	// 現時点では設定項目なし
-	return nil
+	return p.configurePackageFilter(cfg)
 }
 
 func (p *PipxUpdater) Check(ctx context.Context) (*CheckResult, error) {
-	// pipx list --json でインストール済みパッケージを取得
+	installed, err := p.ListInstalled(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := p.filterCheckResult(&CheckResult{Packages: installed})
+	packages := filtered.Packages
+
+	// pipx は個別の outdated チェックがないため、
+	// AvailableUpdates は 0 とし、インストール済みパッケージのみ返す
+	// 実際の更新可否は upgrade-all 実行時に判定される
+	filtered.Message = fmt.Sprintf("%d 件のインストール済みパッケージを確認（更新可否は実行時に判定）", len(packages))
+
+	return filtered, nil
+}
+
+// ListInstalled は "pipx list --json" でインストール済みパッケージを返します。
+func (p *PipxUpdater) ListInstalled(ctx context.Context) ([]PackageInfo, error) {
 	cmd := exec.CommandContext(ctx, "pipx", "list", "--json")
 
 	output, err := cmd.Output()
@@ -47,27 +63,25 @@ func (p *PipxUpdater) Check(ctx context.Context) (*CheckResult, error) {
 		return nil, fmt.Errorf("pipx list の実行に失敗: %w", err)
 	}
 
-	packages := p.parsePipxListJSON(output)
+	return p.parsePipxListJSON(output), nil
+}
 
-	// pipx は個別の outdated チェックがないため、
-	// AvailableUpdates は 0 とし、インストール済みパッケージのみ返す
-	// 実際の更新可否は upgrade-all 実行時に判定される
-	return &CheckResult{
-		AvailableUpdates: 0,
-		Packages:         packages,
-		Message:          fmt.Sprintf("%d 件のインストール済みパッケージを確認（更新可否は実行時に判定）", len(packages)),
-	}, nil
+// RollbackCommand は pipx install --force で更新前のバージョンを入れ直すコマンドを返します。
+func (p *PipxUpdater) RollbackCommand(pkg PackageInfo) (*RollbackCommand, error) {
+	return newRollbackCommand(pkg, "pipx", func(version string) []string {
+		return []string{"install", "--force", pkg.Name + "==" + version}
+	})
 }
 
 func (p *PipxUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
-	result := &UpdateResult{}
-
 	// まず更新確認
 	checkResult, err := p.Check(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	result := &UpdateResult{Held: checkResult.Held}
+
 	if len(checkResult.Packages) == 0 {
 		result.Message = "pipx でインストールされたパッケージがありません"
 		return result, nil
@@ -80,10 +94,13 @@ func (p *PipxUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateRe
 		return result, nil
 	}
 
+	if checkResult.hasFilteredPackages() {
+		return p.upgradeEach(ctx, checkResult, result)
+	}
+
 	// 実際の更新を実行
 	cmd := exec.CommandContext(ctx, "pipx", "upgrade-all")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	attachCommandOutput(ctx, cmd)
 	cmd.Stdin = os.Stdin
 
 	if err := cmd.Run(); err != nil {
@@ -100,6 +117,18 @@ func (p *PipxUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateRe
 	return result, nil
 }
 
+// upgradeEach は hold / ignore で除外されなかったパッケージを1件ずつ更新します。
+// upgrade-all では除外対象まで更新されるため、除外がある場合はこちらを使用します。
+func (p *PipxUpdater) upgradeEach(ctx context.Context, checkResult *CheckResult, result *UpdateResult) (*UpdateResult, error) {
+	err := runPerPackageUpdate(ctx, checkResult, result, "pipx", func(name string) []string {
+		return []string{upgradeCommand, name}
+	})
+
+	result.Message = fmt.Sprintf("%d 件のパッケージを確認・更新しました", result.UpdatedCount)
+
+	return result, err
+}
+
 // parsePipxListJSON は "pipx list --json" の出力をパースします
 // JSON 形式: { "venvs": { "package-name": { "metadata": { "main_package": { "package_version": "1.0.0" } } } } }
 func (p *PipxUpdater) parsePipxListJSON(output []byte) []PackageInfo {
@@ -0,0 +1,602 @@
+package updater
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/scottlz0310/dsx/internal/runner"
+)
+
+const (
+	// PluginProtocolVersion は dsx-updater-* プラグインとやり取りする JSON プロトコルのバージョンです。
+	PluginProtocolVersion = 1
+	// PluginExecutablePrefix はプラグインの実行ファイル名の接頭辞です。"dsx-updater-foo" はマネージャ "foo" になります。
+	PluginExecutablePrefix = "dsx-updater-"
+
+	// PluginErrorUnsupportedMethod は未対応のメソッドを呼び出された場合にプラグインが返すエラーコードです。
+	PluginErrorUnsupportedMethod = "unsupported_method"
+	// PluginCapabilitySelfUpdate は check_self_update / self_update に対応していることを name の応答で宣言する機能名です。
+	PluginCapabilitySelfUpdate = "self_update"
+
+	// pluginQueryTimeout は name / display_name / is_available / configure の応答を待つ上限です。
+	pluginQueryTimeout = 10 * time.Second
+)
+
+// プラグインのメソッド名（Updater / ManagerSelfUpdater のメソッドに対応）
+const (
+	pluginMethodName            = "name"
+	pluginMethodDisplayName     = "display_name"
+	pluginMethodIsAvailable     = "is_available"
+	pluginMethodConfigure       = "configure"
+	pluginMethodCheck           = "check"
+	pluginMethodUpdate          = "update"
+	pluginMethodCheckSelfUpdate = "check_self_update"
+	pluginMethodSelfUpdate      = "self_update"
+)
+
+// pluginRequest はプラグインの標準入力に渡す1回の呼び出しです。
+// プラグインは呼び出しごとに起動するため、sys.managers.<name> の設定は毎回 config として渡します。
+type pluginRequest struct {
+	ProtocolVersion int                  `json:"protocol_version"`
+	Method          string               `json:"method"`
+	Config          config.ManagerConfig `json:"config"`
+	Options         *pluginUpdateOptions `json:"options,omitempty"`
+	Packages        []string             `json:"packages,omitempty"`
+}
+
+type pluginUpdateOptions struct {
+	DryRun         bool   `json:"dry_run"`
+	Verbose        bool   `json:"verbose"`
+	CurrentVersion string `json:"current_version,omitempty"`
+}
+
+// pluginResponse はプラグインが標準出力に書き出す応答です。result と error のどちらか一方を持ちます。
+type pluginResponse struct {
+	ProtocolVersion int             `json:"protocol_version"`
+	Result          json.RawMessage `json:"result,omitempty"`
+	Error           *PluginError    `json:"error,omitempty"`
+}
+
+// PluginError はプラグインが応答の error で返したエラーです。
+type PluginError struct {
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e *PluginError) Error() string {
+	if e.Code == "" {
+		return e.Message
+	}
+
+	return fmt.Sprintf("%s (%s)", e.Message, e.Code)
+}
+
+type pluginNameResult struct {
+	Name         string   `json:"name"`
+	Capabilities []string `json:"capabilities,omitempty"`
+}
+
+type pluginDisplayNameResult struct {
+	DisplayName string `json:"display_name"`
+}
+
+type pluginAvailabilityResult struct {
+	Available bool   `json:"available"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+type pluginPackage struct {
+	Name           string `json:"name"`
+	CurrentVersion string `json:"current_version,omitempty"`
+	NewVersion     string `json:"new_version,omitempty"`
+}
+
+type pluginCheckResult struct {
+	AvailableUpdates int             `json:"available_updates"`
+	Packages         []pluginPackage `json:"packages"`
+	Message          string          `json:"message,omitempty"`
+}
+
+type pluginUpdateResult struct {
+	UpdatedCount int             `json:"updated_count"`
+	FailedCount  int             `json:"failed_count"`
+	Packages     []pluginPackage `json:"packages"`
+	Errors       []string        `json:"errors,omitempty"`
+	Message      string          `json:"message,omitempty"`
+	// Continuation は self_update の応答でのみ使用します（"continue" / "skip"）。
+	Continuation string `json:"continuation,omitempty"`
+}
+
+// PluginExecutable は探索で見つかったプラグインの実行ファイルです。
+type PluginExecutable struct {
+	// Name は実行ファイル名から接頭辞（と Windows の拡張子）を除いたマネージャ名です。
+	Name string
+	Path string
+}
+
+// PluginUpdater は dsx-updater-* プラグインを Updater として扱うアダプタです。
+// hold / ignore は組み込みマネージャと同じくアダプタ側で適用し、プラグインには更新対象のパッケージ名のみを渡します。
+type PluginUpdater struct {
+	packageFilterSupport
+
+	name         string
+	path         string
+	capabilities []string
+
+	mu           sync.Mutex
+	cfg          config.ManagerConfig
+	displayName  string
+	availability *pluginAvailabilityResult
+}
+
+// PluginSource はプラグインから読み込んだUpdaterが実装するインターフェースです。
+type PluginSource interface {
+	// PluginPath はプラグインの実行ファイルのパスを返します。
+	PluginPath() string
+}
+
+// selfUpdatingPluginUpdater は self_update 機能を宣言したプラグインのアダプタです。
+type selfUpdatingPluginUpdater struct {
+	*PluginUpdater
+}
+
+// LoadPlugin はプラグインの name メソッドでプロトコルを確認し、Updater を返します。
+// 応答の名前は実行ファイル名（dsx-updater-<name>）と一致している必要があります。
+func LoadPlugin(ctx context.Context, plugin PluginExecutable) (Updater, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, pluginQueryTimeout)
+	defer cancel()
+
+	var res pluginNameResult
+	if err := callPlugin(queryCtx, plugin.Path, pluginRequest{Method: pluginMethodName}, &res, false); err != nil {
+		return nil, fmt.Errorf("%s の name に失敗: %w", plugin.Path, err)
+	}
+
+	if res.Name != plugin.Name {
+		return nil, fmt.Errorf("%s が返した名前 %q が実行ファイル名の %q と一致しません", plugin.Path, res.Name, plugin.Name)
+	}
+
+	p := &PluginUpdater{name: plugin.Name, path: plugin.Path, capabilities: res.Capabilities}
+	if p.HasCapability(PluginCapabilitySelfUpdate) {
+		return &selfUpdatingPluginUpdater{PluginUpdater: p}, nil
+	}
+
+	return p, nil
+}
+
+// PluginDirs はプラグインを探索するディレクトリを優先順に返します（~/.config/dsx/plugins、PATH の順）。
+func PluginDirs() []string {
+	dirs := make([]string, 0)
+
+	if dir, err := config.PluginDir(); err == nil {
+		dirs = append(dirs, dir)
+	}
+
+	return append(dirs, filepath.SplitList(os.Getenv("PATH"))...)
+}
+
+// DiscoverPlugins は dirs から dsx-updater-* の実行ファイルを探します。
+// 同じ名前のプラグインが複数ある場合は、先に見つかったものを使います（PATH の解決と同じ）。
+func DiscoverPlugins(dirs []string) []PluginExecutable {
+	seen := make(map[string]struct{})
+	plugins := make([]PluginExecutable, 0)
+
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			// 存在しない PATH の要素などは無視する
+			continue
+		}
+
+		for _, entry := range entries {
+			name, ok := pluginNameFromFile(dir, entry.Name())
+			if !ok {
+				continue
+			}
+
+			if _, dup := seen[name]; dup {
+				continue
+			}
+
+			seen[name] = struct{}{}
+			plugins = append(plugins, PluginExecutable{Name: name, Path: filepath.Join(dir, entry.Name())})
+		}
+	}
+
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+
+	return plugins
+}
+
+// RegisterPlugins は dirs で見つかったプラグインをレジストリに登録します。
+// 組み込みマネージャ・カスタムマネージャと同じ名前のプラグインや、name の応答が不正なプラグインは登録せず、
+// まとめてエラーとして返します（他のプラグインの登録は続行します）。
+func RegisterPlugins(ctx context.Context, dirs []string) error {
+	var problems []string
+
+	for _, plugin := range DiscoverPlugins(dirs) {
+		if existing, ok := Get(plugin.Name); ok {
+			if _, isPlugin := existing.(PluginSource); !isPlugin {
+				problems = append(problems, fmt.Sprintf("%s: 既存のマネージャと同じ名前のため %s を登録しません", plugin.Name, plugin.Path))
+				continue
+			}
+		}
+
+		u, err := LoadPlugin(ctx, plugin)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", plugin.Name, err))
+			continue
+		}
+
+		Register(u)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("プラグインの登録に失敗: %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// pluginNameFromFile は dsx-updater-<name> の実行ファイルであればマネージャ名を返します。
+// Homebrew などはコマンドをシンボリックリンクで配置するため、リンク先で判定します。
+func pluginNameFromFile(dir, base string) (string, bool) {
+	if !strings.HasPrefix(base, PluginExecutablePrefix) {
+		return "", false
+	}
+
+	info, err := os.Stat(filepath.Join(dir, base))
+	if err != nil || info.IsDir() {
+		return "", false
+	}
+
+	if runtime.GOOS == "windows" {
+		ext := strings.ToLower(filepath.Ext(base))
+		if ext != ".exe" && ext != ".bat" && ext != ".cmd" {
+			return "", false
+		}
+
+		base = strings.TrimSuffix(base, filepath.Ext(base))
+	} else if info.Mode().Perm()&0o111 == 0 {
+		return "", false
+	}
+
+	name := strings.TrimPrefix(base, PluginExecutablePrefix)
+	if name == "" {
+		return "", false
+	}
+
+	return name, true
+}
+
+func (p *PluginUpdater) Name() string {
+	return p.name
+}
+
+func (p *PluginUpdater) PluginPath() string {
+	return p.path
+}
+
+// HasCapability はプラグインが name の応答で機能を宣言しているかを返します。
+func (p *PluginUpdater) HasCapability(capability string) bool {
+	return slices.Contains(p.capabilities, capability)
+}
+
+// DisplayName はプラグインの display_name を返します。取得に失敗した場合は "name (プラグイン)" を返します。
+func (p *PluginUpdater) DisplayName() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.displayName != "" {
+		return p.displayName
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pluginQueryTimeout)
+	defer cancel()
+
+	var res pluginDisplayNameResult
+	if err := p.callLocked(ctx, pluginRequest{Method: pluginMethodDisplayName}, &res, false); err != nil || strings.TrimSpace(res.DisplayName) == "" {
+		return p.name + " (プラグイン)"
+	}
+
+	p.displayName = strings.TrimSpace(res.DisplayName)
+
+	return p.displayName
+}
+
+func (p *PluginUpdater) IsAvailable() bool {
+	return p.checkAvailability().Available
+}
+
+// UnavailableReason は is_available の応答の reason を返します。プラグインを実行できない場合はそのエラーを返します。
+func (p *PluginUpdater) UnavailableReason() string {
+	availability := p.checkAvailability()
+	if availability.Available {
+		return ""
+	}
+
+	return availability.Reason
+}
+
+// checkAvailability は is_available の結果を返します。Configure で設定が変わるまで結果を再利用します。
+func (p *PluginUpdater) checkAvailability() pluginAvailabilityResult {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.availability != nil {
+		return *p.availability
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pluginQueryTimeout)
+	defer cancel()
+
+	var res pluginAvailabilityResult
+	if err := p.callLocked(ctx, pluginRequest{Method: pluginMethodIsAvailable}, &res, false); err != nil {
+		res = pluginAvailabilityResult{Reason: fmt.Sprintf("is_available に失敗しました: %v", err)}
+	}
+
+	if !res.Available && res.Reason == "" {
+		res.Reason = "プラグインが利用不可と応答しました"
+	}
+
+	p.availability = &res
+
+	return res
+}
+
+// Configure は hold / ignore を適用し、プラグインの configure で設定を検証します。
+// 検証に通った設定は以降のすべての呼び出しで config として渡します。
+func (p *PluginUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	if err := p.configurePackageFilter(cfg); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), pluginQueryTimeout)
+	defer cancel()
+
+	if err := callPlugin(ctx, p.path, pluginRequest{Method: pluginMethodConfigure, Config: cfg}, nil, false); err != nil {
+		return fmt.Errorf("%s プラグインの configure に失敗: %w", p.name, err)
+	}
+
+	p.cfg = cfg
+	p.availability = nil
+
+	return nil
+}
+
+func (p *PluginUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	var res pluginCheckResult
+	if err := p.call(ctx, pluginRequest{Method: pluginMethodCheck}, &res, false); err != nil {
+		return nil, fmt.Errorf("%s プラグインの check に失敗: %w", p.name, err)
+	}
+
+	return p.filterCheckResult(res.toCheckResult()), nil
+}
+
+func (p *PluginUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	// まず更新確認（hold / ignore の適用を含む）
+	checkResult, err := p.Check(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if checkResult.AvailableUpdates == 0 {
+		return &UpdateResult{Held: checkResult.Held, Message: allPackagesUpToDateMessage}, nil
+	}
+
+	req := pluginRequest{
+		Method:   pluginMethodUpdate,
+		Options:  newPluginUpdateOptions(opts),
+		Packages: packageNames(checkResult.Packages),
+	}
+
+	var res pluginUpdateResult
+
+	callErr := p.call(ctx, req, &res, !opts.DryRun)
+
+	result := res.toUpdateResult()
+	result.Held = checkResult.Held
+
+	if callErr != nil {
+		result.Errors = append(result.Errors, callErr)
+		return result, fmt.Errorf("%s プラグインの update に失敗: %w", p.name, callErr)
+	}
+
+	if opts.DryRun {
+		if len(result.Packages) == 0 {
+			result.Packages = checkResult.Packages
+		}
+
+		if result.Message == "" {
+			result.Message = fmt.Sprintf("%d 件のパッケージが更新可能です（DryRunモード）", checkResult.AvailableUpdates)
+		}
+
+		return result, nil
+	}
+
+	if result.Message == "" {
+		result.Message = fmt.Sprintf("%d 件のパッケージを更新しました", result.UpdatedCount)
+	}
+
+	return result, nil
+}
+
+func (p *selfUpdatingPluginUpdater) CheckSelfUpdate(ctx context.Context) (*CheckResult, error) {
+	var res pluginCheckResult
+	if err := p.call(ctx, pluginRequest{Method: pluginMethodCheckSelfUpdate}, &res, false); err != nil {
+		return nil, fmt.Errorf("%s プラグインの check_self_update に失敗: %w", p.name, err)
+	}
+
+	return res.toCheckResult(), nil
+}
+
+func (p *selfUpdatingPluginUpdater) SelfUpdate(ctx context.Context, opts UpdateOptions) (*SelfUpdateResult, error) {
+	req := pluginRequest{Method: pluginMethodSelfUpdate, Options: newPluginUpdateOptions(opts)}
+
+	var res pluginUpdateResult
+
+	callErr := p.call(ctx, req, &res, !opts.DryRun)
+
+	result := &SelfUpdateResult{UpdateResult: *res.toUpdateResult(), Continuation: ContinueNormalUpdate}
+	if SelfUpdateContinuation(res.Continuation) == SkipNormalUpdate {
+		result.Continuation = SkipNormalUpdate
+	}
+
+	if callErr != nil {
+		result.Errors = append(result.Errors, callErr)
+		return result, fmt.Errorf("%s プラグインの self_update に失敗: %w", p.name, callErr)
+	}
+
+	return result, nil
+}
+
+func (p *PluginUpdater) call(ctx context.Context, req pluginRequest, result interface{}, stream bool) error {
+	p.mu.Lock()
+	cfg := p.cfg
+	p.mu.Unlock()
+
+	req.Config = cfg
+
+	return callPlugin(ctx, p.path, req, result, stream)
+}
+
+// callLocked は p.mu を保持した状態で呼び出します。
+func (p *PluginUpdater) callLocked(ctx context.Context, req pluginRequest, result interface{}, stream bool) error {
+	req.Config = p.cfg
+
+	return callPlugin(ctx, p.path, req, result, stream)
+}
+
+// callPlugin はプラグインを1回起動し、標準入力にリクエストを渡して標準出力の応答を result に読み取ります。
+// stream が true の場合、更新の進捗を表示できるよう標準エラーを端末（とジョブ出力ログ）に流します。
+func callPlugin(ctx context.Context, path string, req pluginRequest, result interface{}, stream bool) error {
+	req.ProtocolVersion = PluginProtocolVersion
+	if req.Config == nil {
+		req.Config = config.ManagerConfig{}
+	}
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("リクエストの作成に失敗: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Stdin = bytes.NewReader(payload)
+
+	var stdout, stderr bytes.Buffer
+
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if stream {
+		stderrWriters := []io.Writer{os.Stderr, &stderr}
+		if output := runner.JobOutput(ctx); output != nil {
+			fmt.Fprintf(output, "$ %s (%s)\n", path, req.Method)
+			stderrWriters = append(stderrWriters, output)
+		}
+
+		cmd.Stderr = io.MultiWriter(stderrWriters...)
+	}
+
+	runErr := cmd.Run()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
+	var resp pluginResponse
+	if decodeErr := json.Unmarshal(bytes.TrimSpace(stdout.Bytes()), &resp); decodeErr != nil {
+		if runErr != nil {
+			return buildCommandOutputErr(runErr, combineCommandOutputs(stdout.Bytes(), stderr.Bytes()))
+		}
+
+		return fmt.Errorf("応答を JSON として解析できません: %w", decodeErr)
+	}
+
+	if resp.ProtocolVersion != PluginProtocolVersion {
+		return fmt.Errorf("未対応のプロトコルバージョンです: %d（対応: %d）", resp.ProtocolVersion, PluginProtocolVersion)
+	}
+
+	if resp.Error != nil {
+		return resp.Error
+	}
+
+	if runErr != nil {
+		return buildCommandOutputErr(runErr, stderr.Bytes())
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if len(resp.Result) == 0 {
+		return errors.New("応答に result がありません")
+	}
+
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("result の解析に失敗: %w", err)
+	}
+
+	return nil
+}
+
+func newPluginUpdateOptions(opts UpdateOptions) *pluginUpdateOptions {
+	return &pluginUpdateOptions{DryRun: opts.DryRun, Verbose: opts.Verbose, CurrentVersion: opts.CurrentVersion}
+}
+
+func (r pluginCheckResult) toCheckResult() *CheckResult {
+	packages := toPackageInfos(r.Packages)
+
+	return &CheckResult{
+		AvailableUpdates: max(r.AvailableUpdates, len(packages)),
+		Packages:         packages,
+		Message:          r.Message,
+	}
+}
+
+func (r pluginUpdateResult) toUpdateResult() *UpdateResult {
+	result := &UpdateResult{
+		UpdatedCount: r.UpdatedCount,
+		FailedCount:  r.FailedCount,
+		Packages:     toPackageInfos(r.Packages),
+		Message:      r.Message,
+	}
+
+	for _, msg := range r.Errors {
+		result.Errors = append(result.Errors, errors.New(msg))
+	}
+
+	return result
+}
+
+func toPackageInfos(packages []pluginPackage) []PackageInfo {
+	infos := make([]PackageInfo, 0, len(packages))
+	for _, pkg := range packages {
+		infos = append(infos, PackageInfo{Name: pkg.Name, CurrentVersion: pkg.CurrentVersion, NewVersion: pkg.NewVersion})
+	}
+
+	return infos
+}
@@ -0,0 +1,245 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/scottlz0310/dsx/internal/config"
+)
+
+// pluginConformanceUnknownMethod は未対応メソッドの扱いを確認するために送る、存在しないメソッド名です。
+const pluginConformanceUnknownMethod = "dsx_conformance_unknown_method"
+
+// PluginConformanceStatus は契約の1項目の検証結果の種別です。
+type PluginConformanceStatus string
+
+const (
+	PluginConformancePass PluginConformanceStatus = "pass"
+	PluginConformanceFail PluginConformanceStatus = "fail"
+	// PluginConformanceSkip は前提を満たさないため検証しなかった項目です（利用不可のプラグインの check など）。
+	PluginConformanceSkip PluginConformanceStatus = "skip"
+)
+
+// PluginConformanceCheck は契約の1項目の検証結果です。
+type PluginConformanceCheck struct {
+	Contract string
+	Status   PluginConformanceStatus
+	Detail   string
+}
+
+// PluginConformanceReport はプラグイン1つの適合性検証の結果です。
+type PluginConformanceReport struct {
+	Plugin PluginExecutable
+	Checks []PluginConformanceCheck
+}
+
+// Passed は失敗した項目がないかを返します。
+func (r *PluginConformanceReport) Passed() bool {
+	for _, check := range r.Checks {
+		if check.Status == PluginConformanceFail {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (r *PluginConformanceReport) add(contract string, status PluginConformanceStatus, detail string) {
+	r.Checks = append(r.Checks, PluginConformanceCheck{Contract: contract, Status: status, Detail: detail})
+}
+
+func (r *PluginConformanceReport) addResult(contract string, err error, detail string) {
+	if err != nil {
+		r.add(contract, PluginConformanceFail, err.Error())
+		return
+	}
+
+	r.add(contract, PluginConformancePass, detail)
+}
+
+// VerifyPlugin はプラグインをプロトコルの各メソッドで呼び出し、応答が契約を満たすかを検証します。
+// update / self_update は dry_run のみで呼び出すため、プラグインが契約を守っていれば環境を変更しません。
+// cfg は sys.managers.<name> の設定として毎回渡します（nil の場合は空の設定）。
+func VerifyPlugin(ctx context.Context, plugin PluginExecutable, cfg config.ManagerConfig) *PluginConformanceReport {
+	report := &PluginConformanceReport{Plugin: plugin}
+
+	call := func(req pluginRequest, result interface{}) error {
+		queryCtx, cancel := context.WithTimeout(ctx, pluginQueryTimeout)
+		defer cancel()
+
+		req.Config = cfg
+
+		return callPlugin(queryCtx, plugin.Path, req, result, false)
+	}
+
+	var nameRes pluginNameResult
+
+	nameErr := call(pluginRequest{Method: pluginMethodName}, &nameRes)
+	if nameErr == nil {
+		nameErr = validatePluginName(plugin, nameRes)
+	}
+
+	report.addResult(pluginMethodName, nameErr, fmt.Sprintf("%s（機能: %s）", nameRes.Name, formatCapabilities(nameRes.Capabilities)))
+
+	if nameErr != nil {
+		// 名前が取得できないプラグインは登録されないため、以降の検証は行わない
+		return report
+	}
+
+	var displayRes pluginDisplayNameResult
+
+	displayErr := call(pluginRequest{Method: pluginMethodDisplayName}, &displayRes)
+	if displayErr == nil && strings.TrimSpace(displayRes.DisplayName) == "" {
+		displayErr = errors.New("display_name が空です")
+	}
+
+	report.addResult(pluginMethodDisplayName, displayErr, displayRes.DisplayName)
+
+	report.addResult(pluginMethodConfigure, call(pluginRequest{Method: pluginMethodConfigure}, nil), "設定を受け付けました")
+
+	var availability pluginAvailabilityResult
+
+	availableErr := call(pluginRequest{Method: pluginMethodIsAvailable}, &availability)
+	if availableErr == nil && !availability.Available && strings.TrimSpace(availability.Reason) == "" {
+		availableErr = errors.New("利用できない場合は reason で理由を返す必要があります")
+	}
+
+	availableDetail := "利用可能"
+	if !availability.Available {
+		availableDetail = "利用不可: " + availability.Reason
+	}
+
+	report.addResult(pluginMethodIsAvailable, availableErr, availableDetail)
+
+	unknownErr := call(pluginRequest{Method: pluginConformanceUnknownMethod}, nil)
+	report.addResult("unsupported_method", validateUnsupportedMethod(unknownErr), "未知のメソッドを拒否しました")
+
+	if availableErr != nil || !availability.Available {
+		for _, method := range []string{pluginMethodCheck, pluginMethodUpdate} {
+			report.add(method, PluginConformanceSkip, "is_available が利用可能を返さないため未検証")
+		}
+
+		return report
+	}
+
+	packages := verifyPluginCheck(report, pluginMethodCheck, call)
+	verifyPluginDryRun(report, pluginMethodUpdate, call, packages)
+
+	if !slices.Contains(nameRes.Capabilities, PluginCapabilitySelfUpdate) {
+		return report
+	}
+
+	verifyPluginCheck(report, pluginMethodCheckSelfUpdate, call)
+	verifyPluginDryRun(report, pluginMethodSelfUpdate, call, nil)
+
+	return report
+}
+
+// verifyPluginCheck は check / check_self_update の応答を検証し、更新候補のパッケージ名を返します。
+func verifyPluginCheck(report *PluginConformanceReport, method string, call func(pluginRequest, interface{}) error) []string {
+	var res pluginCheckResult
+	if err := call(pluginRequest{Method: method}, &res); err != nil {
+		report.addResult(method, err, "")
+		return nil
+	}
+
+	seen := make(map[string]struct{}, len(res.Packages))
+
+	for _, pkg := range res.Packages {
+		if strings.TrimSpace(pkg.Name) == "" {
+			report.addResult(method, errors.New("packages に name が空の要素があります"), "")
+			return nil
+		}
+
+		if _, dup := seen[pkg.Name]; dup {
+			report.addResult(method, fmt.Errorf("packages に %q が重複しています", pkg.Name), "")
+			return nil
+		}
+
+		seen[pkg.Name] = struct{}{}
+	}
+
+	if len(res.Packages) > 0 && res.AvailableUpdates != len(res.Packages) {
+		report.addResult(method, fmt.Errorf("available_updates (%d) が packages の件数 (%d) と一致しません", res.AvailableUpdates, len(res.Packages)), "")
+		return nil
+	}
+
+	report.addResult(method, nil, fmt.Sprintf("%d 件の更新候補", res.AvailableUpdates))
+
+	names := make([]string, 0, len(res.Packages))
+	for _, pkg := range res.Packages {
+		names = append(names, pkg.Name)
+	}
+
+	return names
+}
+
+// verifyPluginDryRun は update / self_update を dry_run で呼び出し、何も更新していないことを検証します。
+func verifyPluginDryRun(report *PluginConformanceReport, method string, call func(pluginRequest, interface{}) error, packages []string) {
+	contract := method + " (dry_run)"
+
+	req := pluginRequest{
+		Method:   method,
+		Options:  &pluginUpdateOptions{DryRun: true},
+		Packages: packages,
+	}
+
+	var res pluginUpdateResult
+
+	err := call(req, &res)
+	if err == nil && res.UpdatedCount != 0 {
+		err = fmt.Errorf("dry_run で updated_count が %d です（0 である必要があります）", res.UpdatedCount)
+	}
+
+	if err == nil && method == pluginMethodSelfUpdate {
+		switch SelfUpdateContinuation(res.Continuation) {
+		case "", ContinueNormalUpdate, SkipNormalUpdate:
+		default:
+			err = fmt.Errorf("continuation は %q / %q のいずれかである必要があります: %q", ContinueNormalUpdate, SkipNormalUpdate, res.Continuation)
+		}
+	}
+
+	report.addResult(contract, err, res.Message)
+}
+
+func validatePluginName(plugin PluginExecutable, res pluginNameResult) error {
+	if res.Name != plugin.Name {
+		return fmt.Errorf("name %q が実行ファイル名の %q と一致しません", res.Name, plugin.Name)
+	}
+
+	for _, capability := range res.Capabilities {
+		if capability != PluginCapabilitySelfUpdate {
+			return fmt.Errorf("未知の機能です: %q（対応: %s）", capability, PluginCapabilitySelfUpdate)
+		}
+	}
+
+	return nil
+}
+
+func validateUnsupportedMethod(err error) error {
+	if err == nil {
+		return errors.New("未知のメソッドに成功を返しました")
+	}
+
+	var pluginErr *PluginError
+	if !errors.As(err, &pluginErr) {
+		return fmt.Errorf("未知のメソッドには error を含む応答を返す必要があります: %w", err)
+	}
+
+	if pluginErr.Code != PluginErrorUnsupportedMethod {
+		return fmt.Errorf("error.code は %q である必要があります: %q", PluginErrorUnsupportedMethod, pluginErr.Code)
+	}
+
+	return nil
+}
+
+func formatCapabilities(capabilities []string) string {
+	if len(capabilities) == 0 {
+		return "なし"
+	}
+
+	return strings.Join(capabilities, ", ")
+}
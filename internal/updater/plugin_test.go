@@ -0,0 +1,385 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoverPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("実行権限で判定するため Windows ではスキップ")
+	}
+
+	first := t.TempDir()
+	second := t.TempDir()
+	linkTarget := filepath.Join(t.TempDir(), "real-plugin")
+
+	writeFile := func(path string, mode os.FileMode) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"), mode))
+	}
+
+	writeFile(filepath.Join(first, "dsx-updater-foo"), 0o755)
+	writeFile(filepath.Join(first, "dsx-updater-noexec"), 0o644)
+	writeFile(filepath.Join(first, "dsx-updater-"), 0o755)
+	writeFile(filepath.Join(first, "other-tool"), 0o755)
+	require.NoError(t, os.Mkdir(filepath.Join(first, "dsx-updater-dir"), 0o755))
+	writeFile(filepath.Join(second, "dsx-updater-foo"), 0o755)
+	writeFile(filepath.Join(second, "dsx-updater-bar"), 0o755)
+	writeFile(linkTarget, 0o755)
+	require.NoError(t, os.Symlink(linkTarget, filepath.Join(second, "dsx-updater-link")))
+
+	got := DiscoverPlugins([]string{"", first, filepath.Join(first, "missing"), second})
+
+	assert.Equal(t, []PluginExecutable{
+		{Name: "bar", Path: filepath.Join(second, "dsx-updater-bar")},
+		{Name: "foo", Path: filepath.Join(first, "dsx-updater-foo")},
+		{Name: "link", Path: filepath.Join(second, "dsx-updater-link")},
+	}, got)
+}
+
+func TestPluginUpdater_ReferencePlugin(t *testing.T) {
+	plugin := buildReferencePlugin(t)
+	stateFile := writeExampleState(t)
+
+	u, err := LoadPlugin(context.Background(), plugin)
+	require.NoError(t, err)
+	assert.Equal(t, "example", u.Name())
+	assert.Equal(t, "Example (参照プラグイン)", u.DisplayName())
+
+	self, ok := u.(ManagerSelfUpdater)
+	require.True(t, ok, "self_update を宣言したプラグインは ManagerSelfUpdater を実装する")
+
+	// 状態ファイルがない既定の設定では利用不可
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	require.NoError(t, u.Configure(config.ManagerConfig{}))
+	assert.False(t, u.IsAvailable())
+	assert.Contains(t, UnavailableLabel(u), "状態ファイル")
+
+	require.NoError(t, u.Configure(config.ManagerConfig{"state_file": stateFile, "hold": []interface{}{"bar"}}))
+	assert.True(t, u.IsAvailable())
+
+	checkResult, err := u.Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, checkResult.AvailableUpdates)
+	assert.Equal(t, []PackageInfo{{Name: "foo", CurrentVersion: "1.0.0", NewVersion: "1.1.0"}}, checkResult.Packages)
+	assert.Equal(t, []PackageInfo{{Name: "bar", CurrentVersion: "2.0.0", NewVersion: "2.1.0"}}, checkResult.Held)
+
+	dryRun, err := u.Update(context.Background(), UpdateOptions{DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, 0, dryRun.UpdatedCount)
+	assert.Contains(t, dryRun.Message, "DryRunモード")
+	assert.Equal(t, "1.0.0", readExampleState(t, stateFile).Packages["foo"].Installed)
+
+	updated, err := u.Update(context.Background(), UpdateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, updated.UpdatedCount)
+	assert.Equal(t, checkResult.Held, updated.Held)
+
+	state := readExampleState(t, stateFile)
+	assert.Equal(t, "1.1.0", state.Packages["foo"].Installed)
+	assert.Equal(t, "2.0.0", state.Packages["bar"].Installed, "hold したパッケージは更新しない")
+
+	upToDate, err := u.Update(context.Background(), UpdateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, allPackagesUpToDateMessage, upToDate.Message)
+
+	selfCheck, err := self.CheckSelfUpdate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, selfCheck.AvailableUpdates)
+
+	selfResult, err := self.SelfUpdate(context.Background(), UpdateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, selfResult.UpdatedCount)
+	assert.True(t, selfResult.ShouldContinueNormalUpdate())
+	assert.Equal(t, "0.2.0", readExampleState(t, stateFile).Self.Installed)
+}
+
+func TestVerifyPlugin_ReferencePlugin(t *testing.T) {
+	plugin := buildReferencePlugin(t)
+	stateFile := writeExampleState(t)
+
+	report := VerifyPlugin(context.Background(), plugin, config.ManagerConfig{"state_file": stateFile})
+	assert.True(t, report.Passed(), "%+v", report.Checks)
+
+	contracts := make([]string, 0, len(report.Checks))
+	for _, check := range report.Checks {
+		assert.Equal(t, PluginConformancePass, check.Status, check.Contract)
+		contracts = append(contracts, check.Contract)
+	}
+
+	assert.Equal(t, []string{
+		"name", "display_name", "configure", "is_available", "unsupported_method",
+		"check", "update (dry_run)", "check_self_update", "self_update (dry_run)",
+	}, contracts)
+
+	// dry_run の検証で状態ファイルが変更されていないこと
+	assert.Equal(t, "1.0.0", readExampleState(t, stateFile).Packages["foo"].Installed)
+
+	t.Run("利用不可の場合は check / update を検証しない", func(t *testing.T) {
+		report := VerifyPlugin(context.Background(), plugin, config.ManagerConfig{"state_file": filepath.Join(t.TempDir(), "missing.json")})
+		assert.True(t, report.Passed())
+
+		last := report.Checks[len(report.Checks)-1]
+		assert.Equal(t, PluginConformanceSkip, last.Status)
+	})
+}
+
+func TestVerifyPlugin_NonConforming(t *testing.T) {
+	testCases := []struct {
+		name         string
+		responses    map[string]string
+		wantContract string
+		wantDetail   string
+	}{
+		{
+			name:         "名前が実行ファイル名と一致しない",
+			responses:    map[string]string{"name": `{"name":"other"}`},
+			wantContract: "name",
+			wantDetail:   "一致しません",
+		},
+		{
+			name:         "未知の機能を宣言",
+			responses:    map[string]string{"name": `{"name":"fake","capabilities":["teleport"]}`},
+			wantContract: "name",
+			wantDetail:   "未知の機能です",
+		},
+		{
+			name:         "未知のメソッドに成功を返す",
+			responses:    map[string]string{"*": `{}`},
+			wantContract: "unsupported_method",
+			wantDetail:   "成功を返しました",
+		},
+		{
+			name:         "利用不可の理由がない",
+			responses:    map[string]string{"is_available": `{"available":false}`},
+			wantContract: "is_available",
+			wantDetail:   "reason",
+		},
+		{
+			name:         "件数が packages と一致しない",
+			responses:    map[string]string{"check": `{"available_updates":3,"packages":[{"name":"a"}]}`},
+			wantContract: "check",
+			wantDetail:   "一致しません",
+		},
+		{
+			name:         "dry_run で更新したと応答",
+			responses:    map[string]string{"update": `{"updated_count":1}`},
+			wantContract: "update (dry_run)",
+			wantDetail:   "updated_count が 1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			plugin := writeFakePlugin(t, "fake", tc.responses)
+
+			report := VerifyPlugin(context.Background(), plugin, nil)
+			assert.False(t, report.Passed())
+
+			var found bool
+
+			for _, check := range report.Checks {
+				if check.Contract == tc.wantContract && check.Status == PluginConformanceFail {
+					found = true
+
+					assert.Contains(t, check.Detail, tc.wantDetail)
+				}
+			}
+
+			assert.True(t, found, "%s が失敗として報告されること: %+v", tc.wantContract, report.Checks)
+		})
+	}
+}
+
+func TestPluginUpdater_ErrorResponse(t *testing.T) {
+	plugin := writeFakePlugin(t, "fake", map[string]string{"check": "!registry unreachable"})
+
+	u, err := LoadPlugin(context.Background(), plugin)
+	require.NoError(t, err)
+
+	_, isSelfUpdater := u.(ManagerSelfUpdater)
+	assert.False(t, isSelfUpdater, "self_update を宣言しないプラグインは本体更新フェーズの対象外")
+
+	_, err = u.Check(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "fake プラグインの check に失敗: registry unreachable")
+}
+
+func TestRegisterPlugins(t *testing.T) {
+	t.Cleanup(clearRegistry)
+	clearRegistry()
+
+	Register(&mockUpdater{name: "apt", displayName: "APT"})
+
+	fakePlugin := writeFakePlugin(t, "fake", nil)
+	dir := filepath.Dir(fakePlugin.Path)
+	writeFakePluginTo(t, dir, "apt", nil)
+	writeFakePluginTo(t, dir, "liar", map[string]string{"name": `{"name":"honest"}`})
+
+	err := RegisterPlugins(context.Background(), []string{dir})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "apt: 既存のマネージャと同じ名前")
+	assert.Contains(t, err.Error(), "liar:")
+
+	apt, ok := Get("apt")
+	require.True(t, ok)
+	assert.Equal(t, "APT", apt.DisplayName())
+
+	_, ok = Get("liar")
+	assert.False(t, ok)
+
+	fake, ok := Get("fake")
+	require.True(t, ok)
+	source, ok := fake.(PluginSource)
+	require.True(t, ok)
+	assert.Equal(t, fakePlugin.Path, source.PluginPath())
+}
+
+// buildReferencePlugin は examples/plugins/dsx-updater-example をビルドします。
+func buildReferencePlugin(t *testing.T) PluginExecutable {
+	t.Helper()
+
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("参照プラグインのビルドに go コマンドが必要です")
+	}
+
+	name := "dsx-updater-example"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+
+	path := filepath.Join(t.TempDir(), name)
+
+	output, err := exec.Command(goBin, "build", "-o", path, "../../examples/plugins/dsx-updater-example").CombinedOutput()
+	require.NoError(t, err, string(output))
+
+	return PluginExecutable{Name: "example", Path: path}
+}
+
+type exampleState struct {
+	Self struct {
+		Installed string `json:"installed"`
+		Latest    string `json:"latest"`
+	} `json:"self"`
+	Packages map[string]struct {
+		Installed string `json:"installed"`
+		Latest    string `json:"latest"`
+	} `json:"packages"`
+}
+
+// writeExampleState は参照プラグインの状態ファイルを作成します。
+// foo（1.0.0 → 1.1.0）と bar（2.0.0 → 2.1.0）が更新可能、baz は最新、本体は 0.1.0 → 0.2.0 です。
+func writeExampleState(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	data := `{
+  "self": {"installed": "0.1.0", "latest": "0.2.0"},
+  "packages": {
+    "foo": {"installed": "1.0.0", "latest": "1.1.0"},
+    "bar": {"installed": "2.0.0", "latest": "2.1.0"},
+    "baz": {"installed": "3.0.0", "latest": "3.0.0"}
+  }
+}`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+	return path
+}
+
+func readExampleState(t *testing.T, path string) exampleState {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var state exampleState
+	require.NoError(t, json.Unmarshal(data, &state))
+
+	return state
+}
+
+// writeFakePlugin は responses に従って応答する偽のプラグインを配置します。
+// 既定ではプロトコルに適合した応答を返し、responses でメソッドごとの result を差し替えます（"*" は未知のメソッド、
+// "!" で始まる値は error.message として返します）。
+func writeFakePlugin(t *testing.T, name string, responses map[string]string) PluginExecutable {
+	t.Helper()
+
+	return writeFakePluginTo(t, t.TempDir(), name, responses)
+}
+
+func writeFakePluginTo(t *testing.T, dir, name string, responses map[string]string) PluginExecutable {
+	t.Helper()
+
+	results := map[string]string{
+		"name":         `{"name":"` + name + `"}`,
+		"display_name": `{"display_name":"Fake"}`,
+		"configure":    `{}`,
+		"is_available": `{"available":true}`,
+		"check":        `{"available_updates":1,"packages":[{"name":"a","current_version":"1","new_version":"2"}]}`,
+		"update":       `{"updated_count":0}`,
+		"*":            "!unsupported",
+	}
+	for method, result := range responses {
+		results[method] = result
+	}
+
+	respond := func(result string) string {
+		if len(result) > 0 && result[0] == '!' {
+			code := ""
+			if result == "!unsupported" {
+				code = `"code":"unsupported_method",`
+			}
+
+			return `{"protocol_version":1,"error":{` + code + `"message":"` + result[1:] + `"}}`
+		}
+
+		return `{"protocol_version":1,"result":` + result + `}`
+	}
+
+	methods := []string{"name", "display_name", "configure", "is_available", "check", "update"}
+
+	var (
+		fileName string
+		script   string
+	)
+
+	if runtime.GOOS == "windows" {
+		// リクエストの1行を読み、" を ' に置き換えてからメソッド名の部分一致で分岐する
+		fileName = PluginExecutablePrefix + name + ".cmd"
+		script = "@echo off\nset req=\nset /p req=\nset \"req=%req:\"='%\"\n"
+
+		for _, method := range methods {
+			script += `if not "%req:'method':'` + method + `'=%"=="%req%" goto ` + method + "\n"
+		}
+
+		script += "echo " + respond(results["*"]) + "\nexit /b 0\n"
+		for _, method := range methods {
+			script += ":" + method + "\necho " + respond(results[method]) + "\nexit /b 0\n"
+		}
+	} else {
+		fileName = PluginExecutablePrefix + name
+		script = "#!/bin/sh\nreq=$(cat)\ncase \"$req\" in\n"
+
+		for _, method := range methods {
+			script += `*'"method":"` + method + `"'*) echo '` + respond(results[method]) + "' ;;\n"
+		}
+
+		script += "*) echo '" + respond(results["*"]) + "' ;;\nesac\n"
+	}
+
+	path := filepath.Join(dir, fileName)
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o755))
+
+	return PluginExecutable{Name: name, Path: path}
+}
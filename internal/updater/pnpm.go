@@ -15,7 +15,9 @@ import (
 )
 
 // PnpmUpdater は pnpm グローバルパッケージマネージャの実装です。
-type PnpmUpdater struct{}
+type PnpmUpdater struct {
+	packageFilterSupport
+}
 
 const (
 	pnpmNoImporterManifestErrorCode = "ERR_PNPM_NO_IMPORTER_MANIFEST_FOUND"
@@ -40,9 +42,13 @@ func (p *PnpmUpdater) IsAvailable() bool {
 	return err == nil
 }
 
+// DependsOn は nvm による Node.js 更新の後にグローバルパッケージを更新するための依存を返します。
+func (p *PnpmUpdater) DependsOn() []string {
+	return []string{"nvm"}
+}
+
 func (p *PnpmUpdater) Configure(cfg config.ManagerConfig) error {
-	// 現時点では設定項目なし
-	return nil
+	return p.configurePackageFilter(cfg)
 }
 
 func (p *PnpmUpdater) Check(ctx context.Context) (*CheckResult, error) {
@@ -81,10 +87,10 @@ func (p *PnpmUpdater) check(ctx context.Context, allowManifestCreate bool) (*Che
 		)
 	}
 
-	return &CheckResult{
+	return p.filterCheckResult(&CheckResult{
 		AvailableUpdates: len(packages),
 		Packages:         packages,
-	}, nil
+	}), nil
 }
 
 func (p *PnpmUpdater) runOutdatedCommand(ctx context.Context) (stdout, stderr []byte, err error) {
@@ -113,7 +119,7 @@ func (p *PnpmUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateRe
 		return nil, err
 	}
 
-	result := &UpdateResult{}
+	result := &UpdateResult{Held: checkResult.Held}
 
 	if checkResult.AvailableUpdates == 0 {
 		result.Message = "すべての pnpm グローバルパッケージは最新です"
@@ -128,7 +134,7 @@ func (p *PnpmUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateRe
 		return result, nil
 	}
 
-	if err := p.runUpdate(ctx); err != nil {
+	if err := p.runUpdate(ctx, packageNamesIfFiltered(checkResult)...); err != nil {
 		result.Errors = append(result.Errors, err)
 
 		return result, fmt.Errorf("pnpm update -g --latest に失敗: %w", err)
@@ -141,12 +147,20 @@ func (p *PnpmUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateRe
 	return result, nil
 }
 
-func (p *PnpmUpdater) runUpdate(ctx context.Context) error {
-	cmd := exec.CommandContext(ctx, "pnpm", "update", "-g", "--latest", "--no-interactive")
+// RollbackCommand は pnpm add -g で更新前のバージョンを入れ直すコマンドを返します。
+func (p *PnpmUpdater) RollbackCommand(pkg PackageInfo) (*RollbackCommand, error) {
+	return newRollbackCommand(pkg, "pnpm", func(version string) []string {
+		return []string{"add", "-g", pkg.Name + "@" + version}
+	})
+}
+
+// runUpdate は pnpm update -g --latest を実行します。packages を指定した場合はそれらのみ更新します。
+func (p *PnpmUpdater) runUpdate(ctx context.Context, packages ...string) error {
+	args := append([]string{"update", "-g", "--latest", "--no-interactive"}, packages...)
+	cmd := exec.CommandContext(ctx, "pnpm", args...)
 
 	cmd.Env = append(os.Environ(), "CI=true")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	attachCommandOutput(ctx, cmd)
 	cmd.Stdin = os.Stdin
 
 	return cmd.Run()
@@ -0,0 +1,68 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ErrRollbackVersionUnknown は更新前のバージョンが分からずロールバックできないことを示します。
+var ErrRollbackVersionUnknown = errors.New("更新前のバージョンが記録されていません")
+
+// Rollbacker は更新前のバージョンへ戻すコマンドを生成できるUpdaterが追加で実装する任意インターフェースです。
+// sys rollback で履歴に記録された旧バージョンへのダウングレードに使用します。
+type Rollbacker interface {
+	// RollbackCommand は pkg を pkg.CurrentVersion（更新前のバージョン）へ戻すコマンドを返します。
+	// 戻せないパッケージの場合はエラーを返します。
+	RollbackCommand(pkg PackageInfo) (*RollbackCommand, error)
+}
+
+// RollbackCommand は1パッケージ分のダウングレードコマンドです。
+type RollbackCommand struct {
+	// Name は実行するコマンド名です（例: "go", "sudo"）
+	Name string
+	// Args はコマンド引数です
+	Args []string
+	// Note は戻し先が記録バージョンと一致しない場合などの補足です（計画表示に使用）
+	Note string
+}
+
+// String は表示用のコマンドラインを返します。
+func (c *RollbackCommand) String() string {
+	return strings.Join(append([]string{c.Name}, c.Args...), " ")
+}
+
+// Run はコマンドを実行します。出力はそのまま端末に流します。
+func (c *RollbackCommand) Run(ctx context.Context) error {
+	cmd := exec.CommandContext(ctx, c.Name, c.Args...) //nolint:gosec // G204: コマンド名は各 Updater が固定値で生成する
+	attachCommandOutput(ctx, cmd)
+	cmd.Stdin = os.Stdin
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s に失敗: %w", c.String(), err)
+	}
+
+	return nil
+}
+
+// newRollbackCommand は更新前のバージョンが空でないことを確認してコマンドを生成します。
+func newRollbackCommand(pkg PackageInfo, name string, argsFn func(version string) []string) (*RollbackCommand, error) {
+	version := strings.TrimSpace(pkg.CurrentVersion)
+	if version == "" {
+		return nil, fmt.Errorf("%s: %w", pkg.Name, ErrRollbackVersionUnknown)
+	}
+
+	return &RollbackCommand{Name: name, Args: argsFn(version)}, nil
+}
+
+// withSudo は useSudo が true の場合に sudo 経由のコマンドへ変換します。
+func withSudo(cmd *RollbackCommand, useSudo bool) *RollbackCommand {
+	if !useSudo {
+		return cmd
+	}
+
+	return &RollbackCommand{Name: "sudo", Args: append([]string{cmd.Name}, cmd.Args...), Note: cmd.Note}
+}
@@ -0,0 +1,155 @@
+package updater
+
+import (
+	"testing"
+
+	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollbackCommand(t *testing.T) {
+	goUpdater := &GoUpdater{}
+	assert.NoError(t, goUpdater.Configure(config.ManagerConfig{
+		"targets": []interface{}{"golang.org/x/tools/gopls@latest"},
+	}))
+
+	testCases := []struct {
+		name    string
+		updater Rollbacker
+		pkg     PackageInfo
+		want    string
+	}{
+		{
+			name:    "go はツール名から go.targets のパッケージパスを解決",
+			updater: goUpdater,
+			pkg:     PackageInfo{Name: "gopls", CurrentVersion: "v0.16.0", NewVersion: "v0.17.0"},
+			want:    "go install golang.org/x/tools/gopls@v0.16.0",
+		},
+		{
+			name:    "go はパッケージパスでも指定可能",
+			updater: goUpdater,
+			pkg:     PackageInfo{Name: "golang.org/x/tools/gopls", CurrentVersion: "v0.16.0"},
+			want:    "go install golang.org/x/tools/gopls@v0.16.0",
+		},
+		{
+			name:    "cargo",
+			updater: &CargoUpdater{},
+			pkg:     PackageInfo{Name: "ripgrep", CurrentVersion: "14.0.0"},
+			want:    "cargo install ripgrep --version 14.0.0 --force",
+		},
+		{
+			name:    "pipx",
+			updater: &PipxUpdater{},
+			pkg:     PackageInfo{Name: "black", CurrentVersion: "24.1.0"},
+			want:    "pipx install --force black==24.1.0",
+		},
+		{
+			name:    "uv",
+			updater: &UVUpdater{},
+			pkg:     PackageInfo{Name: "ruff", CurrentVersion: "0.5.0"},
+			want:    "uv tool install --force ruff==0.5.0",
+		},
+		{
+			name:    "gem",
+			updater: &GemUpdater{},
+			pkg:     PackageInfo{Name: "rake", CurrentVersion: "13.1.0"},
+			want:    "gem install rake -v 13.1.0",
+		},
+		{
+			name:    "npm",
+			updater: &NpmUpdater{},
+			pkg:     PackageInfo{Name: "typescript", CurrentVersion: "5.4.5"},
+			want:    "npm install -g typescript@5.4.5",
+		},
+		{
+			name:    "pnpm",
+			updater: &PnpmUpdater{},
+			pkg:     PackageInfo{Name: "typescript", CurrentVersion: "5.4.5"},
+			want:    "pnpm add -g typescript@5.4.5",
+		},
+		{
+			name:    "bun",
+			updater: &BunUpdater{},
+			pkg:     PackageInfo{Name: "typescript", CurrentVersion: "5.4.5"},
+			want:    "bun add -g typescript@5.4.5",
+		},
+		{
+			name:    "apt は sudo 経由でダウングレードを許可",
+			updater: &AptUpdater{useSudo: true},
+			pkg:     PackageInfo{Name: "curl", CurrentVersion: "8.5.0-2ubuntu10", NewVersion: "8.5.0-2ubuntu10.1"},
+			want:    "sudo apt install --allow-downgrades -y curl=8.5.0-2ubuntu10",
+		},
+		{
+			name:    "snap はバージョン不明でも revert 可能",
+			updater: &SnapUpdater{},
+			pkg:     PackageInfo{Name: "firefox"},
+			want:    "snap revert firefox",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, err := tc.updater.RollbackCommand(tc.pkg)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, cmd.String())
+		})
+	}
+}
+
+func TestRollbackCommand_Errors(t *testing.T) {
+	goUpdater := &GoUpdater{}
+	assert.NoError(t, goUpdater.Configure(config.ManagerConfig{
+		"targets": []interface{}{"golang.org/x/tools/gopls@latest"},
+	}))
+
+	_, err := goUpdater.RollbackCommand(PackageInfo{Name: "staticcheck", CurrentVersion: "v0.4.0"})
+	assert.Error(t, err)
+
+	_, err = goUpdater.RollbackCommand(PackageInfo{Name: "gopls", CurrentVersion: "(devel)"})
+	assert.ErrorIs(t, err, ErrRollbackVersionUnknown)
+
+	_, err = (&CargoUpdater{}).RollbackCommand(PackageInfo{Name: "ripgrep"})
+	assert.ErrorIs(t, err, ErrRollbackVersionUnknown)
+}
+
+func TestRollbackCommand_SnapNote(t *testing.T) {
+	// snap revert は記録バージョンではなく直前のリビジョンへ戻るため、sudo 経由でも注記を保持する
+	cmd, err := (&SnapUpdater{useSudo: true}).RollbackCommand(PackageInfo{Name: "firefox", CurrentVersion: "124.0"})
+	assert.NoError(t, err)
+	assert.Equal(t, "sudo snap revert firefox", cmd.String())
+	assert.Equal(t, "直前のリビジョンへ戻す（記録バージョンとは限らない）", cmd.Note)
+
+	cmd, err = (&CargoUpdater{}).RollbackCommand(PackageInfo{Name: "ripgrep", CurrentVersion: "14.0.0"})
+	assert.NoError(t, err)
+	assert.Empty(t, cmd.Note)
+}
+
+func TestRollbackerImplementations(t *testing.T) {
+	testCases := []struct {
+		updater Updater
+		want    bool
+	}{
+		{updater: &AptUpdater{}, want: true},
+		{updater: &BunUpdater{}, want: true},
+		{updater: &CargoUpdater{}, want: true},
+		{updater: &GemUpdater{}, want: true},
+		{updater: &GoUpdater{}, want: true},
+		{updater: &NpmUpdater{}, want: true},
+		{updater: &PipxUpdater{}, want: true},
+		{updater: &PnpmUpdater{}, want: true},
+		{updater: &SnapUpdater{}, want: true},
+		{updater: &UVUpdater{}, want: true},
+		{updater: &BrewUpdater{}, want: false},
+		{updater: &FlatpakUpdater{}, want: false},
+		{updater: &FwupdmgrUpdater{}, want: false},
+		{updater: &NvmUpdater{}, want: false},
+		{updater: &RustupUpdater{}, want: false},
+		{updater: &ScoopUpdater{}, want: false},
+		{updater: &WingetUpdater{}, want: false},
+	}
+
+	for _, tc := range testCases {
+		_, ok := tc.updater.(Rollbacker)
+		assert.Equal(t, tc.want, ok, "%s の Rollbacker 実装有無", tc.updater.Name())
+	}
+}
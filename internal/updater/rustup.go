@@ -9,8 +9,13 @@ import (
 	"github.com/scottlz0310/dsx/internal/config"
 )
 
+// rustupSelfPackageName は rustup check の出力で rustup 本体を表すパッケージ名です。
+const rustupSelfPackageName = "rustup"
+
 // RustupUpdater は rustup (Rust ツールチェーン) の実装です。
-type RustupUpdater struct{}
+type RustupUpdater struct {
+	packageFilterSupport
+}
 
 // 起動時にレジストリに登録
 func init() {
@@ -31,8 +36,7 @@ func (r *RustupUpdater) IsAvailable() bool {
 }
 
 func (r *RustupUpdater) Configure(cfg config.ManagerConfig) error {
-	// 現時点では設定項目なし
-	return nil
+	return r.configurePackageFilter(cfg)
 }
 
 func (r *RustupUpdater) Check(ctx context.Context) (*CheckResult, error) {
@@ -44,10 +48,31 @@ func (r *RustupUpdater) Check(ctx context.Context) (*CheckResult, error) {
 
 	packages := r.parseCheckOutput(string(output))
 
-	return &CheckResult{
+	return r.filterCheckResult(&CheckResult{
 		AvailableUpdates: len(packages),
 		Packages:         packages,
-	}, nil
+	}), nil
+}
+
+// ListInstalled はインストール済みツールチェーンと rustup 本体を返します。
+func (r *RustupUpdater) ListInstalled(ctx context.Context) ([]PackageInfo, error) {
+	output, err := runCommandOutputWithLocaleC(ctx, "rustup", []string{"toolchain", "list"}, "rustup toolchain list の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	packages := []PackageInfo{{Name: rustupSelfPackageName}}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		packages = append(packages, PackageInfo{Name: fields[0]})
+	}
+
+	return packages, nil
 }
 
 func (r *RustupUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
@@ -65,7 +90,7 @@ func (r *RustupUpdater) Update(ctx context.Context, opts UpdateOptions) (*Update
 			return fmt.Sprintf("%d 件の Rust ツールチェーン更新が可能です（DryRunモード）", count)
 		},
 		"rustup",
-		[]string{updateCommand},
+		rustupUpdateArgs(checkResult),
 		"rustup update に失敗: %w",
 		func(count int) string {
 			return fmt.Sprintf("%d 件の Rust ツールチェーンを更新しました", count)
@@ -73,6 +98,37 @@ func (r *RustupUpdater) Update(ctx context.Context, opts UpdateOptions) (*Update
 	)
 }
 
+// rustupUpdateArgs は hold / ignore で除外がある場合、残りのツールチェーンのみを更新する引数を返します。
+// rustup 本体が除外された場合は --no-self-update を付与し、本体のみが残った場合は self update を行います。
+func rustupUpdateArgs(checkResult *CheckResult) []string {
+	if !checkResult.hasFilteredPackages() {
+		return []string{updateCommand}
+	}
+
+	selfUpdate := false
+	toolchains := make([]string, 0, len(checkResult.Packages))
+
+	for _, pkg := range checkResult.Packages {
+		if pkg.Name == rustupSelfPackageName {
+			selfUpdate = true
+			continue
+		}
+
+		toolchains = append(toolchains, pkg.Name)
+	}
+
+	if len(toolchains) == 0 {
+		return []string{"self", updateCommand}
+	}
+
+	args := []string{updateCommand}
+	if !selfUpdate {
+		args = append(args, "--no-self-update")
+	}
+
+	return append(args, toolchains...)
+}
+
 func (r *RustupUpdater) parseCheckOutput(output string) []PackageInfo {
 	lines := strings.Split(output, "\n")
 	packages := make([]PackageInfo, 0, len(lines))
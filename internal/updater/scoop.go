@@ -10,7 +10,9 @@ import (
 )
 
 // ScoopUpdater は Scoop パッケージマネージャの実装です。
-type ScoopUpdater struct{}
+type ScoopUpdater struct {
+	packageFilterSupport
+}
 
 // 起動時にレジストリに登録
 func init() {
@@ -31,7 +33,7 @@ func (s *ScoopUpdater) IsAvailable() bool {
 }
 
 func (s *ScoopUpdater) Configure(cfg config.ManagerConfig) error {
-	return nil
+	return s.configurePackageFilter(cfg)
 }
 
 func (s *ScoopUpdater) Check(ctx context.Context) (*CheckResult, error) {
@@ -55,10 +57,10 @@ func (s *ScoopUpdater) Check(ctx context.Context) (*CheckResult, error) {
 
 	packages := s.parseStatusOutput(string(output))
 
-	return &CheckResult{
+	return s.filterCheckResult(&CheckResult{
 		AvailableUpdates: len(packages),
 		Packages:         packages,
-	}, nil
+	}), nil
 }
 
 func (s *ScoopUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
@@ -74,7 +76,7 @@ func (s *ScoopUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateR
 			return fmt.Sprintf("%d 件の Scoop パッケージが更新可能です（DryRunモード）", count)
 		},
 		"scoop",
-		[]string{updateCommand, "--all"},
+		selectUpdateArgs(checkResult, []string{updateCommand, "--all"}, []string{updateCommand}),
 		"scoop update --all に失敗: %w",
 		func(count int) string {
 			return fmt.Sprintf("%d 件の Scoop パッケージを更新しました", count)
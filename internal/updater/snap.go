@@ -13,6 +13,8 @@ import (
 
 // SnapUpdater は snap (Ubuntu Snap パッケージ) の実装です。
 type SnapUpdater struct {
+	packageFilterSupport
+
 	useSudo bool
 }
 
@@ -54,6 +56,10 @@ func (s *SnapUpdater) Configure(cfg config.ManagerConfig) error {
 		return nil
 	}
 
+	if err := s.configurePackageFilter(cfg); err != nil {
+		return err
+	}
+
 	if useSudo, ok := cfg["use_sudo"].(bool); ok {
 		s.useSudo = useSudo
 		return nil
@@ -68,6 +74,15 @@ func (s *SnapUpdater) Configure(cfg config.ManagerConfig) error {
 }
 
 func (s *SnapUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	result, err := s.checkRefreshList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.filterCheckResult(result), nil
+}
+
+func (s *SnapUpdater) checkRefreshList(ctx context.Context) (*CheckResult, error) {
 	// snap refresh --list で更新可能なスナップを取得
 	// LANG=C でロケールを英語に固定
 	cmd := exec.CommandContext(ctx, "snap", "refresh", "--list")
@@ -108,14 +123,14 @@ func (s *SnapUpdater) Check(ctx context.Context) (*CheckResult, error) {
 }
 
 func (s *SnapUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
-	result := &UpdateResult{}
-
 	// まず更新確認
 	checkResult, err := s.Check(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	result := &UpdateResult{Held: checkResult.Held}
+
 	if checkResult.AvailableUpdates == 0 {
 		result.Message = "すべてのスナップは最新です"
 		return result, nil
@@ -128,8 +143,8 @@ func (s *SnapUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateRe
 		return result, nil
 	}
 
-	// 実際の更新を実行
-	if err := s.runCommand(ctx); err != nil {
+	// 実際の更新を実行（hold / ignore で除外がある場合は残りのスナップのみ更新）
+	if err := s.runCommand(ctx, packageNamesIfFiltered(checkResult)...); err != nil {
 		result.Errors = append(result.Errors, err)
 		return result, fmt.Errorf("snap refresh に失敗: %w", err)
 	}
@@ -142,21 +157,45 @@ func (s *SnapUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateRe
 }
 
 // runCommand は snap refresh コマンドを実行します（必要に応じて sudo を使用）
-func (s *SnapUpdater) runCommand(ctx context.Context) error {
+// snaps を指定した場合はそれらのみ更新します。
+func (s *SnapUpdater) runCommand(ctx context.Context, snaps ...string) error {
+	args := append([]string{"refresh"}, snaps...)
+
 	var cmd *exec.Cmd
 	if s.useSudo {
-		cmd = exec.CommandContext(ctx, "sudo", "snap", "refresh")
+		cmd = exec.CommandContext(ctx, "sudo", append([]string{"snap"}, args...)...)
 	} else {
-		cmd = exec.CommandContext(ctx, "snap", "refresh")
+		cmd = exec.CommandContext(ctx, "snap", args...)
 	}
 
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	attachCommandOutput(ctx, cmd)
 	cmd.Stdin = os.Stdin
 
 	return cmd.Run()
 }
 
+// ListInstalled は "snap list" でインストール済みスナップを返します。
+func (s *SnapUpdater) ListInstalled(ctx context.Context) ([]PackageInfo, error) {
+	output, err := runCommandOutputWithLocaleC(ctx, "snap", []string{"list"}, "snap list の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseFieldPackages(string(output), true), nil
+}
+
+// snapRollbackNote は snap revert の戻し先が記録バージョンと一致しない可能性を示す注記です。
+const snapRollbackNote = "直前のリビジョンへ戻す（記録バージョンとは限らない）"
+
+// RollbackCommand は snap revert で直前のリビジョンへ戻すコマンドを返します。
+// snap は更新前のリビジョンを保持しているため、バージョンの記録がなくても戻せます。
+// ただし revert は記録バージョンではなく直前のリビジョンへ戻るため、その旨を Note に設定します。
+func (s *SnapUpdater) RollbackCommand(pkg PackageInfo) (*RollbackCommand, error) {
+	cmd := &RollbackCommand{Name: "snap", Args: []string{"revert", pkg.Name}, Note: snapRollbackNote}
+
+	return withSudo(cmd, s.useSudo), nil
+}
+
 // parseRefreshList は "snap refresh --list" の出力をパースします
 // 形式:
 // Name     Version    Rev   Size   Publisher   Notes
@@ -78,6 +78,13 @@ type CheckResult struct {
 	Packages []PackageInfo
 	// Message は追加情報（任意）
 	Message string
+	// Held は hold 設定により更新対象から外したパッケージ
+	Held []PackageInfo
+	// Errors は一部の対象を確認できなかった場合のエラー（確認できた分は Packages に含まれる）
+	Errors []error
+
+	// filtered は hold / ignore により除外したパッケージ数
+	filtered int
 }
 
 // PackageInfo はパッケージの情報を保持します。
@@ -109,6 +116,50 @@ type UpdateResult struct {
 	Errors []error
 	// Message は追加情報（任意）
 	Message string
+	// Held は hold 設定により更新しなかったパッケージ
+	Held []PackageInfo
+}
+
+// InstalledLister はインストール済みパッケージを列挙できるUpdaterが追加で実装する任意インターフェースです。
+// config validate で hold の指定が実在するパッケージに一致するかの確認に使用します。
+type InstalledLister interface {
+	// ListInstalled はインストール済みパッケージを副作用なしで返します。
+	ListInstalled(ctx context.Context) ([]PackageInfo, error)
+}
+
+// DependencyDeclarer は他のマネージャの更新完了を前提とするUpdaterが追加で実装する任意インターフェースです。
+// 例: npm は nvm が Node.js を切り替えた後に更新する必要があります。
+// 依存先が sys.enable に含まれない場合、その依存は無視されます。
+type DependencyDeclarer interface {
+	// DependsOn は先に更新を完了させるマネージャ名を返します。
+	DependsOn() []string
+}
+
+// ExecutionConstraints は実行方法の制約を宣言するUpdaterが追加で実装する任意インターフェースです。
+// 設定から登録するカスタムマネージャのように、cmd 側で名前を列挙できないマネージャが sudo や排他実行を宣言します。
+type ExecutionConstraints interface {
+	// RequiresSudo は更新の前に sudo の認証が必要かどうかを返します。
+	RequiresSudo() bool
+	// RunsExclusively は他のマネージャと並列に実行してはならないかどうかを返します。
+	RunsExclusively() bool
+}
+
+// AvailabilityReporter は利用できない理由を説明できるUpdaterが追加で実装する任意インターフェースです。
+// 例: SSH / WSL セッションではエディタの CLI が PATH にないことがあり、単なる未インストールと区別して案内します。
+type AvailabilityReporter interface {
+	// UnavailableReason は利用できない理由を返します。利用可能な場合は空文字を返します。
+	UnavailableReason() string
+}
+
+// UnavailableLabel は利用不可のマネージャ名に、理由を説明できる場合はその理由を添えて返します。
+func UnavailableLabel(u Updater) string {
+	if reporter, ok := u.(AvailabilityReporter); ok {
+		if reason := reporter.UnavailableReason(); reason != "" {
+			return fmt.Sprintf("%s（%s）", u.Name(), reason)
+		}
+	}
+
+	return u.Name()
 }
 
 // Registry はUpdaterの登録・取得を管理します。
@@ -189,18 +240,19 @@ func GetEnabled(cfg *config.SysConfig) ([]Updater, error) {
 			continue
 		}
 
-		if !u.IsAvailable() {
-			// 利用不可のマネージャは警告のみでスキップ
-			unavailable = append(unavailable, name)
-			continue
-		}
-		// マネージャ固有の設定を適用
+		// マネージャ固有の設定を適用（使用する CLI を設定で切り替えるマネージャがあるため、利用可否の判定より先に行う）
 		if managerCfg, ok := cfg.Managers[name]; ok {
 			if err := u.Configure(managerCfg); err != nil {
 				return nil, fmt.Errorf("%s の設定適用に失敗: %w", name, err)
 			}
 		}
 
+		if !u.IsAvailable() {
+			// 利用不可のマネージャは警告のみでスキップ
+			unavailable = append(unavailable, UnavailableLabel(u))
+			continue
+		}
+
 		result = append(result, u)
 	}
 
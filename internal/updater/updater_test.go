@@ -33,6 +33,14 @@ func (m *mockUpdater) Configure(_ config.ManagerConfig) error {
 	return m.configErr
 }
 
+// reportingMockUpdater は利用できない理由を説明できるモックUpdaterです
+type reportingMockUpdater struct {
+	mockUpdater
+	reason string
+}
+
+func (m *reportingMockUpdater) UnavailableReason() string { return m.reason }
+
 // テスト前にレジストリをクリアするヘルパー
 func clearRegistry() {
 	globalRegistry.mu.Lock()
@@ -270,6 +278,22 @@ func TestGetEnabled(t *testing.T) {
 		assert.Equal(t, "apt", result[0].Name())
 	})
 
+	t.Run("利用できない理由を警告に含める", func(t *testing.T) {
+		clearRegistry()
+
+		Register(&reportingMockUpdater{mockUpdater: mockUpdater{name: "vscode"}, reason: "code が PATH にありません"})
+
+		cfg := &config.SysConfig{
+			Enable:   []string{"vscode"},
+			Managers: make(map[string]config.ManagerConfig),
+		}
+
+		result, err := GetEnabled(cfg)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "vscode（code が PATH にありません）")
+		assert.Empty(t, result)
+	})
+
 	t.Run("設定の適用", func(t *testing.T) {
 		clearRegistry()
 
@@ -310,3 +334,35 @@ func TestGetEnabled(t *testing.T) {
 		assert.Contains(t, err.Error(), "apt の設定適用に失敗")
 	})
 }
+
+func TestUnavailableLabel(t *testing.T) {
+	assert.Equal(t, "brew", UnavailableLabel(&mockUpdater{name: "brew"}))
+	assert.Equal(t, "vscode", UnavailableLabel(&reportingMockUpdater{mockUpdater: mockUpdater{name: "vscode"}}))
+	assert.Equal(t, "vscode（理由）", UnavailableLabel(&reportingMockUpdater{mockUpdater: mockUpdater{name: "vscode"}, reason: "理由"}))
+}
+
+func TestDependencyDeclarer(t *testing.T) {
+	testCases := []struct {
+		name    string
+		updater Updater
+		want    []string
+	}{
+		{name: "npm は nvm の後", updater: &NpmUpdater{}, want: []string{"nvm"}},
+		{name: "pnpm は nvm の後", updater: &PnpmUpdater{}, want: []string{"nvm"}},
+		{name: "cargo は rustup の後", updater: &CargoUpdater{}, want: []string{"rustup"}},
+		{name: "apt は依存なし", updater: &AptUpdater{}, want: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			declarer, ok := tc.updater.(DependencyDeclarer)
+			if tc.want == nil {
+				assert.False(t, ok)
+				return
+			}
+
+			require.True(t, ok)
+			assert.Equal(t, tc.want, declarer.DependsOn())
+		})
+	}
+}
@@ -13,7 +13,9 @@ import (
 )
 
 // UVUpdater は uv tool (Python CLI ツール) の実装です。
-type UVUpdater struct{}
+type UVUpdater struct {
+	packageFilterSupport
+}
 
 var uvSelfUpdatePattern = regexp.MustCompile(`(?i)would update uv from v?(\S+) to v?(\S+)`)
 
@@ -38,11 +40,23 @@ func (u *UVUpdater) IsAvailable() bool {
 }
 
 func (u *UVUpdater) Configure(cfg config.ManagerConfig) error {
-	// 現時点では設定項目なし
-	return nil
+	return u.configurePackageFilter(cfg)
 }
 
 func (u *UVUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	installed, err := u.ListInstalled(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := u.filterCheckResult(&CheckResult{Packages: installed})
+	result.Message = fmt.Sprintf("%d 件のインストール済みツールを確認（更新可否は実行時に判定）", len(result.Packages))
+
+	return result, nil
+}
+
+// ListInstalled は "uv tool list" でインストール済みツールを返します。
+func (u *UVUpdater) ListInstalled(ctx context.Context) ([]PackageInfo, error) {
 	cmd := exec.CommandContext(ctx, "uv", "tool", "list")
 
 	var stderr bytes.Buffer
@@ -57,13 +71,14 @@ func (u *UVUpdater) Check(ctx context.Context) (*CheckResult, error) {
 		)
 	}
 
-	packages := u.parseToolListOutput(string(output))
+	return u.parseToolListOutput(string(output)), nil
+}
 
-	return &CheckResult{
-		AvailableUpdates: 0,
-		Packages:         packages,
-		Message:          fmt.Sprintf("%d 件のインストール済みツールを確認（更新可否は実行時に判定）", len(packages)),
-	}, nil
+// RollbackCommand は uv tool install で更新前のバージョンを入れ直すコマンドを返します。
+func (u *UVUpdater) RollbackCommand(pkg PackageInfo) (*RollbackCommand, error) {
+	return newRollbackCommand(pkg, "uv", func(version string) []string {
+		return []string{"tool", "install", "--force", pkg.Name + "==" + version}
+	})
 }
 
 func (u *UVUpdater) CheckSelfUpdate(ctx context.Context) (*CheckResult, error) {
@@ -119,8 +134,7 @@ func (u *UVUpdater) SelfUpdate(ctx context.Context, opts UpdateOptions) (*SelfUp
 	}
 
 	cmd := exec.CommandContext(ctx, "uv", "self", "update")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	attachCommandOutput(ctx, cmd)
 	cmd.Stdin = os.Stdin
 
 	if err := cmd.Run(); err != nil {
@@ -137,13 +151,13 @@ func (u *UVUpdater) SelfUpdate(ctx context.Context, opts UpdateOptions) (*SelfUp
 }
 
 func (u *UVUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
-	result := &UpdateResult{}
-
 	checkResult, err := u.Check(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	result := &UpdateResult{Held: checkResult.Held}
+
 	if len(checkResult.Packages) == 0 {
 		result.Message = "uv tool でインストールされたツールがありません"
 		return result, nil
@@ -156,9 +170,10 @@ func (u *UVUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResu
 		return result, nil
 	}
 
-	cmd := exec.CommandContext(ctx, "uv", "tool", upgradeCommand, "--all")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	// hold / ignore で除外がある場合は残りのツールのみ更新
+	args := selectUpdateArgs(checkResult, []string{"tool", upgradeCommand, "--all"}, []string{"tool", upgradeCommand})
+	cmd := exec.CommandContext(ctx, "uv", args...)
+	attachCommandOutput(ctx, cmd)
 	cmd.Stdin = os.Stdin
 
 	if err := cmd.Run(); err != nil {
@@ -0,0 +1,251 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/scottlz0310/dsx/internal/config"
+)
+
+const vscodeDefaultCLI = "code"
+
+// vscodeSupportedCLIs は cli 設定で指定できるエディタ CLI です。
+var vscodeSupportedCLIs = []string{"code", "code-insiders", "cursor"}
+
+// VSCodeUpdater は VS Code 系エディタの拡張機能の実装です。
+// CLI には更新確認の手段がないため、インストール済みの拡張機能を --force で入れ直し、前後のバージョンを比較して結果を報告します。
+type VSCodeUpdater struct {
+	packageFilterSupport
+
+	// cli は使用するエディタ CLI（code / code-insiders / cursor）です。空の場合は code を使います。
+	cli string
+}
+
+// 起動時にレジストリに登録
+func init() {
+	Register(&VSCodeUpdater{})
+}
+
+func (v *VSCodeUpdater) Name() string {
+	return "vscode"
+}
+
+func (v *VSCodeUpdater) DisplayName() string {
+	return fmt.Sprintf("VS Code 拡張機能 (%s)", v.command())
+}
+
+func (v *VSCodeUpdater) IsAvailable() bool {
+	return v.UnavailableReason() == ""
+}
+
+// UnavailableReason はエディタ CLI が使えない理由を返します。
+// SSH / WSL セッションでは、エディタ本体がインストール済みでも CLI が PATH にないことがあるため、その旨を案内します。
+func (v *VSCodeUpdater) UnavailableReason() string {
+	command := v.command()
+
+	path, err := exec.LookPath(command)
+	if err != nil {
+		switch {
+		case os.Getenv("SSH_CONNECTION") != "":
+			return fmt.Sprintf("%s が PATH にありません。SSH セッションではエディタのリモート接続の統合ターミナルから実行してください", command)
+		case os.Getenv("WSL_DISTRO_NAME") != "":
+			return fmt.Sprintf("%s が PATH にありません。WSL では Windows 側のエディタで「PATH への追加」を有効にしてください", command)
+		default:
+			return fmt.Sprintf("%s が PATH にありません", command)
+		}
+	}
+
+	// VS Code Server のリモート CLI は、エディタから接続中の統合ターミナル以外では動作しない
+	if isVSCodeRemoteCLI(path) && os.Getenv("VSCODE_IPC_HOOK_CLI") == "" {
+		return fmt.Sprintf("%s はリモート接続用の CLI のため、エディタの統合ターミナル以外では使用できません", command)
+	}
+
+	return ""
+}
+
+func (v *VSCodeUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	if err := v.configurePackageFilter(cfg); err != nil {
+		return err
+	}
+
+	cli, exists := cfg["cli"]
+	if !exists {
+		return nil
+	}
+
+	name, isString := cli.(string)
+	if !isString || !isSupportedVSCodeCLI(name) {
+		return fmt.Errorf("vscode の cli は %s のいずれかを指定してください: %v", strings.Join(vscodeSupportedCLIs, " / "), cli)
+	}
+
+	v.cli = name
+
+	return nil
+}
+
+// Check は入れ直しの対象となる拡張機能の件数を返します。
+// 実際の更新可否は --install-extension --force の実行時まで分からないため、
+// 更新待ちのパッケージとしては報告せず（Packages / AvailableUpdates は空）、件数のみ Message に含めます。
+func (v *VSCodeUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	targets, err := v.listTargets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("%d 件の拡張機能を確認（更新可否は実行時に判定）", len(targets.Packages))
+	if len(targets.Held) > 0 {
+		message += fmt.Sprintf("、保留 %d 件", len(targets.Held))
+	}
+
+	return &CheckResult{Packages: []PackageInfo{}, Held: targets.Held, Message: message}, nil
+}
+
+// listTargets は hold / ignore を適用したインストール済みの拡張機能を返します。
+// Packages が入れ直しの対象、Held が hold により保留した拡張機能です。
+func (v *VSCodeUpdater) listTargets(ctx context.Context) (*CheckResult, error) {
+	installed, err := v.ListInstalled(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.filterCheckResult(&CheckResult{Packages: installed}), nil
+}
+
+func (v *VSCodeUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	targets, err := v.listTargets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UpdateResult{Held: targets.Held}
+
+	if len(targets.Packages) == 0 {
+		result.Message = "更新対象の拡張機能がありません"
+		return result, nil
+	}
+
+	if opts.DryRun {
+		result.Message = fmt.Sprintf("%d 件の拡張機能を確認します（DryRunモード）", len(targets.Packages))
+		return result, nil
+	}
+
+	command := v.command()
+
+	// 拡張機能ごとに入れ直す（1件の失敗で他の拡張機能の更新は止めない）
+	for _, pkg := range targets.Packages {
+		cmd := exec.CommandContext(ctx, command, "--install-extension", pkg.Name, "--force")
+		attachCommandOutput(ctx, cmd)
+		cmd.Stdin = os.Stdin
+
+		if err := cmd.Run(); err != nil {
+			result.FailedCount++
+			result.Errors = append(result.Errors, fmt.Errorf("%s: %w", pkg.Name, err))
+		}
+	}
+
+	after, err := v.ListInstalled(ctx)
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+		result.Message = "更新後の拡張機能の一覧取得に失敗しました"
+
+		return result, nil
+	}
+
+	result.Packages = vscodeVersionTransitions(targets.Packages, after)
+	result.UpdatedCount = len(result.Packages)
+
+	switch {
+	case result.FailedCount > 0:
+		result.Message = fmt.Sprintf("%d 件更新、%d 件失敗", result.UpdatedCount, result.FailedCount)
+	case result.UpdatedCount == 0:
+		result.Message = "すべての拡張機能は最新です"
+	default:
+		result.Message = fmt.Sprintf("%d 件の拡張機能を更新しました", result.UpdatedCount)
+	}
+
+	return result, nil
+}
+
+// ListInstalled は "--list-extensions --show-versions" でインストール済みの拡張機能を返します。
+func (v *VSCodeUpdater) ListInstalled(ctx context.Context) ([]PackageInfo, error) {
+	command := v.command()
+
+	output, err := runCommandOutputWithLocaleC(ctx, command, []string{"--list-extensions", "--show-versions"},
+		command+" --list-extensions の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseVSCodeExtensions(string(output)), nil
+}
+
+// command は使用するエディタ CLI を返します。
+func (v *VSCodeUpdater) command() string {
+	if v.cli == "" {
+		return vscodeDefaultCLI
+	}
+
+	return v.cli
+}
+
+func isSupportedVSCodeCLI(name string) bool {
+	for _, supported := range vscodeSupportedCLIs {
+		if name == supported {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isVSCodeRemoteCLI は VS Code Server が配置するリモート接続用 CLI（.../bin/remote-cli/code）かどうかを返します。
+func isVSCodeRemoteCLI(path string) bool {
+	return filepath.Base(filepath.Dir(path)) == "remote-cli"
+}
+
+// parseVSCodeExtensions は "publisher.name@1.2.3" 形式の行を拡張機能の一覧に変換します。
+func parseVSCodeExtensions(output string) []PackageInfo {
+	packages := make([]PackageInfo, 0)
+
+	for _, line := range strings.Split(output, "\n") {
+		id, version, found := strings.Cut(strings.TrimSpace(line), "@")
+		// 拡張機能 ID は必ず "publisher.name" 形式のため、警告などの行はここで除外される
+		if !found || id == "" || !strings.Contains(id, ".") {
+			continue
+		}
+
+		packages = append(packages, PackageInfo{Name: id, CurrentVersion: version})
+	}
+
+	return packages
+}
+
+// vscodeVersionTransitions は更新前後の一覧を比較し、バージョンが変わった拡張機能を返します。
+// 拡張機能 ID は大文字小文字を区別しないため、小文字に揃えて突き合わせます。
+func vscodeVersionTransitions(before, after []PackageInfo) []PackageInfo {
+	afterVersions := make(map[string]string, len(after))
+	for _, pkg := range after {
+		afterVersions[strings.ToLower(pkg.Name)] = pkg.CurrentVersion
+	}
+
+	transitions := make([]PackageInfo, 0)
+
+	for _, pkg := range before {
+		newVersion, exists := afterVersions[strings.ToLower(pkg.Name)]
+		if !exists || newVersion == pkg.CurrentVersion {
+			continue
+		}
+
+		transitions = append(transitions, PackageInfo{Name: pkg.Name, CurrentVersion: pkg.CurrentVersion, NewVersion: newVersion})
+	}
+
+	return transitions
+}
@@ -0,0 +1,315 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/scottlz0310/dsx/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVSCodeUpdater_Configure(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.ManagerConfig
+		wantCLI string
+		wantErr bool
+	}{
+		{name: "nilの設定は code", cfg: nil, wantCLI: "code"},
+		{name: "cursor", cfg: config.ManagerConfig{"cli": "cursor"}, wantCLI: "cursor"},
+		{name: "code-insiders", cfg: config.ManagerConfig{"cli": "code-insiders"}, wantCLI: "code-insiders"},
+		{name: "未対応の CLI はエラー", cfg: config.ManagerConfig{"cli": "vim"}, wantErr: true},
+		{name: "文字列以外はエラー", cfg: config.ManagerConfig{"cli": []interface{}{"code"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &VSCodeUpdater{}
+
+			err := v.Configure(tt.cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCLI, v.command())
+		})
+	}
+}
+
+func TestParseVSCodeExtensions(t *testing.T) {
+	output := `Extensions installed on SSH: devbox:
+golang.go@0.41.4
+ms-python.python@2024.8.1
+
+GitHub.copilot@1.200.0
+`
+
+	assert.Equal(t, []PackageInfo{
+		{Name: "golang.go", CurrentVersion: "0.41.4"},
+		{Name: "ms-python.python", CurrentVersion: "2024.8.1"},
+		{Name: "GitHub.copilot", CurrentVersion: "1.200.0"},
+	}, parseVSCodeExtensions(output))
+
+	assert.Empty(t, parseVSCodeExtensions(""))
+}
+
+func TestVSCodeVersionTransitions(t *testing.T) {
+	before := []PackageInfo{
+		{Name: "golang.go", CurrentVersion: "0.41.3"},
+		{Name: "GitHub.copilot", CurrentVersion: "1.199.0"},
+		{Name: "ms-python.python", CurrentVersion: "2024.8.1"},
+		{Name: "removed.ext", CurrentVersion: "1.0.0"},
+	}
+	after := []PackageInfo{
+		{Name: "golang.go", CurrentVersion: "0.41.4"},
+		{Name: "github.copilot", CurrentVersion: "1.200.0"},
+		{Name: "ms-python.python", CurrentVersion: "2024.8.1"},
+	}
+
+	assert.Equal(t, []PackageInfo{
+		{Name: "golang.go", CurrentVersion: "0.41.3", NewVersion: "0.41.4"},
+		{Name: "GitHub.copilot", CurrentVersion: "1.199.0", NewVersion: "1.200.0"},
+	}, vscodeVersionTransitions(before, after))
+}
+
+func TestVSCodeUpdater_UnavailableReason(t *testing.T) {
+	testCases := []struct {
+		name        string
+		env         map[string]string
+		remoteCLI   bool
+		wantEmpty   bool
+		msgContains string
+	}{
+		{name: "PATH にない", msgContains: "code が PATH にありません"},
+		{name: "SSH セッション", env: map[string]string{"SSH_CONNECTION": "10.0.0.1 50000 10.0.0.2 22"}, msgContains: "SSH セッション"},
+		{name: "WSL", env: map[string]string{"WSL_DISTRO_NAME": "Ubuntu"}, msgContains: "WSL"},
+		{name: "統合ターミナル外のリモート CLI", remoteCLI: true, msgContains: "リモート接続用の CLI"},
+		{name: "統合ターミナル内のリモート CLI", remoteCLI: true, env: map[string]string{"VSCODE_IPC_HOOK_CLI": "/tmp/vscode-ipc.sock"}, wantEmpty: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, key := range []string{"SSH_CONNECTION", "WSL_DISTRO_NAME", "VSCODE_IPC_HOOK_CLI"} {
+				t.Setenv(key, tc.env[key])
+			}
+
+			dir := t.TempDir()
+			if tc.remoteCLI {
+				dir = filepath.Join(dir, "bin", "remote-cli")
+				require.NoError(t, os.MkdirAll(dir, 0o755))
+				writeFakeVSCodeCommand(t, dir, "code")
+			}
+
+			t.Setenv("PATH", dir)
+
+			v := &VSCodeUpdater{}
+			reason := v.UnavailableReason()
+
+			if tc.wantEmpty {
+				assert.Empty(t, reason)
+				assert.True(t, v.IsAvailable())
+
+				return
+			}
+
+			assert.Contains(t, reason, tc.msgContains)
+			assert.False(t, v.IsAvailable())
+		})
+	}
+}
+
+func TestVSCodeUpdater_Check(t *testing.T) {
+	setupFakeVSCode(t, "code", "")
+
+	v := &VSCodeUpdater{}
+	require.NoError(t, v.Configure(config.ManagerConfig{"hold": []interface{}{"ms-python.*"}}))
+
+	got, err := v.Check(context.Background())
+	require.NoError(t, err)
+
+	// 更新可否は実行時まで分からないため、インストール済みの拡張機能を更新待ちとして報告しない
+	assert.Empty(t, got.Packages)
+	assert.Equal(t, []PackageInfo{{Name: "ms-python.python", CurrentVersion: "2024.8.1"}}, got.Held)
+	assert.Zero(t, got.AvailableUpdates)
+	assert.Equal(t, "1 件の拡張機能を確認（更新可否は実行時に判定）、保留 1 件", got.Message)
+}
+
+func TestVSCodeUpdater_Update(t *testing.T) {
+	testCases := []struct {
+		name        string
+		cli         string
+		mode        string
+		cfg         config.ManagerConfig
+		opts        UpdateOptions
+		wantUpdated int
+		wantFailed  int
+		wantPkgs    []PackageInfo
+		wantCalls   []string
+		msgContains string
+	}{
+		{
+			name:        "DryRunは入れ直さない",
+			cli:         "code",
+			opts:        UpdateOptions{DryRun: true},
+			msgContains: "DryRunモード",
+		},
+		{
+			name:        "入れ直してバージョンの変化を報告",
+			cli:         "code",
+			wantUpdated: 1,
+			wantPkgs:    []PackageInfo{{Name: "golang.go", CurrentVersion: "0.41.3", NewVersion: "0.41.4"}},
+			wantCalls:   []string{"code --install-extension golang.go --force", "code --install-extension ms-python.python --force"},
+			msgContains: "1 件の拡張機能を更新しました",
+		},
+		{
+			name:        "cursor を使い ignore した拡張機能は対象外",
+			cli:         "cursor",
+			cfg:         config.ManagerConfig{"cli": "cursor", "ignore": []interface{}{"ms-python.*"}},
+			wantUpdated: 1,
+			wantPkgs:    []PackageInfo{{Name: "golang.go", CurrentVersion: "0.41.3", NewVersion: "0.41.4"}},
+			wantCalls:   []string{"cursor --install-extension golang.go --force"},
+		},
+		{
+			name:        "1件の失敗で他の拡張機能は止めない",
+			cli:         "code",
+			mode:        "install_error_golang",
+			wantFailed:  1,
+			wantPkgs:    []PackageInfo{},
+			wantCalls:   []string{"code --install-extension golang.go --force", "code --install-extension ms-python.python --force"},
+			msgContains: "0 件更新、1 件失敗",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := setupFakeVSCode(t, tc.cli, tc.mode)
+
+			v := &VSCodeUpdater{}
+			require.NoError(t, v.Configure(tc.cfg))
+
+			got, err := v.Update(context.Background(), tc.opts)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.wantUpdated, got.UpdatedCount)
+			assert.Equal(t, tc.wantFailed, got.FailedCount)
+			assert.Contains(t, got.Message, tc.msgContains)
+
+			if tc.wantPkgs != nil {
+				assert.Equal(t, tc.wantPkgs, got.Packages)
+			}
+
+			assert.Equal(t, tc.wantCalls, readFakeVSCodeInstallCalls(t, dir))
+		})
+	}
+}
+
+// readFakeVSCodeInstallCalls は --install-extension の呼び出しのみを返します。
+func readFakeVSCodeInstallCalls(t *testing.T, dir string) []string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(dir, "calls"))
+	require.NoError(t, err)
+
+	var calls []string
+
+	// Windows の .cmd は CRLF で追記するため改行を揃える
+	for _, line := range strings.Split(strings.TrimSpace(strings.ReplaceAll(string(data), "\r\n", "\n")), "\n") {
+		if strings.Contains(line, "--install-extension") {
+			calls = append(calls, line)
+		}
+	}
+
+	return calls
+}
+
+// setupFakeVSCode は偽のエディタ CLI を PATH の先頭に配置します。
+// golang.go は --install-extension 後に 0.41.3 から 0.41.4 になり、ms-python.python は最新のままです。
+func setupFakeVSCode(t *testing.T, name, mode string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	writeFakeVSCodeCommand(t, dir, name)
+
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("DSX_TEST_VSCODE_DIR", dir)
+	t.Setenv("DSX_TEST_VSCODE_MODE", mode)
+
+	return dir
+}
+
+func writeFakeVSCodeCommand(t *testing.T, dir, name string) {
+	t.Helper()
+
+	var (
+		fileName string
+		content  string
+	)
+
+	if runtime.GOOS == "windows" {
+		fileName = name + ".cmd"
+		content = `@echo off
+set dir=%DSX_TEST_VSCODE_DIR%
+>>"%dir%\calls" echo %~n0 %*
+if "%1"=="--list-extensions" goto dolist
+if "%1"=="--install-extension" goto doinstall
+echo invalid args 1>&2
+exit /b 1
+:dolist
+if exist "%dir%\golang.updated" (
+  echo golang.go@0.41.4
+) else (
+  echo golang.go@0.41.3
+)
+echo ms-python.python@2024.8.1
+exit /b 0
+:doinstall
+if not "%2"=="golang.go" exit /b 0
+if "%DSX_TEST_VSCODE_MODE%"=="install_error_golang" (
+  echo Failed Installing Extensions: golang.go 1>&2
+  exit /b 1
+)
+type nul > "%dir%\golang.updated"
+exit /b 0
+`
+	} else {
+		fileName = name
+		content = `#!/bin/sh
+dir="${DSX_TEST_VSCODE_DIR}"
+echo "${0##*/} $*" >> "${dir}/calls"
+case "$1" in
+--list-extensions)
+  if [ -f "${dir}/golang.updated" ]; then
+    echo "golang.go@0.41.4"
+  else
+    echo "golang.go@0.41.3"
+  fi
+  echo "ms-python.python@2024.8.1"
+  exit 0
+  ;;
+--install-extension)
+  if [ "$2" = "golang.go" ]; then
+    if [ "${DSX_TEST_VSCODE_MODE}" = "install_error_golang" ]; then
+      echo "Failed Installing Extensions: golang.go" 1>&2
+      exit 1
+    fi
+    touch "${dir}/golang.updated"
+  fi
+  exit 0
+  ;;
+esac
+echo "invalid args" 1>&2
+exit 1
+`
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, fileName), []byte(content), 0o755); err != nil {
+		t.Fatalf("fake %s command write failed: %v", name, err)
+	}
+}
@@ -12,7 +12,9 @@ import (
 )
 
 // WingetUpdater は Windows Package Manager (winget) の実装です。
-type WingetUpdater struct{}
+type WingetUpdater struct {
+	packageFilterSupport
+}
 
 // 起動時にレジストリに登録
 func init() {
@@ -33,7 +35,7 @@ func (w *WingetUpdater) IsAvailable() bool {
 }
 
 func (w *WingetUpdater) Configure(cfg config.ManagerConfig) error {
-	return nil
+	return w.configurePackageFilter(cfg)
 }
 
 func (w *WingetUpdater) Check(ctx context.Context) (*CheckResult, error) {
@@ -52,10 +54,10 @@ func (w *WingetUpdater) Check(ctx context.Context) (*CheckResult, error) {
 		return nil, fmt.Errorf("winget upgrade の実行に失敗: %w", buildCommandOutputErr(err, output))
 	}
 
-	return &CheckResult{
+	return w.filterCheckResult(&CheckResult{
 		AvailableUpdates: len(packages),
 		Packages:         packages,
-	}, nil
+	}), nil
 }
 
 func (w *WingetUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
@@ -64,6 +66,17 @@ func (w *WingetUpdater) Update(ctx context.Context, opts UpdateOptions) (*Update
 		return nil, err
 	}
 
+	// winget upgrade は複数パッケージの同時指定に対応しないため、除外がある場合は1件ずつ更新する
+	if checkResult.hasFilteredPackages() && checkResult.AvailableUpdates > 0 && !opts.DryRun {
+		result := &UpdateResult{Held: checkResult.Held}
+		err := runPerPackageUpdate(ctx, checkResult, result, "winget", func(name string) []string {
+			return []string{upgradeCommand, "--name", name, "--exact", "--disable-interactivity", "--accept-source-agreements", "--accept-package-agreements"}
+		})
+		result.Message = fmt.Sprintf("%d 件の winget パッケージを更新しました", result.UpdatedCount)
+
+		return result, err
+	}
+
 	return runCountBasedUpdate(
 		ctx, opts, checkResult,
 		"すべての winget パッケージは最新です",